@@ -0,0 +1,123 @@
+// Package audit appends the operations performed on the help center to a local JSON Lines log.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"time"
+)
+
+const (
+	ResultCreated = "created"
+	ResultUpdated = "updated"
+	ResultPulled  = "pulled"
+	ResultDeleted = "deleted"
+	ResultFailed  = "failed"
+)
+
+// Record is an operation on an article, a translation or a comment.
+type Record struct {
+	Time      string `json:"time"`
+	User      string `json:"user"`
+	Command   string `json:"command"`
+	Subdomain string `json:"subdomain,omitempty"`
+	File      string `json:"file,omitempty"`
+	ArticleID int    `json:"article_id,omitempty"`
+	Locale    string `json:"locale,omitempty"`
+	CommentID int    `json:"comment_id,omitempty"`
+	Result    string `json:"result"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Log is the audit log file. It is rotated when it exceeds MaxSize bytes, keeping MaxBackups rotated files
+// named with the suffixes .1 (the newest) to .N.
+type Log struct {
+	Path       string
+	MaxSize    int64
+	MaxBackups int
+	now        func() time.Time
+}
+
+// Append appends the records. The time and the user are filled in if not set.
+func (l *Log) Append(records ...Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	now := time.Now
+	if l.now != nil {
+		now = l.now
+	}
+
+	var b []byte
+	for _, r := range records {
+		if r.Time == "" {
+			r.Time = now().UTC().Format(time.RFC3339)
+		}
+		if r.User == "" {
+			r.User = currentUser()
+		}
+		line, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		b = append(append(b, line...), '\n')
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.Path), 0o755); err != nil {
+		return err
+	}
+	if err := l.rotate(int64(len(b))); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(l.Path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// rotate rotates the log if appending n bytes makes it exceed the maximum size.
+func (l *Log) rotate(n int64) error {
+	if l.MaxSize <= 0 {
+		return nil
+	}
+	fi, err := os.Stat(l.Path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if fi.Size() == 0 || fi.Size()+n <= l.MaxSize {
+		return nil
+	}
+
+	if l.MaxBackups <= 0 {
+		return os.Remove(l.Path)
+	}
+	if err := os.Remove(fmt.Sprintf("%s.%d", l.Path, l.MaxBackups)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for i := l.MaxBackups - 1; i >= 1; i-- {
+		err := os.Rename(fmt.Sprintf("%s.%d", l.Path, i), fmt.Sprintf("%s.%d", l.Path, i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.Rename(l.Path, l.Path+".1")
+}
+
+// currentUser returns the name of the user running zgsync.
+func currentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
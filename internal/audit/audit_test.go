@@ -0,0 +1,61 @@
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "audit.log")
+	l := &Log{Path: path, now: func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }}
+	err := l.Append(
+		Record{User: "alice", Command: "push", File: "123-ja.md", ArticleID: 123, Locale: "ja", Result: ResultUpdated},
+		Record{User: "alice", Command: "push", File: "456-ja.md", Result: ResultFailed, Error: "unexpected status code: 404"},
+	)
+	if err != nil {
+		t.Fatalf("Append() failed: %v", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Append() wrote %d lines, want 2", len(lines))
+	}
+	var r Record
+	if err := json.Unmarshal([]byte(lines[0]), &r); err != nil {
+		t.Fatal(err)
+	}
+	expected := Record{Time: "2024-01-02T03:04:05Z", User: "alice", Command: "push", File: "123-ja.md", ArticleID: 123, Locale: "ja", Result: ResultUpdated}
+	if r != expected {
+		t.Errorf("Append() wrote %+v, want %+v", r, expected)
+	}
+}
+
+func TestRotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	l := &Log{Path: path, MaxSize: 100, MaxBackups: 2}
+	for i := 0; i < 4; i++ {
+		if err := l.Append(Record{User: "alice", Command: "pull", ArticleID: i + 1, Result: ResultPulled}); err != nil {
+			t.Fatalf("Append() failed: %v", err)
+		}
+	}
+	// every record exceeds the half of the maximum size, so each append rotates the log.
+	for suffix, id := range map[string]string{"": `"article_id":4`, ".1": `"article_id":3`, ".2": `"article_id":2`} {
+		b, err := os.ReadFile(path + suffix)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(b), id) || strings.Count(string(b), "\n") != 1 {
+			t.Errorf("%s = %s, want the record with %s", filepath.Base(path+suffix), b, id)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("audit.log.3 should not exist: %v", err)
+	}
+}
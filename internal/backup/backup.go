@@ -0,0 +1,251 @@
+// Package backup reads and writes the archives of the snapshots of help centers.
+//
+// An archive is a zip or a gzipped tar file holding the manifest and the JSON of the objects:
+//
+//	manifest.json
+//	{subdomain}/categories/{id}.json
+//	{subdomain}/sections/{id}.json
+//	{subdomain}/articles/{id}.json
+//	{subdomain}/articles/{id}/translations/{locale}.json
+package backup
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const ManifestName = "manifest.json"
+
+// Manifest describes the snapshot in the archive.
+type Manifest struct {
+	CreatedAt string  `json:"created_at"`
+	Brands    []Brand `json:"brands"`
+}
+
+// Brand is the summary of the snapshot of a help center.
+type Brand struct {
+	Name         string `json:"name,omitempty"`
+	Subdomain    string `json:"subdomain"`
+	Sections     []int  `json:"sections,omitempty"`
+	Articles     int    `json:"articles"`
+	Translations int    `json:"translations"`
+}
+
+func CategoryPath(subdomain string, id int) string {
+	return fmt.Sprintf("%s/categories/%d.json", subdomain, id)
+}
+
+func SectionPath(subdomain string, id int) string {
+	return fmt.Sprintf("%s/sections/%d.json", subdomain, id)
+}
+
+func ArticlePath(subdomain string, id int) string {
+	return fmt.Sprintf("%s/articles/%d.json", subdomain, id)
+}
+
+func TranslationPath(subdomain string, articleID int, locale string) string {
+	return fmt.Sprintf("%s/articles/%d/translations/%s.json", subdomain, articleID, locale)
+}
+
+// FileName returns the default file name of the archive created at t.
+func FileName(t time.Time) string {
+	return "zgsync-backup-" + t.UTC().Format("20060102T150405Z") + ".zip"
+}
+
+// isTar reports whether the archive is a gzipped tar file by the extension, otherwise it is a zip file.
+func isTar(name string) bool {
+	name = strings.ToLower(name)
+	return strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".tgz")
+}
+
+// Writer writes the entries to the archive.
+type Writer struct {
+	f   *os.File
+	zw  *zip.Writer
+	gw  *gzip.Writer
+	tw  *tar.Writer
+	now time.Time
+}
+
+// Create creates the archive. It is a gzipped tar file if the name ends with .tar.gz or .tgz,
+// otherwise a zip file.
+func Create(name string) (*Writer, error) {
+	f, err := os.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	w := &Writer{f: f, now: time.Now()}
+	if isTar(name) {
+		w.gw = gzip.NewWriter(f)
+		w.tw = tar.NewWriter(w.gw)
+	} else {
+		w.zw = zip.NewWriter(f)
+	}
+	return w, nil
+}
+
+// WriteJSON writes v as the indented JSON entry.
+func (w *Writer) WriteJSON(name string, v any) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	if w.tw != nil {
+		hdr := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(b)), ModTime: w.now}
+		if err := w.tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err = w.tw.Write(b)
+		return err
+	}
+	fw, err := w.zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: w.now})
+	if err != nil {
+		return err
+	}
+	_, err = fw.Write(b)
+	return err
+}
+
+// Close flushes the entries and closes the archive.
+func (w *Writer) Close() error {
+	var err error
+	if w.tw != nil {
+		err = w.tw.Close()
+		if gerr := w.gw.Close(); err == nil {
+			err = gerr
+		}
+	} else {
+		err = w.zw.Close()
+	}
+	if ferr := w.f.Close(); err == nil {
+		err = ferr
+	}
+	return err
+}
+
+// Archive is the entries read from the archive.
+type Archive struct {
+	Manifest Manifest
+	Files    map[string][]byte
+}
+
+// Open reads all the entries of the archive.
+func Open(name string) (*Archive, error) {
+	a := &Archive{Files: map[string][]byte{}}
+	add := func(p string, r io.Reader) error {
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		a.Files[path.Clean(p)] = b
+		return nil
+	}
+
+	if isTar(name) {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		gr, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		tr := tar.NewReader(gr)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			if hdr.Typeflag != tar.TypeReg {
+				continue
+			}
+			if err := add(hdr.Name, tr); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		zr, err := zip.OpenReader(name)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		for _, zf := range zr.File {
+			if zf.FileInfo().IsDir() {
+				continue
+			}
+			rc, err := zf.Open()
+			if err != nil {
+				return nil, err
+			}
+			err = add(zf.Name, rc)
+			rc.Close()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	b, ok := a.Files[ManifestName]
+	if !ok {
+		return nil, fmt.Errorf("%s is not found in %s", ManifestName, name)
+	}
+	if err := json.Unmarshal(b, &a.Manifest); err != nil {
+		return nil, fmt.Errorf("%s: %w", ManifestName, err)
+	}
+	return a, nil
+}
+
+// ReadJSON decodes the entry into v.
+func (a *Archive) ReadJSON(name string, v any) error {
+	b, ok := a.Files[name]
+	if !ok {
+		return fmt.Errorf("%s is not found in the archive", name)
+	}
+	return json.Unmarshal(b, v)
+}
+
+// Articles returns the IDs of the articles of the help center in the archive in ascending order.
+func (a *Archive) Articles(subdomain string) []int {
+	var ids []int
+	prefix := subdomain + "/articles/"
+	for name := range a.Files {
+		rest, ok := strings.CutPrefix(name, prefix)
+		if !ok || strings.Contains(rest, "/") {
+			continue
+		}
+		if id, err := strconv.Atoi(strings.TrimSuffix(rest, ".json")); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// Locales returns the locales of the translations of the article in the archive in ascending order.
+func (a *Archive) Locales(subdomain string, articleID int) []string {
+	var locales []string
+	prefix := fmt.Sprintf("%s/articles/%d/translations/", subdomain, articleID)
+	for name := range a.Files {
+		if rest, ok := strings.CutPrefix(name, prefix); ok && path.Ext(rest) == ".json" {
+			locales = append(locales, strings.TrimSuffix(rest, ".json"))
+		}
+	}
+	sort.Strings(locales)
+	return locales
+}
@@ -0,0 +1,58 @@
+package backup
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestArchive(t *testing.T) {
+	manifest := Manifest{
+		CreatedAt: "2024-01-02T03:04:05Z",
+		Brands:    []Brand{{Subdomain: "example", Sections: []int{1}, Articles: 1, Translations: 2}},
+	}
+	for _, name := range []string{"backup.zip", "backup.tar.gz"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			w, err := Create(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := w.WriteJSON(ArticlePath("example", 123), map[string]any{"id": 123}); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.WriteJSON(TranslationPath("example", 123, "ja"), map[string]any{"locale": "ja"}); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.WriteJSON(ManifestName, manifest); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			a, err := Open(path)
+			if err != nil {
+				t.Fatalf("Open() failed: %v", err)
+			}
+			if !reflect.DeepEqual(a.Manifest, manifest) {
+				t.Errorf("Open() manifest = %+v, want %+v", a.Manifest, manifest)
+			}
+			var article struct {
+				ID int `json:"id"`
+			}
+			if err := a.ReadJSON("example/articles/123.json", &article); err != nil || article.ID != 123 {
+				t.Errorf("ReadJSON() = %+v, %v", article, err)
+			}
+			if err := a.ReadJSON("example/articles/456.json", &article); err == nil {
+				t.Error("ReadJSON() should fail for a missing entry")
+			}
+			if ids := a.Articles("example"); !reflect.DeepEqual(ids, []int{123}) {
+				t.Errorf("Articles() = %v", ids)
+			}
+			if locales := a.Locales("example", 123); !reflect.DeepEqual(locales, []string{"ja"}) {
+				t.Errorf("Locales() = %v", locales)
+			}
+		})
+	}
+}
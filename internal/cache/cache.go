@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const dirName = "zgsync"
+
+// Cache is a directory of the cached values, which expire after the TTL.
+// The values are stored in files named by their slash-separated keys.
+type Cache struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+}
+
+// Dir returns the cache directory of the contents directory, which is {user cache dir}/zgsync/{hash of the path}
+// such as $XDG_CACHE_HOME/zgsync/0123456789abcdef, so that the commands run in the same repository share it.
+func Dir(contentsDir string) (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	abs, err := filepath.Abs(contentsDir)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(abs))
+	return filepath.Join(base, dirName, hex.EncodeToString(sum[:8])), nil
+}
+
+func New(dir string, ttl time.Duration) *Cache {
+	return &Cache{dir: dir, ttl: ttl, now: time.Now}
+}
+
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, filepath.FromSlash(key)+".json")
+}
+
+// Get returns the value of the key. The expired value is removed and is not returned.
+func (c *Cache) Get(key string) (string, bool) {
+	path := c.path(key)
+	fi, err := os.Stat(path)
+	if err != nil {
+		return "", false
+	}
+	if c.now().Sub(fi.ModTime()) >= c.ttl {
+		_ = os.Remove(path)
+		return "", false
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	return string(b), true
+}
+
+// Put stores the value of the key. The value is written to a temporary file and renamed,
+// so that the readers in the other processes do not see a partial value.
+func (c *Cache) Put(key string, value string) error {
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(value); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// Delete removes the value of the key and the values of the keys under it.
+func (c *Cache) Delete(key string) error {
+	if err := os.Remove(c.path(key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.RemoveAll(filepath.Join(c.dir, filepath.FromSlash(key)))
+}
+
+// Clear removes all the values.
+func (c *Cache) Clear() error {
+	return os.RemoveAll(c.dir)
+}
@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cache")
+	c := New(dir, time.Minute)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	if _, ok := c.Get("example/articles/1/ja"); ok {
+		t.Error("Get() should miss before Put()")
+	}
+	for _, key := range []string{"example/articles/1/ja", "example/articles/1/en-us", "example/articles/2/ja"} {
+		if err := c.Put(key, key); err != nil {
+			t.Fatalf("Put() failed: %v", err)
+		}
+	}
+	if v, ok := c.Get("example/articles/1/ja"); !ok || v != "example/articles/1/ja" {
+		t.Errorf("Get() failed: got %q, %v", v, ok)
+	}
+
+	if err := c.Delete("example/articles/1"); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+	for key, expected := range map[string]bool{"example/articles/1/ja": false, "example/articles/1/en-us": false, "example/articles/2/ja": true} {
+		if _, ok := c.Get(key); ok != expected {
+			t.Errorf("Delete() failed: %s cached = %v, want %v", key, ok, expected)
+		}
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok := c.Get("example/articles/2/ja"); ok {
+		t.Error("Get() should miss after the TTL")
+	}
+	if _, err := os.Stat(c.path("example/articles/2/ja")); !os.IsNotExist(err) {
+		t.Error("Get() should remove the expired value")
+	}
+
+	if err := c.Clear(); err != nil {
+		t.Fatalf("Clear() failed: %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("Clear() failed: %s should be removed", dir)
+	}
+}
+
+func TestDir(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	a, err := Dir("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Dir("b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a == b {
+		t.Errorf("Dir() failed: the contents directories share %s", a)
+	}
+	if filepath.Dir(filepath.Dir(a)) != os.Getenv("XDG_CACHE_HOME") {
+		t.Errorf("Dir() failed: got %s out of XDG_CACHE_HOME", a)
+	}
+}
@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/tukaelu/zgsync/internal/state"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+// assetLinkPattern matches the relative links of the images and the anchors in the HTML body.
+var assetLinkPattern = regexp.MustCompile(`(src|href)="([^":#?/][^":#?]*)"`)
+
+// uploadAssets uploads the linked files of the assets directory as inline attachments and rewrites the links.
+func (c *CommandPush) uploadAssets(g *Global, client zendesk.Client, file string, t *zendesk.Translation) error {
+	e := c.state.Get(file)
+	return replaceAssetLinks(file, t, func(link, target string) (string, error) {
+		asset, err := c.uploadAsset(g, client, e, t.SourceID, link, target)
+		return asset.URL, err
+	})
+}
+
+// uploadedAssets rewrites the links into the URLs of the attachments already uploaded, without uploading.
+func uploadedAssets(s *state.Store, file string, t *zendesk.Translation) error {
+	e, ok := s.Lookup(file)
+	if !ok || len(e.Assets) == 0 {
+		return nil
+	}
+	return replaceAssetLinks(file, t, func(link, target string) (string, error) {
+		asset, ok := e.Assets[link]
+		if !ok {
+			return "", nil
+		}
+		if hash, err := state.HashFile(target); err != nil || hash != asset.Hash {
+			return "", nil
+		}
+		return asset.URL, nil
+	})
+}
+
+// replaceAssetLinks replaces the links to the assets with the URLs given by assetURL, keeping those with an empty URL.
+func replaceAssetLinks(file string, t *zendesk.Translation, assetURL func(link, target string) (string, error)) error {
+	dir := filepath.Join(filepath.Dir(file), filepath.FromSlash(t.AssetsDir))
+	var err error
+	t.Body = assetLinkPattern.ReplaceAllStringFunc(t.Body, func(m string) string {
+		sub := assetLinkPattern.FindStringSubmatch(m)
+		if err != nil || strings.HasSuffix(sub[2], ".md") {
+			return m
+		}
+		link, uerr := url.PathUnescape(sub[2])
+		if uerr != nil {
+			return m
+		}
+		target := filepath.Join(filepath.Dir(file), filepath.FromSlash(link))
+		if !inDir(dir, target) {
+			return m
+		}
+		u, aerr := assetURL(link, target)
+		if aerr != nil {
+			err = fmt.Errorf("%s: %w", link, aerr)
+			return m
+		}
+		if u == "" {
+			return m
+		}
+		return fmt.Sprintf(`%s="%s"`, sub[1], u)
+	})
+	return err
+}
+
+// uploadAsset uploads the file unless the same content has been uploaded for the link.
+func (c *CommandPush) uploadAsset(g *Global, client zendesk.Client, e *state.Entry, articleID int, link, target string) (state.Asset, error) {
+	hash, err := state.HashFile(target)
+	if err != nil {
+		return state.Asset{}, err
+	}
+	if asset, ok := e.Assets[link]; ok && asset.Hash == hash {
+		return asset, nil
+	}
+
+	b, err := os.ReadFile(target)
+	if err != nil {
+		return state.Asset{}, err
+	}
+	name := filepath.Base(target)
+	if g.Config.Images.Optimize {
+		size := len(b)
+		if b, name, err = g.Config.Images.options().Optimize(name, b); err != nil {
+			return state.Asset{}, fmt.Errorf("failed to optimize the image: %w", err)
+		}
+		slog.Debug("optimized", "file", target, "name", name, "size", size, "optimized_size", len(b))
+	}
+	res, err := client.CreateArticleAttachment(articleID, name, bytes.NewReader(b), true)
+	if err != nil {
+		return state.Asset{}, err
+	}
+	a := &zendesk.ArticleAttachment{}
+	if err := a.FromJson(res); err != nil {
+		return state.Asset{}, err
+	}
+	slog.Info("uploaded", "file", target, "article_id", articleID, "attachment_id", a.ID)
+
+	asset := state.Asset{Hash: hash, URL: a.ContentURL}
+	if e.Assets == nil {
+		e.Assets = map[string]state.Asset{}
+	}
+	e.Assets[link] = asset
+	return asset, nil
+}
+
+// inDir reports whether the path is in the directory.
+func inDir(dir, p string) bool {
+	rel, err := filepath.Rel(dir, p)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// restoreAssets rewrites the URLs of the uploaded attachments back into the links to the local files.
+func restoreAssets(s *state.Store, file, body string) string {
+	e, ok := s.Lookup(file)
+	if !ok || len(e.Assets) == 0 {
+		return body
+	}
+	links := make([]string, 0, len(e.Assets))
+	for link := range e.Assets {
+		links = append(links, link)
+	}
+	// the order is fixed for the assets uploaded to the same URL.
+	sort.Strings(links)
+	pairs := make([]string, 0, len(links)*2)
+	for _, link := range links {
+		pairs = append(pairs, e.Assets[link].URL, (&url.URL{Path: link}).EscapedPath())
+	}
+	return strings.NewReplacer(pairs...).Replace(body)
+}
+
+// localAssetsDir returns assets_dir of the local translation, which is empty if it does not exist.
+func localAssetsDir(path string) string {
+	t := &zendesk.Translation{}
+	if err := t.FromFile(path); err != nil {
+		return ""
+	}
+	return t.AssetsDir
+}
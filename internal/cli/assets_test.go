@@ -0,0 +1,205 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tukaelu/zgsync/internal/converter"
+	"github.com/tukaelu/zgsync/internal/state"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+type assetsClient struct {
+	zendesk.Client
+	uploaded []string
+}
+
+func (c *assetsClient) CreateArticleAttachment(articleID int, fileName string, content io.Reader, inline bool) (string, error) {
+	b, _ := io.ReadAll(content)
+	c.uploaded = append(c.uploaded, fmt.Sprintf("%d/%s:%s", articleID, fileName, b))
+	return fmt.Sprintf(`{"article_attachment":{"id":%d,"content_url":"https://example.zendesk.com/hc/article_attachments/%d"}}`, len(c.uploaded), len(c.uploaded)), nil
+}
+
+func TestUploadAssets(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{"assets/ja/shot 1.png": "ja", "assets/en/shot 1.png": "en", "logo.png": "logo"} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	file := filepath.Join(dir, "1-ja.md")
+	s, err := state.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &CommandPush{state: s}
+	client := &assetsClient{}
+	body := `<p><img src="assets/ja/shot%201.png"><img src="assets/en/shot%201.png"><img src="logo.png"><a href="assets/ja/shot%201.png">shot</a><a href="2-ja.md">2</a></p>`
+
+	tr := &zendesk.Translation{SourceID: 1, AssetsDir: "assets/ja", Body: body}
+	if err := c.uploadAssets(&Global{}, client, file, tr); err != nil {
+		t.Fatalf("uploadAssets() failed: %v", err)
+	}
+	expected := `<p><img src="https://example.zendesk.com/hc/article_attachments/1"><img src="assets/en/shot%201.png"><img src="logo.png"><a href="https://example.zendesk.com/hc/article_attachments/1">shot</a><a href="2-ja.md">2</a></p>`
+	if tr.Body != expected {
+		t.Errorf("uploadAssets() failed:\ngot  %s\nwant %s", tr.Body, expected)
+	}
+	if fmt.Sprint(client.uploaded) != "[1/shot 1.png:ja]" {
+		t.Errorf("uploadAssets() failed: got %v uploaded", client.uploaded)
+	}
+
+	// the unchanged asset is not uploaded again, and the changed one is.
+	tr = &zendesk.Translation{SourceID: 1, AssetsDir: "assets/ja", Body: body}
+	if err := c.uploadAssets(&Global{}, client, file, tr); err != nil {
+		t.Fatalf("uploadAssets() failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "assets", "ja", "shot 1.png"), []byte("ja2"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tr = &zendesk.Translation{SourceID: 1, AssetsDir: "assets/ja", Body: body}
+	if err := c.uploadAssets(&Global{}, client, file, tr); err != nil {
+		t.Fatalf("uploadAssets() failed: %v", err)
+	}
+	if fmt.Sprint(client.uploaded) != "[1/shot 1.png:ja 1/shot 1.png:ja2]" {
+		t.Errorf("uploadAssets() failed: got %v uploaded", client.uploaded)
+	}
+
+	restored := restoreAssets(s, file, `<img src="https://example.zendesk.com/hc/article_attachments/2">`)
+	if restored != `<img src="assets/ja/shot%201.png">` {
+		t.Errorf("restoreAssets() failed: got %s", restored)
+	}
+
+	// the links are rewritten into the URLs uploaded before without uploading, except those of the changed files.
+	tr = &zendesk.Translation{SourceID: 1, AssetsDir: "assets/ja", Body: `<img src="assets/ja/shot%201.png">`}
+	if err := uploadedAssets(s, file, tr); err != nil || tr.Body != `<img src="https://example.zendesk.com/hc/article_attachments/2">` {
+		t.Errorf("uploadedAssets() failed: got %s, %v", tr.Body, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "assets", "ja", "shot 1.png"), []byte("ja3"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tr = &zendesk.Translation{SourceID: 1, AssetsDir: "assets/ja", Body: `<img src="assets/ja/shot%201.png">`}
+	if err := uploadedAssets(s, file, tr); err != nil || tr.Body != `<img src="assets/ja/shot%201.png">` {
+		t.Errorf("uploadedAssets() failed: got %s, %v", tr.Body, err)
+	}
+	if fmt.Sprint(client.uploaded) != "[1/shot 1.png:ja 1/shot 1.png:ja2]" {
+		t.Errorf("uploadedAssets() failed: got %v uploaded", client.uploaded)
+	}
+}
+
+// assetsPushClient uploads the assets of the translations pushed.
+type assetsPushClient struct {
+	pushClient
+	assets assetsClient
+}
+
+func (c *assetsPushClient) CreateArticleAttachment(articleID int, fileName string, content io.Reader, inline bool) (string, error) {
+	return c.assets.CreateArticleAttachment(articleID, fileName, content, inline)
+}
+
+func TestPushAssetsAfterChecks(t *testing.T) {
+	const uploadedURL = "https://example.zendesk.com/hc/article_attachments/7"
+	tests := []struct {
+		name     string
+		remote   string
+		asset    string
+		uploaded int
+		updated  bool
+	}{
+		{"identical", `{"translation":{"title":"t","body":"<p><img src=\"` + uploadedURL + `\" alt=\"shot\"></p>","updated_at":"2024-01-01T00:00:00Z"}}`, "shot", 0, false},
+		{"conflict", `{"translation":{"title":"t","body":"<p>remote</p>","updated_at":"2024-02-01T00:00:00Z"}}`, "shot2", 0, false},
+		{"changed", `{"translation":{"title":"t","body":"<p>old</p>","updated_at":"2024-01-01T00:00:00Z"}}`, "shot2", 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			file := filepath.Join(dir, "1-ja.md")
+			if err := os.WriteFile(file, []byte("---\ntitle: t\nlocale: ja\nsource_id: 1\nassets_dir: assets\n---\n![shot](assets/shot.png)\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			asset := filepath.Join(dir, "assets", "shot.png")
+			if err := os.MkdirAll(filepath.Dir(asset), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(asset, []byte("shot"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja"}}
+			s, err := g.LoadState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			hash, err := state.HashFile(asset)
+			if err != nil {
+				t.Fatal(err)
+			}
+			e := s.Get(file)
+			e.Kind, e.ArticleID, e.Locale, e.RemoteUpdatedAt = state.KindTranslation, 1, "ja", "2024-01-01T00:00:00Z"
+			e.Assets = map[string]state.Asset{"assets/shot.png": {Hash: hash, URL: uploadedURL}}
+			if err := s.Save(); err != nil {
+				t.Fatal(err)
+			}
+
+			// the changed asset is uploaded only for the translation pushed after the checks.
+			if err := os.WriteFile(asset, []byte(tt.asset), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			client := &assetsPushClient{pushClient: pushClient{remote: map[int]string{1: tt.remote}}}
+			c := &CommandPush{OnConflict: conflictSkip, Files: []string{file}, client: client, clients: map[string]zendesk.Client{}, converter: converter.NewConverter()}
+			if err := c.Run(g); err != nil {
+				t.Fatalf("Run() failed: %v", err)
+			}
+			if len(client.assets.uploaded) != tt.uploaded {
+				t.Errorf("Run() failed: got %v uploaded", client.assets.uploaded)
+			}
+			if (len(client.updated) > 0) != tt.updated {
+				t.Errorf("Run() failed: got %v updated", client.updated)
+			}
+		})
+	}
+}
+
+func TestUploadAssetsOptimized(t *testing.T) {
+	dir := t.TempDir()
+	img := image.NewRGBA(image.Rect(0, 0, 200, 100))
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "shot.png"), buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := state.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &CommandPush{state: s}
+	client := &assetsClient{}
+	g := &Global{Config: Config{Images: Images{Optimize: true, MaxWidth: 50, Format: "jpeg"}}}
+
+	tr := &zendesk.Translation{SourceID: 1, AssetsDir: ".", Body: `<img src="shot.png">`}
+	if err := c.uploadAssets(g, client, filepath.Join(dir, "1-ja.md"), tr); err != nil {
+		t.Fatalf("uploadAssets() failed: %v", err)
+	}
+	if len(client.uploaded) != 1 {
+		t.Fatalf("uploadAssets() failed: got %d uploaded", len(client.uploaded))
+	}
+	uploaded, ok := strings.CutPrefix(client.uploaded[0], "1/shot.jpg:")
+	if !ok {
+		t.Fatalf("uploadAssets() failed: got %.20q uploaded", client.uploaded[0])
+	}
+	cfg, format, err := image.DecodeConfig(strings.NewReader(uploaded))
+	if err != nil || format != "jpeg" || cfg.Width != 50 || cfg.Height != 25 {
+		t.Errorf("uploadAssets() failed: uploaded %s %dx%d, %v", format, cfg.Width, cfg.Height, err)
+	}
+}
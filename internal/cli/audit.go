@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"log/slog"
+	"path/filepath"
+
+	"github.com/tukaelu/zgsync/internal/audit"
+)
+
+// audit appends the records to the audit log if it is configured, without failing the command.
+func (g *Global) audit(records ...audit.Record) {
+	conf := g.Config.AuditLog
+	if conf.Path == "" {
+		return
+	}
+	path := conf.Path
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(g.Config.ContentsDir, path)
+	}
+	maxSize := conf.MaxSizeMB
+	if maxSize == 0 {
+		maxSize = 10
+	}
+	maxBackups := conf.MaxBackups
+	if maxBackups == 0 {
+		maxBackups = 3
+	}
+	for i := range records {
+		if records[i].Subdomain == "" {
+			records[i].Subdomain = g.Config.Subdomain
+		}
+	}
+	l := &audit.Log{Path: path, MaxSize: int64(maxSize) << 20, MaxBackups: maxBackups}
+	if err := l.Append(records...); err != nil {
+		slog.Warn("failed to write the audit log", "error", err)
+	}
+}
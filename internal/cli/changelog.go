@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"fmt"
+	"html"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/tukaelu/zgsync/internal/state"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+// changeComment returns the change note as a hidden HTML comment appended to the body.
+func changeComment(message string) string {
+	// a comment cannot contain "--".
+	return "\n<!-- zgsync: " + strings.ReplaceAll(message, "--", "- -") + " -->\n"
+}
+
+// recordChange records the change note in the sync state and prepends it to the "What's new" article if configured.
+func (c *CommandPush) recordChange(g *Global, now time.Time) error {
+	if c.Message == "" || len(c.results) == 0 {
+		return nil
+	}
+	var files []string
+	for _, r := range c.results {
+		files = append(files, c.state.Key(r.File))
+	}
+	c.state.Changelog = append(c.state.Changelog, state.Change{Time: now.UTC().Format(time.RFC3339), Message: c.Message, Files: files})
+
+	conf := g.Config.Changelog
+	if conf.ArticleID == 0 {
+		return nil
+	}
+	locale := conf.Locale
+	if locale == "" {
+		locale = g.Config.DefaultLocale
+	}
+	locale = g.Config.remoteLocale(locale)
+
+	// the entries added since the translation was cached would be lost.
+	res, err := zendesk.Uncached(c.client).ShowTranslation(conf.ArticleID, locale)
+	if err != nil {
+		return fmt.Errorf("changelog article %d: %w", conf.ArticleID, err)
+	}
+	t := &zendesk.Translation{}
+	if err := t.FromJson(res); err != nil {
+		return err
+	}
+	t.Body = c.changeEntry(conf.ArticleID, now) + t.Body
+	payload, err := t.ToPayload()
+	if err != nil {
+		return err
+	}
+	if _, err := c.client.UpdateTranslation(conf.ArticleID, locale, payload); err != nil {
+		return fmt.Errorf("changelog article %d: %w", conf.ArticleID, err)
+	}
+	slog.Info("updated the changelog", "article_id", conf.ArticleID, "locale", locale)
+	return nil
+}
+
+// changeEntry returns the HTML of the change note listing the pushed articles except the "What's new" article.
+func (c *CommandPush) changeEntry(articleID int, now time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<h2>%s</h2>\n<p>%s</p>\n", now.Format("2006-01-02"), html.EscapeString(c.Message))
+
+	var items []string
+	seen := map[int]bool{articleID: true}
+	for _, r := range c.results {
+		if seen[r.ArticleID] || r.Title == "" {
+			continue
+		}
+		seen[r.ArticleID] = true
+		if r.URL != "" {
+			items = append(items, fmt.Sprintf("<li><a href=\"%s\">%s</a></li>\n", html.EscapeString(r.URL), html.EscapeString(r.Title)))
+		} else {
+			items = append(items, fmt.Sprintf("<li>%s</li>\n", html.EscapeString(r.Title)))
+		}
+	}
+	if len(items) > 0 {
+		b.WriteString("<ul>\n" + strings.Join(items, "") + "</ul>\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/tukaelu/zgsync/internal/report"
+)
+
+// defaultCheckerPattern matches the lines of vale --output=line and cspell, such as path:12:3: message.
+const defaultCheckerPattern = `^.*?:(?P<line>\d+)(?::\d+)?:?\s*(?:- )?(?P<message>\S.*)$`
+
+// Checker is the external checker run by validate for each file, such as vale or cspell.
+type Checker struct {
+	Name     string   `yaml:"name" description:"Name of the checker, which is the rule of its diagnostics"`
+	Command  []string `yaml:"command" description:"Command and its arguments, where {file} is replaced by the path of the file"`
+	Pattern  string   `yaml:"pattern" description:"Regular expression of the lines of the output with the named groups line, rule and message"`
+	Severity string   `yaml:"severity" description:"Severity of the diagnostics (error or warning)" default:"warning"`
+}
+
+// validateCheckers verifies the checkers of the configuration.
+func (c *Config) validateCheckers() error {
+	names := map[string]bool{}
+	for i, ch := range c.Checkers {
+		if ch.Name == "" || len(ch.Command) == 0 {
+			return fmt.Errorf("checkers[%d]: name and command are required", i)
+		}
+		if names[ch.Name] {
+			return fmt.Errorf("checkers: %s is duplicated", ch.Name)
+		}
+		names[ch.Name] = true
+		if _, err := ch.pattern(); err != nil {
+			return fmt.Errorf("checkers: %s: %w", ch.Name, err)
+		}
+		switch ch.Severity {
+		case "", report.SeverityError, report.SeverityWarning:
+		default:
+			return fmt.Errorf("checkers: %s: severity must be %s or %s", ch.Name, report.SeverityError, report.SeverityWarning)
+		}
+	}
+	return nil
+}
+
+func (ch *Checker) pattern() (*regexp.Regexp, error) {
+	if ch.Pattern == "" {
+		return regexp.MustCompile(defaultCheckerPattern), nil
+	}
+	re, err := regexp.Compile(ch.Pattern)
+	if err != nil {
+		return nil, err
+	}
+	if re.SubexpIndex("message") < 0 {
+		return nil, fmt.Errorf("pattern must have the named group message")
+	}
+	return re, nil
+}
+
+// check runs the checker for the file and returns its diagnostics without the file name.
+func (ch *Checker) check(file string) ([]report.Diagnostic, error) {
+	re, err := ch.pattern()
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, len(ch.Command)-1)
+	for i, arg := range ch.Command[1:] {
+		args[i] = strings.ReplaceAll(arg, "{file}", file)
+	}
+	// the checkers exit with a non-zero status when they find problems, with the problems in the output.
+	out, runErr := runCommand(ch.Command[0], args, nil)
+	var exitErr *exec.ExitError
+	if runErr != nil && !errors.As(runErr, &exitErr) {
+		return nil, fmt.Errorf("checker %s: %w", ch.Name, runErr)
+	}
+
+	severity := ch.Severity
+	if severity == "" {
+		severity = report.SeverityWarning
+	}
+	var diags []report.Diagnostic
+	for _, line := range strings.Split(string(out), "\n") {
+		m := re.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if m == nil {
+			continue
+		}
+		d := report.Diagnostic{Line: 1, Rule: ch.Name, Severity: severity, Message: strings.TrimSpace(m[re.SubexpIndex("message")])}
+		if i := re.SubexpIndex("line"); i >= 0 {
+			if n, err := strconv.Atoi(m[i]); err == nil && n > 0 {
+				d.Line = n
+			}
+		}
+		if i := re.SubexpIndex("rule"); i >= 0 && m[i] != "" {
+			d.Rule = ch.Name + "/" + m[i]
+		}
+		diags = append(diags, d)
+	}
+	if runErr != nil && len(diags) == 0 {
+		diags = append(diags, report.Diagnostic{Line: 1, Rule: ch.Name, Severity: report.SeverityError, Message: fmt.Sprintf("%s failed: %v", ch.Name, runErr)})
+	}
+	return diags, nil
+}
+
+// checkerRules returns the rules of the checkers of the configuration.
+func (c *Config) checkerRules() []report.Rule {
+	var rules []report.Rule
+	for _, ch := range c.Checkers {
+		rules = append(rules, report.Rule{ID: ch.Name, Description: fmt.Sprintf("The file must pass the external checker %s (%s).", ch.Name, strings.Join(ch.Command, " "))})
+	}
+	return rules
+}
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"fmt"
+	"os/exec"
+	"reflect"
+	"testing"
+
+	"github.com/tukaelu/zgsync/internal/report"
+)
+
+func TestCheckerCheck(t *testing.T) {
+	defer func(f func(string, []string, []string) ([]byte, error)) { runCommand = f }(runCommand)
+
+	tests := []struct {
+		name     string
+		checker  Checker
+		out      string
+		err      error
+		expected []report.Diagnostic
+	}{
+		{
+			"vale",
+			Checker{Name: "vale", Command: []string{"vale", "--output=line", "{file}"}},
+			"guides/1-ja.md:12:3:Vale.Spelling:Did you really mean 'zgsnyc'?\nguides/1-ja.md:20:1:Google.Headings:'Getting Started' should use sentence-style capitalization.\n",
+			&exec.ExitError{},
+			[]report.Diagnostic{
+				{Line: 12, Rule: "vale", Severity: report.SeverityWarning, Message: "Vale.Spelling:Did you really mean 'zgsnyc'?"},
+				{Line: 20, Rule: "vale", Severity: report.SeverityWarning, Message: "Google.Headings:'Getting Started' should use sentence-style capitalization."},
+			},
+		},
+		{
+			"cspell",
+			Checker{Name: "cspell", Command: []string{"cspell", "--no-progress", "{file}"}, Severity: report.SeverityError},
+			"guides/1-ja.md:4:10 - Unknown word (zgsnyc)\r\n1/1 files checked\r\n",
+			nil,
+			[]report.Diagnostic{
+				{Line: 4, Rule: "cspell", Severity: report.SeverityError, Message: "Unknown word (zgsnyc)"},
+			},
+		},
+		{
+			"pattern",
+			Checker{Name: "style", Command: []string{"style", "{file}"}, Pattern: `^\[(?P<rule>\w+)\] line (?P<line>\d+): (?P<message>.+)$`},
+			"[passive] line 7: avoid the passive voice\n",
+			nil,
+			[]report.Diagnostic{
+				{Line: 7, Rule: "style/passive", Severity: report.SeverityWarning, Message: "avoid the passive voice"},
+			},
+		},
+		{
+			"failed without output",
+			Checker{Name: "vale", Command: []string{"vale", "{file}"}},
+			"",
+			fmt.Errorf("%w: E100 config not found", &exec.ExitError{}),
+			[]report.Diagnostic{
+				{Line: 1, Rule: "vale", Severity: report.SeverityError, Message: "vale failed: <nil>: E100 config not found"},
+			},
+		},
+		{"clean", Checker{Name: "vale", Command: []string{"vale", "{file}"}}, "", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args []string
+			runCommand = func(name string, a []string, env []string) ([]byte, error) {
+				args = append([]string{name}, a...)
+				return []byte(tt.out), tt.err
+			}
+			actual, err := tt.checker.check("guides/1-ja.md")
+			if err != nil {
+				t.Fatalf("check() failed: %v", err)
+			}
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("check() failed: got %v, want %v", actual, tt.expected)
+			}
+			if args[len(args)-1] != "guides/1-ja.md" {
+				t.Errorf("check() failed: {file} is not replaced: %v", args)
+			}
+		})
+	}
+
+	runCommand = func(name string, a []string, env []string) ([]byte, error) {
+		return nil, exec.ErrNotFound
+	}
+	if _, err := (&Checker{Name: "vale", Command: []string{"vale"}}).check("1-ja.md"); err == nil {
+		t.Error("check() should fail when the checker cannot run")
+	}
+}
+
+func TestValidateCheckers(t *testing.T) {
+	tests := []struct {
+		checkers []Checker
+		expected string
+	}{
+		{[]Checker{{Name: "vale", Command: []string{"vale"}}, {Name: "cspell", Command: []string{"cspell"}, Severity: "error"}}, ""},
+		{[]Checker{{Name: "vale"}}, "checkers[0]: name and command are required"},
+		{[]Checker{{Name: "vale", Command: []string{"vale"}}, {Name: "vale", Command: []string{"vale"}}}, "checkers: vale is duplicated"},
+		{[]Checker{{Name: "vale", Command: []string{"vale"}, Pattern: `(?P<line>\d+)`}}, "checkers: vale: pattern must have the named group message"},
+		{[]Checker{{Name: "vale", Command: []string{"vale"}, Severity: "info"}}, "checkers: vale: severity must be error or warning"},
+	}
+	for _, tt := range tests {
+		var actual string
+		if err := (&Config{Checkers: tt.checkers}).validateCheckers(); err != nil {
+			actual = err.Error()
+		}
+		if actual != tt.expected {
+			t.Errorf("validateCheckers() failed: got %q, want %q", actual, tt.expected)
+		}
+	}
+}
@@ -1,33 +1,100 @@
 package cli
 
-import "github.com/alecthomas/kong"
+import (
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
 
 type Global struct {
-	ConfigPath string `name:"config" help:"path to the configuration file" default:"~/.config/zgsync/config.yaml" type:"path"`
+	ConfigPath string `name:"config" help:"path to the configuration file" default:"~/.config/zgsync/config.yaml" type:"path" env:"ZGSYNC_CONFIG"`
+	Env        string `name:"env" help:"name of the environment defined in the configuration file" env:"ZGSYNC_ENV"`
+	Brand      string `name:"brand" help:"name of the brand defined in the configuration file"`
+	AgeKeyFile string `name:"age-key-file" help:"path to the age key file to decrypt the configuration file" type:"path" env:"ZGSYNC_AGE_KEY_FILE"`
+	LogLevel   string `name:"log-level" help:"level of the logs written to the standard error (trace, debug, info, warn or error)" enum:"trace,debug,info,warn,error" default:"info" env:"ZGSYNC_LOG_LEVEL"`
+	Quiet      bool   `name:"quiet" short:"q" help:"write the summaries and the warnings only instead of the logs of each file" xor:"verbosity"`
+	Verbose    int    `name:"verbose" short:"v" type:"counter" help:"write the debug logs, and the HTTP requests and responses with -vv" xor:"verbosity"`
+	LogFormat  string `name:"log-format" help:"format of the logs (text or json)" enum:"text,json" default:"text" env:"ZGSYNC_LOG_FORMAT"`
+	NoColor    bool   `name:"no-color" help:"disable the colors of the logs written to the terminal, which are also disabled by NO_COLOR"`
 	Config     Config `kong:"-"`
 }
 
 type cli struct {
 	Global
-	Push    CommandPush    `cmd:"push" help:"Push translations or articles to the remote."`
-	Pull    CommandPull    `cmd:"pull" help:"Pull translations or articles from the remote."`
-	Empty   CommandEmpty   `cmd:"empty" help:"Creates an empty draft article remotely and saves it locally."`
-	Version CommandVersion `cmd:"version" help:"Show version."`
+	Push      CommandPush      `cmd:"push" help:"Push translations or articles to the remote."`
+	Pull      CommandPull      `cmd:"pull" help:"Pull translations or articles from the remote."`
+	Sync      CommandSync      `cmd:"sync" help:"Push the local changes and pull the remote changes of the files."`
+	Empty     CommandEmpty     `cmd:"empty" help:"Creates an empty draft article remotely and saves it locally."`
+	Scaffold  CommandScaffold  `cmd:"scaffold" help:"Generate the article stubs planned in a CSV file."`
+	New       CommandNew       `cmd:"new" help:"Create a new article file from a template."`
+	Open      CommandOpen      `cmd:"open" help:"Open the article in the browser."`
+	Move      CommandMove      `cmd:"" name:"mv" help:"Move the article to another section."`
+	Publish   CommandPublish   `cmd:"publish" help:"Publish the translations of the articles."`
+	Unpublish CommandUnpublish `cmd:"unpublish" help:"Unpublish the translations of the articles."`
+	Promote   CommandPromote   `cmd:"promote" help:"Promote the articles."`
+	Demote    CommandDemote    `cmd:"demote" help:"Demote the promoted articles."`
+	Meta      CommandMeta      `cmd:"meta" help:"Show the metadata of the remote article."`
+	Export    CommandExport    `cmd:"export" help:"Export the local articles as static HTML."`
+	Import    CommandImport    `cmd:"import" help:"Import HTML pages as local translations."`
+	XLIFF     CommandXLIFF     `cmd:"xliff" help:"Export and import the translations as XLIFF 2.0 files."`
+	Translate CommandTranslate `cmd:"translate" help:"Draft translations with machine translation."`
+	Index     CommandIndex     `cmd:"index" help:"Generate the index of the tracked articles."`
+	Backup    CommandBackup    `cmd:"backup" help:"Back up the remote articles and translations into an archive."`
+	Restore   CommandRestore   `cmd:"restore" help:"Restore the articles and translations from a backup archive."`
+	Stats     CommandStats     `cmd:"stats" help:"Report the votes and the freshness of the articles."`
+	Subscribe CommandSubscribe `cmd:"subscribe" help:"Subscribe a user to the articles to be notified of their changes."`
+	Comments  CommandComments  `cmd:"comments" help:"List, export or delete the comments of the article."`
+	Locales   CommandLocales   `cmd:"locales" help:"List the locales enabled in the help center."`
+	Posts     CommandPosts     `cmd:"posts" help:"Pull and push the community posts."`
+	Events    CommandEvents    `cmd:"events" help:"List the changes of the translations made on the remote."`
+	Bench     CommandBench     `cmd:"bench" help:"Measure the conversion throughput and the API latency."`
+	Validate  CommandValidate  `cmd:"validate" help:"Validate the local files."`
+	Serve     CommandServe     `cmd:"serve" help:"Serve the endpoint receiving Zendesk webhooks."`
+	Cache     CommandCache     `cmd:"cache" help:"Manage the cache of the lookups."`
+	Config    CommandConfig    `cmd:"config" help:"Get or set the configuration."`
+	Version   CommandVersion   `cmd:"version" help:"Show version."`
 }
 
 func (c *cli) AfterApply(kCtx *kong.Context) error {
+	format := c.LogFormat
+	if format == "text" && useColor(os.Stderr, c.NoColor) {
+		format = formatColor
+	}
+	stderr.enabled = isTerminal(os.Stderr) && !c.Quiet
+	slog.SetDefault(newLogger(stderr, c.logLevel(), format, kCtx.Command()))
 	if kCtx.Command() == "version" {
 		return nil
 	}
-	if err := c.Global.ConfigExists(); err != nil {
+	if strings.HasPrefix(kCtx.Command(), "config set") {
+		// the configuration file may not exist or be incomplete yet.
+		return nil
+	}
+	if err := c.Global.ConfigExists(); err != nil && !hasEnvConfig() {
 		return err
 	}
 	if err := c.Global.LoadConfig(); err != nil {
 		return err
 	}
+	zendesk.LineEnding, _ = c.Global.Config.lineEnding()
 	return nil
 }
 
+// logLevel returns the level of the logs, which is overridden by -q and -v.
+func (g *Global) logLevel() string {
+	switch {
+	case g.Quiet:
+		return levelQuiet
+	case g.Verbose >= 2:
+		return "trace"
+	case g.Verbose == 1:
+		return "debug"
+	}
+	return g.LogLevel
+}
+
 func Bind() {
 	c := &cli{}
 	kCtx := kong.Parse(c,
@@ -37,5 +104,5 @@ func Bind() {
 		kong.Bind(&c.Global),
 	)
 	err := kCtx.Run()
-	kCtx.FatalIfErrorf(err)
+	kCtx.FatalIfErrorf(withHint(err))
 }
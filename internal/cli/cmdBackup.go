@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/tukaelu/zgsync/internal/backup"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+type CommandBackup struct {
+	Out       string                    `name:"out" short:"o" help:"Specify the archive file (.zip, .tar.gz or .tgz)." type:"path"`
+	SectionID []int                     `name:"section-id" short:"s" help:"Specify the section IDs to back up separated by commas."`
+	AllBrands bool                      `name:"all-brands" help:"It backs up the help centers of all the brands in the configuration."`
+	client    zendesk.Client            `kong:"-"`
+	clients   map[string]zendesk.Client `kong:"-"`
+}
+
+// AfterApply creates the clients without the cache, so that the backup is not taken from the stale lookups.
+func (c *CommandBackup) AfterApply(g *Global) error {
+	c.client = zendesk.NewClient(g.Config.Subdomain, g.Config.Email, g.Config.Token)
+	c.clients = map[string]zendesk.Client{}
+	return nil
+}
+
+// clientFor returns the client of the help center of the subdomain.
+func (c *CommandBackup) clientFor(g *Global, subdomain string) zendesk.Client {
+	if subdomain == g.Config.Subdomain {
+		return c.client
+	}
+	if client, ok := c.clients[subdomain]; ok {
+		return client
+	}
+	client := zendesk.NewClient(subdomain, g.Config.Email, g.Config.Token)
+	c.clients[subdomain] = client
+	return client
+}
+
+func (c *CommandBackup) Run(g *Global) (err error) {
+	now := time.Now()
+	if c.Out == "" {
+		c.Out = backup.FileName(now)
+	}
+
+	brands := []backup.Brand{{Name: g.Config.Brand, Subdomain: g.Config.Subdomain}}
+	if c.AllBrands && len(g.Config.Brands) > 0 {
+		brands = brands[:0]
+		for name, subdomain := range g.Config.Brands {
+			brands = append(brands, backup.Brand{Name: name, Subdomain: subdomain})
+		}
+		sort.Slice(brands, func(i, j int) bool { return brands[i].Name < brands[j].Name })
+	}
+
+	// the sections in the configuration belong to the help center selected by the configuration.
+	sections := c.SectionID
+	if len(sections) == 0 && !c.AllBrands {
+		seen := map[int]bool{}
+		for _, id := range g.Config.Sections {
+			if !seen[id] {
+				seen[id] = true
+				sections = append(sections, id)
+			}
+		}
+		sort.Ints(sections)
+	}
+
+	w, err := backup.Create(c.Out)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := w.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+		// the partial archive is not left as a backup.
+		if err != nil {
+			os.Remove(c.Out)
+		}
+	}()
+
+	manifest := backup.Manifest{CreatedAt: now.UTC().Format(time.RFC3339)}
+	for _, b := range brands {
+		b.Sections = sections
+		if err := c.backupBrand(g, w, &b); err != nil {
+			return fmt.Errorf("%s: %w", b.Subdomain, err)
+		}
+		manifest.Brands = append(manifest.Brands, b)
+		slog.Info("backed up", "subdomain", b.Subdomain, "articles", b.Articles, "translations", b.Translations)
+	}
+	if err := w.WriteJSON(backup.ManifestName, manifest); err != nil {
+		return err
+	}
+	slog.Info("written", "file", c.Out)
+	return nil
+}
+
+// backupBrand writes the articles, translations, sections and categories of the brand.
+func (c *CommandBackup) backupBrand(g *Global, w *backup.Writer, b *backup.Brand) error {
+	client := c.clientFor(g, b.Subdomain)
+
+	var articles []zendesk.Article
+	if len(b.Sections) == 0 {
+		list, err := listArticles(client.ListArticles)
+		if err != nil {
+			return err
+		}
+		articles = list
+	}
+	for _, sectionID := range b.Sections {
+		list, err := listArticles(func(page int) (string, error) {
+			return client.ListSectionArticles(sectionID, page)
+		})
+		if err != nil {
+			return fmt.Errorf("section %d: %w", sectionID, err)
+		}
+		articles = append(articles, list...)
+	}
+
+	locale := g.Config.remoteLocale(g.Config.DefaultLocale)
+	sections := map[int]bool{}
+	categories := map[int]bool{}
+	for _, a := range articles {
+		if a.SectionID != 0 && !sections[a.SectionID] {
+			sections[a.SectionID] = true
+			res, err := client.ShowSection(locale, a.SectionID)
+			if err != nil {
+				return fmt.Errorf("section %d: %w", a.SectionID, err)
+			}
+			s := &zendesk.Section{}
+			if err := s.FromJson(res); err != nil {
+				return err
+			}
+			if err := w.WriteJSON(backup.SectionPath(b.Subdomain, s.ID), s); err != nil {
+				return err
+			}
+			if s.CategoryID != 0 && !categories[s.CategoryID] {
+				categories[s.CategoryID] = true
+				res, err := client.ShowCategory(locale, s.CategoryID)
+				if err != nil {
+					return fmt.Errorf("category %d: %w", s.CategoryID, err)
+				}
+				cat := &zendesk.Category{}
+				if err := cat.FromJson(res); err != nil {
+					return err
+				}
+				if err := w.WriteJSON(backup.CategoryPath(b.Subdomain, cat.ID), cat); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := w.WriteJSON(backup.ArticlePath(b.Subdomain, a.ID), a); err != nil {
+			return err
+		}
+		b.Articles++
+
+		translations, err := listTranslations(client, a.ID)
+		if err != nil {
+			return fmt.Errorf("article %d: %w", a.ID, err)
+		}
+		for _, t := range translations {
+			if err := w.WriteJSON(backup.TranslationPath(b.Subdomain, a.ID, t.Locale), t); err != nil {
+				return err
+			}
+			b.Translations++
+		}
+	}
+	return nil
+}
+
+// listArticles returns the articles of all the pages of the list.
+func listArticles(list func(page int) (string, error)) ([]zendesk.Article, error) {
+	var articles []zendesk.Article
+	for page := 1; ; page++ {
+		res, err := list(page)
+		if err != nil {
+			return nil, err
+		}
+		a, next, err := zendesk.ArticlesFromJson(res)
+		if err != nil {
+			return nil, err
+		}
+		articles = append(articles, a...)
+		if !next {
+			return articles, nil
+		}
+	}
+}
+
+// listTranslations returns the translations of the article in all the pages of the list.
+func listTranslations(client zendesk.Client, articleID int) ([]zendesk.Translation, error) {
+	var translations []zendesk.Translation
+	for page := 1; ; page++ {
+		res, err := client.ListTranslations(articleID, page)
+		if err != nil {
+			return nil, err
+		}
+		t, next, err := zendesk.TranslationsFromJson(res)
+		if err != nil {
+			return nil, err
+		}
+		translations = append(translations, t...)
+		if !next {
+			return translations, nil
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/tukaelu/zgsync/internal/backup"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+// backupClient serves two pages of articles in section 10 of category 100.
+type backupClient struct {
+	zendesk.Client
+}
+
+func (c *backupClient) ListArticles(page int) (string, error) {
+	if page == 1 {
+		return `{"articles":[{"id":1,"title":"Setup","locale":"ja","section_id":10}],"next_page":"https://example.zendesk.com/api/v2/help_center/articles?page=2"}`, nil
+	}
+	return `{"articles":[{"id":2,"title":"FAQ","locale":"ja","section_id":10}],"next_page":null}`, nil
+}
+
+// ListTranslations serves the ja translation in the first page and the en-us one in the second page.
+func (c *backupClient) ListTranslations(articleID int, page int) (string, error) {
+	if page == 1 {
+		return fmt.Sprintf(`{"translations":[{"id":%d1,"source_id":%d,"locale":"ja","title":"t","body":"<p>b</p>"}],"next_page":"next"}`, articleID, articleID), nil
+	}
+	return fmt.Sprintf(`{"translations":[{"id":%d2,"source_id":%d,"locale":"en-us","title":"t","body":"<p>b</p>"}],"next_page":null}`, articleID, articleID), nil
+}
+
+func (c *backupClient) ShowSection(locale string, sectionID int) (string, error) {
+	return fmt.Sprintf(`{"section":{"id":%d,"name":"Guides","category_id":100}}`, sectionID), nil
+}
+
+func (c *backupClient) ShowCategory(locale string, categoryID int) (string, error) {
+	return fmt.Sprintf(`{"category":{"id":%d,"name":"Docs"}}`, categoryID), nil
+}
+
+func TestBackup(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "backup.tar.gz")
+	g := &Global{Config: Config{Subdomain: "example", DefaultLocale: "ja"}}
+	c := &CommandBackup{Out: out, client: &backupClient{}}
+	if err := c.Run(g); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+
+	a, err := backup.Open(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for name := range a.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	expected := []string{
+		"example/articles/1.json",
+		"example/articles/1/translations/en-us.json",
+		"example/articles/1/translations/ja.json",
+		"example/articles/2.json",
+		"example/articles/2/translations/en-us.json",
+		"example/articles/2/translations/ja.json",
+		"example/categories/100.json",
+		"example/sections/10.json",
+		"manifest.json",
+	}
+	if fmt.Sprint(names) != fmt.Sprint(expected) {
+		t.Errorf("Run() wrote %v, want %v", names, expected)
+	}
+	if b := a.Manifest.Brands; len(b) != 1 || b[0].Articles != 2 || b[0].Translations != 4 {
+		t.Errorf("Run() manifest = %+v", a.Manifest)
+	}
+
+	tr := &zendesk.Translation{}
+	if err := a.ReadJSON("example/articles/2/translations/ja.json", tr); err != nil || tr.Body != "<p>b</p>" || tr.SourceID != 2 {
+		t.Errorf("Run() wrote the translation %+v, %v", tr, err)
+	}
+}
@@ -0,0 +1,229 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/tukaelu/zgsync/internal/converter"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+type CommandBench struct {
+	Requests      int                 `name:"requests" short:"n" help:"Specify the number of the API requests." default:"100"`
+	Concurrency   int                 `name:"concurrency" short:"j" help:"Specify the maximum number of the API requests in flight." default:"8"`
+	Sandbox       bool                `name:"sandbox" help:"It measures the API latency against the help center of the configuration."`
+	ArticleID     int                 `name:"article-id" help:"Specify the ID of the article looked up with --sandbox."`
+	MockLatency   time.Duration       `name:"mock-latency" help:"Specify the latency of the responses of the built-in mock server." default:"50ms"`
+	MockRateLimit int                 `name:"mock-rate-limit" help:"Specify the requests per minute accepted by the mock server." default:"700"`
+	Files         []string            `arg:"" optional:"" help:"Specify the files or directories whose conversion is measured. If not specified, the contents directory is used." type:"path"`
+	converter     converter.Converter `kong:"-"`
+	out           io.Writer           `kong:"-"`
+}
+
+// benchReport is the result of a benchmark.
+type benchReport struct {
+	Files      int
+	Bytes      int
+	Conversion time.Duration
+
+	Target           string
+	Requests         int
+	Errors           int
+	RateLimited      int
+	Concurrency      int
+	FinalConcurrency int
+	Elapsed          time.Duration
+	// Latencies are the latencies of the requests in ascending order.
+	Latencies []time.Duration
+}
+
+func (c *CommandBench) AfterApply(g *Global) error {
+	if c.Sandbox && c.ArticleID == 0 {
+		return fmt.Errorf("--article-id is required with --sandbox")
+	}
+	if c.Requests < 1 {
+		return fmt.Errorf("--requests must be greater than 0")
+	}
+	c.converter = converter.NewConverter()
+	c.out = os.Stdout
+	return nil
+}
+
+func (c *CommandBench) Run(g *Global) error {
+	r := &benchReport{}
+	if err := c.benchConversion(g, r); err != nil {
+		return err
+	}
+
+	// the client is not cached, so that every lookup reaches the API.
+	var client zendesk.Client
+	if c.Sandbox {
+		r.Target = g.Config.Subdomain
+		client = zendesk.NewClient(g.Config.Subdomain, g.Config.Email, g.Config.Token)
+	} else {
+		r.Target = "mock server"
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			return err
+		}
+		srv := &http.Server{Handler: &benchServer{latency: c.MockLatency, limit: c.MockRateLimit}}
+		go func() { _ = srv.Serve(ln) }()
+		defer srv.Close()
+		client = zendesk.NewClientWithBaseURL("http://"+ln.Addr().String(), "bench", "bench")
+	}
+	c.benchAPI(client, g.Config.remoteLocale(g.Config.DefaultLocale), r)
+	return r.write(c.out)
+}
+
+// benchConversion converts the bodies of the Markdown translation files to HTML.
+func (c *CommandBench) benchConversion(g *Global, r *benchReport) error {
+	paths := c.Files
+	if len(paths) == 0 {
+		paths = []string{g.Config.ContentsDir}
+	}
+	files, err := expandFiles(g.Config.ContentsDir, paths, isTranslationFile)
+	if err != nil {
+		return err
+	}
+	var bodies []string
+	for _, file := range files {
+		t, err := g.Config.readTranslation(file)
+		if err != nil {
+			return err
+		}
+		if html, err := t.IsHTML(); err != nil || html {
+			continue
+		}
+		bodies = append(bodies, t.Body)
+	}
+
+	start := time.Now()
+	for _, body := range bodies {
+		if _, err := c.converter.ConvertToHTML(body); err != nil {
+			return err
+		}
+		r.Files++
+		r.Bytes += len(body)
+	}
+	r.Conversion = time.Since(start)
+	return nil
+}
+
+// benchAPI looks up the articles in parallel with the pool used by pull --all.
+func (c *CommandBench) benchAPI(client zendesk.Client, locale string, r *benchReport) {
+	ids := make([]int, c.Requests)
+	for i := range ids {
+		ids[i] = c.ArticleID
+		if !c.Sandbox {
+			ids[i] = i + 1
+		}
+	}
+
+	pool := newRatePool(client, c.Concurrency)
+	var mu sync.Mutex
+	start := time.Now()
+	// the failed requests are counted instead of stopping the pool.
+	_ = pool.run(ids, func(id int) error {
+		t := time.Now()
+		_, err := client.ShowArticle(locale, id)
+		d := time.Since(t)
+		pool.report(err)
+
+		mu.Lock()
+		defer mu.Unlock()
+		r.Latencies = append(r.Latencies, d)
+		if err != nil {
+			r.Errors++
+			if zendesk.IsRateLimited(err) {
+				r.RateLimited++
+			}
+		}
+		return nil
+	})
+	r.Elapsed = time.Since(start)
+	r.Requests = len(ids)
+	r.Concurrency = pool.max
+	r.FinalConcurrency = pool.limit
+	slices.Sort(r.Latencies)
+}
+
+// percentile returns the latency of the percentile by the nearest-rank method.
+func (r *benchReport) percentile(p float64) time.Duration {
+	if len(r.Latencies) == 0 {
+		return 0
+	}
+	i := int(math.Ceil(p/100*float64(len(r.Latencies)))) - 1
+	return r.Latencies[max(0, i)]
+}
+
+func (r *benchReport) write(out io.Writer) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CONVERSION")
+	fmt.Fprintf(w, "files\t%d (%.1f KB)\n", r.Files, float64(r.Bytes)/1024)
+	fmt.Fprintf(w, "elapsed\t%s\n", r.Conversion.Round(time.Microsecond))
+	if sec := r.Conversion.Seconds(); sec > 0 {
+		fmt.Fprintf(w, "throughput\t%.1f files/s, %.2f MB/s\n", float64(r.Files)/sec, float64(r.Bytes)/1024/1024/sec)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "API (%s)\n", r.Target)
+	fmt.Fprintf(w, "requests\t%d (errors %d, rate limited %d)\n", r.Requests, r.Errors, r.RateLimited)
+	fmt.Fprintf(w, "concurrency\t%d at the end (maximum %d)\n", r.FinalConcurrency, r.Concurrency)
+	fmt.Fprintf(w, "elapsed\t%s\n", r.Elapsed.Round(time.Millisecond))
+	if sec := r.Elapsed.Seconds(); sec > 0 {
+		fmt.Fprintf(w, "throughput\t%.1f requests/s\n", float64(r.Requests)/sec)
+	}
+	fmt.Fprintf(w, "latency\tp50 %s, p90 %s, p99 %s, max %s\n",
+		r.percentile(50).Round(time.Millisecond), r.percentile(90).Round(time.Millisecond),
+		r.percentile(99).Round(time.Millisecond), r.percentile(100).Round(time.Millisecond))
+	return w.Flush()
+}
+
+// benchServer is the mock server of the article lookups with the latency and the rate limit of Zendesk.
+type benchServer struct {
+	latency time.Duration
+	limit   int
+
+	mu     sync.Mutex
+	window time.Time
+	count  int
+}
+
+func (s *benchServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	now := time.Now()
+	if now.Sub(s.window) >= time.Minute {
+		s.window, s.count = now, 0
+	}
+	s.count++
+	remaining := s.limit - s.count
+	reset := s.window.Add(time.Minute).Sub(now)
+	s.mu.Unlock()
+
+	time.Sleep(s.latency)
+	if s.limit > 0 {
+		w.Header().Set("X-Rate-Limit", strconv.Itoa(s.limit))
+		w.Header().Set("X-Rate-Limit-Remaining", strconv.Itoa(max(0, remaining)))
+		if remaining < 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(reset.Seconds()))))
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+	}
+	id, err := strconv.Atoi(path.Base(r.URL.Path))
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"article":{"id":%d,"title":"Benchmark","locale":"en-us","body":"<p>Benchmark</p>"}}`, id)
+}
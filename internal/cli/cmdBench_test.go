@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tukaelu/zgsync/internal/converter"
+)
+
+func TestBench(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"1-ja.md": "---\ntitle: t1\nlocale: ja\nsource_id: 1\n---\n## Title\nbody\n",
+		"2-ja.md": "---\ntitle: t2\nlocale: ja\nsource_id: 2\nbody_format: html\n---\n<p>body</p>\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja"}}
+	var out bytes.Buffer
+	c := &CommandBench{Requests: 20, Concurrency: 4, MockLatency: time.Millisecond, MockRateLimit: 10, converter: converter.NewConverter(), out: &out}
+	if err := c.Run(g); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	// the HTML file is not converted, and the requests over the rate limit are rejected.
+	report := strings.Join(strings.Fields(out.String()), " ")
+	for _, expected := range []string{"files 1 (", "requests 20 (errors 10, rate limited 10)", "concurrency 1 at the end (maximum 4)"} {
+		if !strings.Contains(report, expected) {
+			t.Errorf("Run() failed: %q is not reported in\n%s", expected, out.String())
+		}
+	}
+}
+
+func TestBenchReportPercentile(t *testing.T) {
+	r := &benchReport{}
+	for i := 1; i <= 10; i++ {
+		r.Latencies = append(r.Latencies, time.Duration(i)*time.Millisecond)
+	}
+	for p, expected := range map[float64]time.Duration{50: 5 * time.Millisecond, 90: 9 * time.Millisecond, 99: 10 * time.Millisecond, 100: 10 * time.Millisecond} {
+		if got := r.percentile(p); got != expected {
+			t.Errorf("percentile(%v) failed: got %v, want %v", p, got, expected)
+		}
+	}
+}
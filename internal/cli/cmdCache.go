@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"log/slog"
+
+	"github.com/tukaelu/zgsync/internal/cache"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+type CommandCache struct {
+	Clear CommandCacheClear `cmd:"clear" help:"Remove the cached lookups of the contents directory."`
+}
+
+type CommandCacheClear struct{}
+
+func (c *CommandCacheClear) Run(g *Global) error {
+	dir, err := cache.Dir(g.Config.ContentsDir)
+	if err != nil {
+		return err
+	}
+	if err := cache.New(dir, 0).Clear(); err != nil {
+		return err
+	}
+	slog.Info("cleared", "dir", dir)
+	return nil
+}
+
+// newClient returns the client of the subdomain, which caches the lookups when cache_ttl is configured.
+func (g *Global) newClient(subdomain string) zendesk.Client {
+	client := zendesk.NewClient(subdomain, g.Config.Email, g.Config.Token)
+	ttl, err := g.Config.cacheTTL()
+	if err != nil || ttl == 0 {
+		return client
+	}
+	dir, err := cache.Dir(g.Config.ContentsDir)
+	if err != nil {
+		slog.Warn("the cache is disabled", "error", err)
+		return client
+	}
+	return zendesk.NewCachedClient(client, subdomain, cache.New(dir, ttl))
+}
+
+// newUncachedClient returns the client which looks up the articles and the translations without the cache.
+func (g *Global) newUncachedClient(subdomain string) zendesk.Client {
+	return zendesk.Uncached(g.newClient(subdomain))
+}
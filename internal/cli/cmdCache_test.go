@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/tukaelu/zgsync/internal/cache"
+)
+
+func TestCacheClear(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	g := &Global{Config: Config{ContentsDir: t.TempDir(), CacheTTL: "10m"}}
+	dir, err := cache.Dir(g.Config.ContentsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.New(dir, time.Minute).Put("example/articles/1/ja", "{}"); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &CommandCacheClear{}
+	if err := c.Run(g); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("Run() failed: %s should be removed", dir)
+	}
+}
+
+func TestConfigCacheTTL(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"", 0, false},
+		{"10m", 10 * time.Minute, false},
+		{"-1m", 0, true},
+		{"10", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			c := &Config{CacheTTL: tt.value}
+			got, err := c.cacheTTL()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("cacheTTL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("cacheTTL() failed: got %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
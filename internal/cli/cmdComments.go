@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/tukaelu/zgsync/internal/audit"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+type CommandComments struct {
+	Locale  string         `name:"locale" short:"l" help:"Specify the locale of the comments to list. If not specified, the comments of all locales will be listed."`
+	Format  string         `name:"format" short:"o" help:"Specify the output format (table, csv or json)." enum:"table,csv,json" default:"table"`
+	Columns []string       `name:"columns" help:"Specify the columns of the table separated by commas: id, author_id, locale, votes, created_at, body and html_url."`
+	Delete  []int          `name:"delete" help:"Specify the IDs of the comments to delete instead of listing the comments."`
+	Confirm Confirm        `embed:""`
+	Target  string         `arg:"" help:"Specify the file or the article ID."`
+	client  zendesk.Client `kong:"-"`
+}
+
+func (c *CommandComments) AfterApply(g *Global) error {
+	c.client = g.newClient(g.Config.Subdomain)
+	c.Confirm.interactive = isTerminal(os.Stdin)
+	return nil
+}
+
+// newCommentsTable returns the table of the comments, without html_url by default.
+func newCommentsTable() *table {
+	return newTable(
+		[]string{"id", "author_id", "locale", "votes", "created_at", "body", "html_url"},
+		"id", "author_id", "locale", "votes", "created_at", "body",
+	)
+}
+
+func (c *CommandComments) Run(g *Global) error {
+	ref, err := resolveFileRef(c.Target)
+	if err != nil {
+		return err
+	}
+	if len(c.Delete) > 0 {
+		return c.deleteComments(g, ref.ID)
+	}
+	t := newCommentsTable()
+	if err := t.validate(c.Columns); err != nil {
+		return err
+	}
+
+	comments, err := c.listComments(ref.ID, g.Config.remoteLocale(c.Locale))
+	if err != nil {
+		return err
+	}
+
+	switch c.Format {
+	case "json":
+		b, err := json.MarshalIndent(comments, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+		return nil
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		_ = w.Write([]string{"id", "author_id", "locale", "vote_sum", "vote_count", "created_at", "updated_at", "body", "html_url"})
+		for _, cm := range comments {
+			_ = w.Write([]string{fmt.Sprint(cm.ID), fmt.Sprint(cm.AuthorID), cm.Locale, fmt.Sprint(cm.VoteSum), fmt.Sprint(cm.VoteCount), cm.CreatedAt, cm.UpdatedAt, commentText(cm.Body), cm.HtmlURL})
+		}
+		w.Flush()
+		return w.Error()
+	}
+
+	for _, cm := range comments {
+		t.add(fmt.Sprint(cm.ID), fmt.Sprint(cm.AuthorID), cm.Locale, fmt.Sprintf("%d/%d", cm.VoteSum, cm.VoteCount), cm.CreatedAt, commentText(cm.Body), cm.HtmlURL)
+	}
+	return t.print(c.Columns)
+}
+
+// listComments returns the comments of the article, only of the locale unless it is empty.
+func (c *CommandComments) listComments(articleID int, locale string) ([]zendesk.Comment, error) {
+	comments := []zendesk.Comment{}
+	for page := 1; ; page++ {
+		res, err := c.client.ListArticleComments(articleID, page)
+		if err != nil {
+			return nil, err
+		}
+		list, hasNext, err := zendesk.CommentsFromJson(res)
+		if err != nil {
+			return nil, err
+		}
+		for _, cm := range list {
+			if locale == "" || strings.EqualFold(cm.Locale, locale) {
+				comments = append(comments, cm)
+			}
+		}
+		if !hasNext {
+			return comments, nil
+		}
+	}
+}
+
+// deleteComments deletes the comments of --delete from the article after the confirmation.
+func (c *CommandComments) deleteComments(g *Global, articleID int) error {
+	var affected []string
+	for _, id := range c.Delete {
+		affected = append(affected, fmt.Sprintf("comment %d of article %d", id, articleID))
+	}
+	ok, err := c.Confirm.confirm("Delete", affected)
+	if err != nil || !ok {
+		if err == nil {
+			slog.Info("cancelled")
+		}
+		return err
+	}
+	for _, id := range c.Delete {
+		r := audit.Record{Command: "comments", ArticleID: articleID, CommentID: id, Result: audit.ResultDeleted}
+		if err := c.client.DeleteArticleComment(articleID, id); err != nil {
+			r.Result = audit.ResultFailed
+			r.Error = err.Error()
+			g.audit(r)
+			return fmt.Errorf("comment %d: %w", id, err)
+		}
+		g.audit(r)
+		slog.Info("deleted", "article_id", articleID, "comment_id", id)
+	}
+	return nil
+}
+
+// commentText returns the text of the HTML body of the comment, whose whitespace is collapsed.
+func commentText(body string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
+	if err != nil {
+		return body
+	}
+	return strings.Join(strings.Fields(doc.Text()), " ")
+}
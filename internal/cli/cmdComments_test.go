@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tukaelu/zgsync/internal/audit"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+// commentsClient has the comments of the article on two pages.
+type commentsClient struct {
+	zendesk.Client
+	deleted []string
+}
+
+func (c *commentsClient) ListArticleComments(articleID int, page int) (string, error) {
+	if page == 1 {
+		return `{"comments":[{"id":1,"author_id":7,"locale":"ja","body":"<p>古い  手順です</p>"}],"next_page":"https://example.zendesk.com/api/v2/help_center/articles/1/comments?page=2"}`, nil
+	}
+	return `{"comments":[{"id":2,"author_id":8,"locale":"en-us","body":"<p>Typo in <b>step 2</b></p>"}],"next_page":null}`, nil
+}
+
+func (c *commentsClient) DeleteArticleComment(articleID int, commentID int) error {
+	c.deleted = append(c.deleted, fmt.Sprintf("%d/%d", articleID, commentID))
+	return nil
+}
+
+func TestListComments(t *testing.T) {
+	tests := []struct {
+		locale   string
+		expected string
+	}{
+		{"", "[1:古い 手順です 2:Typo in step 2]"},
+		{"en-US", "[2:Typo in step 2]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			c := &CommandComments{client: &commentsClient{}}
+			comments, err := c.listComments(1, tt.locale)
+			if err != nil {
+				t.Fatalf("listComments() failed: %v", err)
+			}
+			var got []string
+			for _, cm := range comments {
+				got = append(got, fmt.Sprintf("%d:%s", cm.ID, commentText(cm.Body)))
+			}
+			if fmt.Sprint(got) != tt.expected {
+				t.Errorf("listComments() failed: got %v, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDeleteComments(t *testing.T) {
+	auditLog := filepath.Join(t.TempDir(), "audit.log")
+	client := &commentsClient{}
+	c := &CommandComments{Delete: []int{1, 2}, Confirm: Confirm{Yes: true}, Target: "1", client: client}
+	if err := c.Run(&Global{Config: Config{AuditLog: AuditLog{Path: auditLog}}}); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if fmt.Sprint(client.deleted) != "[1/1 1/2]" {
+		t.Errorf("Run() failed: got %v deleted", client.deleted)
+	}
+	b, err := os.ReadFile(auditLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var audited []string
+	for _, line := range strings.Split(strings.TrimSpace(string(b)), "\n") {
+		var r audit.Record
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatal(err)
+		}
+		audited = append(audited, fmt.Sprintf("%s %d/%d %s", r.Command, r.ArticleID, r.CommentID, r.Result))
+	}
+	if fmt.Sprint(audited) != "[comments 1/1 deleted comments 1/2 deleted]" {
+		t.Errorf("Run() failed: got %v audited", audited)
+	}
+
+	// the comments are not deleted without the confirmation when the input is not a terminal.
+	client = &commentsClient{}
+	c = &CommandComments{Delete: []int{1}, Target: "1", client: client}
+	if err := c.Run(&Global{}); err == nil || len(client.deleted) > 0 {
+		t.Errorf("Run() failed: got %v deleted with the error %v", client.deleted, err)
+	}
+}
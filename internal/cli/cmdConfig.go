@@ -0,0 +1,265 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "********"
+
+// secretKeys are the config keys that are redacted by `config show` and `config get`.
+var secretKeys = map[string]bool{
+	"token": true,
+	// the webhook URLs contain the credentials of the endpoints.
+	"notifications":       true,
+	"machine_translation": true,
+}
+
+type CommandConfig struct {
+	Get  CommandConfigGet  `cmd:"get" help:"Print the effective value of the config key with the secrets redacted."`
+	Set  CommandConfigSet  `cmd:"set" help:"Write the config key to the configuration file."`
+	Show CommandConfigShow `cmd:"show" help:"Print the effective configuration with the secrets redacted."`
+}
+
+type CommandConfigGet struct {
+	Key         string `arg:"" help:"Specify the config key."`
+	ShowSecrets bool   `name:"show-secrets" help:"It prints the value of the secret key as it is instead of redacting it."`
+}
+
+func (c *CommandConfigGet) Run(g *Global) error {
+	val, err := getConfigValue(&g.Config, c.Key, c.ShowSecrets)
+	if err != nil {
+		return err
+	}
+	fmt.Println(val)
+	return nil
+}
+
+// getConfigValue returns the formatted value of the config key, which is redacted for the secrets unless showSecrets.
+func getConfigValue(c *Config, key string, showSecrets bool) (string, error) {
+	f, err := configField(c, key)
+	if err != nil {
+		return "", err
+	}
+	if secretKeys[key] && !showSecrets && !f.IsZero() {
+		return redacted, nil
+	}
+	return formatConfigValue(f), nil
+}
+
+type CommandConfigSet struct {
+	Key   string `arg:"" help:"Specify the config key."`
+	Value string `arg:"" help:"Specify the value. An empty value removes the key."`
+}
+
+func (c *CommandConfigSet) Run(g *Global) error {
+	b, err := os.ReadFile(g.ConfigPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if isEncrypted(b) {
+		return fmt.Errorf("%s is encrypted, edit it with sops or age instead", g.ConfigPath)
+	}
+
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal(b, doc); err != nil {
+		return err
+	}
+	if err := setConfigKey(doc, g.Env, c.Key, c.Value); err != nil {
+		return err
+	}
+	if err := validateConfigDocument(doc, g.Env); err != nil {
+		return err
+	}
+
+	b, err = marshalConfig(doc)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(g.ConfigPath), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(g.ConfigPath, b, 0o600)
+}
+
+type CommandConfigShow struct{}
+
+func (c *CommandConfigShow) Run(g *Global) error {
+	b, err := showConfig(&g.Config)
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(b))
+	return nil
+}
+
+// configField returns the field of the config key. The environments are not accessible as a key.
+func configField(c *Config, key string) (reflect.Value, error) {
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		k := yamlKey(t.Field(i))
+		if k == "" || k == "environments" || k != key {
+			continue
+		}
+		return v.Field(i), nil
+	}
+	return reflect.Value{}, fmt.Errorf("unknown config key %s", key)
+}
+
+func formatConfigValue(f reflect.Value) string {
+	if f.Kind() == reflect.Pointer {
+		if f.IsNil() {
+			return ""
+		}
+		f = f.Elem()
+	}
+	if n, ok := f.Interface().(yaml.Node); ok {
+		b, _ := yaml.Marshal(&n)
+		return strings.TrimSuffix(string(b), "\n")
+	}
+	return fmt.Sprint(f.Interface())
+}
+
+// validateConfigValue parses the value as the type of the config key and returns the typed value.
+func validateConfigValue(key, val string) (interface{}, error) {
+	f, err := configField(&Config{}, key)
+	if err != nil {
+		return nil, err
+	}
+	if err := setFieldFromString(f, val); err != nil {
+		return nil, fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+	switch key {
+	case "hierarchy_layout", "filename_template", "article_filename_template":
+		if _, err := parseLayout(key, val); err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+	}
+	return f.Interface(), nil
+}
+
+// setConfigKey sets the key in the YAML document, or in the named environment. An empty value removes the key.
+func setConfigKey(doc *yaml.Node, env, key, val string) error {
+	var typed interface{}
+	if val != "" {
+		var err error
+		if typed, err = validateConfigValue(key, val); err != nil {
+			return err
+		}
+	} else if _, err := configField(&Config{}, key); err != nil {
+		return err
+	}
+
+	if doc.Kind == 0 {
+		doc.Kind = yaml.DocumentNode
+	}
+	if len(doc.Content) == 0 {
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+	m := doc.Content[0]
+	if m.Kind != yaml.MappingNode {
+		return fmt.Errorf("the configuration file is not a mapping")
+	}
+	if env != "" {
+		m = mappingChild(mappingChild(m, "environments"), env)
+	}
+
+	if val == "" {
+		removeMappingKey(m, key)
+		return nil
+	}
+	n := &yaml.Node{}
+	if err := n.Encode(typed); err != nil {
+		return err
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			n.HeadComment = m.Content[i+1].HeadComment
+			n.LineComment = m.Content[i+1].LineComment
+			m.Content[i+1] = n
+			return nil
+		}
+	}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, n)
+	return nil
+}
+
+// validateConfigDocument checks the values of the config in the YAML document, except the required keys.
+func validateConfigDocument(doc *yaml.Node, env string) error {
+	var c Config
+	if err := doc.Decode(&c); err != nil {
+		return err
+	}
+	if err := c.applyEnvironment(env); err != nil {
+		return err
+	}
+	return c.validateValues()
+}
+
+func marshalConfig(doc *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	ye := yaml.NewEncoder(&buf)
+	ye.SetIndent(2)
+	if err := ye.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := ye.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// mappingChild returns the mapping under the key, creating it if it does not exist.
+func mappingChild(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key && m.Content[i+1].Kind == yaml.MappingNode {
+			child := m.Content[i+1]
+			child.Style = 0
+			return child
+		}
+	}
+	removeMappingKey(m, key)
+	child := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, child)
+	return child
+}
+
+func removeMappingKey(m *yaml.Node, key string) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content = append(m.Content[:i], m.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// showConfig renders the config keys as YAML in the order of the fields with the secrets redacted.
+func showConfig(c *Config) ([]byte, error) {
+	m := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		key := yamlKey(t.Field(i))
+		if key == "" || key == "environments" {
+			continue
+		}
+		var val interface{} = v.Field(i).Interface()
+		if secretKeys[key] && !v.Field(i).IsZero() {
+			val = redacted
+		}
+		n := &yaml.Node{}
+		if err := n.Encode(val); err != nil {
+			return nil, err
+		}
+		m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, n)
+	}
+	return yaml.Marshal(m)
+}
@@ -0,0 +1,218 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSetConfigKey(t *testing.T) {
+	src := "# zgsync\nsubdomain: example # comment\ndefault_locale: ja\n"
+	tests := []struct {
+		name     string
+		env      string
+		key      string
+		val      string
+		want     string
+		notError bool
+	}{
+		{
+			"replace",
+			"",
+			"default_locale",
+			"en-us",
+			"# zgsync\nsubdomain: example # comment\ndefault_locale: en-us\n",
+			true,
+		},
+		{
+			"append",
+			"",
+			"default_permission_group_id",
+			"123",
+			"# zgsync\nsubdomain: example # comment\ndefault_locale: ja\ndefault_permission_group_id: 123\n",
+			true,
+		},
+		{
+			"keep comment",
+			"",
+			"subdomain",
+			"foo",
+			"# zgsync\nsubdomain: foo # comment\ndefault_locale: ja\n",
+			true,
+		},
+		{
+			"remove",
+			"",
+			"default_locale",
+			"",
+			"# zgsync\nsubdomain: example # comment\n",
+			true,
+		},
+		{
+			"environment",
+			"sandbox",
+			"notify_subscribers",
+			"true",
+			"# zgsync\nsubdomain: example # comment\ndefault_locale: ja\nenvironments:\n  sandbox:\n    notify_subscribers: true\n",
+			true,
+		},
+		{"unknown key", "", "default_local", "ja", "", false},
+		{"environments", "", "environments", "x", "", false},
+		{"invalid int", "", "default_permission_group_id", "abc", "", false},
+		{"invalid bool", "", "notify_subscribers", "yes!", "", false},
+		{"invalid template", "", "filename_template", "{{.ArticleID", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &yaml.Node{}
+			if err := yaml.Unmarshal([]byte(src), doc); err != nil {
+				t.Fatal(err)
+			}
+			err := setConfigKey(doc, tt.env, tt.key, tt.val)
+			if tt.notError == (err != nil) {
+				t.Fatalf("setConfigKey() failed: %v", err)
+			}
+			if err != nil {
+				return
+			}
+			b, err := marshalConfig(doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.want {
+				t.Errorf("setConfigKey() failed: got %q, want %q", string(b), tt.want)
+			}
+		})
+	}
+}
+
+func TestSetConfigKeyEmptyDocument(t *testing.T) {
+	doc := &yaml.Node{}
+	if err := setConfigKey(doc, "", "subdomain", "example"); err != nil {
+		t.Fatalf("setConfigKey() failed: %v", err)
+	}
+	b, err := marshalConfig(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "subdomain: example\n" {
+		t.Errorf("setConfigKey() failed: got %q", string(b))
+	}
+}
+
+func TestConfigSetValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      string
+		key      string
+		val      string
+		notError bool
+	}{
+		{"line endings", "", "line_endings", "crlf", true},
+		{"invalid line endings", "", "line_endings", "foo", false},
+		{"invalid locale", "", "default_locale", "ja jp", false},
+		{"invalid URL", "", "notifications", "{webhook_url: example.com/zgsync}", false},
+		{"invalid in environment", "sandbox", "line_endings", "foo", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			src := "subdomain: example\n"
+			if err := os.WriteFile(path, []byte(src), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			g := &Global{ConfigPath: path, Env: tt.env}
+			err := (&CommandConfigSet{Key: tt.key, Value: tt.val}).Run(g)
+			if tt.notError == (err != nil) {
+				t.Fatalf("Run() failed: %v", err)
+			}
+			b, _ := os.ReadFile(path)
+			if !tt.notError && string(b) != src {
+				t.Errorf("Run() failed: the invalid config is written: %q", string(b))
+			}
+		})
+	}
+}
+
+func TestShowConfig(t *testing.T) {
+	var g Global
+	g.ConfigPath = "testdata/config.yaml"
+	if err := g.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+	b, err := showConfig(&g.Config)
+	if err != nil {
+		t.Fatalf("showConfig() failed: %v", err)
+	}
+	out := string(b)
+	if strings.Contains(out, "foobarfoobar") {
+		t.Errorf("showConfig() failed: the token is not redacted: %s", out)
+	}
+	if !strings.Contains(out, "subdomain: example\n") {
+		t.Errorf("showConfig() failed: got %s", out)
+	}
+	if strings.Contains(out, "environments") {
+		t.Errorf("showConfig() failed: environments should not be shown: %s", out)
+	}
+}
+
+func TestConfigField(t *testing.T) {
+	var g Global
+	g.ConfigPath = "testdata/config.yaml"
+	if err := g.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"subdomain", "example"},
+		{"default_permission_group_id", "123"},
+		{"default_user_segment_id", "456"},
+		{"default_comments_disabled", "true"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			f, err := configField(&g.Config, tt.key)
+			if err != nil {
+				t.Fatalf("configField() failed: %v", err)
+			}
+			if got := formatConfigValue(f); got != tt.want {
+				t.Errorf("configField() failed: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetConfigValue(t *testing.T) {
+	var g Global
+	g.ConfigPath = "testdata/config.yaml"
+	if err := g.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+	tests := []struct {
+		key         string
+		showSecrets bool
+		want        string
+	}{
+		{"subdomain", false, "example"},
+		{"token", false, redacted},
+		{"token", true, "foobarfoobar"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, err := getConfigValue(&g.Config, tt.key, tt.showSecrets)
+			if err != nil {
+				t.Fatalf("getConfigValue() failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("getConfigValue() failed: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
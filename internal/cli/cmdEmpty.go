@@ -2,55 +2,98 @@ package cli
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"strconv"
 
+	"github.com/tukaelu/zgsync/internal/state"
 	"github.com/tukaelu/zgsync/internal/zendesk"
 )
 
 type CommandEmpty struct {
-	SectionID         int            `name:"section-id" short:"s" help:"Specify the section ID of the article." required:""`
+	SectionID         int            `name:"section-id" short:"s" help:"Specify the section ID of the article. If not specified, it is picked on the terminal."`
 	Title             string         `name:"title" short:"t" help:"Specify the title of the article." required:""`
 	Locale            string         `name:"locale" short:"l" help:"Specify the locale to pull. If not specified, the default locale will be used."`
 	PermissionGroupID int            `name:"permission-group-id" short:"p" help:"Specify the permission group ID. If not specified, the default value will be used."`
-	UserSegmentID     *int           `name:"user-segment-id" short:"u" help:"Specify the user segment ID. If not specified, the default value will be used."`
+	UserSegmentID     *int           `name:"user-segment-id" short:"u" help:"Specify the user segment ID. If not specified, the default value will be used." xor:"segment"`
+	UserSegmentIDs    []int          `name:"user-segment-ids" help:"Specify the user segment IDs of the article separated by commas, all of which can view the article." xor:"segment"`
+	Labels            []string       `name:"labels" help:"Specify the labels of the article separated by commas."`
 	SaveArticle       bool           `name:"save-article" help:"It saves the article in addition to the translation."`
 	WithSectionDir    bool           `name:"with-section-dir" short:"S" help:"A .md file will be created in the section ID directory."`
+	Locales           []string       `name:"locales" short:"L" help:"Specify the locales to create placeholder translations for, or 'all' for every enabled locale."`
+	Retry             Retry          `embed:""`
 	client            zendesk.Client `kong:"-"`
+	saveDir           string         `kong:"-"`
+	interactive       bool           `kong:"-"`
 }
 
 func (c *CommandEmpty) AfterApply(g *Global) error {
-	c.client = zendesk.NewClient(g.Config.Subdomain, g.Config.Email, g.Config.Token)
+	c.client = g.newClient(g.Config.Subdomain)
+	c.interactive = isTerminal(os.Stdin)
 	return nil
 }
 
-func (c *CommandEmpty) Run(g *Global) error {
+func (c *CommandEmpty) Run(g *Global) (err error) {
 	if c.Locale == "" {
 		c.Locale = g.Config.DefaultLocale
 	}
-	if c.PermissionGroupID == 0 {
-		c.PermissionGroupID = g.Config.DefaultPermissionGroupID
+	if c.SectionID == 0 {
+		if !c.interactive {
+			return fmt.Errorf("--section-id is required when the input is not a terminal")
+		}
+		if c.SectionID, err = pickSection(c.client, c.Locale, false); err != nil {
+			return err
+		}
+	}
+	articleTmpl, translationTmpl, err := g.Config.frontMatterTemplates()
+	if err != nil {
+		return err
+	}
+
+	names, err := newFileNamer(g.Config.FilenameTemplate, g.Config.ArticleFilenameTemplate, false)
+	if err != nil {
+		return err
 	}
-	if c.UserSegmentID == nil {
-		c.UserSegmentID = g.Config.DefailtUserSegmentID
+
+	locales, err := c.stubLocales()
+	if err != nil {
+		return err
 	}
 
+	commentsDisabled := g.Config.DefaultCommentsDisabled
 	a := &zendesk.Article{
 		Draft:             true,
-		CommentsDisabled:  g.Config.DefaultCommentsDisabled,
+		CommentsDisabled:  &commentsDisabled,
 		Locale:            c.Locale,
 		PermissionGroupID: c.PermissionGroupID,
 		SectionID:         c.SectionID,
 		Title:             c.Title,
 		UserSegmentID:     c.UserSegmentID,
+		UserSegmentIDs:    c.UserSegmentIDs,
+		LabelNames:        c.Labels,
 		Body:              "",
 	}
+	// the template takes precedence over the defaults in the configuration.
+	if err := articleTmpl.ApplyDefaults(a); err != nil {
+		return err
+	}
+	if a.PermissionGroupID == 0 {
+		a.PermissionGroupID = g.Config.DefaultPermissionGroupID
+	}
+	if a.UserSegmentID == nil && len(a.UserSegmentIDs) == 0 {
+		a.UserSegmentID = g.Config.DefailtUserSegmentID
+	}
+
 	payload, err := a.ToPayload(g.Config.NotifySubscribers)
 	if err != nil {
 		return err
 	}
 
-	res, err := c.client.CreateArticle(c.Locale, c.SectionID, payload)
+	var res string
+	err = c.Retry.do(func() (err error) {
+		res, err = c.client.CreateArticle(c.Locale, c.SectionID, payload)
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -58,31 +101,146 @@ func (c *CommandEmpty) Run(g *Global) error {
 	if err = a.FromJson(res); err != nil {
 		return err
 	}
+	a.Brand = g.Config.Brand
+
+	s, err := g.LoadState()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if serr := s.Save(); serr != nil && err == nil {
+			err = serr
+		}
+	}()
 
+	data := newLayoutData(a, c.Locale)
 	saveDirPath := g.Config.ContentsDir
+	if c.saveDir != "" {
+		saveDirPath = c.saveDir
+	}
 	if c.WithSectionDir {
 		saveDirPath = filepath.Join(g.Config.ContentsDir, strconv.Itoa(a.SectionID))
 	}
 
 	if c.SaveArticle {
-		if err = a.Save(saveDirPath, true); err != nil {
+		name, err := names.Article(data)
+		if err != nil {
+			return err
+		}
+		path, err := layoutPath(saveDirPath, name)
+		if err != nil {
+			return err
+		}
+		if err = a.SaveWithTemplate(path, false, articleTmpl); err != nil {
 			return fmt.Errorf("failed to save the article: %w", err)
 		}
+		err = trackPulled(s, path, state.Entry{
+			Kind:            state.KindArticle,
+			Brand:           a.Brand,
+			ArticleID:       a.ID,
+			SectionID:       a.SectionID,
+			RemoteUpdatedAt: a.UpdatedAt,
+		})
+		if err != nil {
+			return err
+		}
 	}
 
-	res, err = c.client.ShowTranslation(a.ID, c.Locale)
+	err = c.Retry.do(func() (err error) {
+		res, err = c.client.ShowTranslation(a.ID, c.Locale)
+		return err
+	})
 	if err != nil {
 		return err
 	}
+	if err = c.saveTranslation(s, names, translationTmpl, saveDirPath, a, res); err != nil {
+		return err
+	}
+
+	for _, locale := range locales {
+		stub := &zendesk.Translation{
+			Title:  c.Title,
+			Locale: locale,
+			Draft:  true,
+		}
+		if err := translationTmpl.ApplyDefaults(stub); err != nil {
+			return err
+		}
+		payload, err := stub.ToPayload()
+		if err != nil {
+			return err
+		}
+		var res string
+		err = c.Retry.do(func() (err error) {
+			res, err = c.client.CreateTranslation(a.ID, payload)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if err = c.saveTranslation(s, names, translationTmpl, saveDirPath, a, res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
+// stubLocales returns the locales to create placeholder translations for, excluding the source locale.
+func (c *CommandEmpty) stubLocales() ([]string, error) {
+	locales := c.Locales
+	for _, l := range c.Locales {
+		if l != "all" {
+			continue
+		}
+		res, err := c.client.ListLocales()
+		if err != nil {
+			return nil, err
+		}
+		enabled := &zendesk.Locales{}
+		if err := enabled.FromJson(res); err != nil {
+			return nil, err
+		}
+		locales = enabled.Locales
+		break
+	}
+
+	var stubs []string
+	seen := map[string]bool{c.Locale: true}
+	for _, l := range locales {
+		if seen[l] {
+			continue
+		}
+		seen[l] = true
+		stubs = append(stubs, l)
+	}
+	return stubs, nil
+}
+
+func (c *CommandEmpty) saveTranslation(s *state.Store, names *fileNamer, tmpl *zendesk.FrontMatterTemplate, saveDirPath string, a *zendesk.Article, res string) error {
 	t := &zendesk.Translation{}
-	if err = t.FromJson(res); err != nil {
+	if err := t.FromJson(res); err != nil {
 		return err
 	}
 	t.SectionID = a.SectionID
+	t.Brand = a.Brand
 
-	if err = t.Save(saveDirPath, true); err != nil {
+	name, err := names.Translation(newLayoutData(a, t.Locale))
+	if err != nil {
+		return err
+	}
+	path, err := layoutPath(saveDirPath, name)
+	if err != nil {
+		return err
+	}
+	if err = t.SaveWithTemplate(path, false, tmpl); err != nil {
 		return fmt.Errorf("failed to save the translation: %w", err)
 	}
-	return nil
+	return trackPulled(s, path, state.Entry{
+		Kind:            state.KindTranslation,
+		Brand:           a.Brand,
+		ArticleID:       a.ID,
+		Locale:          t.Locale,
+		SectionID:       t.SectionID,
+		RemoteUpdatedAt: t.UpdatedAt,
+	})
 }
@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/tukaelu/zgsync/internal/state"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+const (
+	// eventSynced is the change known to the sync state, such as by a push or a pull.
+	eventSynced = "synced"
+	// eventChanged is the change of a tracked file made remotely after its last sync.
+	eventChanged = "changed"
+	// eventUntracked is the change of a translation which has no local file.
+	eventUntracked = "untracked"
+)
+
+type CommandEvents struct {
+	Since   string         `name:"since" required:"" help:"It lists the changes after the time, such as 2024-07-01, 24h or 'last'."`
+	Locale  string         `name:"locale" short:"l" help:"Specify the locale of the translations. If not specified, the changes of all locales will be listed."`
+	Format  string         `name:"format" short:"o" help:"Specify the output format (table, csv or json)." enum:"table,csv,json" default:"table"`
+	Columns []string       `name:"columns" help:"Specify the columns of the table separated by commas: updated_at, action, article_id, locale, updated_by, status, file and title."`
+	client  zendesk.Client `kong:"-"`
+}
+
+// changeEvent is a change of a translation on the remote.
+type changeEvent struct {
+	UpdatedAt string `json:"updated_at"`
+	// Action is created for a new translation, and updated otherwise.
+	Action    string `json:"action"`
+	ArticleID int    `json:"article_id"`
+	Locale    string `json:"locale"`
+	Title     string `json:"title"`
+	UpdatedBy string `json:"updated_by"`
+	Status    string `json:"status"`
+	File      string `json:"file,omitempty"`
+}
+
+func (c *CommandEvents) AfterApply(g *Global) error {
+	c.client = g.newClient(g.Config.Subdomain)
+	return nil
+}
+
+// newEventsTable returns the table of the events, without title by default.
+func newEventsTable() *table {
+	return newTable(
+		[]string{"updated_at", "action", "article_id", "locale", "updated_by", "status", "file", "title"},
+		"updated_at", "action", "article_id", "locale", "updated_by", "status", "file",
+	)
+}
+
+func (c *CommandEvents) Run(g *Global) (err error) {
+	t := newEventsTable()
+	if err := t.validate(c.Columns); err != nil {
+		return err
+	}
+	s, err := g.LoadState()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if serr := s.Save(); serr != nil && err == nil {
+			err = serr
+		}
+	}()
+
+	cursor := "events"
+	now := time.Now()
+	since, err := parseSince(c.Since, s.Cursors[cursor], now)
+	if err != nil {
+		return err
+	}
+	events, err := changeEvents(g, s, c.client, since, c.Locale)
+	if err != nil {
+		return err
+	}
+	// the changes made while listing are listed again by the next run with --since last.
+	s.Cursors[cursor] = now.UTC().Format(time.RFC3339)
+	if len(events) == 0 {
+		slog.Info("no translations are changed", "since", since.Format(time.RFC3339))
+		return nil
+	}
+
+	switch c.Format {
+	case "json":
+		b, err := json.MarshalIndent(events, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+		return nil
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		_ = w.Write([]string{"updated_at", "action", "article_id", "locale", "updated_by", "status", "file", "title"})
+		for _, e := range events {
+			_ = w.Write([]string{e.UpdatedAt, e.Action, fmt.Sprint(e.ArticleID), e.Locale, e.UpdatedBy, e.Status, e.File, e.Title})
+		}
+		w.Flush()
+		return w.Error()
+	}
+
+	for _, e := range events {
+		t.add(e.UpdatedAt, e.Action, fmt.Sprint(e.ArticleID), e.Locale, e.UpdatedBy, e.Status, e.File, e.Title)
+	}
+	return t.print(c.Columns)
+}
+
+// changeEvents returns the changes of the translations after since, found with the incremental export.
+func changeEvents(g *Global, s *state.Store, client zendesk.Client, since time.Time, locale string) ([]changeEvent, error) {
+	articles, err := articlesSince(since, client.ListArticlesSince)
+	if err != nil {
+		return nil, err
+	}
+	remoteLocale := g.Config.remoteLocale(locale)
+	users := newUserNames(client)
+	events := []changeEvent{}
+	for _, a := range articles {
+		translations, err := listTranslations(client, a.ID)
+		if err != nil {
+			return nil, fmt.Errorf("article %d: %w", a.ID, err)
+		}
+		for _, t := range translations {
+			if locale != "" && t.Locale != remoteLocale || !updatedSince(t.UpdatedAt, since) {
+				continue
+			}
+			e := changeEvent{
+				UpdatedAt: t.UpdatedAt,
+				Action:    "updated",
+				ArticleID: a.ID,
+				Locale:    g.Config.localLocale(t.Locale),
+				Title:     t.Title,
+				UpdatedBy: users.name(t.UpdatedById),
+				Status:    eventUntracked,
+			}
+			if t.CreatedAt == t.UpdatedAt {
+				e.Action = "created"
+			}
+			if key, entry, ok := s.Find(state.KindTranslation, g.Config.Brand, a.ID, e.Locale); ok {
+				e.File = key
+				e.Status = eventChanged
+				if entry.RemoteUpdatedAt == t.UpdatedAt {
+					e.Status = eventSynced
+				}
+			}
+			events = append(events, e)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].UpdatedAt < events[j].UpdatedAt
+	})
+	return events, nil
+}
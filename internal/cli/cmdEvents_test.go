@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/tukaelu/zgsync/internal/state"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+// eventsClient has the articles 1 and 2 updated after 2024-01-01, whose translations were changed by the user 7.
+type eventsClient struct {
+	zendesk.Client
+}
+
+func (c *eventsClient) ListArticlesSince(startTime int64) (string, error) {
+	return `{"articles":[{"id":1,"updated_at":"2024-01-03T00:00:00Z"},{"id":2,"updated_at":"2024-01-02T00:00:00Z"}],"end_time":1704240000,"next_page":null}`, nil
+}
+
+func (c *eventsClient) ListTranslations(articleID int, page int) (string, error) {
+	if articleID == 1 {
+		return `{"translations":[
+			{"locale":"ja","title":"A","created_at":"2023-01-01T00:00:00Z","updated_at":"2024-01-03T00:00:00Z","updated_by_id":7},
+			{"locale":"en-us","title":"A","created_at":"2023-01-01T00:00:00Z","updated_at":"2023-06-01T00:00:00Z","updated_by_id":7}
+		]}`, nil
+	}
+	return `{"translations":[
+		{"locale":"ja","title":"B","created_at":"2023-01-01T00:00:00Z","updated_at":"2024-01-02T00:00:00Z","updated_by_id":7},
+		{"locale":"en-us","title":"B","created_at":"2024-01-02T12:00:00Z","updated_at":"2024-01-02T12:00:00Z","updated_by_id":8}
+	]}`, nil
+}
+
+func (c *eventsClient) ShowUser(userID int) (string, error) {
+	if userID != 7 {
+		return "", &zendesk.StatusError{StatusCode: 403}
+	}
+	return `{"user":{"id":7,"name":"Jane Doe"}}`, nil
+}
+
+func TestChangeEvents(t *testing.T) {
+	dir := t.TempDir()
+	g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja", LocaleAliases: map[string]string{"en": "en-us"}}}
+	s, err := state.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	*s.Get(dir + "/1-ja.md") = state.Entry{Kind: state.KindTranslation, ArticleID: 1, Locale: "ja", RemoteUpdatedAt: "2024-01-01T00:00:00Z"}
+	*s.Get(dir + "/2-ja.md") = state.Entry{Kind: state.KindTranslation, ArticleID: 2, Locale: "ja", RemoteUpdatedAt: "2024-01-02T00:00:00Z"}
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		locale   string
+		expected string
+	}{
+		{"", "[{2024-01-02T00:00:00Z updated 2 ja B Jane Doe synced 2-ja.md} {2024-01-02T12:00:00Z created 2 en B 8 untracked } {2024-01-03T00:00:00Z updated 1 ja A Jane Doe changed 1-ja.md}]"},
+		{"en", "[{2024-01-02T12:00:00Z created 2 en B 8 untracked }]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			events, err := changeEvents(g, s, &eventsClient{}, since, tt.locale)
+			if err != nil {
+				t.Fatalf("changeEvents() failed: %v", err)
+			}
+			if got := fmt.Sprint(events); got != tt.expected {
+				t.Errorf("changeEvents() failed:\ngot  %s\nwant %s", got, tt.expected)
+			}
+		})
+	}
+}
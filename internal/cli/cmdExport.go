@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"fmt"
+	"html/template"
+	"log/slog"
+
+	"github.com/tukaelu/zgsync/internal/converter"
+	"github.com/tukaelu/zgsync/internal/export"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+type CommandExport struct {
+	Out          string              `name:"out" short:"o" help:"Specify the output directory." default:"site" type:"path"`
+	Title        string              `name:"title" help:"Specify the title of the site." default:"Help Center"`
+	Locale       string              `name:"locale" short:"l" help:"Specify the locale to export. If not specified, all locales will be exported."`
+	SectionNames bool                `name:"section-names" help:"It retrieves the section names from the remote for the navigation."`
+	Raw          bool                `name:"raw" help:"It exports the body as is without converting it from Markdown to HTML."`
+	client       zendesk.Client      `kong:"-"`
+	converter    converter.Converter `kong:"-"`
+}
+
+func (c *CommandExport) AfterApply(g *Global) error {
+	c.client = g.newClient(g.Config.Subdomain)
+	c.converter = converter.NewConverter()
+	return nil
+}
+
+func (c *CommandExport) Run(g *Global) error {
+	files, err := expandFiles(g.Config.ContentsDir, []string{g.Config.ContentsDir}, isTranslationFile)
+	if err != nil {
+		return err
+	}
+
+	var pages []export.Page
+	sectionNames := map[int]string{}
+	for _, file := range files {
+		t := &zendesk.Translation{}
+		if err := t.FromFile(file); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		if c.Locale != "" && t.Locale != c.Locale {
+			continue
+		}
+
+		body := t.Body
+		html, err := t.IsHTML()
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		if !c.Raw && !html {
+			if body, err = c.converter.ConvertToHTML(t.Body); err != nil {
+				return fmt.Errorf("%s: %w", file, err)
+			}
+		}
+		pages = append(pages, export.Page{
+			ArticleID: t.SourceID,
+			Locale:    t.Locale,
+			SectionID: t.SectionID,
+			Title:     t.Title,
+			Body:      template.HTML(body),
+		})
+
+		if _, ok := sectionNames[t.SectionID]; ok || !c.SectionNames || t.SectionID == 0 {
+			continue
+		}
+		res, err := c.client.ShowSection(t.Locale, t.SectionID)
+		if err != nil {
+			return err
+		}
+		s := &zendesk.Section{}
+		if err := s.FromJson(res); err != nil {
+			return err
+		}
+		sectionNames[t.SectionID] = s.Name
+	}
+
+	if err := export.Write(c.Out, c.Title, pages, sectionNames); err != nil {
+		return err
+	}
+	slog.Info("exported", "pages", len(pages), "dest", c.Out)
+	return nil
+}
@@ -0,0 +1,209 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+
+	"github.com/tukaelu/zgsync/internal/converter"
+	"github.com/tukaelu/zgsync/internal/importer"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+	"gopkg.in/yaml.v3"
+)
+
+type CommandImport struct {
+	Out       string              `name:"out" short:"o" help:"Specify the output directory. If not specified, the contents directory will be used." type:"path"`
+	Locale    string              `name:"locale" short:"l" help:"Specify the locale of the imported pages. If not specified, the default locale will be used."`
+	SectionID int                 `name:"section-id" short:"s" help:"Specify the section ID of the imported pages."`
+	Mapping   string              `name:"mapping" short:"m" help:"Specify a YAML file mapping path prefixes of the pages to section IDs." type:"existingfile"`
+	Raw       bool                `name:"raw" help:"It imports the body as is without converting it from HTML to Markdown."`
+	Format    string              `name:"format" short:"f" help:"Specify the format of the export (html, confluence or notion)." enum:"html,confluence,notion" default:"html"`
+	Articles  bool                `name:"articles" short:"A" help:"It also writes the article files paired with the translations, so that push --all creates the articles."`
+	Source    string              `arg:"" help:"Specify the directory or the zip archive of the export." type:"path"`
+	converter converter.Converter `kong:"-"`
+	names     map[string]bool     `kong:"-"`
+}
+
+func (c *CommandImport) AfterApply(g *Global) error {
+	c.converter = converter.NewConverter()
+	return nil
+}
+
+func (c *CommandImport) Run(g *Global) error {
+	if c.Locale == "" {
+		c.Locale = g.Config.DefaultLocale
+	}
+	if c.Out == "" {
+		c.Out = g.Config.ContentsDir
+	}
+
+	mapping := map[string]int{}
+	if c.Mapping != "" {
+		b, err := os.ReadFile(c.Mapping)
+		if err != nil {
+			return err
+		}
+		if err := yaml.Unmarshal(b, &mapping); err != nil {
+			return fmt.Errorf("failed to parse the mapping: %w", err)
+		}
+	}
+
+	var docs []*importer.Document
+	var err error
+	switch c.Format {
+	case "confluence":
+		docs, err = importer.ReadConfluence(c.Source)
+	case "notion":
+		docs, err = importer.ReadNotion(c.Source)
+	default:
+		docs, err = importer.ReadHTML(c.Source)
+	}
+	if err != nil {
+		return err
+	}
+
+	c.names = map[string]bool{}
+	attachments := map[string]string{}
+	sections := &sectionChooser{mapping: mapping, fallback: c.SectionID, answers: map[string]int{}}
+	for _, doc := range docs {
+		rel := c.importPath(doc)
+		sectionID, err := sections.choose(rel)
+		if err != nil {
+			return err
+		}
+		path, err := c.write(doc, rel, sectionID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", doc.Path, err)
+		}
+		slog.Info("imported", "file", doc.Path, "dest", path)
+		for _, a := range doc.Attachments {
+			dest := filepath.Join(filepath.Dir(path), filepath.FromSlash(a.Path))
+			if !isUnder(c.Out, dest) {
+				return fmt.Errorf("%s: %s is outside the output directory", doc.Path, dest)
+			}
+			attachments[a.Src] = dest
+		}
+	}
+
+	if len(attachments) == 0 {
+		return nil
+	}
+	missing, err := importer.Extract(c.Source, attachments)
+	if err != nil {
+		return err
+	}
+	for _, p := range missing {
+		slog.Warn("attachment is not found in the export", "file", p)
+	}
+	slog.Info("copied attachments", "count", len(attachments)-len(missing))
+	return nil
+}
+
+// importPath returns the slash-separated path of the page in the output directory.
+func (c *CommandImport) importPath(doc *importer.Document) string {
+	if c.Format == "html" {
+		return doc.Path
+	}
+	elems := make([]string, 0, len(doc.Tree)+1)
+	for _, title := range doc.Tree {
+		elems = append(elems, slugify(title))
+	}
+	return path.Join(append(elems, path.Base(doc.Path))...)
+}
+
+func (c *CommandImport) write(doc *importer.Document, rel string, sectionID int) (string, error) {
+	body := doc.Body
+	if !c.Raw && !doc.Markdown {
+		var err error
+		if body, err = c.converter.ConvertToMarkdown(doc.Body); err != nil {
+			return "", err
+		}
+	}
+
+	t := &zendesk.Translation{
+		Title:     doc.Title,
+		Locale:    c.Locale,
+		Draft:     true,
+		SectionID: sectionID,
+		Body:      body + "\n",
+	}
+	name := slugify(doc.Title)
+	if name == "" {
+		base := path.Base(doc.Path)
+		name = base[:len(base)-len(path.Ext(base))]
+	}
+	dir := filepath.Join(c.Out, filepath.FromSlash(path.Dir(rel)))
+	if !isUnder(c.Out, dir) {
+		return "", fmt.Errorf("%s is outside the output directory", dir)
+	}
+	name = c.uniqueName(dir, name)
+	if !c.Articles {
+		dest := filepath.Join(dir, name+"-"+c.Locale+".md")
+		return dest, t.Save(dest, false)
+	}
+
+	// the translation named {article file name}.{locale}.md is paired with the new article by push --all.
+	a := &zendesk.Article{
+		Title:     doc.Title,
+		Locale:    c.Locale,
+		Draft:     true,
+		SectionID: sectionID,
+	}
+	if err := a.Save(filepath.Join(dir, name+".md"), false); err != nil {
+		return "", err
+	}
+	dest := filepath.Join(dir, name+"."+c.Locale+".md")
+	return dest, t.Save(dest, false)
+}
+
+// uniqueName returns the name suffixed with a number if a page of the same name is already written in the directory.
+func (c *CommandImport) uniqueName(dir, name string) string {
+	unique := name
+	for i := 2; c.names[filepath.Join(dir, unique)]; i++ {
+		unique = name + "-" + strconv.Itoa(i)
+	}
+	c.names[filepath.Join(dir, unique)] = true
+	return unique
+}
+
+// sectionChooser determines the sections of the pages, asking once per directory for the unmapped ones.
+type sectionChooser struct {
+	mapping  map[string]int
+	fallback int
+	answers  map[string]int
+}
+
+func (s *sectionChooser) choose(p string) (int, error) {
+	if id, ok := sectionForPath(s.mapping, p); ok {
+		return id, nil
+	}
+	if s.fallback != 0 {
+		return s.fallback, nil
+	}
+
+	dir := path.Dir(p)
+	if id, ok := s.answers[dir]; ok {
+		return id, nil
+	}
+	if !isTerminal(os.Stdin) {
+		s.answers[dir] = 0
+		return 0, nil
+	}
+	for {
+		answer, err := prompt(fmt.Sprintf("section ID for %s/ (empty to skip): ", dir))
+		if err != nil {
+			return 0, err
+		}
+		if answer == "" {
+			s.answers[dir] = 0
+			return 0, nil
+		}
+		if id, err := strconv.Atoi(answer); err == nil {
+			s.answers[dir] = id
+			return id, nil
+		}
+	}
+}
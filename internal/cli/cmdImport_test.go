@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tukaelu/zgsync/internal/converter"
+	"github.com/tukaelu/zgsync/internal/state"
+)
+
+func TestImportArticles(t *testing.T) {
+	out := t.TempDir()
+	g := &Global{Config: Config{ContentsDir: out, DefaultLocale: "ja"}}
+	c := &CommandImport{
+		Out:       out,
+		SectionID: 10,
+		Format:    "html",
+		Articles:  true,
+		Source:    "../importer/testdata/html",
+		converter: converter.NewConverter(),
+	}
+	if err := c.Run(g); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+
+	tests := []struct {
+		file     string
+		expected string
+	}{
+		{"welcome.md", state.KindArticle},
+		{"welcome.ja.md", state.KindTranslation},
+		{"guides/setup-guide.md", state.KindArticle},
+		{"guides/setup-guide.ja.md", state.KindTranslation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			path := filepath.Join(out, filepath.FromSlash(tt.file))
+			if _, err := os.Stat(path); err != nil {
+				t.Fatalf("Run() failed: %s is not written: %v", tt.file, err)
+			}
+			if kind := classifyFile(path); kind != tt.expected {
+				t.Errorf("classifyFile() failed: got %q, want %q", kind, tt.expected)
+			}
+		})
+	}
+
+	a, err := g.Config.readArticle(filepath.Join(out, "guides", "setup-guide.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Title != "Setup guide" || a.SectionID != 10 || !a.Draft {
+		t.Errorf("Run() failed: the article file is %+v", a)
+	}
+}
+
+func TestImportSameTitle(t *testing.T) {
+	src := t.TempDir()
+	for _, name := range []string{"a.html", "b.html"} {
+		if err := os.WriteFile(filepath.Join(src, name), []byte("<html><head><title>Same title</title></head><body><p>"+name+"</p></body></html>"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out := t.TempDir()
+	g := &Global{Config: Config{ContentsDir: out, DefaultLocale: "ja"}}
+	c := &CommandImport{Out: out, SectionID: 10, Format: "html", Source: src, converter: converter.NewConverter()}
+	if err := c.Run(g); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	for _, file := range []string{"same-title-ja.md", "same-title-2-ja.md"} {
+		if _, err := os.Stat(filepath.Join(out, file)); err != nil {
+			t.Errorf("Run() failed: %s is not written: %v", file, err)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/tukaelu/zgsync/internal/state"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+type CommandIndex struct {
+	Locale       string         `name:"locale" short:"l" help:"Specify the locale of the articles. If not specified, all locales will be listed."`
+	Format       string         `name:"format" help:"Specify the output format (markdown or json)." enum:"markdown,json" default:"markdown"`
+	Title        string         `name:"title" help:"Specify the heading of the Markdown index." default:"Index"`
+	Out          string         `name:"out" short:"o" help:"Write the index to the file instead of the standard output." type:"path"`
+	SectionNames bool           `name:"section-names" help:"It retrieves the section and category names from the remote for the section paths."`
+	client       zendesk.Client `kong:"-"`
+}
+
+type indexEntry struct {
+	ArticleID   int    `json:"article_id"`
+	Title       string `json:"title"`
+	Locale      string `json:"locale"`
+	SectionID   int    `json:"section_id"`
+	SectionPath string `json:"section_path,omitempty"`
+	HtmlURL     string `json:"html_url,omitempty"`
+	File        string `json:"file"`
+}
+
+func (c *CommandIndex) AfterApply(g *Global) error {
+	c.client = g.newClient(g.Config.Subdomain)
+	return nil
+}
+
+func (c *CommandIndex) Run(g *Global) error {
+	s, err := g.LoadState()
+	if err != nil {
+		return err
+	}
+	entries, err := c.collect(g, s)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if c.Format == "json" {
+		b, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return err
+		}
+		buf.Write(append(b, '\n'))
+	} else {
+		writeMarkdownIndex(&buf, c.Title, entries)
+	}
+
+	if c.Out == "" {
+		_, err := io.Copy(os.Stdout, &buf)
+		return err
+	}
+	return c.write(g, buf.String())
+}
+
+// collect returns the tracked translations sorted by locale, section and title, except the output file.
+func (c *CommandIndex) collect(g *Global, s *state.Store) ([]indexEntry, error) {
+	var out string
+	if c.Out != "" {
+		out = s.Key(c.Out)
+	}
+	paths := map[string]string{}
+	var entries []indexEntry
+	for _, key := range s.Keys() {
+		e := s.Files[key]
+		if e.Kind != state.KindTranslation || e.Brand != g.Config.Brand || key == out {
+			continue
+		}
+		t := &zendesk.Translation{}
+		if err := t.FromFile(s.Abs(key)); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		if c.Locale != "" && g.Config.remoteLocale(t.Locale) != g.Config.remoteLocale(c.Locale) {
+			continue
+		}
+		entry := indexEntry{
+			ArticleID: t.SourceID,
+			Title:     t.Title,
+			Locale:    t.Locale,
+			SectionID: t.SectionID,
+			HtmlURL:   t.HtmlURL,
+			File:      key,
+		}
+		if c.SectionNames && t.SectionID != 0 {
+			cacheKey := fmt.Sprintf("%s/%d", t.Locale, t.SectionID)
+			if _, ok := paths[cacheKey]; !ok {
+				p, err := c.sectionPath(g.Config.remoteLocale(t.Locale), t.SectionID)
+				if err != nil {
+					return nil, err
+				}
+				paths[cacheKey] = p
+			}
+			entry.SectionPath = paths[cacheKey]
+		}
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Locale != b.Locale {
+			return a.Locale < b.Locale
+		}
+		if sectionHeading(a) != sectionHeading(b) {
+			return sectionHeading(a) < sectionHeading(b)
+		}
+		return a.Title < b.Title
+	})
+	return entries, nil
+}
+
+// sectionPath returns the names of the category and the section separated by a slash.
+func (c *CommandIndex) sectionPath(locale string, sectionID int) (string, error) {
+	res, err := c.client.ShowSection(locale, sectionID)
+	if err != nil {
+		return "", fmt.Errorf("section %d: %w", sectionID, err)
+	}
+	sec := &zendesk.Section{}
+	if err := sec.FromJson(res); err != nil {
+		return "", err
+	}
+	if sec.CategoryID == 0 {
+		return sec.Name, nil
+	}
+	res, err = c.client.ShowCategory(locale, sec.CategoryID)
+	if err != nil {
+		return "", fmt.Errorf("category %d: %w", sec.CategoryID, err)
+	}
+	cat := &zendesk.Category{}
+	if err := cat.FromJson(res); err != nil {
+		return "", err
+	}
+	return cat.Name + " / " + sec.Name, nil
+}
+
+// write writes the index to the output file. The Markdown index replaces the body of a translation file.
+func (c *CommandIndex) write(g *Global, index string) error {
+	if c.Format == "markdown" {
+		if ref, err := readFileRef(c.Out); err == nil && ref.Kind == state.KindTranslation {
+			t := &zendesk.Translation{}
+			if err := t.FromFile(c.Out); err != nil {
+				return err
+			}
+			t.Body = index
+			_, tmpl, err := g.Config.frontMatterTemplates()
+			if err != nil {
+				return err
+			}
+			if err := t.SaveWithTemplate(c.Out, false, tmpl); err != nil {
+				return err
+			}
+			slog.Info("updated", "file", c.Out)
+			return nil
+		}
+	}
+	if err := os.WriteFile(c.Out, []byte(index), 0o644); err != nil {
+		return err
+	}
+	slog.Info("written", "file", c.Out)
+	return nil
+}
+
+func sectionHeading(e indexEntry) string {
+	if e.SectionPath != "" {
+		return e.SectionPath
+	}
+	if e.SectionID == 0 {
+		return "No section"
+	}
+	return fmt.Sprintf("Section %d", e.SectionID)
+}
+
+// writeMarkdownIndex writes the entries as lists of links grouped by locale and section.
+func writeMarkdownIndex(w io.Writer, title string, entries []indexEntry) {
+	fmt.Fprintf(w, "# %s\n", title)
+	locale, section := "", ""
+	for i, e := range entries {
+		if i == 0 || e.Locale != locale {
+			locale, section = e.Locale, ""
+			fmt.Fprintf(w, "\n## %s\n", e.Locale)
+		}
+		if h := sectionHeading(e); h != section {
+			section = h
+			fmt.Fprintf(w, "\n### %s\n\n", h)
+		}
+		title := strings.NewReplacer("[", `\[`, "]", `\]`).Replace(e.Title)
+		if e.HtmlURL != "" {
+			fmt.Fprintf(w, "- [%s](%s)\n", title, e.HtmlURL)
+		} else {
+			fmt.Fprintf(w, "- %s\n", title)
+		}
+	}
+}
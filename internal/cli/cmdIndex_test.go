@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tukaelu/zgsync/internal/state"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+func TestIndex(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"1-ja.md":     "---\ntitle: Setup\nlocale: ja\nsection_id: 10\nsource_id: 1\nhtml_url: https://example.zendesk.com/hc/ja/articles/1\n---\n",
+		"2-ja.md":     "---\ntitle: About [beta]\nlocale: ja\nsection_id: 10\nsource_id: 2\nhtml_url: https://example.zendesk.com/hc/ja/articles/2\n---\n",
+		"3-en.md":     "---\ntitle: Draft\nlocale: en\nsource_id: 3\n---\n",
+		"index-ja.md": "---\ntitle: Site map\nlocale: ja\nsection_id: 10\nsource_id: 9\n---\nold\n",
+	}
+	g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja"}}
+	s, err := g.LoadState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		s.Get(path).Kind = state.KindTranslation
+	}
+	// the tracked file removed locally is skipped.
+	s.Get(filepath.Join(dir, "4-ja.md")).Kind = state.KindTranslation
+
+	out := filepath.Join(dir, "index-ja.md")
+	c := &CommandIndex{Format: "markdown", Title: "Index", Out: out}
+	entries, err := c.collect(g, s)
+	if err != nil {
+		t.Fatalf("collect() failed: %v", err)
+	}
+	var buf bytes.Buffer
+	writeMarkdownIndex(&buf, c.Title, entries)
+	expected := `# Index
+
+## en
+
+### No section
+
+- Draft
+
+## ja
+
+### Section 10
+
+- [About \[beta\]](https://example.zendesk.com/hc/ja/articles/2)
+- [Setup](https://example.zendesk.com/hc/ja/articles/1)
+`
+	if buf.String() != expected {
+		t.Errorf("writeMarkdownIndex() failed:\n%s\nwant:\n%s", buf.String(), expected)
+	}
+
+	if err := c.write(g, buf.String()); err != nil {
+		t.Fatalf("write() failed: %v", err)
+	}
+	tr := &zendesk.Translation{}
+	if err := tr.FromFile(out); err != nil {
+		t.Fatal(err)
+	}
+	if tr.Title != "Site map" || tr.SourceID != 9 || tr.Body != expected {
+		t.Errorf("write() failed: unexpected translation %+v", tr)
+	}
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+type CommandLocales struct {
+	Format  string         `name:"format" short:"o" help:"Specify the output format (table or json)." enum:"table,json" default:"table"`
+	Columns []string       `name:"columns" help:"Specify the columns of the table separated by commas: locale, default and alias."`
+	client  zendesk.Client `kong:"-"`
+}
+
+type localeOutput struct {
+	Locale  string `json:"locale"`
+	Default bool   `json:"default"`
+	// Alias is the locale code used in the local files given by locale_aliases.
+	Alias string `json:"alias,omitempty"`
+}
+
+func (c *CommandLocales) AfterApply(g *Global) error {
+	c.client = g.newClient(g.Config.Subdomain)
+	return nil
+}
+
+func newLocalesTable() *table {
+	return newTable([]string{"locale", "default", "alias"})
+}
+
+func (c *CommandLocales) Run(g *Global) error {
+	t := newLocalesTable()
+	if err := t.validate(c.Columns); err != nil {
+		return err
+	}
+	enabled, err := enabledLocales(c.client)
+	if err != nil {
+		return err
+	}
+
+	out := []localeOutput{}
+	for _, l := range enabled.Locales {
+		o := localeOutput{Locale: l, Default: l == enabled.DefaultLocale}
+		if alias := g.Config.localLocale(l); alias != l {
+			o.Alias = alias
+		}
+		out = append(out, o)
+	}
+
+	if c.Format == "json" {
+		b, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+		return nil
+	}
+	for _, o := range out {
+		t.add(o.Locale, fmt.Sprint(o.Default), o.Alias)
+	}
+	return t.print(c.Columns)
+}
+
+// enabledLocales returns the locales enabled in the help center of the client.
+func enabledLocales(client zendesk.Client) (*zendesk.Locales, error) {
+	res, err := client.ListLocales()
+	if err != nil {
+		return nil, err
+	}
+	enabled := &zendesk.Locales{}
+	if err := enabled.FromJson(res); err != nil {
+		return nil, err
+	}
+	return enabled, nil
+}
+
+// checkEnabledLocale returns an error if the locale is not one of the enabled locales.
+func checkEnabledLocale(enabled *zendesk.Locales, locale string) error {
+	if enabled.Contains(locale) {
+		return nil
+	}
+	return fmt.Errorf("locale: %s is not enabled in the help center, whose locales are %s", locale, strings.Join(enabled.Locales, ", "))
+}
@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+type CommandMeta struct {
+	Locale  string         `name:"locale" short:"l" help:"Specify the locale of the article. If not specified, the locale of the file or the default locale will be used."`
+	Format  string         `name:"format" short:"o" help:"Specify the output format (table or json)." enum:"table,json" default:"table"`
+	Columns []string       `name:"columns" help:"Specify the columns of the table of the translations separated by commas: locale, draft, outdated, updated_at and title."`
+	Target  string         `arg:"" help:"Specify the file or the article ID."`
+	client  zendesk.Client `kong:"-"`
+}
+
+type metaTranslation struct {
+	Locale    string `json:"locale"`
+	Title     string `json:"title"`
+	Draft     bool   `json:"draft"`
+	Outdated  bool   `json:"outdated"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type metaOutput struct {
+	Article      *zendesk.Article  `json:"article"`
+	Author       string            `json:"author,omitempty"`
+	Translations []metaTranslation `json:"translations"`
+}
+
+func (c *CommandMeta) AfterApply(g *Global) error {
+	c.client = g.newClient(g.Config.Subdomain)
+	return nil
+}
+
+func newMetaTable() *table {
+	return newTable([]string{"locale", "draft", "outdated", "updated_at", "title"})
+}
+
+func (c *CommandMeta) Run(g *Global) error {
+	if err := newMetaTable().validate(c.Columns); err != nil {
+		return err
+	}
+	ref, err := resolveFileRef(c.Target)
+	if err != nil {
+		return err
+	}
+	locale := c.Locale
+	if locale == "" {
+		locale = ref.Locale
+	}
+	if locale == "" {
+		locale = g.Config.DefaultLocale
+	}
+
+	stop := spin(fmt.Sprintf("fetching article %d", ref.ID))
+	defer stop()
+	res, err := c.client.ShowArticle(locale, ref.ID)
+	if err != nil {
+		return err
+	}
+	a := &zendesk.Article{}
+	if err := a.FromJson(res); err != nil {
+		return err
+	}
+
+	translations, err := listTranslations(c.client, ref.ID)
+	if err != nil {
+		return err
+	}
+
+	author := newUserNames(c.client).name(a.AuthorID)
+
+	stop()
+
+	out := metaOutput{Article: a, Author: author}
+	for _, t := range translations {
+		out.Translations = append(out.Translations, metaTranslation{
+			Locale:    t.Locale,
+			Title:     t.Title,
+			Draft:     t.Draft,
+			Outdated:  t.Outdated,
+			UpdatedAt: t.UpdatedAt,
+		})
+	}
+
+	if c.Format == "json" {
+		b, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+		return nil
+	}
+	return printMeta(out, c.Columns)
+}
+
+func printMeta(out metaOutput, columns []string) error {
+	a := out.Article
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	rows := [][2]string{
+		{"id", fmt.Sprint(a.ID)},
+		{"title", a.Title},
+		{"section_id", fmt.Sprint(a.SectionID)},
+		{"author_id", fmt.Sprint(a.AuthorID)},
+		{"author", out.Author},
+		{"draft", fmt.Sprint(a.Draft)},
+		{"promoted", fmt.Sprint(a.Promoted != nil && *a.Promoted)},
+		{"labels", strings.Join(a.LabelNames, ", ")},
+		{"vote_sum", fmt.Sprint(a.VoteSum)},
+		{"vote_count", fmt.Sprint(a.VoteCount)},
+		{"rating", formatRating(rating(a.VoteSum, a.VoteCount))},
+		{"created_at", a.CreatedAt},
+		{"updated_at", a.UpdatedAt},
+		{"edited_at", a.EditedAt},
+		{"source_locale", a.SourceLocale},
+		{"outdated_locales", strings.Join(a.OutdatedLocales, ", ")},
+		{"html_url", a.HtmlURL},
+	}
+	for _, r := range rows {
+		fmt.Fprintf(w, "%s\t%s\n", r[0], r[1])
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(out.Translations) == 0 {
+		return nil
+	}
+	fmt.Println()
+	tbl := newMetaTable()
+	for _, t := range out.Translations {
+		tbl.add(t.Locale, fmt.Sprint(t.Draft), fmt.Sprint(t.Outdated), t.UpdatedAt, t.Title)
+	}
+	return tbl.print(columns)
+}
@@ -0,0 +1,283 @@
+package cli
+
+import (
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/tukaelu/zgsync/internal/state"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+type CommandMove struct {
+	Target    string         `arg:"" help:"Specify the file or the article ID to move."`
+	SectionID int            `arg:"" name:"section-id" help:"Specify the section ID of the destination."`
+	client    zendesk.Client `kong:"-"`
+}
+
+func (c *CommandMove) AfterApply(g *Global) error {
+	c.client = g.newClient(g.Config.Subdomain)
+	return nil
+}
+
+func (c *CommandMove) Run(g *Global) (err error) {
+	ref, err := resolveFileRef(c.Target)
+	if err != nil {
+		return err
+	}
+
+	s, err := g.LoadState()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if serr := s.Save(); serr != nil && err == nil {
+			err = serr
+		}
+	}()
+
+	res, err := c.client.MoveArticle(ref.ID, c.SectionID)
+	if err != nil {
+		return err
+	}
+	a := &zendesk.Article{}
+	if err := a.FromJson(res); err != nil {
+		return err
+	}
+
+	files := map[string]string{}
+	for _, key := range s.Keys() {
+		if e := s.Files[key]; e.ArticleID == ref.ID && e.Brand == g.Config.Brand && e.Kind != state.KindPost {
+			files[s.Abs(key)] = e.Kind
+		}
+	}
+	if ref.Path != "" {
+		if abs, err := filepath.Abs(ref.Path); err == nil {
+			files[abs] = ref.Kind
+		}
+	}
+
+	for file, kind := range files {
+		if _, err := os.Stat(file); os.IsNotExist(err) {
+			continue
+		}
+		dest, err := c.moveFile(g, file, kind, a)
+		if err != nil {
+			return err
+		}
+		s.Move(file, dest)
+		if e, ok := s.Lookup(dest); ok {
+			if e.PulledHash, err = state.HashFile(dest); err != nil {
+				return err
+			}
+			e.SectionID = a.SectionID
+			if kind == state.KindArticle {
+				e.RemoteUpdatedAt = a.UpdatedAt
+			}
+		}
+		slog.Info("moved", "file", file, "dest", dest)
+	}
+	return nil
+}
+
+// moveFile rewrites the section_id of the file and moves it to the path of the new section in its layout.
+func (c *CommandMove) moveFile(g *Global, file string, kind string, a *zendesk.Article) (string, error) {
+	articleTmpl, translationTmpl, err := g.Config.frontMatterTemplates()
+	if err != nil {
+		return "", err
+	}
+	var oldSectionID int
+	var locale string
+	var save func(string) error
+	if kind == state.KindArticle {
+		fa := &zendesk.Article{}
+		if err := fa.FromFile(file); err != nil {
+			return "", err
+		}
+		oldSectionID, locale = fa.SectionID, fa.Locale
+		fa.SectionID = c.SectionID
+		save = func(path string) error { return fa.SaveWithTemplate(path, false, articleTmpl) }
+	} else {
+		t := &zendesk.Translation{}
+		if err := t.FromFile(file); err != nil {
+			return "", err
+		}
+		oldSectionID, locale = t.SectionID, t.Locale
+		t.SectionID = c.SectionID
+		save = func(path string) error { return t.SaveWithTemplate(path, false, translationTmpl) }
+	}
+	if locale == "" {
+		locale = g.Config.DefaultLocale
+	}
+
+	dest := file
+	if oldSectionID != 0 && oldSectionID != c.SectionID {
+		from := newLayoutData(a, g.Config.localLocale(locale))
+		from.SectionID = oldSectionID
+		if dest, err = c.destination(g, file, kind, from); err != nil {
+			return "", err
+		}
+	}
+
+	if dest != file {
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			return "", err
+		}
+	}
+	if err := save(dest); err != nil {
+		return "", err
+	}
+	if dest != file {
+		if err := os.Remove(file); err != nil {
+			return "", err
+		}
+		// the old directories are removed only if they became empty.
+		removeEmptyDirs(g.Config.ContentsDir, filepath.Dir(file))
+	}
+	return dest, nil
+}
+
+// destination returns the path of the file in the new section following the layout the file was pulled in.
+func (c *CommandMove) destination(g *Global, file string, kind string, from layoutData) (string, error) {
+	to := from
+	to.SectionID = c.SectionID
+	dir, name := filepath.Dir(file), filepath.Base(file)
+
+	// the file name is rendered again only when it follows the file name template.
+	hierarchy := name == from.Locale+".md" || name == hierarchyArticleFilenameTemplate
+	names, err := newFileNamer(g.Config.FilenameTemplate, g.Config.ArticleFilenameTemplate, hierarchy)
+	if err != nil {
+		return "", err
+	}
+	render := names.Translation
+	if kind == state.KindArticle {
+		render = names.Article
+	}
+
+	root, err := filepath.Abs(g.Config.ContentsDir)
+	if err != nil {
+		return "", err
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	if !isUnder(root, abs) {
+		return file, nil
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil {
+		return "", err
+	}
+
+	mapped, err := c.mappedDir(g, root, file)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case mapped != "":
+		dir = mapped
+	case filepath.Base(dir) == strconv.Itoa(from.SectionID):
+		dir = filepath.Join(filepath.Dir(dir), strconv.Itoa(c.SectionID))
+	case rel != ".":
+		r, err := newHierarchyResolver(c.client, g.Config.remoteLocale(from.Locale), g.Config.HierarchyLayout)
+		if err != nil {
+			return "", err
+		}
+		old, err := r.Dir(&from)
+		if err != nil {
+			return "", err
+		}
+		if old == rel {
+			moved, err := r.Dir(&to)
+			if err != nil {
+				return "", err
+			}
+			if dir, err = layoutPath(root, moved); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	if old, err := render(from); err == nil && old == name {
+		if name, err = render(to); err != nil {
+			return "", err
+		}
+	}
+	return layoutPath(dir, name)
+}
+
+// mappedDir returns the directory mapped to the new section when the file lives in a mapped directory.
+func (c *CommandMove) mappedDir(g *Global, root string, file string) (string, error) {
+	dirs, err := newDirConfigs(g.Config.ContentsDir)
+	if err != nil {
+		return "", err
+	}
+	dc, err := dirs.For(file)
+	if err != nil {
+		return "", err
+	}
+	if dc.SectionID != nil {
+		var found string
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || !d.IsDir() || found != "" {
+				return err
+			}
+			local, err := readDirConfig(path)
+			if err != nil {
+				return err
+			}
+			if local.SectionID != nil && *local.SectionID == c.SectionID {
+				found = path
+				return filepath.SkipAll
+			}
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+		if found == "" {
+			slog.Warn("no directory is configured for the section", "file", file, "section_id", c.SectionID)
+			return filepath.Dir(file), nil
+		}
+		return found, nil
+	}
+
+	if _, ok := g.Config.sectionForFile(file); ok {
+		var prefixes []string
+		for prefix, id := range g.Config.Sections {
+			if id == c.SectionID {
+				prefixes = append(prefixes, prefix)
+			}
+		}
+		if len(prefixes) == 0 {
+			slog.Warn("no directory is mapped to the section", "file", file, "section_id", c.SectionID)
+			return filepath.Dir(file), nil
+		}
+		slices.Sort(prefixes)
+		return filepath.Join(root, filepath.FromSlash(strings.Trim(prefixes[0], "/"))), nil
+	}
+	return "", nil
+}
+
+// removeEmptyDirs removes the directory and its parents up to the contents directory while they are empty.
+func removeEmptyDirs(contentsDir string, dir string) {
+	root, err := filepath.Abs(contentsDir)
+	if err != nil {
+		return
+	}
+	for {
+		abs, err := filepath.Abs(dir)
+		if err != nil || abs == root || !isUnder(root, abs) {
+			return
+		}
+		if os.Remove(abs) != nil {
+			return
+		}
+		dir = filepath.Dir(abs)
+	}
+}
@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tukaelu/zgsync/internal/state"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+	"gopkg.in/yaml.v3"
+)
+
+// mvClient moves the article 1 titled "Getting Started" between the sections named "Section <id>" in the category 100.
+type mvClient struct {
+	zendesk.Client
+}
+
+func (c *mvClient) MoveArticle(articleID int, sectionID int) (string, error) {
+	return fmt.Sprintf(`{"article":{"id":%d,"title":"Getting Started","locale":"ja","section_id":%d,"updated_at":"2024-02-01T00:00:00Z"}}`, articleID, sectionID), nil
+}
+
+func (c *mvClient) ShowSection(locale string, sectionID int) (string, error) {
+	return fmt.Sprintf(`{"section":{"id":%d,"name":"Section %d","category_id":100}}`, sectionID, sectionID), nil
+}
+
+func (c *mvClient) ShowCategory(locale string, categoryID int) (string, error) {
+	return fmt.Sprintf(`{"category":{"id":%d,"name":"Docs"}}`, categoryID), nil
+}
+
+func TestMove(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		files    map[string]string
+		file     string
+		expected string
+	}{
+		{
+			name:     "flat",
+			file:     "1-ja.md",
+			expected: "1-ja.md",
+		},
+		{
+			name:     "section directory",
+			file:     "10/1-ja.md",
+			expected: "20/1-ja.md",
+		},
+		{
+			name:     "hierarchy",
+			file:     "docs/section-10/getting-started/ja.md",
+			expected: "docs/section-20/getting-started/ja.md",
+		},
+		{
+			name:     "filename template",
+			config:   Config{FilenameTemplate: "{{.SectionID}}/{{.ArticleID}}-{{.Locale}}.md"},
+			file:     "10/1-ja.md",
+			expected: "20/1-ja.md",
+		},
+		{
+			name:     "filename template with the section",
+			config:   Config{FilenameTemplate: "{{.SectionID}}-{{.ArticleID}}-{{.Locale}}.md"},
+			file:     "10-1-ja.md",
+			expected: "20-1-ja.md",
+		},
+		{
+			name:     "sections config",
+			config:   Config{Sections: map[string]int{"guides": 10, "faq": 20}},
+			file:     "guides/1-ja.md",
+			expected: "faq/1-ja.md",
+		},
+		{
+			name: "directory configuration",
+			files: map[string]string{
+				"guides/.zgsync.yaml": "section_id: 10\n",
+				"faq/.zgsync.yaml":    "section_id: 20\n",
+			},
+			file:     "guides/1-ja.md",
+			expected: "faq/1-ja.md",
+		},
+		{
+			name: "directory configuration without the section",
+			files: map[string]string{
+				"guides/.zgsync.yaml": "section_id: 10\n",
+			},
+			file:     "guides/1-ja.md",
+			expected: "guides/1-ja.md",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				path := filepath.Join(dir, filepath.FromSlash(name))
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			file := filepath.Join(dir, filepath.FromSlash(tt.file))
+			if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(file, []byte("---\ntitle: Getting Started\nlocale: ja\nsource_id: 1\nsection_id: 10\n---\nbody\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			g := &Global{Config: tt.config}
+			g.Config.ContentsDir = dir
+			g.Config.DefaultLocale = "ja"
+			s, err := g.LoadState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := trackPulled(s, file, state.Entry{Kind: state.KindTranslation, ArticleID: 1, Locale: "ja", SectionID: 10}); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.Save(); err != nil {
+				t.Fatal(err)
+			}
+			c := &CommandMove{Target: file, SectionID: 20, client: &mvClient{}}
+			if err := c.Run(g); err != nil {
+				t.Fatalf("Run() failed: %v", err)
+			}
+
+			expected := filepath.Join(dir, filepath.FromSlash(tt.expected))
+			b, err := os.ReadFile(expected)
+			if err != nil {
+				t.Fatalf("Run() failed: %s is not moved to %s: %v", tt.file, tt.expected, err)
+			}
+			if !strings.Contains(string(b), "section_id: 20") {
+				t.Errorf("Run() failed: section_id is not rewritten\n%s", b)
+			}
+			if expected != file {
+				if _, err := os.Stat(file); !os.IsNotExist(err) {
+					t.Errorf("Run() failed: %s is left", tt.file)
+				}
+				if old := filepath.Dir(file); old != dir {
+					if _, err := os.Stat(old); !os.IsNotExist(err) && len(tt.files) == 0 {
+						t.Errorf("Run() failed: the empty directory %s is left", old)
+					}
+				}
+			}
+
+			if s, err = g.LoadState(); err != nil {
+				t.Fatal(err)
+			}
+			if e, ok := s.Lookup(expected); !ok || e.SectionID != 20 {
+				t.Errorf("Run() failed: the state of %s is %+v", tt.expected, e)
+			}
+		})
+	}
+}
+
+func TestMoveBrand(t *testing.T) {
+	dir := t.TempDir()
+	g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja"}}
+	if err := yaml.Unmarshal([]byte("title: \"\"\nowner: docs\n"), &g.Config.TranslationFrontMatter); err != nil {
+		t.Fatal(err)
+	}
+	s, err := g.LoadState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the article 1 of the other brand is another article with the same ID.
+	files := map[string]string{"10/1-ja.md": "", "other/10/1-ja.md": "other"}
+	for name, brand := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("---\ntitle: Getting Started\nlocale: ja\nsource_id: 1\nsection_id: 10\n---\nbody\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := trackPulled(s, path, state.Entry{Kind: state.KindTranslation, Brand: brand, ArticleID: 1, Locale: "ja", SectionID: 10}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &CommandMove{Target: "1", SectionID: 20, client: &mvClient{}}
+	if err := c.Run(g); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "20", "1-ja.md"))
+	if err != nil {
+		t.Fatalf("Run() failed: the file is not moved: %v", err)
+	}
+	if !strings.Contains(string(b), "owner: docs") {
+		t.Errorf("Run() failed: the front matter template is not applied\n%s", b)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "other", "10", "1-ja.md")); err != nil {
+		t.Errorf("Run() failed: the file of the other brand is moved: %v", err)
+	}
+}
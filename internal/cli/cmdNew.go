@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"text/template"
+	"time"
+
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+type CommandNew struct {
+	Template    string            `name:"template" short:"T" help:"Specify the name of the template directory in the templates directory." required:""`
+	Title       string            `name:"title" short:"t" help:"Specify the title of the article." required:""`
+	Section     string            `name:"section" short:"s" help:"Specify the section ID or a key of the sections config. If not specified, it is picked on the terminal."`
+	Locale      string            `name:"locale" short:"l" help:"Specify the locale of the article. If not specified, the default locale will be used."`
+	Vars        map[string]string `name:"var" help:"Specify the variable of the template as key=value."`
+	client      zendesk.Client    `kong:"-"`
+	interactive bool              `kong:"-"`
+}
+
+func (c *CommandNew) AfterApply(g *Global) error {
+	c.client = g.newClient(g.Config.Subdomain)
+	c.interactive = isTerminal(os.Stdin)
+	return nil
+}
+
+// newTemplateData is the data of the templates.
+type newTemplateData struct {
+	Title     string
+	Slug      string
+	Locale    string
+	SectionID int
+	Date      string
+	Vars      map[string]string
+}
+
+func (c *CommandNew) Run(g *Global) error {
+	name := slugify(c.Title)
+	if name == "" {
+		return fmt.Errorf("title %q cannot be used as the file name", c.Title)
+	}
+	locale := c.Locale
+	if locale == "" {
+		locale = g.Config.DefaultLocale
+	}
+	section := c.Section
+	if section == "" && c.interactive {
+		id, err := pickSection(c.client, locale, true)
+		if err != nil {
+			return err
+		}
+		if id != 0 {
+			section = sectionKey(g.Config.Sections, id)
+		}
+	}
+	dir := g.Config.ContentsDir
+	var sectionID int
+	if section != "" {
+		var err error
+		if sectionID, dir, err = g.Config.resolveSection(section); err != nil {
+			return err
+		}
+	}
+
+	tmplDir := filepath.Join(g.Config.templatesDir(), c.Template)
+	if _, err := os.Stat(filepath.Join(tmplDir, "article.md")); err != nil {
+		return fmt.Errorf("template %s does not have article.md in %s", c.Template, tmplDir)
+	}
+	data := newTemplateData{
+		Title:     c.Title,
+		Slug:      name,
+		Locale:    locale,
+		SectionID: sectionID,
+		Date:      time.Now().Format(time.DateOnly),
+		Vars:      c.Vars,
+	}
+	if data.Vars == nil {
+		data.Vars = map[string]string{}
+	}
+
+	// both are rendered before writing, so that nothing is written if either fails.
+	rendered := map[string][]byte{}
+	var paths []string
+	for src, path := range map[string]string{
+		"article.md":     filepath.Join(dir, name+".md"),
+		"translation.md": filepath.Join(dir, name+"."+locale+".md"),
+	} {
+		b, err := renderNewTemplate(filepath.Join(tmplDir, src), data)
+		if errors.Is(err, os.ErrNotExist) && src == "translation.md" {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%s already exists", path)
+		}
+		rendered[path] = b
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	for _, path := range paths {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, rendered[path], 0o644); err != nil {
+			return err
+		}
+		slog.Info("created", "file", path, "template", c.Template)
+	}
+	return nil
+}
+
+// sectionKey returns the key of the sections config mapped to the section ID, or the ID itself.
+func sectionKey(sections map[string]int, id int) string {
+	var keys []string
+	for key := range sections {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		if sections[key] == id {
+			return key
+		}
+	}
+	return strconv.Itoa(id)
+}
+
+// renderNewTemplate renders the template file. Undefined variables are errors.
+func renderNewTemplate(path string, data newTemplateData) ([]byte, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New(filepath.Base(path)).Option("missingkey=error").Parse(string(src))
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	dir := t.TempDir()
+	tmplDir, err := filepath.Abs(filepath.Join("testdata", "templates"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := &Global{Config: Config{
+		ContentsDir:   dir,
+		DefaultLocale: "ja",
+		TemplatesDir:  tmplDir,
+		Sections:      map[string]int{"guides": 456},
+	}}
+
+	c := &CommandNew{Template: "troubleshooting", Title: "Cannot sign in", Section: "guides", Vars: map[string]string{"product": "sso"}}
+	if err := c.Run(g); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	expected := map[string]string{
+		"cannot-sign-in.md":    "---\ntitle: \"Cannot sign in\"\nlocale: ja\nsection_id: 456\ndraft: true\nlabel_names:\n  - troubleshooting\n  - sso\n---\n",
+		"cannot-sign-in.ja.md": "---\ntitle: \"Cannot sign in\"\nlocale: ja\ndraft: true\n---\n## Symptoms\n\n## Cause\n\n## Resolution\n\nUpdated on " + time.Now().Format(time.DateOnly) + ".\n",
+	}
+	for name, content := range expected {
+		b, err := os.ReadFile(filepath.Join(dir, "guides", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != content {
+			t.Errorf("Run() failed: got %q, want %q", b, content)
+		}
+	}
+
+	tests := []struct {
+		name string
+		cmd  *CommandNew
+		err  string
+	}{
+		{"exists", c, "already exists"},
+		{"undefined variable", &CommandNew{Template: "troubleshooting", Title: "Slow pages"}, `map has no entry for key "product"`},
+		{"unknown template", &CommandNew{Template: "faq", Title: "Slow pages"}, "template faq does not have article.md"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cmd.Run(g); err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Run() error = %v, want %s", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestNewPickSection(t *testing.T) {
+	dir := t.TempDir()
+	tmplDir, err := filepath.Abs(filepath.Join("testdata", "templates"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := &Global{Config: Config{
+		ContentsDir:   dir,
+		DefaultLocale: "ja",
+		TemplatesDir:  tmplDir,
+		Sections:      map[string]int{"faq/billing": 20},
+	}}
+
+	// the picked section is created in the directory of its key.
+	stdin = bufio.NewReader(strings.NewReader("bill\n\n"))
+	defer func() { stdin = bufio.NewReader(os.Stdin) }()
+	c := &CommandNew{Template: "troubleshooting", Title: "Cannot pay", Vars: map[string]string{"product": "billing"}, client: &treeClient{}, interactive: true}
+	if err := c.Run(g); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "faq", "billing", "cannot-pay.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "section_id: 20\n") {
+		t.Errorf("Run() failed: the section is not picked\n%s", b)
+	}
+}
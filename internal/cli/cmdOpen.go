@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+type CommandOpen struct {
+	Edit   bool           `name:"edit" short:"e" help:"It opens the edit view of the article in the agent interface."`
+	Print  bool           `name:"print" help:"It prints the URL instead of opening the browser."`
+	Target string         `arg:"" help:"Specify the file or the article ID to open."`
+	client zendesk.Client `kong:"-"`
+}
+
+func (c *CommandOpen) AfterApply(g *Global) error {
+	c.client = g.newClient(g.Config.Subdomain)
+	return nil
+}
+
+func (c *CommandOpen) Run(g *Global) error {
+	target, err := resolveFileRef(c.Target)
+	if err != nil {
+		return err
+	}
+	if target.Locale == "" {
+		target.Locale = g.Config.DefaultLocale
+	}
+
+	var url string
+	if c.Edit {
+		url = fmt.Sprintf(zendesk.BaseURL+"/knowledge/articles/%d/%s", g.Config.Subdomain, target.ID, target.Locale)
+	} else {
+		if target.HtmlURL == "" {
+			res, err := c.client.ShowArticle(target.Locale, target.ID)
+			if err != nil {
+				return err
+			}
+			a := &zendesk.Article{}
+			if err := a.FromJson(res); err != nil {
+				return err
+			}
+			target.HtmlURL = a.HtmlURL
+		}
+		url = target.HtmlURL
+	}
+
+	if c.Print {
+		fmt.Println(url)
+		return nil
+	}
+	return openBrowser(url)
+}
@@ -0,0 +1,255 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/tukaelu/zgsync/internal/converter"
+	"github.com/tukaelu/zgsync/internal/state"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+type CommandPosts struct {
+	Pull CommandPostsPull `cmd:"pull" help:"Pull the community posts into the posts directory."`
+	Push CommandPostsPush `cmd:"push" help:"Push the community posts to the remote."`
+}
+
+type CommandPostsPull struct {
+	Topic   int                 `name:"topic" help:"Specify the topic whose posts are pulled."`
+	Raw     bool                `name:"raw" help:"It saves the details of the posts as HTML without converting them to Markdown."`
+	PostIDs []int               `arg:"" optional:"" help:"Specify the post IDs. If not specified, the tracked posts will be pulled."`
+	client  zendesk.Client      `kong:"-"`
+	conv    converter.Converter `kong:"-"`
+}
+
+type CommandPostsPush struct {
+	DryRun bool                `name:"dry-run" help:"dry run"`
+	Force  bool                `name:"force" short:"f" help:"It pushes even if the file has not changed since the last push."`
+	Raw    bool                `name:"raw" help:"It pushes raw data without converting it from Markdown to HTML."`
+	Files  []string            `arg:"" optional:"" help:"Specify the post files or directories to push. If not specified, the posts directory will be pushed." type:"path"`
+	client zendesk.Client      `kong:"-"`
+	conv   converter.Converter `kong:"-"`
+}
+
+func (c *CommandPostsPull) AfterApply(g *Global) error {
+	c.client = g.newClient(g.Config.Subdomain)
+	c.conv = converter.NewConverter()
+	return nil
+}
+
+func (c *CommandPostsPull) Run(g *Global) (err error) {
+	s, err := g.LoadState()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if serr := s.Save(); serr != nil && err == nil {
+			err = serr
+		}
+	}()
+
+	ids := c.PostIDs
+	if len(ids) == 0 && c.Topic == 0 {
+		for _, key := range s.Keys() {
+			if e := s.Files[key]; e.Kind == state.KindPost && e.Brand == g.Config.Brand {
+				ids = append(ids, e.ArticleID)
+			}
+		}
+		if len(ids) == 0 {
+			return fmt.Errorf("no posts are specified, and no posts are tracked in the sync state")
+		}
+	}
+
+	var posts []zendesk.Post
+	if len(ids) > 0 {
+		for _, id := range ids {
+			res, err := c.client.ShowPost(id)
+			if err != nil {
+				return fmt.Errorf("post %d: %w", id, err)
+			}
+			p := zendesk.Post{}
+			if err := p.FromJson(res); err != nil {
+				return err
+			}
+			posts = append(posts, p)
+		}
+	} else if posts, err = c.topicPosts(); err != nil {
+		return err
+	}
+
+	for i := range posts {
+		if err := c.save(g, s, &posts[i]); err != nil {
+			return fmt.Errorf("post %d: %w", posts[i].ID, err)
+		}
+	}
+	return nil
+}
+
+// topicPosts returns the posts of the topic of --topic.
+func (c *CommandPostsPull) topicPosts() ([]zendesk.Post, error) {
+	var posts []zendesk.Post
+	for page := 1; ; page++ {
+		res, err := c.client.ListPosts(c.Topic, page)
+		if err != nil {
+			return nil, err
+		}
+		list, hasNext, err := zendesk.PostsFromJson(res)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, list...)
+		if !hasNext {
+			return posts, nil
+		}
+	}
+}
+
+// save writes the post to the file tracking it, or to {id}.md in the posts directory.
+func (c *CommandPostsPull) save(g *Global, s *state.Store, p *zendesk.Post) error {
+	file := filepath.Join(g.Config.postsDir(), p.FileName())
+	if key, _, ok := s.Find(state.KindPost, g.Config.Brand, p.ID, ""); ok {
+		file = s.Abs(key)
+	}
+	step, err := planPull(s, file, p.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	if step.Action == state.ActionSkip {
+		upToDate(file)
+		return nil
+	}
+
+	if c.Raw {
+		p.BodyFormat = zendesk.BodyFormatHTML
+	} else if p.Details, err = c.conv.ConvertToMarkdown(p.Details); err != nil {
+		return err
+	}
+	if err := p.Save(file, false); err != nil {
+		return err
+	}
+	slog.Info("pulled", "file", file, "post_id", p.ID)
+	return trackPulled(s, file, state.Entry{
+		Kind:            state.KindPost,
+		Brand:           g.Config.Brand,
+		ArticleID:       p.ID,
+		RemoteUpdatedAt: p.UpdatedAt,
+	})
+}
+
+func (c *CommandPostsPush) AfterApply(g *Global) error {
+	c.client = g.newClient(g.Config.Subdomain)
+	c.conv = converter.NewConverter()
+	return nil
+}
+
+func (c *CommandPostsPush) Run(g *Global) (err error) {
+	paths := c.Files
+	if len(paths) == 0 {
+		dir := g.Config.postsDir()
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			return fmt.Errorf("posts directory %s does not exist", dir)
+		}
+		paths = []string{dir}
+	}
+	files, err := expandFiles(g.Config.ContentsDir, paths, isPostFile)
+	if err != nil {
+		return err
+	}
+
+	s, err := g.LoadState()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if serr := s.Save(); serr != nil && err == nil {
+			err = serr
+		}
+	}()
+
+	for _, file := range files {
+		if err := c.push(g, s, file); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// isPostFile reports whether the file has the front matter of a community post.
+func isPostFile(path string) bool {
+	ref, err := parseFileRef(path)
+	return err == nil && ref.Kind == state.KindPost
+}
+
+func (c *CommandPostsPush) push(g *Global, s *state.Store, file string) error {
+	p := &zendesk.Post{}
+	if err := p.FromFile(file); err != nil {
+		return err
+	}
+	if p.TopicID == 0 {
+		return fmt.Errorf("topic_id is not specified")
+	}
+	html, err := p.IsHTML()
+	if err != nil {
+		return err
+	}
+	if !html && !c.Raw {
+		if p.Details, err = c.conv.ConvertToHTML(p.Details); err != nil {
+			return err
+		}
+	}
+	payload, err := p.ToPayload()
+	if err != nil {
+		return err
+	}
+	if c.DryRun {
+		dryRun(p, file)
+		return nil
+	}
+	step := planPush(s, file, payload, p.ID != 0)
+	if step.Action == state.ActionSkip && !c.Force {
+		upToDate(file)
+		return nil
+	}
+
+	var res string
+	if step.Action == state.ActionCreate {
+		res, err = c.client.CreatePost(payload)
+	} else {
+		res, err = c.client.UpdatePost(p.ID, payload)
+	}
+	if err != nil {
+		return err
+	}
+	remote := &zendesk.Post{}
+	if err := remote.FromJson(res); err != nil {
+		return err
+	}
+
+	if step.Action == state.ActionCreate {
+		// the created post is written back to the file with the details as written.
+		local := &zendesk.Post{}
+		if err := local.FromFile(file); err != nil {
+			return err
+		}
+		local.ID = remote.ID
+		local.AuthorID = remote.AuthorID
+		local.HtmlURL = remote.HtmlURL
+		local.CreatedAt = remote.CreatedAt
+		local.UpdatedAt = remote.UpdatedAt
+		if err := local.Save(file, false); err != nil {
+			return err
+		}
+		slog.Info("created", "file", file, "post_id", remote.ID)
+	} else {
+		slog.Info("pushed", "file", file, "post_id", remote.ID)
+	}
+	trackPushed(s, file, state.Entry{
+		Kind:            state.KindPost,
+		Brand:           g.Config.Brand,
+		ArticleID:       remote.ID,
+		RemoteUpdatedAt: remote.UpdatedAt,
+	}, payload)
+	return nil
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tukaelu/zgsync/internal/converter"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+// postsClient has the post 5 in the topic 3, and creates the posts as the post 6.
+type postsClient struct {
+	zendesk.Client
+	requests []string
+}
+
+func (c *postsClient) ShowPost(postID int) (string, error) {
+	c.requests = append(c.requests, fmt.Sprintf("show/%d", postID))
+	return `{"post":{"id":5,"title":"Tips","details":"<p>Hello</p>","topic_id":3,"updated_at":"2024-01-01T00:00:00Z"}}`, nil
+}
+
+func (c *postsClient) ListPosts(topicID int, page int) (string, error) {
+	c.requests = append(c.requests, fmt.Sprintf("list/%d/%d", topicID, page))
+	return `{"posts":[{"id":5,"title":"Tips","details":"<p>Hello</p>","topic_id":3,"updated_at":"2024-01-01T00:00:00Z"}],"next_page":null}`, nil
+}
+
+func (c *postsClient) CreatePost(payload string) (string, error) {
+	c.requests = append(c.requests, "create:"+payload)
+	return `{"post":{"id":6,"title":"New","topic_id":3,"author_id":7,"updated_at":"2024-01-02T00:00:00Z"}}`, nil
+}
+
+func (c *postsClient) UpdatePost(postID int, payload string) (string, error) {
+	c.requests = append(c.requests, fmt.Sprintf("update/%d:%s", postID, payload))
+	return fmt.Sprintf(`{"post":{"id":%d,"title":"Tips","topic_id":3,"updated_at":"2024-01-03T00:00:00Z"}}`, postID), nil
+}
+
+func TestPostsPull(t *testing.T) {
+	dir := t.TempDir()
+	g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja"}}
+	client := &postsClient{}
+	c := &CommandPostsPull{Topic: 3, client: client, conv: converter.NewConverter()}
+	if err := c.Run(g); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "posts", "5.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "topic_id: 3") || !strings.HasSuffix(string(b), "---\nHello") {
+		t.Errorf("Run() failed: got\n%s", b)
+	}
+
+	// the tracked posts are pulled without the arguments, and skipped as they are up to date.
+	c = &CommandPostsPull{client: client, conv: converter.NewConverter()}
+	if err := c.Run(g); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if fmt.Sprint(client.requests) != "[list/3/1 show/5]" {
+		t.Errorf("Run() failed: got %v requested", client.requests)
+	}
+}
+
+func TestPostsPush(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "posts", "new.md")
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, []byte("---\ntitle: New\ntopic_id: 3\n---\nHello\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// an article in the posts directory is not a post.
+	if err := os.WriteFile(filepath.Join(dir, "posts", "1.md"), []byte("---\nid: 1\ntitle: t\nsection_id: 2\n---\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja"}}
+
+	client := &postsClient{}
+	for i := 0; i < 2; i++ {
+		c := &CommandPostsPush{client: client, conv: converter.NewConverter()}
+		if err := c.Run(g); err != nil {
+			t.Fatalf("Run() failed: %v", err)
+		}
+	}
+	expected := `[create:{"post":{"title":"New","topic_id":3,"featured":false,"pinned":false,"closed":false,"details":"\u003cp\u003eHello\u003c/p\u003e\n"}}]`
+	if fmt.Sprint(client.requests) != expected {
+		t.Errorf("Run() failed: got %v, want %s", client.requests, expected)
+	}
+	b, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "id: 6\n") || !strings.HasSuffix(string(b), "Hello\n") {
+		t.Errorf("Run() failed: the created post is not written back\n%s", b)
+	}
+
+	c := &CommandPostsPush{Force: true, client: client, conv: converter.NewConverter()}
+	if err := c.Run(g); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if got := client.requests[len(client.requests)-1]; !strings.HasPrefix(got, "update/6:") {
+		t.Errorf("Run() failed: got %s", got)
+	}
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/tukaelu/zgsync/internal/state"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+type CommandPromote struct {
+	Targets []string       `arg:"" help:"Specify the files or the article IDs to promote."`
+	client  zendesk.Client `kong:"-"`
+}
+
+type CommandDemote struct {
+	Targets []string       `arg:"" help:"Specify the files or the article IDs to demote."`
+	client  zendesk.Client `kong:"-"`
+}
+
+func (c *CommandPromote) AfterApply(g *Global) error {
+	c.client = g.newClient(g.Config.Subdomain)
+	return nil
+}
+
+func (c *CommandPromote) Run(g *Global) error {
+	return setPromoted(g, c.client, c.Targets, true)
+}
+
+func (c *CommandDemote) AfterApply(g *Global) error {
+	c.client = g.newClient(g.Config.Subdomain)
+	return nil
+}
+
+func (c *CommandDemote) Run(g *Global) error {
+	return setPromoted(g, c.client, c.Targets, false)
+}
+
+// setPromoted flips the promoted flag of the articles remotely and reflects it in the tracked article files.
+func setPromoted(g *Global, client zendesk.Client, targets []string, promoted bool) (err error) {
+	s, err := g.LoadState()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if serr := s.Save(); serr != nil && err == nil {
+			err = serr
+		}
+	}()
+
+	payload := fmt.Sprintf(`{"article":{"promoted":%t}}`, promoted)
+	for _, target := range targets {
+		ref, err := resolveFileRef(target)
+		if err != nil {
+			return err
+		}
+		locale := ref.Locale
+		if locale == "" {
+			locale = g.Config.DefaultLocale
+		}
+		res, err := client.UpdateArticle(g.Config.remoteLocale(locale), ref.ID, payload)
+		if err != nil {
+			return fmt.Errorf("article %d: %w", ref.ID, err)
+		}
+		remote := &zendesk.Article{}
+		if err := remote.FromJson(res); err != nil {
+			return err
+		}
+		if !g.Config.isHugo() {
+			if err := updateLocalPromoted(s, g.Config.Brand, ref, promoted, remote.UpdatedAt); err != nil {
+				return err
+			}
+		}
+		if promoted {
+			slog.Info("promoted", "article_id", ref.ID)
+		} else {
+			slog.Info("demoted", "article_id", ref.ID)
+		}
+	}
+	return nil
+}
+
+// updateLocalPromoted rewrites the promoted flag of the tracked article file.
+func updateLocalPromoted(s *state.Store, brand string, ref *fileRef, promoted bool, updatedAt string) error {
+	var path string
+	if ref.Kind == state.KindArticle && ref.Path != "" {
+		path = ref.Path
+	} else if key, _, ok := s.Find(state.KindArticle, brand, ref.ID, ""); ok {
+		path = s.Abs(key)
+	}
+	if path == "" {
+		return nil
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+
+	a := &zendesk.Article{}
+	if err := a.FromFile(path); err != nil {
+		return err
+	}
+	if a.Promoted != nil && *a.Promoted == promoted {
+		return nil
+	}
+	a.Promoted = &promoted
+	if err := a.Save(path, false); err != nil {
+		return err
+	}
+
+	if e, ok := s.Lookup(path); ok {
+		hash, err := state.HashFile(path)
+		if err != nil {
+			return err
+		}
+		e.PulledHash = hash
+		e.RemoteUpdatedAt = updatedAt
+	}
+	return nil
+}
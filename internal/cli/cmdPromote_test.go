@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+type promoteClient struct {
+	zendesk.Client
+	payloads []string
+}
+
+func (c *promoteClient) UpdateArticle(locale string, articleID int, payload string) (string, error) {
+	c.payloads = append(c.payloads, fmt.Sprintf("%s/%d:%s", locale, articleID, payload))
+	return fmt.Sprintf(`{"article":{"id":%d,"updated_at":"2024-02-01T00:00:00Z"}}`, articleID), nil
+}
+
+func TestSetPromoted(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "1.md")
+	if err := os.WriteFile(file, []byte("---\nid: 1\ntitle: t\nlocale: en-us\npromoted: false\n---\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja"}}
+	client := &promoteClient{}
+
+	if err := setPromoted(g, client, []string{file, "2"}, true); err != nil {
+		t.Fatalf("setPromoted() failed: %v", err)
+	}
+	expected := `[en-us/1:{"article":{"promoted":true}} ja/2:{"article":{"promoted":true}}]`
+	if fmt.Sprint(client.payloads) != expected {
+		t.Errorf("setPromoted() failed: got %v, want %s", client.payloads, expected)
+	}
+	b, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "promoted: true") {
+		t.Errorf("setPromoted() failed: promoted is not written to the file\n%s", b)
+	}
+
+	client = &promoteClient{}
+	if err := setPromoted(g, client, []string{file}, false); err != nil {
+		t.Fatalf("setPromoted() failed: %v", err)
+	}
+	if b, _ := os.ReadFile(file); !strings.Contains(string(b), "promoted: false") {
+		t.Errorf("setPromoted() failed: the article is not demoted in the file\n%s", b)
+	}
+}
@@ -0,0 +1,235 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/tukaelu/zgsync/internal/state"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+type CommandPublish struct {
+	Locale     string         `name:"locale" short:"l" help:"Specify the locale to publish. If not specified, the locale of the file or the default locale will be used."`
+	AllLocales bool           `name:"all-locales" short:"A" help:"It publishes the translations of all locales of the article."`
+	Due        bool           `name:"due" help:"It publishes the draft translations in the contents directory whose publish_at has passed."`
+	Targets    []string       `arg:"" optional:"" help:"Specify the files or the article IDs to publish."`
+	client     zendesk.Client `kong:"-"`
+}
+
+type CommandUnpublish struct {
+	Locale     string         `name:"locale" short:"l" help:"Specify the locale to unpublish. If not specified, the locale of the file or the default locale will be used."`
+	AllLocales bool           `name:"all-locales" short:"A" help:"It unpublishes the translations of all locales of the article."`
+	Confirm    Confirm        `embed:""`
+	Targets    []string       `arg:"" help:"Specify the files or the article IDs to unpublish."`
+	client     zendesk.Client `kong:"-"`
+}
+
+func (c *CommandPublish) AfterApply(g *Global) error {
+	c.client = g.newClient(g.Config.Subdomain)
+	return nil
+}
+
+func (c *CommandPublish) Run(g *Global) error {
+	targets := c.Targets
+	if c.Due {
+		due, err := dueTranslations(g.Config.ContentsDir, time.Now())
+		if err != nil {
+			return err
+		}
+		targets = append(targets, due...)
+	}
+	if len(targets) == 0 {
+		if !c.Due {
+			return fmt.Errorf("no files or article IDs are specified")
+		}
+		return nil
+	}
+	d := &drafter{client: c.client, locale: c.Locale, allLocales: c.AllLocales}
+	return d.run(g, targets, false)
+}
+
+// dueTranslations returns the draft translation files whose publish_at is not after now.
+func dueTranslations(contentsDir string, now time.Time) ([]string, error) {
+	files, err := expandFiles(contentsDir, []string{contentsDir}, isTranslationFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var due []string
+	for _, file := range files {
+		t := &zendesk.Translation{}
+		if err := t.FromFile(file); err != nil || !t.Draft {
+			continue
+		}
+		pt, err := t.PublishTime()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		if !pt.IsZero() && !pt.After(now) {
+			due = append(due, file)
+		}
+	}
+	return due, nil
+}
+
+func (c *CommandUnpublish) AfterApply(g *Global) error {
+	c.client = g.newClient(g.Config.Subdomain)
+	c.Confirm.interactive = isTerminal(os.Stdin)
+	return nil
+}
+
+func (c *CommandUnpublish) Run(g *Global) error {
+	var affected []string
+	for _, target := range c.Targets {
+		ref, err := resolveFileRef(target)
+		if err != nil {
+			return err
+		}
+		locale := "all locales"
+		switch {
+		case c.AllLocales:
+		case c.Locale != "":
+			locale = c.Locale
+		case ref.Locale != "":
+			locale = ref.Locale
+		default:
+			locale = g.Config.DefaultLocale
+		}
+		affected = append(affected, fmt.Sprintf("%s: article %d in %s", target, ref.ID, locale))
+	}
+	ok, err := c.Confirm.confirm("Unpublish", affected)
+	if err != nil || !ok {
+		if err == nil {
+			slog.Info("cancelled")
+		}
+		return err
+	}
+
+	d := &drafter{client: c.client, locale: c.Locale, allLocales: c.AllLocales}
+	return d.run(g, c.Targets, true)
+}
+
+// drafter flips the draft flag of translations remotely and reflects it in the tracked local files.
+type drafter struct {
+	client     zendesk.Client
+	locale     string
+	allLocales bool
+}
+
+func (d *drafter) run(g *Global, targets []string, draft bool) (err error) {
+	s, err := g.LoadState()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if serr := s.Save(); serr != nil && err == nil {
+			err = serr
+		}
+	}()
+
+	for _, target := range targets {
+		ref, err := resolveFileRef(target)
+		if err != nil {
+			return err
+		}
+		if err := d.setDraft(g, s, ref, draft); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *drafter) setDraft(g *Global, s *state.Store, ref *fileRef, draft bool) error {
+	var locales []string
+	switch {
+	case d.allLocales:
+		translations, err := listTranslations(d.client, ref.ID)
+		if err != nil {
+			return err
+		}
+		for _, t := range translations {
+			locales = append(locales, t.Locale)
+		}
+	case d.locale != "":
+		locales = []string{d.locale}
+	case ref.Locale != "":
+		locales = []string{ref.Locale}
+	default:
+		locales = []string{g.Config.DefaultLocale}
+	}
+
+	payload := fmt.Sprintf(`{"translation":{"draft":%t}}`, draft)
+	for _, locale := range locales {
+		res, err := d.client.UpdateTranslation(ref.ID, locale, payload)
+		if err != nil {
+			return err
+		}
+		remote := &zendesk.Translation{}
+		if err := remote.FromJson(res); err != nil {
+			return err
+		}
+		if err := updateLocalDraft(s, g.Config.Brand, ref, locale, draft, remote.UpdatedAt); err != nil {
+			return err
+		}
+		if draft {
+			slog.Info("unpublished", "article_id", ref.ID, "locale", locale)
+		} else {
+			slog.Info("published", "article_id", ref.ID, "locale", locale)
+		}
+	}
+	return nil
+}
+
+// updateLocalDraft rewrites the draft flag of the tracked translation file so that a later push does not revert it.
+func updateLocalDraft(s *state.Store, brand string, ref *fileRef, locale string, draft bool, updatedAt string) error {
+	var path string
+	if ref.Kind == state.KindTranslation && ref.Locale == locale {
+		path = ref.Path
+	} else if key, _, ok := s.Find(state.KindTranslation, brand, ref.ID, locale); ok {
+		path = s.Abs(key)
+	}
+	return updateLocalTranslation(s, path, updatedAt, func(t *zendesk.Translation) bool {
+		if t.Draft == draft {
+			return false
+		}
+		t.Draft = draft
+		return true
+	})
+}
+
+// updateLocalTranslation rewrites the tracked translation file by update.
+func updateLocalTranslation(s *state.Store, path string, updatedAt string, update func(t *zendesk.Translation) bool) error {
+	if path == "" {
+		return nil
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+
+	t := &zendesk.Translation{}
+	if err := t.FromFile(path); err != nil {
+		return err
+	}
+	if !update(t) {
+		return nil
+	}
+	if updatedAt != "" {
+		t.UpdatedAt = updatedAt
+	}
+	if err := t.Save(path, false); err != nil {
+		return err
+	}
+
+	if e, ok := s.Lookup(path); ok {
+		hash, err := state.HashFile(path)
+		if err != nil {
+			return err
+		}
+		e.PulledHash = hash
+		e.RemoteUpdatedAt = updatedAt
+		e.PulledAt = time.Now().UTC().Format(time.RFC3339)
+	}
+	return nil
+}
@@ -2,10 +2,17 @@ package cli
 
 import (
 	"fmt"
+	"log/slog"
 	"path/filepath"
+	"slices"
 	"strconv"
+	"sync"
+	"time"
 
+	"github.com/tukaelu/zgsync/internal/audit"
 	"github.com/tukaelu/zgsync/internal/converter"
+	"github.com/tukaelu/zgsync/internal/metrics"
+	"github.com/tukaelu/zgsync/internal/state"
 	"github.com/tukaelu/zgsync/internal/zendesk"
 )
 
@@ -13,63 +20,479 @@ type CommandPull struct {
 	Locale         string              `name:"locale" short:"l" help:"Specify the locale to pull. If not specified, the default locale will be used."`
 	Raw            bool                `name:"raw" help:"It pulls raw data without converting it from HTML to Markdown."`
 	SaveArticle    bool                `name:"save-article" short:"a" help:"It pulls and saves the article in addition to the translation."`
-	WithSectionDir bool                `name:"with-section-dir" short:"S" help:"A .md file will be created in the section ID directory."`
-	ArticleIDs     []int               `arg:"" help:"Specify the article IDs to pull." type:"int"`
+	WithSectionDir bool                `name:"with-section-dir" short:"S" help:"A .md file will be created in the section ID directory." xor:"layout"`
+	Hierarchy      bool                `name:"hierarchy" short:"H" help:"Files will be created in directories mirroring the category and section hierarchy." xor:"layout"`
+	RetryFailed    bool                `name:"retry-failed" help:"It pulls only the articles that failed in the previous runs."`
+	Outdated       bool                `name:"outdated" help:"It pulls only the translations marked as outdated."`
+	Since          string              `name:"since" help:"It pulls only the articles updated after the time, such as 2024-07-01, 24h or 'last'."`
+	All            bool                `name:"all" help:"It pulls all the articles of the help center."`
+	Concurrency    int                 `name:"concurrency" short:"j" help:"Specify the maximum number of articles pulled in parallel." default:"8"`
+	Force          bool                `name:"force" short:"f" help:"It pulls even if neither the remote nor the local file has changed since the last pull."`
+	ArticleIDs     []int               `arg:"" optional:"" help:"Specify the article IDs to pull." type:"int"`
+	Retry          Retry               `embed:""`
 	client         zendesk.Client      `kong:"-"`
 	converter      converter.Converter `kong:"-"`
 }
 
 func (c *CommandPull) AfterApply(g *Global) error {
-	c.client = zendesk.NewClient(g.Config.Subdomain, g.Config.Email, g.Config.Token)
+	c.client = g.newUncachedClient(g.Config.Subdomain)
 	c.converter = converter.NewConverter()
 	return nil
 }
 
-func (c *CommandPull) Run(g *Global) error {
+func (c *CommandPull) Run(g *Global) (err error) {
 	if c.Locale == "" {
 		c.Locale = g.Config.DefaultLocale
 	}
+	c.Locale = g.Config.remoteLocale(c.Locale)
+	local := g.Config.localLocale(c.Locale)
 
-	for _, articleID := range c.ArticleIDs {
-		res, err := c.client.ShowArticle(c.Locale, articleID)
+	failed, err := g.LoadFailed()
+	if err != nil {
+		return err
+	}
+	if c.All && (len(c.ArticleIDs) > 0 || c.RetryFailed || c.Since != "") {
+		return fmt.Errorf("--all cannot be specified with the article IDs, --retry-failed or --since")
+	}
+	if c.RetryFailed {
+		if len(c.ArticleIDs) > 0 {
+			return fmt.Errorf("article IDs cannot be specified with --retry-failed")
+		}
+		for _, item := range failed.Pull {
+			c.ArticleIDs = append(c.ArticleIDs, item.ArticleID)
+		}
+		if len(c.ArticleIDs) == 0 {
+			slog.Info("no failed articles to retry")
+			return nil
+		}
+	}
+
+	s, err := g.LoadState()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if serr := s.Save(); serr != nil && err == nil {
+			err = serr
+		}
+	}()
+
+	var since time.Time
+	cursor := "pull:" + c.Locale
+	if c.Since != "" {
+		if len(c.ArticleIDs) > 0 || c.RetryFailed {
+			return fmt.Errorf("--since cannot be specified with the article IDs or --retry-failed")
+		}
+		now := time.Now()
+		if since, err = parseSince(c.Since, s.Cursors[cursor], now); err != nil {
+			return err
+		}
+		if c.ArticleIDs, err = c.updatedArticles(since); err != nil {
+			return err
+		}
+		// the articles updated while pulling are pulled again by the next run.
+		defer func() {
+			if err == nil {
+				s.Cursors[cursor] = now.UTC().Format(time.RFC3339)
+			}
+		}()
+		if len(c.ArticleIDs) == 0 {
+			slog.Info("no articles are updated", "since", since.Format(time.RFC3339))
+			return nil
+		}
+	}
+	// the articles listed with their translations by --all are not fetched one by one.
+	prefetched := map[int]*zendesk.Article{}
+	if c.All {
+		var articles []zendesk.Article
+		stop := spin("listing the articles")
+		err := c.Retry.do(func() (err error) {
+			articles, err = listArticles(func(page int) (string, error) {
+				return c.client.ListArticlesWithTranslations(c.Locale, page)
+			})
+			return err
+		})
+		stop()
+		if err != nil {
+			return err
+		}
+		for i := range articles {
+			c.ArticleIDs = append(c.ArticleIDs, articles[i].ID)
+			prefetched[articles[i].ID] = &articles[i]
+		}
+		if len(c.ArticleIDs) == 0 {
+			slog.Info("no articles to pull")
+			return nil
+		}
+	}
+	if c.Outdated && len(c.ArticleIDs) == 0 && c.Since == "" && !c.RetryFailed {
+		c.ArticleIDs = trackedArticleIDs(s, g.Config.Brand)
+	}
+	if len(c.ArticleIDs) == 0 {
+		return fmt.Errorf("specify the article IDs to pull")
+	}
+
+	articleTmpl, translationTmpl, err := g.Config.frontMatterTemplates()
+	if err != nil {
+		return err
+	}
+
+	names, err := newFileNamer(g.Config.FilenameTemplate, g.Config.ArticleFilenameTemplate, c.Hierarchy)
+	if err != nil {
+		return err
+	}
+
+	var hierarchy *hierarchyResolver
+	if c.Hierarchy {
+		if hierarchy, err = newHierarchyResolver(c.client, c.Locale, g.Config.HierarchyLayout); err != nil {
+			return err
+		}
+	}
+
+	job := &pullJob{
+		g:               g,
+		s:               s,
+		failed:          failed,
+		since:           since,
+		local:           local,
+		names:           names,
+		hierarchy:       hierarchy,
+		articleTmpl:     articleTmpl,
+		translationTmpl: translationTmpl,
+		prefetched:      prefetched,
+		done:            map[int]bool{},
+		errs:            map[int]error{},
+	}
+
+	// the articles failed are recorded in the audit log and in the failed items with the articles left by the failure.
+	defer func() {
+		if err != nil {
+			for _, id := range c.ArticleIDs {
+				if e, ok := job.errs[id]; ok {
+					g.audit(audit.Record{Command: "pull", ArticleID: id, Locale: local, Result: audit.ResultFailed, Error: e.Error()})
+					failed.Pull.Put(state.FailedItem{ArticleID: id, Error: e.Error()})
+				} else if !job.done[id] {
+					failed.Pull.Put(state.FailedItem{ArticleID: id})
+				}
+			}
+		}
+		if ferr := failed.Save(); ferr != nil && err == nil {
+			err = ferr
+		}
+	}()
+
+	defer func() {
+		if c.Outdated && err == nil {
+			slog.Info("summary", "articles", len(c.ArticleIDs), "outdated", job.outdated)
+		}
+	}()
+
+	// a single article is pulled by the requests in a row, which may take long for a large article.
+	if len(c.ArticleIDs) == 1 {
+		defer spin(fmt.Sprintf("pulling article %d", c.ArticleIDs[0]))()
+	}
+	// the rate limits retried by --max-retries are reported to the pool too.
+	pool := newRatePool(c.client, c.Concurrency)
+	c.Retry.observe = pool.report
+	return pool.run(c.ArticleIDs, func(articleID int) error {
+		err := c.pullArticle(job, articleID)
+		job.mu.Lock()
+		defer job.mu.Unlock()
 		if err != nil {
+			job.errs[articleID] = err
 			return err
 		}
-		a := &zendesk.Article{}
+		delete(job.errs, articleID)
+		job.done[articleID] = true
+		job.failed.Pull.Delete("", articleID)
+		return nil
+	})
+}
+
+// pullJob is the state of a pull shared by the articles pulled in parallel.
+type pullJob struct {
+	g               *Global
+	s               *state.Store
+	failed          *state.Failed
+	since           time.Time
+	local           string
+	names           *fileNamer
+	hierarchy       *hierarchyResolver
+	articleTmpl     *zendesk.FrontMatterTemplate
+	translationTmpl *zendesk.FrontMatterTemplate
+	prefetched      map[int]*zendesk.Article
+
+	// mu guards the fields below, the sync state, the failed items, the hierarchy and the audit log.
+	mu       sync.Mutex
+	done     map[int]bool
+	errs     map[int]error
+	outdated int
+}
+
+// pullArticle pulls the translation of the article, and the article with --save-article.
+func (c *CommandPull) pullArticle(j *pullJob, articleID int) error {
+	g, local := j.g, j.local
+	var res string
+	a, prefetched := j.prefetched[articleID]
+	if !prefetched {
+		err := c.Retry.do(func() (err error) {
+			res, err = c.client.ShowArticle(c.Locale, articleID)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		a = &zendesk.Article{}
 		if err := a.FromJson(res); err != nil {
 			return err
 		}
+	}
+
+	if c.Outdated && !slices.Contains(a.OutdatedLocales, c.Locale) {
+		slog.Debug("translation is not outdated", "article_id", articleID, "locale", local)
+		return nil
+	}
+	j.mu.Lock()
+	j.outdated++
+	j.mu.Unlock()
+
+	a.Locale = g.Config.localLocale(a.Locale)
+	a.Slug = zendesk.SlugFromURL(a.HtmlURL)
+	a.Brand = g.Config.Brand
 
-		saveDirPath := g.Config.ContentsDir
-		if c.WithSectionDir {
-			saveDirPath = filepath.Join(g.Config.ContentsDir, strconv.Itoa(a.SectionID))
+	data := newLayoutData(a, local)
+	saveDirPath := g.Config.ContentsDir
+	if c.WithSectionDir {
+		saveDirPath = filepath.Join(g.Config.ContentsDir, strconv.Itoa(a.SectionID))
+	}
+	if j.hierarchy != nil {
+		var dir string
+		err := c.Retry.do(func() (err error) {
+			j.mu.Lock()
+			defer j.mu.Unlock()
+			dir, err = j.hierarchy.Dir(&data)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if saveDirPath, err = layoutPath(g.Config.ContentsDir, dir); err != nil {
+			return err
 		}
+	}
 
-		if c.SaveArticle {
-			if err = a.Save(saveDirPath, true); err != nil {
+	if c.SaveArticle {
+		name, err := j.names.Article(data)
+		if err != nil {
+			return err
+		}
+		path, err := layoutPath(saveDirPath, name)
+		if err != nil {
+			return err
+		}
+		skip, err := c.isUpToDate(j, path, a.UpdatedAt)
+		if err != nil {
+			return err
+		}
+		if !skip {
+			if err = a.SaveWithTemplate(path, false, j.articleTmpl); err != nil {
 				return fmt.Errorf("failed to save the article: %w", err)
 			}
+			metrics.Pulls.Inc()
+			j.mu.Lock()
+			g.audit(audit.Record{Command: "pull", File: path, ArticleID: a.ID, Result: audit.ResultPulled})
+			err = trackPulled(j.s, path, state.Entry{
+				Kind:            state.KindArticle,
+				Brand:           g.Config.Brand,
+				ArticleID:       a.ID,
+				SectionID:       a.SectionID,
+				RemoteUpdatedAt: a.UpdatedAt,
+			})
+			j.mu.Unlock()
+			if err != nil {
+				return err
+			}
 		}
+	}
 
-		res, err = c.client.ShowTranslation(articleID, c.Locale)
+	t, ok := a.Translation(c.Locale)
+	if !ok {
+		err := c.Retry.do(func() (err error) {
+			res, err = c.client.ShowTranslation(articleID, c.Locale)
+			return err
+		})
+		if !j.since.IsZero() && zendesk.IsNotFound(err) {
+			slog.Debug("no translation", "article_id", articleID, "locale", local)
+			return nil
+		}
 		if err != nil {
 			return err
 		}
-		t := &zendesk.Translation{}
+		t = &zendesk.Translation{}
 		if err := t.FromJson(res); err != nil {
 			return err
 		}
-		t.SectionID = a.SectionID
+	}
+	if !j.since.IsZero() && !updatedSince(t.UpdatedAt, j.since) {
+		slog.Debug("translation is not updated", "article_id", articleID, "locale", local)
+		return nil
+	}
+	t.SectionID = a.SectionID
+	t.AuthorID = a.AuthorID
+	t.Slug = zendesk.SlugFromURL(t.HtmlURL)
+	t.Locale = local
+	t.Brand = g.Config.Brand
 
-		if !c.Raw {
-			if t.Body, err = c.converter.ConvertToMarkdown(t.Body); err != nil {
-				return err
+	name, err := j.names.Translation(data)
+	if err != nil {
+		return err
+	}
+	path, err := layoutPath(saveDirPath, name)
+	if err != nil {
+		return err
+	}
+	if skip, err := c.isUpToDate(j, path, t.UpdatedAt); err != nil || skip {
+		return err
+	}
+
+	// the links to the assets of the local file are kept as they are.
+	if t.AssetsDir = localAssetsDir(path); t.AssetsDir != "" {
+		j.mu.Lock()
+		t.Body = restoreAssets(j.s, path, t.Body)
+		j.mu.Unlock()
+	}
+	// the raw HTML body of the local file is kept as HTML.
+	if t.BodyFormat = localBodyFormat(path); t.BodyFormat != zendesk.BodyFormatHTML && !c.Raw {
+		if t.Body, err = c.converter.ConvertToMarkdown(t.Body); err != nil {
+			metrics.ConversionFailures.Inc()
+			return err
+		}
+	}
+	if err = t.SaveWithTemplate(path, false, j.translationTmpl); err != nil {
+		return fmt.Errorf("failed to save the translation: %w", err)
+	}
+	metrics.Pulls.Inc()
+	switch {
+	case c.Outdated:
+		slog.Info("outdated", "file", path, "article_id", articleID, "locale", local)
+	case t.Outdated:
+		slog.Warn("the translation is outdated", "file", path, "article_id", articleID, "locale", local)
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	g.audit(audit.Record{Command: "pull", File: path, ArticleID: articleID, Locale: local, Result: audit.ResultPulled})
+	return trackPulled(j.s, path, state.Entry{
+		Kind:            state.KindTranslation,
+		Brand:           g.Config.Brand,
+		ArticleID:       articleID,
+		Locale:          c.Locale,
+		SectionID:       t.SectionID,
+		RemoteUpdatedAt: t.UpdatedAt,
+	})
+}
+
+// isUpToDate reports whether neither the remote nor the file has changed since the last pull.
+func (c *CommandPull) isUpToDate(j *pullJob, file string, updatedAt string) (bool, error) {
+	if c.Force {
+		return false, nil
+	}
+	j.mu.Lock()
+	step, err := planPull(j.s, file, updatedAt)
+	j.mu.Unlock()
+	if err != nil || step.Action != state.ActionSkip {
+		return false, err
+	}
+	upToDate(file)
+	return true, nil
+}
+
+// updatedArticles returns the IDs of the articles updated after since with the incremental export.
+func (c *CommandPull) updatedArticles(since time.Time) ([]int, error) {
+	articles, err := articlesSince(since, func(start int64) (res string, err error) {
+		err = c.Retry.do(func() (err error) {
+			res, err = c.client.ListArticlesSince(start)
+			return err
+		})
+		return res, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, 0, len(articles))
+	for _, a := range articles {
+		ids = append(ids, a.ID)
+	}
+	return ids, nil
+}
+
+// articlesSince returns the articles updated after since, paging the incremental export with list.
+func articlesSince(since time.Time, list func(start int64) (string, error)) ([]zendesk.Article, error) {
+	var articles []zendesk.Article
+	seen := map[int]bool{}
+	for start := since.Unix(); ; {
+		res, err := list(start)
+		if err != nil {
+			return nil, err
+		}
+		page, end, next, err := zendesk.IncrementalArticlesFromJson(res)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range page {
+			// the export includes the articles updated exactly at the start time.
+			if !seen[a.ID] && updatedSince(a.UpdatedAt, since) {
+				seen[a.ID] = true
+				articles = append(articles, a)
 			}
 		}
+		if !next || end <= start {
+			return articles, nil
+		}
+		start = end
+	}
+}
+
+// updatedSince reports whether updatedAt is after since. The time that cannot be parsed is regarded as updated.
+func updatedSince(updatedAt string, since time.Time) bool {
+	t, err := time.Parse(time.RFC3339, updatedAt)
+	return err != nil || t.After(since)
+}
+
+var sinceLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
 
-		if err = t.Save(saveDirPath, true); err != nil {
-			return fmt.Errorf("failed to save the translation: %w", err)
+// localBodyFormat returns the body_format of the local translation, which is empty if it does not exist.
+func localBodyFormat(path string) string {
+	t := &zendesk.Translation{}
+	if err := t.FromFile(path); err != nil {
+		return ""
+	}
+	return t.BodyFormat
+}
+
+// parseSince parses the value of --since. last is the time of the last pull recorded in the sync state.
+func parseSince(value string, last string, now time.Time) (time.Time, error) {
+	if value == "last" {
+		if last == "" {
+			return time.Time{}, fmt.Errorf("no pull with --since is recorded yet; specify the time")
 		}
+		return time.Parse(time.RFC3339, last)
 	}
-	return nil
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	if unix, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(unix, 0), nil
+	}
+	for _, layout := range sinceLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid --since: %s", value)
 }
@@ -0,0 +1,332 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tukaelu/zgsync/internal/converter"
+	"github.com/tukaelu/zgsync/internal/state"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value    string
+		last     string
+		expected time.Time
+		wantErr  bool
+	}{
+		{"2024-02-01T00:00:00Z", "", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), false},
+		{"2024-02-01", "", time.Date(2024, 2, 1, 0, 0, 0, 0, time.Local), false},
+		{"1706745600", "", time.Unix(1706745600, 0), false},
+		{"24h", "", time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC), false},
+		{"last", "2024-02-15T00:00:00Z", time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC), false},
+		{"last", "", time.Time{}, true},
+		{"yesterday", "", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseSince(tt.value, tt.last, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSince() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.expected) {
+				t.Errorf("parseSince() failed: got %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+// pullClient exports the articles in two pages and has the ja translations of the articles in translations,
+// which are outdated for the articles in outdated. shows counts the requests of the articles and the translations.
+type pullClient struct {
+	zendesk.Client
+	translations map[int]string
+	outdated     map[int]bool
+	starts       []int64
+	shows        atomic.Int32
+}
+
+func (c *pullClient) ListArticlesSince(startTime int64) (string, error) {
+	c.starts = append(c.starts, startTime)
+	if len(c.starts) == 1 {
+		return `{"articles":[{"id":1,"updated_at":"2024-02-01T00:00:00Z"},{"id":2,"updated_at":"2024-02-02T00:00:00Z"}],"next_page":"next","end_time":1706832000}`, nil
+	}
+	return `{"articles":[{"id":2,"updated_at":"2024-02-02T00:00:00Z"},{"id":3,"updated_at":"2024-02-03T00:00:00Z"}],"next_page":null,"end_time":1706918400}`, nil
+}
+
+// ListArticlesWithTranslations lists the articles 1 and 2 in the first page and 3 in the second page.
+func (c *pullClient) ListArticlesWithTranslations(locale string, page int) (string, error) {
+	ids := []int{1, 2}
+	next := any("next")
+	if page > 1 {
+		ids, next = []int{3}, nil
+	}
+	var articles []map[string]any
+	for _, id := range ids {
+		var translations []map[string]any
+		if updatedAt, ok := c.translations[id]; ok {
+			translations = append(translations, map[string]any{"source_id": id, "locale": locale, "title": fmt.Sprintf("t%d", id), "body": "<p>body</p>", "updated_at": updatedAt})
+		}
+		articles = append(articles, map[string]any{"id": id, "locale": locale, "section_id": 10, "translations": translations})
+	}
+	b, err := json.Marshal(map[string]any{"articles": articles, "next_page": next})
+	return string(b), err
+}
+
+func (c *pullClient) ShowArticle(locale string, articleID int) (string, error) {
+	c.shows.Add(1)
+	var outdated []string
+	if c.outdated[articleID] {
+		outdated = []string{"ja"}
+	}
+	b, err := json.Marshal(map[string]any{"article": map[string]any{"id": articleID, "locale": locale, "section_id": 10, "outdated_locales": outdated}})
+	return string(b), err
+}
+
+func (c *pullClient) ShowTranslation(articleID int, locale string) (string, error) {
+	c.shows.Add(1)
+	updatedAt, ok := c.translations[articleID]
+	if !ok {
+		return "", &zendesk.StatusError{StatusCode: 404}
+	}
+	return fmt.Sprintf(`{"translation":{"source_id":%d,"locale":%q,"title":"t%d","body":"<p>body</p>","updated_at":%q}}`, articleID, locale, articleID, updatedAt), nil
+}
+
+func TestPullSince(t *testing.T) {
+	dir := t.TempDir()
+	g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja"}}
+	client := &pullClient{translations: map[int]string{
+		1: "2024-02-01T00:00:00Z",
+		2: "2023-12-01T00:00:00Z",
+	}}
+	c := &CommandPull{Since: "2024-01-01T00:00:00Z", client: client, converter: converter.NewConverter()}
+	if err := c.Run(g); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if fmt.Sprint(client.starts) != "[1704067200 1706832000]" {
+		t.Errorf("Run() failed: got start times %v", client.starts)
+	}
+	if fmt.Sprint(c.ArticleIDs) != "[1 2 3]" {
+		t.Errorf("Run() failed: got %v articles, want [1 2 3]", c.ArticleIDs)
+	}
+	// the translation of 2 is not updated and 3 has no translation.
+	for name, exists := range map[string]bool{"1-ja.md": true, "2-ja.md": false, "3-ja.md": false} {
+		if _, err := os.Stat(filepath.Join(dir, name)); (err == nil) != exists {
+			t.Errorf("Run() failed: %s exists = %v, want %v", name, err == nil, exists)
+		}
+	}
+
+	s, err := g.LoadState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	last, err := time.Parse(time.RFC3339, s.Cursors["pull:ja"])
+	if err != nil || time.Since(last) > time.Minute {
+		t.Errorf("Run() failed: unexpected cursor %q", s.Cursors["pull:ja"])
+	}
+
+	client = &pullClient{}
+	c = &CommandPull{Since: "last", client: client, converter: converter.NewConverter()}
+	if err := c.Run(g); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if len(client.starts) == 0 || client.starts[0] != last.Unix() {
+		t.Errorf("Run() failed: got start times %v, want from %d", client.starts, last.Unix())
+	}
+}
+
+func TestPullOutdated(t *testing.T) {
+	dir := t.TempDir()
+	g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja"}}
+	s, err := g.LoadState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []int{1, 2} {
+		e := s.Get(filepath.Join(dir, fmt.Sprintf("%d-ja.md", id)))
+		e.Kind = state.KindTranslation
+		e.ArticleID = id
+	}
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	client := &pullClient{
+		translations: map[int]string{1: "2024-02-01T00:00:00Z", 2: "2024-02-01T00:00:00Z"},
+		outdated:     map[int]bool{2: true},
+	}
+	c := &CommandPull{Outdated: true, client: client, converter: converter.NewConverter()}
+	if err := c.Run(g); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	for name, exists := range map[string]bool{"1-ja.md": false, "2-ja.md": true} {
+		if _, err := os.Stat(filepath.Join(dir, name)); (err == nil) != exists {
+			t.Errorf("Run() failed: %s exists = %v, want %v", name, err == nil, exists)
+		}
+	}
+}
+
+func TestPullBodyFormat(t *testing.T) {
+	dir := t.TempDir()
+	g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja"}}
+	path := filepath.Join(dir, "1-ja.md")
+	if err := os.WriteFile(path, []byte("---\ntitle: t1\nlocale: ja\nsource_id: 1\nbody_format: html\n---\n<p>old</p>"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	client := &pullClient{translations: map[int]string{1: "2024-02-01T00:00:00Z", 2: "2024-02-01T00:00:00Z"}}
+	c := &CommandPull{ArticleIDs: []int{1, 2}, client: client, converter: converter.NewConverter()}
+	if err := c.Run(g); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	// the body of the HTML file is kept as HTML, and the others are converted to Markdown.
+	for name, expected := range map[string]string{"1-ja.md": "<p>body</p>", "2-ja.md": "body"} {
+		tr := &zendesk.Translation{}
+		if err := tr.FromFile(filepath.Join(dir, name)); err != nil {
+			t.Fatal(err)
+		}
+		if tr.Body != expected {
+			t.Errorf("Run() failed: got %q, want %q in %s", tr.Body, expected, name)
+		}
+	}
+}
+
+func TestPullAll(t *testing.T) {
+	dir := t.TempDir()
+	g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja"}}
+	client := &pullClient{translations: map[int]string{1: "2024-02-01T00:00:00Z", 2: "2024-02-01T00:00:00Z"}}
+	c := &CommandPull{All: true, Concurrency: 3, client: client, converter: converter.NewConverter()}
+	// the translation of 3 does not exist.
+	if err := c.Run(g); err == nil {
+		t.Fatal("Run() should fail")
+	}
+	for name, exists := range map[string]bool{"1-ja.md": true, "2-ja.md": true, "3-ja.md": false} {
+		if _, err := os.Stat(filepath.Join(dir, name)); (err == nil) != exists {
+			t.Errorf("Run() failed: %s exists = %v, want %v", name, err == nil, exists)
+		}
+	}
+	failed, err := g.LoadFailed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(failed.Pull) != 1 || failed.Pull[0].ArticleID != 3 || failed.Pull[0].Error == "" {
+		t.Errorf("Run() failed: got failed items %+v", failed.Pull)
+	}
+	s, err := g.LoadState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Files) != 2 {
+		t.Errorf("Run() failed: got %d tracked files, want 2", len(s.Files))
+	}
+	// only the translation of 3 missing in the list is requested.
+	if shows := client.shows.Load(); shows != 1 {
+		t.Errorf("Run() failed: got %d requests of the articles and the translations, want 1", shows)
+	}
+
+	c = &CommandPull{All: true, ArticleIDs: []int{1}, client: client, converter: converter.NewConverter()}
+	if err := c.Run(g); err == nil {
+		t.Error("Run() should fail with the article IDs")
+	}
+}
+
+// rateLimitedPullClient is rate limited on the first lookup of the translation of the article limited.
+type rateLimitedPullClient struct {
+	pullClient
+	limited int
+	limits  atomic.Int32
+}
+
+func (c *rateLimitedPullClient) ShowTranslation(articleID int, locale string) (string, error) {
+	if articleID == c.limited && c.limits.Add(1) == 1 {
+		return "", &zendesk.StatusError{StatusCode: 429, RetryAfter: 3 * time.Second}
+	}
+	return c.pullClient.ShowTranslation(articleID, locale)
+}
+
+func TestPullRateLimited(t *testing.T) {
+	var waits []time.Duration
+	var mu sync.Mutex
+	sleep = func(d time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		waits = append(waits, d)
+	}
+	defer func() { sleep = time.Sleep }()
+
+	dir := t.TempDir()
+	g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja"}}
+	updatedAt := "2024-02-01T00:00:00Z"
+	client := &rateLimitedPullClient{pullClient: pullClient{translations: map[int]string{1: updatedAt, 2: updatedAt, 3: updatedAt}}, limited: 2}
+	// the article rate limited in the middle is pulled again without --max-retries instead of failing the pull.
+	c := &CommandPull{ArticleIDs: []int{1, 2, 3}, Concurrency: 2, client: client, converter: converter.NewConverter()}
+	if err := c.Run(g); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	for _, name := range []string{"1-ja.md", "2-ja.md", "3-ja.md"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("Run() failed: %s is not pulled: %v", name, err)
+		}
+	}
+	if limits := client.limits.Load(); limits != 2 {
+		t.Errorf("Run() failed: got %d lookups of the article rate limited, want 2", limits)
+	}
+	if fmt.Sprint(waits) != "[3s]" {
+		t.Errorf("Run() failed: got %v waits, want Retry-After", waits)
+	}
+	failed, err := g.LoadFailed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(failed.Pull) != 0 {
+		t.Errorf("Run() failed: got failed items %+v", failed.Pull)
+	}
+}
+
+func TestPullUnchanged(t *testing.T) {
+	dir := t.TempDir()
+	g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja"}}
+	client := &pullClient{translations: map[int]string{1: "2024-02-01T00:00:00Z"}}
+	c := &CommandPull{ArticleIDs: []int{1}, client: client, converter: converter.NewConverter()}
+	if err := c.Run(g); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	path := filepath.Join(dir, "1-ja.md")
+
+	tests := []struct {
+		name      string
+		updatedAt string
+		force     bool
+		written   bool
+	}{
+		{"unchanged", "2024-02-01T00:00:00Z", false, false},
+		{"force", "2024-02-01T00:00:00Z", true, true},
+		{"updated on the remote", "2024-03-01T00:00:00Z", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.Chtimes(path, time.Time{}, time.Unix(0, 0)); err != nil {
+				t.Fatal(err)
+			}
+			client.translations[1] = tt.updatedAt
+			c := &CommandPull{Force: tt.force, ArticleIDs: []int{1}, client: client, converter: converter.NewConverter()}
+			if err := c.Run(g); err != nil {
+				t.Fatalf("Run() failed: %v", err)
+			}
+			fi, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if written := fi.ModTime().Unix() != 0; written != tt.written {
+				t.Errorf("Run() failed: written = %v, want %v", written, tt.written)
+			}
+		})
+	}
+}
@@ -3,124 +3,864 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
-	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
 
+	"github.com/tukaelu/zgsync/internal/audit"
 	"github.com/tukaelu/zgsync/internal/converter"
+	"github.com/tukaelu/zgsync/internal/metrics"
+	"github.com/tukaelu/zgsync/internal/notify"
+	"github.com/tukaelu/zgsync/internal/state"
 	"github.com/tukaelu/zgsync/internal/zendesk"
 )
 
 type CommandPush struct {
-	Article   bool                `name:"article" help:"Specify when posting an article. If not specified, the translation will be pushed."`
-	DryRun    bool                `name:"dry-run" help:"dry run"`
-	Raw       bool                `name:"raw" help:"It pushes raw data without converting it from Markdown to HTML."`
-	Files     []string            `arg:"" help:"Specify the files to push." type:"existingfile"`
-	client    zendesk.Client      `kong:"-"`
-	converter converter.Converter `kong:"-"`
+	Article             bool                        `name:"article" help:"Specify when posting an article. If not specified, the translation will be pushed."`
+	WithTranslations    bool                        `name:"with-translations" short:"T" help:"It pushes the article and then its translations. It implies --article."`
+	All                 bool                        `name:"all" help:"It pushes the sections, the articles and then the translations."`
+	DryRun              bool                        `name:"dry-run" help:"dry run"`
+	Force               bool                        `name:"force" short:"f" help:"It pushes even if the file has not changed since the last push."`
+	KeepGoing           bool                        `name:"keep-going" short:"k" help:"It pushes the remaining files even if some files fail."`
+	RetryFailed         bool                        `name:"retry-failed" help:"It pushes only the files that failed in the previous runs."`
+	Notify              *bool                       `name:"notify" negatable:"" help:"It overrides whether to notify subscribers when pushing articles."`
+	Raw                 bool                        `name:"raw" help:"It pushes raw data without converting it from Markdown to HTML."`
+	Draft               bool                        `name:"draft" help:"It pushes the translations as drafts regardless of the front matter." xor:"draft"`
+	Publish             bool                        `name:"publish" help:"It pushes the translations as published regardless of the front matter." xor:"draft"`
+	MarkOutdated        bool                        `name:"mark-outdated" help:"It marks the translations of the other locales as outdated."`
+	Message             string                      `name:"message" short:"m" help:"Specify the change note of the push."`
+	OnConflict          string                      `name:"on-conflict" enum:"ask,local,remote,skip" default:"ask" help:"Specify how to resolve the translations updated remotely: ask, local, remote or skip."`
+	OnMismatch          string                      `name:"on-mismatch" enum:"ask,repair,push,skip" default:"ask" help:"Specify how to resolve the articles moved remotely: ask, repair, push or skip."`
+	OnDuplicate         string                      `name:"on-duplicate" enum:"warn,fail" default:"warn" help:"Specify how to handle the duplicate titles: warn or fail."`
+	MoveSections        bool                        `name:"move-sections" help:"It moves the articles of the files moved into the directories of other sections."`
+	SkipPermissionCheck bool                        `name:"skip-permission-check" help:"It skips the check of the Guide permissions."`
+	Retry               Retry                       `embed:""`
+	Files               []string                    `arg:"" optional:"" help:"Specify the files or directories to push." type:"path"`
+	client              zendesk.Client              `kong:"-"`
+	clients             map[string]zendesk.Client   `kong:"-"`
+	converter           converter.Converter         `kong:"-"`
+	state               *state.Store                `kong:"-"`
+	dirs                *dirConfigs                 `kong:"-"`
+	results             []notify.Result             `kong:"-"`
+	failures            []string                    `kong:"-"`
+	failed              *state.Failed               `kong:"-"`
+	refs                refCache                    `kong:"-"`
+	locales             map[string]*zendesk.Locales `kong:"-"`
+	perms               permissions                 `kong:"-"`
+	links               map[string]string           `kong:"-"`
+	sections            map[string]int              `kong:"-"`
+	authors             map[string]int              `kong:"-"`
+	interactive         bool                        `kong:"-"`
+	// pullChanges is set by sync to pull the remote changes into the files not changed locally.
+	pullChanges bool `kong:"-"`
+	// excluded is the keys of the files which are not pushed, such as those of the articles deleted remotely.
+	excluded map[string]bool `kong:"-"`
 }
 
 func (c *CommandPush) AfterApply(g *Global) error {
-	c.client = zendesk.NewClient(g.Config.Subdomain, g.Config.Email, g.Config.Token)
+	if c.All {
+		if c.Article || c.WithTranslations {
+			return fmt.Errorf("--all cannot be used with --article or --with-translations")
+		}
+		if g.Config.isHugo() {
+			return fmt.Errorf("--all is not supported with the front matter format %s", frontMatterHugo)
+		}
+	}
+	if c.WithTranslations {
+		if g.Config.isHugo() {
+			return fmt.Errorf("--with-translations is not supported with the front matter format %s", frontMatterHugo)
+		}
+		c.Article = true
+	}
+	c.client = g.newClient(g.Config.Subdomain)
 	c.converter = converter.NewConverter()
+	c.clients = map[string]zendesk.Client{}
+	c.interactive = isTerminal(os.Stdin)
 	return nil
 }
 
-func (c *CommandPush) Run(g *Global) error {
-	var err error
-	for _, file := range c.Files {
-		if !filepath.IsAbs(file) {
-			if file, err = filepath.Abs(file); err != nil {
-				return err
+// clientFor returns the client of the brand. An empty brand is the brand of the configuration.
+func (c *CommandPush) clientFor(g *Global, brand string) (zendesk.Client, error) {
+	if brand == "" || brand == g.Config.Brand {
+		return c.client, nil
+	}
+	if client, ok := c.clients[brand]; ok {
+		return client, nil
+	}
+	subdomain, ok := g.Config.Brands[brand]
+	if !ok {
+		return nil, fmt.Errorf("brand %s is not defined in brands", brand)
+	}
+	client := g.newClient(subdomain)
+	c.clients[brand] = client
+	return client, nil
+}
+
+// brandOf returns the brand of the file given by the front matter or the directory configuration.
+func brandOf(g *Global, brand string, dc *DirConfig) string {
+	if brand == "" {
+		brand = dc.Brand
+	}
+	if brand == "" {
+		brand = g.Config.Brand
+	}
+	return brand
+}
+
+func (c *CommandPush) Run(g *Global) (err error) {
+	if c.state, err = g.LoadState(); err != nil {
+		return err
+	}
+	if c.failed, err = g.LoadFailed(); err != nil {
+		return err
+	}
+	if c.RetryFailed {
+		if len(c.Files) > 0 {
+			return fmt.Errorf("files cannot be specified with --retry-failed")
+		}
+		var items state.FailedItems
+		for _, item := range c.failed.Push {
+			path := c.state.Abs(item.File)
+			if _, err := os.Stat(path); os.IsNotExist(err) {
+				slog.Warn("skipped as it no longer exists", "file", path)
+				continue
 			}
+			items = append(items, item)
+			c.Files = append(c.Files, path)
 		}
+		c.failed.Push = items
+		if len(c.Files) == 0 {
+			slog.Info("no failed files to retry")
+			if c.DryRun {
+				return nil
+			}
+			return c.failed.Save()
+		}
+	}
+	if len(c.Files) == 0 {
+		return fmt.Errorf("specify the files to push")
+	}
 
-		if _, err = os.Stat(file); os.IsNotExist(err) {
+	for _, file := range c.Files {
+		if _, err := os.Stat(file); os.IsNotExist(err) {
 			return fmt.Errorf("file %s does not exist", file)
 		}
+	}
+
+	files, err := expandFiles(g.Config.ContentsDir, c.Files, func(path string) bool {
+		return c.isPushable(g, path)
+	})
+	if err != nil {
+		return err
+	}
+	items := make([]pushItem, len(files))
+	for i, file := range files {
+		items[i] = pushItem{file: file, article: c.Article}
+	}
+	if c.All {
+		items = orderFiles(files)
+	}
 
-		if c.Article {
+	if c.dirs, err = newDirConfigs(g.Config.ContentsDir); err != nil {
+		return err
+	}
+	if err := c.detectMoves(g, files); err != nil {
+		return err
+	}
+	if len(items) > 1 {
+		if err := c.checkDuplicates(g, items); err != nil {
+			return err
+		}
+	}
+	if !c.DryRun {
+		defer func() {
+			if ferr := c.failed.Save(); ferr != nil && err == nil {
+				err = ferr
+			}
+		}()
+		defer func() {
+			c.sendNotifications(g, err)
+		}()
+		defer func() {
+			if serr := c.state.Save(); serr != nil && err == nil {
+				err = serr
+			}
+		}()
+	}
+
+	if c.All {
+		if err := c.createSections(g, files); err != nil {
+			return err
+		}
+	}
+
+	// the translations are converted ahead while the others are pushed. The output of --dry-run is kept in order.
+	var pp *pushPipeline
+	if !c.DryRun && len(items) > 1 {
+		pp = c.startPipeline(g, items)
+		defer pp.stop()
+	}
+
+	for i, item := range items {
+		file := item.file
+		n := len(c.results)
+		err = c.Retry.do(func() error {
+			if !item.article && pp != nil {
+				p, err := c.take(g, pp, i, file)
+				if err != nil {
+					return err
+				}
+				return c.sendTranslation(g, file, p)
+			}
+			if !item.article {
+				return c.pushTranslation(g, file)
+			}
 			if err := c.pushArticle(g, file); err != nil {
 				return err
 			}
+			switch {
+			case c.All:
+				// the translations are pushed after all the articles.
+				_, err := c.pairTranslations(g, file)
+				return err
+			case c.WithTranslations:
+				return c.pushArticleTranslations(g, file)
+			}
+			return nil
+		})
+		if !c.DryRun {
+			c.auditPush(g, file, c.results[n:], err)
+		}
+		if err != nil && c.KeepGoing {
+			args := []any{"file", file, "error", err}
+			if h := hint(err); h != "" {
+				args = append(args, "hint", h)
+			}
+			slog.Error("failed", args...)
+			c.failures = append(c.failures, fmt.Sprintf("%s: %v", file, err))
+			c.failed.Push.Put(state.FailedItem{File: c.state.Key(file), Error: err.Error()})
 			continue
 		}
-
-		if err = c.pushTranslation(g, file); err != nil {
+		if err != nil {
+			c.failed.Push.Put(state.FailedItem{File: c.state.Key(file), Error: err.Error()})
+			// the files left by the failure are pushed by the retry too.
+			for _, rest := range items[i+1:] {
+				c.failed.Push.Put(state.FailedItem{File: c.state.Key(rest.file)})
+			}
 			return err
 		}
+		c.failed.Push.Delete(c.state.Key(file), 0)
+	}
+	if !c.DryRun {
+		if err = c.recordChange(g, time.Now()); err != nil {
+			return err
+		}
+	}
+	if c.KeepGoing {
+		slog.Info("summary", "files", len(items), "succeeded", len(items)-len(c.failures), "failed", len(c.failures))
+		if len(c.failures) > 0 {
+			return fmt.Errorf("%d of %d files failed to push", len(c.failures), len(items))
+		}
 	}
 	return nil
 }
 
+func (c *CommandPush) auditPush(g *Global, file string, results []notify.Result, err error) {
+	var records []audit.Record
+	for _, r := range results {
+		records = append(records, audit.Record{Command: "push", File: r.File, ArticleID: r.ArticleID, Locale: r.Locale, Result: r.Action})
+	}
+	if err != nil {
+		r := audit.Record{Command: "push", File: file, Result: audit.ResultFailed, Error: err.Error()}
+		if ref, rerr := readFileRef(file); rerr == nil {
+			r.ArticleID = ref.ID
+			r.Locale = ref.Locale
+		}
+		records = append(records, r)
+	}
+	g.audit(records...)
+}
+
+func (c *CommandPush) isPushable(g *Global, path string) bool {
+	if c.excluded[c.state.Key(path)] {
+		return false
+	}
+	if c.All {
+		return classifyFile(path) != ""
+	}
+	ref, err := readFileRef(path)
+	if err != nil {
+		return false
+	}
+	if c.Article && !g.Config.isHugo() {
+		return ref.Kind == state.KindArticle
+	}
+	return ref.Kind == state.KindTranslation
+}
+
 func (c *CommandPush) pushArticle(g *Global, file string) error {
-	a := &zendesk.Article{}
-	if err := a.FromFile(file); err != nil {
+	a, err := g.Config.readArticle(file)
+	if err != nil {
 		return err
 	}
-
-	if c.DryRun {
-		dryRun(a, file)
-		return nil
+	if err := g.Config.checkRequiredFields(file, true); err != nil {
+		return err
+	}
+	if a.ID == 0 && g.Config.isHugo() {
+		// creating the article would overwrite the Hugo front matter.
+		return fmt.Errorf("source_id of %s is not specified", file)
 	}
 
-	payload, err := a.ToPayload(g.Config.NotifySubscribers)
+	dc, err := c.dirs.For(file)
 	if err != nil {
 		return err
 	}
+	if a.SectionID == 0 && dc.SectionID != nil {
+		a.SectionID = *dc.SectionID
+	}
+	if a.PermissionGroupID == 0 && dc.PermissionGroupID != nil {
+		a.PermissionGroupID = *dc.PermissionGroupID
+	}
+	if a.Locale == "" {
+		a.Locale = dc.Locale
+	}
+	brand := brandOf(g, a.Brand, dc)
+	client, err := c.clientFor(g, brand)
+	if err != nil {
+		return err
+	}
+	if a.SectionID == 0 {
+		if id, ok := g.Config.sectionForFile(file); ok {
+			a.SectionID = id
+		}
+	}
 
 	var locale string
 	if a.Locale == "" {
-		locale = g.Config.DefaultLocale
+		locale = g.Config.remoteLocale(g.Config.DefaultLocale)
 	} else {
+		a.Locale = g.Config.remoteLocale(a.Locale)
 		locale = a.Locale
 	}
+	if a.ID == 0 {
+		// the defaults of `empty` are applied to the new article.
+		a.Locale = locale
+		if a.PermissionGroupID == 0 {
+			a.PermissionGroupID = g.Config.DefaultPermissionGroupID
+		}
+		if a.UserSegmentID == nil && len(a.UserSegmentIDs) == 0 {
+			a.UserSegmentID = g.Config.DefailtUserSegmentID
+		}
+	}
+
+	if c.DryRun {
+		dryRun(a, file)
+		return nil
+	}
 
-	_, err = c.client.UpdateArticle(locale, a.ID, payload)
+	if g.Config.isHugo() {
+		if a, err = mergeHugoArticle(client, locale, a); err != nil {
+			return err
+		}
+	}
+	if a.AuthorEmail != "" {
+		if a.AuthorID, err = c.authorID(client, brand, a.AuthorEmail); err != nil {
+			return err
+		}
+	}
+
+	notifySubscribers := g.Config.NotifySubscribers
+	if dc.NotifySubscribers != nil {
+		notifySubscribers = *dc.NotifySubscribers
+	}
+	if a.NotifySubscribers != nil {
+		notifySubscribers = *a.NotifySubscribers
+	}
+	if c.Notify != nil {
+		notifySubscribers = *c.Notify
+	}
+
+	payload, err := a.ToPayload(notifySubscribers)
+	if err != nil {
+		return err
+	}
+
+	step := planPush(c.state, file, payload, a.ID != 0)
+	if step.Action == state.ActionSkip && !c.Force {
+		upToDate(file)
+		return nil
+	}
+	if err := c.checkLocale(client, brand, locale); err != nil {
+		return err
+	}
+	if err := c.checkArticleRefs(client, brand, a); err != nil {
+		return err
+	}
+	if err := c.checkPermission(client, brand, a.PermissionGroupID, a.ID); err != nil {
+		return err
+	}
+
+	if step.Action == state.ActionCreate {
+		return c.createArticle(g, client, brand, file, a, payload)
+	}
+
+	// the article moved in the UI is not moved back to the stale section_id of the file without asking.
+	if a.SectionID != 0 && !g.Config.isHugo() {
+		sectionID, err := c.remoteSection(client, brand, a.ID)
+		if err != nil {
+			return err
+		}
+		if movedRemotely(c.state, file, a.SectionID, sectionID) {
+			resolved, push, err := c.resolveMismatch(g, file, true, a.SectionID, sectionID)
+			if err != nil || !push {
+				return err
+			}
+			a.SectionID = resolved
+			if payload, err = a.ToPayload(notifySubscribers); err != nil {
+				return err
+			}
+		}
+	}
+
+	res, err := client.UpdateArticle(locale, a.ID, payload)
 	if err != nil {
 		return err
 	}
+	metrics.Pushes.Inc()
+
+	remote := &zendesk.Article{}
+	if err := remote.FromJson(res); err != nil {
+		return err
+	}
+	c.results = append(c.results, notify.Result{File: file, Action: notify.ActionUpdated, ArticleID: a.ID, Title: remote.Title, URL: remote.HtmlURL})
+	trackPushed(c.state, file, state.Entry{
+		Kind:            state.KindArticle,
+		Brand:           brand,
+		ArticleID:       a.ID,
+		SectionID:       remote.SectionID,
+		RemoteUpdatedAt: remote.UpdatedAt,
+	}, payload)
 
 	return nil
 }
 
-func (c *CommandPush) pushTranslation(g *Global, file string) error {
-	t := &zendesk.Translation{}
-	err := t.FromFile(file)
+// createArticle creates the article without an ID remotely and writes the created article back to the file.
+func (c *CommandPush) createArticle(g *Global, client zendesk.Client, brand string, file string, a *zendesk.Article, payload string) error {
+	if a.SectionID == 0 {
+		return fmt.Errorf("section_id of %s is not specified", file)
+	}
+
+	res, err := client.CreateArticle(a.Locale, a.SectionID, payload)
 	if err != nil {
 		return err
 	}
+	metrics.Pushes.Inc()
 
-	if !c.Raw {
+	remote := &zendesk.Article{}
+	if err := remote.FromJson(res); err != nil {
+		return err
+	}
+	remote.NotifySubscribers = a.NotifySubscribers
+	remote.Brand = a.Brand
+	remote.Locale = g.Config.localLocale(remote.Locale)
+	articleTmpl, _, err := g.Config.frontMatterTemplates()
+	if err != nil {
+		return err
+	}
+	if err := remote.SaveWithTemplate(file, false, articleTmpl); err != nil {
+		return fmt.Errorf("failed to save the article: %w", err)
+	}
+	slog.Info("created", "file", file, "article_id", remote.ID)
+	c.results = append(c.results, notify.Result{File: file, Action: notify.ActionCreated, ArticleID: remote.ID, Title: remote.Title, URL: remote.HtmlURL})
+
+	return trackPulled(c.state, file, state.Entry{
+		Kind:            state.KindArticle,
+		Brand:           brand,
+		ArticleID:       remote.ID,
+		SectionID:       remote.SectionID,
+		RemoteUpdatedAt: remote.UpdatedAt,
+	})
+}
+
+// preparedTranslation is the translation file converted into the payload to push.
+type preparedTranslation struct {
+	t       *zendesk.Translation
+	locale  string
+	brand   string
+	payload string
+}
+
+func (c *CommandPush) pushTranslation(g *Global, file string) error {
+	p, err := c.prepareTranslation(g, file)
+	if err != nil || p == nil {
+		return err
+	}
+	return c.sendTranslation(g, file, p)
+}
+
+// prepareTranslation converts the translation file into the payload without requests. It returns nil with --dry-run.
+func (c *CommandPush) prepareTranslation(g *Global, file string) (*preparedTranslation, error) {
+	t, err := g.Config.readTranslation(file)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.Config.checkRequiredFields(file, false); err != nil {
+		return nil, err
+	}
+
+	t.Locale = g.Config.remoteLocale(t.Locale)
+
+	scheduled, err := t.IsScheduled(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case scheduled || c.Draft:
+		// keeps the scheduled translation in draft until it is published by `publish --due`.
+		t.Draft = true
+	case c.Publish:
+		t.Draft = false
+	}
+
+	html, err := t.IsHTML()
+	if err != nil {
+		return nil, err
+	}
+	if !c.Raw && !html {
 		if t.Body, err = c.converter.ConvertToHTML(t.Body); err != nil {
-			return err
+			metrics.ConversionFailures.Inc()
+			return nil, err
 		}
 	}
+	for _, m := range g.Config.strippedMarkup(t.Body) {
+		slog.Warn("stripped by the help center", "file", file, "markup", m)
+	}
+	if err := g.Config.checkLimits(file, t.Body); err != nil {
+		return nil, err
+	}
+
+	dc, err := c.dirs.For(file)
+	if err != nil {
+		return nil, err
+	}
+	locale := t.Locale
+	if locale == "" {
+		locale = dc.Locale
+	}
+	if locale == "" {
+		locale = g.Config.DefaultLocale
+	}
+	locale = g.Config.remoteLocale(locale)
+	brand := brandOf(g, t.Brand, dc)
+	t.Body = resolveLinks(g, file, t.Body, locale, brand)
 
 	if c.DryRun {
 		dryRun(t, file)
+		return nil, nil
+	}
+	if t.SourceID == 0 {
+		return nil, fmt.Errorf("source_id of %s is not specified", file)
+	}
+
+	payload, err := c.translationPayload(t)
+	if err != nil {
+		return nil, err
+	}
+	return &preparedTranslation{t: t, locale: locale, brand: brand, payload: payload}, nil
+}
+
+func (c *CommandPush) sendTranslation(g *Global, file string, p *preparedTranslation) error {
+	// the translation is copied, since it is sent again when the push is retried.
+	copied := *p.t
+	t, locale, brand, payload := &copied, p.locale, p.brand, p.payload
+	unchanged := !c.Force && planPush(c.state, file, payload, true).Action == state.ActionSkip
+	if c.pullChanges && (unchanged || unchangedSincePull(c.state, file)) {
+		return c.pullChanged(g, file, t.SourceID, locale, brand)
+	}
+	if unchanged {
+		upToDate(file)
 		return nil
 	}
 
-	payload, err := t.ToPayload()
+	client, err := c.clientFor(g, brand)
 	if err != nil {
 		return err
 	}
+	if err := c.checkLocale(client, brand, locale); err != nil {
+		return err
+	}
+	// the translation identical to the remote is not written, and the one updated remotely is resolved by --on-conflict.
+	if !c.Force {
+		res, err := zendesk.Uncached(client).ShowTranslation(t.SourceID, locale)
+		if err != nil && !zendesk.IsNotFound(err) {
+			return err
+		}
+		remote := &zendesk.Translation{}
+		if err == nil {
+			c.refs.found(brand, "article", t.SourceID)
+			if err := remote.FromJson(res); err != nil {
+				return err
+			}
+			// the assets are compared by the URLs uploaded before, as they are uploaded after the checks.
+			compared := *t
+			if t.AssetsDir != "" {
+				if err := uploadedAssets(c.state, file, &compared); err != nil {
+					return err
+				}
+			}
+			if c.isIdentical(remote, &compared) {
+				slog.Info("identical to the remote", "file", file)
+				trackPushed(c.state, file, state.Entry{
+					Kind:            state.KindTranslation,
+					Brand:           brand,
+					ArticleID:       t.SourceID,
+					Locale:          locale,
+					SectionID:       t.SectionID,
+					RemoteUpdatedAt: remote.UpdatedAt,
+				}, p.payload)
+				return nil
+			}
+			if remoteNewer(c.state, file, remote) {
+				push, err := c.resolveConflict(g, file, remote)
+				if err != nil || !push {
+					return err
+				}
+			}
+			if remote.Title != t.Title && !remote.Draft && zendesk.SlugFromURL(remote.HtmlURL) != "" {
+				slog.Warn("the title change alters the public URL", "file", file, "title", t.Title, "url", remote.HtmlURL)
+			}
+		}
+	}
 
-	var locale string
-	if t.Locale == "" {
-		locale = g.Config.DefaultLocale
-	} else {
-		locale = t.Locale
+	if err := c.checkSourceID(client, brand, t.SourceID); err != nil {
+		return err
+	}
+	if t.SectionID != 0 {
+		sectionID, err := c.remoteSection(client, brand, t.SourceID)
+		if err != nil {
+			return err
+		}
+		if movedRemotely(c.state, file, t.SectionID, sectionID) {
+			resolved, push, err := c.resolveMismatch(g, file, false, t.SectionID, sectionID)
+			if err != nil || !push {
+				return err
+			}
+			t.SectionID = resolved
+		}
+	}
+	if err := c.checkPermission(client, brand, 0, t.SourceID); err != nil {
+		return err
+	}
+	if err := c.checkLinks(g, file, t.Body); err != nil {
+		return err
+	}
+	// the assets are uploaded after the checks, so that they are not uploaded for the translation not pushed.
+	if t.AssetsDir != "" {
+		if err := c.uploadAssets(g, client, file, t); err != nil {
+			return err
+		}
+		if payload, err = c.translationPayload(t); err != nil {
+			return err
+		}
 	}
 
-	_, err = c.client.UpdateTranslation(t.SourceID, locale, payload)
+	// the comment does not make the file changed for the later pushes.
+	hashed := p.payload
+	if c.Message != "" && g.Config.Changelog.Comment {
+		t.Body += changeComment(c.Message)
+		if payload, err = c.translationPayload(t); err != nil {
+			return err
+		}
+	}
+
+	action := notify.ActionUpdated
+	res, err := client.UpdateTranslation(t.SourceID, locale, payload)
+	if err != nil && (c.WithTranslations || c.All) && zendesk.IsNotFound(err) {
+		// the translations of the locales other than the source locale are added to the article.
+		t.Locale = locale
+		if payload, err = c.translationPayload(t); err != nil {
+			return err
+		}
+		action = notify.ActionCreated
+		res, err = client.CreateTranslation(t.SourceID, payload)
+	}
 	if err != nil {
 		return err
 	}
+	metrics.Pushes.Inc()
+
+	remote := &zendesk.Translation{}
+	if err := remote.FromJson(res); err != nil {
+		return err
+	}
+	if action == notify.ActionCreated {
+		slog.Info("created", "file", file, "article_id", t.SourceID, "locale", locale)
+	}
+	c.results = append(c.results, notify.Result{File: file, Action: action, ArticleID: t.SourceID, Locale: locale, Title: t.Title, URL: remote.HtmlURL})
+	trackPushed(c.state, file, state.Entry{
+		Kind:            state.KindTranslation,
+		Brand:           brand,
+		ArticleID:       t.SourceID,
+		Locale:          locale,
+		SectionID:       t.SectionID,
+		RemoteUpdatedAt: remote.UpdatedAt,
+	}, hashed)
+	if err := updateLocalSlug(c.state, file, t.Slug, remote); err != nil {
+		return err
+	}
+
+	if action == notify.ActionUpdated {
+		if err := c.flagOutdated(client, brand, t.SourceID, locale); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updateLocalSlug writes the slug and html_url changed by the title to the file pulled with the slug.
+func updateLocalSlug(s *state.Store, file string, slug string, remote *zendesk.Translation) error {
+	updated := zendesk.SlugFromURL(remote.HtmlURL)
+	if slug == "" || updated == "" || updated == slug {
+		return nil
+	}
+	slog.Warn("the public URL is changed", "file", file, "url", remote.HtmlURL)
+	return updateLocalTranslation(s, file, remote.UpdatedAt, func(t *zendesk.Translation) bool {
+		t.Slug = updated
+		t.HtmlURL = remote.HtmlURL
+		return true
+	})
+}
 
+func (c *CommandPush) pushArticleTranslations(g *Global, file string) error {
+	files, err := c.pairTranslations(g, file)
+	if err != nil {
+		return err
+	}
+	for _, tf := range files {
+		if err := c.pushTranslation(g, tf); err != nil {
+			return fmt.Errorf("%s: %w", tf, err)
+		}
+	}
 	return nil
 }
 
+// pairTranslations returns the translation files of the article, giving source_id to those of a new article.
+func (c *CommandPush) pairTranslations(g *Global, file string) ([]string, error) {
+	a, err := g.Config.readArticle(file)
+	if err != nil {
+		return nil, err
+	}
+	locale := a.Locale
+	if locale == "" {
+		dc, err := c.dirs.For(file)
+		if err != nil {
+			return nil, err
+		}
+		locale = dc.Locale
+	}
+	if locale == "" {
+		locale = g.Config.DefaultLocale
+	}
+
+	files, err := articleTranslationFiles(g, file, a.ID, g.Config.remoteLocale(locale))
+	if err != nil {
+		return nil, err
+	}
+	if a.ID == 0 || c.DryRun {
+		return files, nil
+	}
+	_, translationTmpl, err := g.Config.frontMatterTemplates()
+	if err != nil {
+		return nil, err
+	}
+	for _, tf := range files {
+		t, err := g.Config.readTranslation(tf)
+		if err != nil {
+			return nil, err
+		}
+		if t.SourceID == 0 {
+			t.SourceID = a.ID
+			if err := t.SaveWithTemplate(tf, false, translationTmpl); err != nil {
+				return nil, fmt.Errorf("failed to save the translation: %w", err)
+			}
+		}
+	}
+	return files, nil
+}
+
+func (c *CommandPush) sendNotifications(g *Global, err error) {
+	n := g.Config.Notifications
+	if n.SlackWebhookURL == "" && n.WebhookURL == "" {
+		return
+	}
+	if len(c.results) == 0 && err == nil {
+		return
+	}
+	s := &notify.Summary{Command: "push", Subdomain: g.Config.Subdomain, Results: c.results, Errors: c.failures}
+	if err != nil && len(c.failures) == 0 {
+		s.Errors = []string{err.Error()}
+	}
+	if n.SlackWebhookURL != "" {
+		if err := notify.Slack(n.SlackWebhookURL, s); err != nil {
+			slog.Warn("failed to notify Slack", "error", err)
+		}
+	}
+	if n.WebhookURL != "" {
+		if err := notify.Webhook(n.WebhookURL, s); err != nil {
+			slog.Warn("failed to notify the webhook", "error", err)
+		}
+	}
+}
+
+// translationPayload returns the payload of the translation, with draft set to false explicitly with --publish.
+func (c *CommandPush) translationPayload(t *zendesk.Translation) (string, error) {
+	payload, err := t.ToPayload()
+	if err != nil || !c.Publish || t.Draft {
+		return payload, err
+	}
+	var v map[string]map[string]any
+	if err := json.Unmarshal([]byte(payload), &v); err != nil {
+		return "", err
+	}
+	v["translation"]["draft"] = false
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func (c *CommandPush) isIdentical(remote, t *zendesk.Translation) bool {
+	if remote.Title != t.Title {
+		return false
+	}
+	if (t.Draft || c.Publish) && remote.Draft != t.Draft {
+		return false
+	}
+	return state.Hash(normalizeHTML(remote.Body)) == state.Hash(normalizeHTML(t.Body))
+}
+
+var (
+	changeCommentPattern = regexp.MustCompile(`\n?<!-- zgsync: .*? -->\n?`)
+	interTagSpacePattern = regexp.MustCompile(`>\s+<`)
+	spacePattern         = regexp.MustCompile(`\s+`)
+)
+
+// normalizeHTML normalizes the whitespace, the line endings and the change comments which Zendesk may rewrite.
+func normalizeHTML(body string) string {
+	body = changeCommentPattern.ReplaceAllString(body, "")
+	body = interTagSpacePattern.ReplaceAllString(body, "><")
+	body = spacePattern.ReplaceAllString(body, " ")
+	return strings.TrimSpace(body)
+}
+
+func upToDate(file string) {
+	slog.Info("up to date", "file", file)
+}
+
 func dryRun(v interface{}, file string) {
 	prettyPayload, _ := json.MarshalIndent(v, "", "  ")
 	fmt.Printf("file: %s\n", file)
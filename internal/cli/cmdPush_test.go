@@ -0,0 +1,450 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tukaelu/zgsync/internal/converter"
+	"github.com/tukaelu/zgsync/internal/state"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+// pushClient fails to update the translations of the articles in failing.
+// The remote translations are in remote, and the others are the changelog.
+type pushClient struct {
+	zendesk.Client
+	failing  map[int]bool
+	remote   map[int]string
+	updated  []int
+	payloads []string
+}
+
+func (c *pushClient) UpdateTranslation(articleID int, locale string, payload string) (string, error) {
+	if c.failing[articleID] {
+		return "", &zendesk.StatusError{StatusCode: 422}
+	}
+	c.updated = append(c.updated, articleID)
+	c.payloads = append(c.payloads, payload)
+	return fmt.Sprintf(`{"translation":{"source_id":%d,"locale":%q}}`, articleID, locale), nil
+}
+
+func (c *pushClient) ListLocales() (string, error) {
+	return `{"locales":["ja","en-us","fr"],"default_locale":"ja"}`, nil
+}
+
+func (c *pushClient) ShowCurrentUser() (string, error) {
+	return `{"user":{"id":1,"email":"admin@example.com","role":"admin"}}`, nil
+}
+
+func TestPushKeepGoing(t *testing.T) {
+	dir := t.TempDir()
+	var files []string
+	for _, id := range []int{1, 2, 3} {
+		file := filepath.Join(dir, fmt.Sprintf("%d-ja.md", id))
+		if err := os.WriteFile(file, []byte(fmt.Sprintf("---\ntitle: t%d\nlocale: ja\nsource_id: %d\n---\nbody\n", id, id)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, file)
+	}
+
+	tests := []struct {
+		name      string
+		keepGoing bool
+		updated   []int
+		failures  int
+	}{
+		{"stops at the first failure", false, []int{1}, 0},
+		{"keeps going", true, []int{1, 3}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &Global{Config: Config{ContentsDir: t.TempDir(), DefaultLocale: "ja"}}
+			client := &pushClient{failing: map[int]bool{2: true}}
+			c := &CommandPush{KeepGoing: tt.keepGoing, Files: files, client: client, clients: map[string]zendesk.Client{}, converter: converter.NewConverter()}
+			err := c.Run(g)
+			if err == nil {
+				t.Fatal("Run() should fail")
+			}
+			if tt.keepGoing && !strings.Contains(err.Error(), "1 of 3 files failed") {
+				t.Errorf("Run() failed: unexpected error %v", err)
+			}
+			if fmt.Sprint(client.updated) != fmt.Sprint(tt.updated) {
+				t.Errorf("Run() failed: got %v updated, want %v", client.updated, tt.updated)
+			}
+			if len(c.failures) != tt.failures {
+				t.Errorf("Run() failed: got %v failures, want %d", c.failures, tt.failures)
+			}
+		})
+	}
+}
+
+func TestPushRetryFailed(t *testing.T) {
+	dir := t.TempDir()
+	var files []string
+	for _, id := range []int{1, 2, 3} {
+		file := filepath.Join(dir, fmt.Sprintf("%d-ja.md", id))
+		if err := os.WriteFile(file, []byte(fmt.Sprintf("---\ntitle: t%d\nlocale: ja\nsource_id: %d\n---\nbody\n", id, id)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, file)
+	}
+	g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja"}}
+
+	client := &pushClient{failing: map[int]bool{2: true}}
+	c := &CommandPush{Files: files, client: client, clients: map[string]zendesk.Client{}, converter: converter.NewConverter()}
+	if err := c.Run(g); err == nil {
+		t.Fatal("Run() should fail")
+	}
+	failed, err := g.LoadFailed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := state.FailedItems{{File: "2-ja.md", Error: "unexpected status code: 422"}, {File: "3-ja.md"}}
+	if !reflect.DeepEqual(failed.Push, want) {
+		t.Errorf("Run() failed: got %v failed, want %v", failed.Push, want)
+	}
+
+	client = &pushClient{}
+	c = &CommandPush{RetryFailed: true, client: client, clients: map[string]zendesk.Client{}, converter: converter.NewConverter()}
+	if err := c.Run(g); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if fmt.Sprint(client.updated) != "[2 3]" {
+		t.Errorf("Run() failed: got %v updated, want [2 3]", client.updated)
+	}
+	if _, err := os.Stat(failed.Path()); !os.IsNotExist(err) {
+		t.Errorf("Run() failed: %s should be removed", failed.Path())
+	}
+}
+
+func TestPushDraft(t *testing.T) {
+	tests := []struct {
+		name        string
+		frontMatter string
+		draft       bool
+		publish     bool
+		expected    string
+	}{
+		{"front matter", "draft: true", false, false, `"draft":true`},
+		{"draft", "draft: false", true, false, `"draft":true`},
+		{"publish", "draft: true", false, true, `"draft":false`},
+		{"scheduled is not published", "publish_at: 2999-01-01", false, true, `"draft":true`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			file := filepath.Join(dir, "1-ja.md")
+			if err := os.WriteFile(file, []byte("---\ntitle: t\nlocale: ja\nsource_id: 1\n"+tt.frontMatter+"\n---\nbody\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja"}}
+			client := &pushClient{}
+			c := &CommandPush{Draft: tt.draft, Publish: tt.publish, Files: []string{file}, client: client, clients: map[string]zendesk.Client{}, converter: converter.NewConverter()}
+			if err := c.Run(g); err != nil {
+				t.Fatalf("Run() failed: %v", err)
+			}
+			if len(client.payloads) != 1 || !strings.Contains(client.payloads[0], tt.expected) {
+				t.Errorf("Run() failed: got %v, want %s", client.payloads, tt.expected)
+			}
+		})
+	}
+}
+
+func (c *pushClient) ShowTranslation(articleID int, locale string) (string, error) {
+	if res, ok := c.remote[articleID]; ok {
+		return res, nil
+	}
+	return fmt.Sprintf(`{"translation":{"source_id":%d,"locale":%q,"title":"What's new","body":"<p>old</p>"}}`, articleID, locale), nil
+}
+
+func TestPushLocaleNotEnabled(t *testing.T) {
+	dir := t.TempDir()
+	var files []string
+	for _, locale := range []string{"de", "ja"} {
+		file := filepath.Join(dir, "1-"+locale+".md")
+		if err := os.WriteFile(file, []byte("---\ntitle: t\nlocale: "+locale+"\nsource_id: 1\n---\nbody\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, file)
+	}
+	g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja"}}
+
+	client := &pushClient{}
+	c := &CommandPush{KeepGoing: true, Files: files, client: client, clients: map[string]zendesk.Client{}, converter: converter.NewConverter()}
+	if err := c.Run(g); err == nil {
+		t.Fatal("Run() succeeded, want the failure of de")
+	}
+	if fmt.Sprint(client.updated) != "[1]" {
+		t.Errorf("Run() failed: got %v updated, want [1] of ja", client.updated)
+	}
+
+	err := c.checkLocale(client, "", "de")
+	if err == nil || err.Error() != "locale: de is not enabled in the help center, whose locales are ja, en-us, fr" {
+		t.Errorf("checkLocale() failed: unexpected error %v", err)
+	}
+}
+
+func TestPushMessage(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "1-ja.md")
+	if err := os.WriteFile(file, []byte("---\ntitle: t1\nlocale: ja\nsource_id: 1\n---\nbody\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja", Changelog: Changelog{Comment: true, ArticleID: 99}}}
+
+	client := &pushClient{}
+	c := &CommandPush{Message: "Release 1.2 -- <new>", Files: []string{file}, client: client, clients: map[string]zendesk.Client{}, converter: converter.NewConverter()}
+	if err := c.Run(g); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if fmt.Sprint(client.updated) != "[1 99]" {
+		t.Fatalf("Run() failed: got %v updated, want [1 99]", client.updated)
+	}
+	var pushed, changelog zendesk.Translation
+	if err := pushed.FromJson(client.payloads[0]); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(pushed.Body, "\n<!-- zgsync: Release 1.2 - - <new> -->\n") {
+		t.Errorf("Run() failed: the comment is not appended: %q", pushed.Body)
+	}
+	if err := changelog.FromJson(client.payloads[1]); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(changelog.Body, "<h2>") || !strings.HasSuffix(changelog.Body, "<p>Release 1.2 -- &lt;new&gt;</p>\n<ul>\n<li>t1</li>\n</ul>\n<p>old</p>") {
+		t.Errorf("Run() failed: unexpected changelog %q", changelog.Body)
+	}
+
+	s, err := g.LoadState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Changelog) != 1 || s.Changelog[0].Message != "Release 1.2 -- <new>" || fmt.Sprint(s.Changelog[0].Files) != "[1-ja.md]" {
+		t.Errorf("Run() failed: unexpected changelog %+v", s.Changelog)
+	}
+
+	// the file is up to date without the comment.
+	client = &pushClient{}
+	c = &CommandPush{Files: []string{file}, client: client, clients: map[string]zendesk.Client{}, converter: converter.NewConverter()}
+	if err := c.Run(g); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if len(client.updated) != 0 {
+		t.Errorf("Run() failed: got %v updated, want none", client.updated)
+	}
+}
+
+func TestPushIdentical(t *testing.T) {
+	tests := []struct {
+		name    string
+		remote  string
+		updated bool
+	}{
+		{"identical", `{"translation":{"title":"t1","body":"<h2>Title</h2>\n\n<p>body\r\ntext</p>\n<!-- zgsync: Release 1.1 -->\n"}}`, false},
+		{"body", `{"translation":{"title":"t1","body":"<h2>Title</h2><p>old</p>"}}`, true},
+		{"title", `{"translation":{"title":"t0","body":"<h2>Title</h2><p>body text</p>"}}`, true},
+		{"draft", `{"translation":{"title":"t1","body":"<h2>Title</h2><p>body text</p>","draft":true}}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			file := filepath.Join(dir, "1-ja.md")
+			if err := os.WriteFile(file, []byte("---\ntitle: t1\nlocale: ja\nsource_id: 1\n---\n## Title\nbody text\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja"}}
+			client := &pushClient{remote: map[int]string{1: tt.remote}}
+			c := &CommandPush{Publish: tt.name == "draft", Files: []string{file}, client: client, clients: map[string]zendesk.Client{}, converter: converter.NewConverter()}
+			if err := c.Run(g); err != nil {
+				t.Fatalf("Run() failed: %v", err)
+			}
+			if (len(client.updated) > 0) != tt.updated {
+				t.Errorf("Run() failed: got %v updated, want updated = %v", client.updated, tt.updated)
+			}
+			s, err := g.LoadState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if e, ok := s.Lookup(file); !ok || e.PushedHash == "" {
+				t.Errorf("Run() failed: the push is not recorded")
+			}
+		})
+	}
+}
+
+func TestNormalizeHTML(t *testing.T) {
+	tests := []struct {
+		body     string
+		expected string
+	}{
+		{"<p>a</p>\n\n<p>b</p>\n", "<p>a</p><p>b</p>"},
+		{"<p>a\r\n  b</p>", "<p>a b</p>"},
+		{"<p>a</p>\n<!-- zgsync: note -->\n", "<p>a</p>"},
+		{"<p>a <b>b</b></p>", "<p>a <b>b</b></p>"},
+	}
+	for _, tt := range tests {
+		if got := normalizeHTML(tt.body); got != tt.expected {
+			t.Errorf("normalizeHTML(%q) failed: got %q, want %q", tt.body, got, tt.expected)
+		}
+	}
+}
+
+// sourceClient has the article whose source locale is ja, and the en-us and the outdated fr translations.
+type sourceClient struct {
+	pushClient
+}
+
+func (c *sourceClient) ShowArticle(locale string, articleID int) (string, error) {
+	return fmt.Sprintf(`{"article":{"id":%d,"locale":%q,"source_locale":"ja"}}`, articleID, locale), nil
+}
+
+func (c *sourceClient) ListTranslations(articleID int, page int) (string, error) {
+	return `{"translations":[{"locale":"ja"},{"locale":"en-us"},{"locale":"fr","outdated":true}]}`, nil
+}
+
+func TestPushMarkOutdated(t *testing.T) {
+	tests := []struct {
+		name         string
+		markOutdated bool
+		file         string
+		payloads     int
+		outdated     bool
+	}{
+		{"source locale", true, "1-ja.md", 2, true},
+		{"without --mark-outdated", false, "1-ja.md", 1, false},
+		{"other locale", true, "1-en-us.md", 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, locale := range []string{"ja", "en-us"} {
+				if err := os.WriteFile(filepath.Join(dir, "1-"+locale+".md"), []byte("---\ntitle: t\nlocale: "+locale+"\nsource_id: 1\n---\nbody\n"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja"}}
+			s, err := g.LoadState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			e := s.Get(filepath.Join(dir, "1-en-us.md"))
+			e.Kind = state.KindTranslation
+			e.ArticleID = 1
+			e.Locale = "en-us"
+			if err := s.Save(); err != nil {
+				t.Fatal(err)
+			}
+
+			client := &sourceClient{}
+			c := &CommandPush{MarkOutdated: tt.markOutdated, Files: []string{filepath.Join(dir, tt.file)}, client: client, clients: map[string]zendesk.Client{}, converter: converter.NewConverter()}
+			if err := c.Run(g); err != nil {
+				t.Fatalf("Run() failed: %v", err)
+			}
+			if len(client.payloads) != tt.payloads {
+				t.Fatalf("Run() failed: got %d payloads, want %d", len(client.payloads), tt.payloads)
+			}
+			if tt.payloads == 2 && client.payloads[1] != outdatedPayload {
+				t.Errorf("Run() failed: got %s, want %s", client.payloads[1], outdatedPayload)
+			}
+			translation := &zendesk.Translation{}
+			if err := translation.FromFile(filepath.Join(dir, "1-en-us.md")); err != nil {
+				t.Fatal(err)
+			}
+			if translation.Outdated != tt.outdated {
+				t.Errorf("Run() failed: got outdated %v, want %v", translation.Outdated, tt.outdated)
+			}
+		})
+	}
+}
+
+func TestPushBodyFormat(t *testing.T) {
+	tests := []struct {
+		name        string
+		frontMatter string
+		body        string
+		expected    string
+		wantErr     bool
+	}{
+		{"markdown", "", "**body**", "<p><strong>body</strong></p>\n", false},
+		{"explicit markdown", "body_format: markdown", "**body**", "<p><strong>body</strong></p>\n", false},
+		{"html", "body_format: html", `<div class="hero">**body**</div>`, `<div class="hero">**body**</div>`, false},
+		{"unknown", "body_format: xml", "<body/>", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			file := filepath.Join(dir, "1-ja.md")
+			if err := os.WriteFile(file, []byte("---\ntitle: t\nlocale: ja\nsource_id: 1\n"+tt.frontMatter+"\n---\n"+tt.body), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja"}}
+			client := &pushClient{}
+			c := &CommandPush{Files: []string{file}, client: client, clients: map[string]zendesk.Client{}, converter: converter.NewConverter()}
+			err := c.Run(g)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			pushed := &zendesk.Translation{}
+			if err := pushed.FromJson(client.payloads[0]); err != nil {
+				t.Fatal(err)
+			}
+			if pushed.Body != tt.expected {
+				t.Errorf("Run() failed: got %q, want %q", pushed.Body, tt.expected)
+			}
+		})
+	}
+}
+
+// slugClient returns the translations with the slug of their title in html_url.
+type slugClient struct {
+	pushClient
+}
+
+func (c *slugClient) UpdateTranslation(articleID int, locale string, payload string) (string, error) {
+	c.payloads = append(c.payloads, payload)
+	pushed := &zendesk.Translation{}
+	if err := pushed.FromJson(payload); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(`{"translation":{"source_id":%d,"locale":%q,"title":%q,"html_url":"https://example.zendesk.com/hc/%s/articles/%d-%s"}}`,
+		articleID, locale, pushed.Title, locale, articleID, strings.ReplaceAll(pushed.Title, " ", "-")), nil
+}
+
+func TestPushSlug(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "1-ja.md")
+	content := "---\ntitle: New title\nlocale: ja\nsource_id: 1\nhtml_url: https://example.zendesk.com/hc/ja/articles/1-Old-title\nslug: Old-title\n---\nbody\n"
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja"}}
+	client := &slugClient{pushClient{remote: map[int]string{
+		1: `{"translation":{"source_id":1,"locale":"ja","title":"Old title","body":"<p>body</p>","html_url":"https://example.zendesk.com/hc/ja/articles/1-Old-title"}}`,
+	}}}
+	c := &CommandPush{Files: []string{file}, client: client, clients: map[string]zendesk.Client{}, converter: converter.NewConverter()}
+	if err := c.Run(g); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+
+	tr := &zendesk.Translation{}
+	if err := tr.FromFile(file); err != nil {
+		t.Fatal(err)
+	}
+	if tr.Slug != "New-title" || tr.HtmlURL != "https://example.zendesk.com/hc/ja/articles/1-New-title" {
+		t.Errorf("Run() failed: got slug %s and html_url %s", tr.Slug, tr.HtmlURL)
+	}
+	// the slug written back does not make the file changed for the later pushes.
+	client.payloads = nil
+	if err := c.Run(g); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if len(client.payloads) != 0 {
+		t.Errorf("Run() failed: got %v, want no payload", client.payloads)
+	}
+}
@@ -0,0 +1,293 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+
+	"github.com/tukaelu/zgsync/internal/audit"
+	"github.com/tukaelu/zgsync/internal/backup"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+type CommandRestore struct {
+	ArticleID []int          `name:"article-id" short:"a" help:"Specify the article IDs to restore separated by commas."`
+	Locales   []string       `name:"locales" short:"L" help:"Specify the locales of the translations to restore separated by commas."`
+	From      string         `name:"from" help:"Specify the subdomain of the help center in the archive."`
+	DryRun    bool           `name:"dry-run" help:"It shows what would be created and overwritten without restoring."`
+	Confirm   Confirm        `embed:""`
+	Archive   string         `arg:"" help:"Specify the backup archive." type:"existingfile"`
+	client    zendesk.Client `kong:"-"`
+}
+
+const (
+	restoreCreate    = "create"
+	restoreOverwrite = "overwrite"
+	restoreUnchanged = "unchanged"
+)
+
+// restoreAction is a step of the plan restoring an article or a translation.
+type restoreAction struct {
+	Op          string
+	Article     *zendesk.Article
+	Translation *zendesk.Translation
+	// RemoteUpdatedAt is the time the remote object was updated, which is overwritten.
+	RemoteUpdatedAt string
+}
+
+func (a *restoreAction) String() string {
+	var s string
+	if a.Translation != nil {
+		s = fmt.Sprintf("translation %d/%s", a.Article.ID, a.Translation.Locale)
+	} else {
+		s = fmt.Sprintf("article %d %q", a.Article.ID, a.Article.Title)
+	}
+	if a.Op == restoreOverwrite {
+		updatedAt := a.Article.UpdatedAt
+		if a.Translation != nil {
+			updatedAt = a.Translation.UpdatedAt
+		}
+		s += fmt.Sprintf(" (remote updated at %s, backed up at %s)", a.RemoteUpdatedAt, updatedAt)
+	}
+	return s
+}
+
+func (a *restoreAction) attrs() []any {
+	attrs := []any{"article_id", a.Article.ID}
+	if a.Translation != nil {
+		attrs = append(attrs, "locale", a.Translation.Locale)
+	}
+	return attrs
+}
+
+func (c *CommandRestore) AfterApply(g *Global) error {
+	c.client = g.newUncachedClient(g.Config.Subdomain)
+	c.Confirm.interactive = isTerminal(os.Stdin)
+	return nil
+}
+
+func (c *CommandRestore) Run(g *Global) error {
+	archive, err := backup.Open(c.Archive)
+	if err != nil {
+		return err
+	}
+	from, err := c.source(g, archive)
+	if err != nil {
+		return err
+	}
+	plan, err := c.plan(g, archive, from)
+	if err != nil {
+		return err
+	}
+
+	if c.DryRun {
+		for _, a := range plan {
+			fmt.Printf("%s: %s\n", a.Op, a)
+		}
+		return nil
+	}
+
+	var affected []string
+	for _, a := range plan {
+		if a.Op != restoreUnchanged {
+			affected = append(affected, fmt.Sprintf("%s: %s", a.Op, a))
+		}
+	}
+	ok, err := c.Confirm.confirm("Restore", affected)
+	if err != nil || !ok {
+		if err == nil {
+			slog.Info("cancelled")
+		}
+		return err
+	}
+	return c.restore(g, plan)
+}
+
+// source returns the subdomain of the help center to restore in the archive.
+func (c *CommandRestore) source(g *Global, archive *backup.Archive) (string, error) {
+	var subdomains []string
+	for _, b := range archive.Manifest.Brands {
+		subdomains = append(subdomains, b.Subdomain)
+	}
+	switch {
+	case c.From != "":
+		if !slices.Contains(subdomains, c.From) {
+			return "", fmt.Errorf("%s is not found in the archive", c.From)
+		}
+		return c.From, nil
+	case slices.Contains(subdomains, g.Config.Subdomain):
+		return g.Config.Subdomain, nil
+	case len(subdomains) == 1:
+		return subdomains[0], nil
+	}
+	return "", fmt.Errorf("specify the help center to restore with --from: %v", subdomains)
+}
+
+func (c *CommandRestore) plan(g *Global, archive *backup.Archive, from string) ([]*restoreAction, error) {
+	ids := archive.Articles(from)
+	if len(c.ArticleID) > 0 {
+		for _, id := range c.ArticleID {
+			if !slices.Contains(ids, id) {
+				return nil, fmt.Errorf("article %d is not found in the archive", id)
+			}
+		}
+		ids = c.ArticleID
+	}
+	var locales []string
+	for _, l := range c.Locales {
+		locales = append(locales, g.Config.remoteLocale(l))
+	}
+
+	var plan []*restoreAction
+	for _, id := range ids {
+		a := &zendesk.Article{}
+		if err := archive.ReadJSON(backup.ArticlePath(from, id), a); err != nil {
+			return nil, err
+		}
+		action := &restoreAction{Op: restoreCreate, Article: a}
+		res, err := c.client.ShowArticle(articleLocale(a), id)
+		if err != nil && !zendesk.IsNotFound(err) {
+			return nil, fmt.Errorf("article %d: %w", id, err)
+		}
+		if err == nil {
+			remote := &zendesk.Article{}
+			if err := remote.FromJson(res); err != nil {
+				return nil, err
+			}
+			action.Op = restoreOverwrite
+			action.RemoteUpdatedAt = remote.UpdatedAt
+			if remote.UpdatedAt == a.UpdatedAt {
+				action.Op = restoreUnchanged
+			}
+		}
+		// the existing article is kept when the translations of the locales are restored.
+		if action.Op == restoreCreate || len(locales) == 0 {
+			plan = append(plan, action)
+		}
+
+		for _, locale := range archive.Locales(from, id) {
+			if len(locales) > 0 && !slices.Contains(locales, locale) {
+				continue
+			}
+			t := &zendesk.Translation{}
+			if err := archive.ReadJSON(backup.TranslationPath(from, id, locale), t); err != nil {
+				return nil, err
+			}
+			taction := &restoreAction{Op: restoreCreate, Article: a, Translation: t}
+			if action.Op != restoreCreate {
+				res, err := c.client.ShowTranslation(id, locale)
+				if err != nil && !zendesk.IsNotFound(err) {
+					return nil, fmt.Errorf("translation %d/%s: %w", id, locale, err)
+				}
+				if err == nil {
+					remote := &zendesk.Translation{}
+					if err := remote.FromJson(res); err != nil {
+						return nil, err
+					}
+					taction.Op = restoreOverwrite
+					taction.RemoteUpdatedAt = remote.UpdatedAt
+					if remote.UpdatedAt == t.UpdatedAt {
+						taction.Op = restoreUnchanged
+					}
+				}
+			}
+			plan = append(plan, taction)
+		}
+	}
+	return plan, nil
+}
+
+// restore executes the plan, recording the creates and the overwrites in the audit log.
+func (c *CommandRestore) restore(g *Global, plan []*restoreAction) error {
+	created := map[int]int{}
+	for _, a := range plan {
+		if a.Op == restoreUnchanged {
+			slog.Info("skipped as it is unchanged", a.attrs()...)
+			continue
+		}
+		if a.Translation == nil {
+			r, err := c.restoreArticle(a, created)
+			if err != nil {
+				g.audit(audit.Record{Command: "restore", ArticleID: a.Article.ID, Locale: articleLocale(a.Article), Result: audit.ResultFailed, Error: err.Error()})
+				return fmt.Errorf("article %d: %w", a.Article.ID, err)
+			}
+			g.audit(r)
+			continue
+		}
+		r, err := c.restoreTranslation(a, created)
+		if err != nil {
+			g.audit(audit.Record{Command: "restore", ArticleID: a.Article.ID, Locale: a.Translation.Locale, Result: audit.ResultFailed, Error: err.Error()})
+			return fmt.Errorf("translation %d/%s: %w", a.Article.ID, a.Translation.Locale, err)
+		}
+		g.audit(r)
+	}
+	return nil
+}
+
+func (c *CommandRestore) restoreArticle(a *restoreAction, created map[int]int) (audit.Record, error) {
+	article := *a.Article
+	locale := articleLocale(&article)
+	if a.Op == restoreOverwrite {
+		payload, err := article.ToPayload(false)
+		if err != nil {
+			return audit.Record{}, err
+		}
+		if _, err := c.client.UpdateArticle(locale, article.ID, payload); err != nil {
+			return audit.Record{}, err
+		}
+		slog.Info("restored", a.attrs()...)
+		return audit.Record{Command: "restore", ArticleID: article.ID, Locale: locale, Result: audit.ResultUpdated}, nil
+	}
+
+	article.ID = 0
+	payload, err := article.ToPayload(false)
+	if err != nil {
+		return audit.Record{}, err
+	}
+	res, err := c.client.CreateArticle(locale, article.SectionID, payload)
+	if err != nil {
+		return audit.Record{}, err
+	}
+	remote := &zendesk.Article{}
+	if err := remote.FromJson(res); err != nil {
+		return audit.Record{}, err
+	}
+	created[a.Article.ID] = remote.ID
+	slog.Info("created", append(a.attrs(), "new_article_id", remote.ID)...)
+	return audit.Record{Command: "restore", ArticleID: remote.ID, Locale: locale, Result: audit.ResultCreated}, nil
+}
+
+func (c *CommandRestore) restoreTranslation(a *restoreAction, created map[int]int) (audit.Record, error) {
+	t := *a.Translation
+	t.SourceID = a.Article.ID
+	newID, recreated := created[a.Article.ID]
+	if recreated {
+		t.ID = 0
+		t.SourceID = newID
+	}
+	payload, err := t.ToPayload()
+	if err != nil {
+		return audit.Record{}, err
+	}
+	result := audit.ResultCreated
+	switch {
+	case a.Op == restoreOverwrite || (recreated && t.Locale == articleLocale(a.Article)):
+		_, err = c.client.UpdateTranslation(t.SourceID, t.Locale, payload)
+		result = audit.ResultUpdated
+	default:
+		_, err = c.client.CreateTranslation(t.SourceID, payload)
+	}
+	if err != nil {
+		return audit.Record{}, err
+	}
+	slog.Info("restored", a.attrs()...)
+	return audit.Record{Command: "restore", ArticleID: t.SourceID, Locale: t.Locale, Result: result}, nil
+}
+
+func articleLocale(a *zendesk.Article) string {
+	if a.SourceLocale != "" {
+		return a.SourceLocale
+	}
+	return a.Locale
+}
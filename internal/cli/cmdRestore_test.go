@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tukaelu/zgsync/internal/audit"
+	"github.com/tukaelu/zgsync/internal/backup"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+// restoreClient has article 1 unchanged since the backup with the modified translation ja, and records the writes.
+// Article 2 is deleted.
+type restoreClient struct {
+	zendesk.Client
+	calls []string
+}
+
+func (c *restoreClient) ShowArticle(locale string, articleID int) (string, error) {
+	if articleID != 1 {
+		return "", &zendesk.StatusError{StatusCode: http.StatusNotFound}
+	}
+	return `{"article":{"id":1,"updated_at":"2024-01-01T00:00:00Z"}}`, nil
+}
+
+func (c *restoreClient) ShowTranslation(articleID int, locale string) (string, error) {
+	if locale != "ja" {
+		return "", &zendesk.StatusError{StatusCode: http.StatusNotFound}
+	}
+	return `{"translation":{"id":11,"locale":"ja","updated_at":"2024-02-01T00:00:00Z"}}`, nil
+}
+
+func (c *restoreClient) CreateArticle(locale string, sectionID int, payload string) (string, error) {
+	c.calls = append(c.calls, fmt.Sprintf("CreateArticle %s %d", locale, sectionID))
+	return `{"article":{"id":3}}`, nil
+}
+
+func (c *restoreClient) UpdateTranslation(articleID int, locale string, payload string) (string, error) {
+	c.calls = append(c.calls, fmt.Sprintf("UpdateTranslation %d %s", articleID, locale))
+	return "{}", nil
+}
+
+func (c *restoreClient) CreateTranslation(articleID int, payload string) (string, error) {
+	c.calls = append(c.calls, fmt.Sprintf("CreateTranslation %d", articleID))
+	return "{}", nil
+}
+
+func TestRestore(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "backup.zip")
+	w, err := backup.Create(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := map[string]any{
+		backup.ManifestName:                       backup.Manifest{Brands: []backup.Brand{{Subdomain: "old"}}},
+		backup.ArticlePath("old", 1):              zendesk.Article{ID: 1, Locale: "ja", SectionID: 10, UpdatedAt: "2024-01-01T00:00:00Z"},
+		backup.TranslationPath("old", 1, "ja"):    zendesk.Translation{ID: 11, SourceID: 1, Locale: "ja", UpdatedAt: "2024-01-01T00:00:00Z"},
+		backup.TranslationPath("old", 1, "en-us"): zendesk.Translation{ID: 12, SourceID: 1, Locale: "en-us"},
+		backup.ArticlePath("old", 2):              zendesk.Article{ID: 2, Locale: "ja", SectionID: 10},
+		backup.TranslationPath("old", 2, "ja"):    zendesk.Translation{ID: 21, SourceID: 2, Locale: "ja"},
+		backup.TranslationPath("old", 2, "en-us"): zendesk.Translation{ID: 22, SourceID: 2, Locale: "en-us"},
+	}
+	for name, v := range entries {
+		if err := w.WriteJSON(name, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	auditLog := filepath.Join(t.TempDir(), "audit.log")
+	g := &Global{Config: Config{Subdomain: "example", DefaultLocale: "ja", AuditLog: AuditLog{Path: auditLog}}}
+	client := &restoreClient{}
+	c := &CommandRestore{Archive: archive, client: client}
+	a, err := backup.Open(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, err := c.source(g, a)
+	if err != nil || from != "old" {
+		t.Fatalf("source() = %s, %v", from, err)
+	}
+	plan, err := c.plan(g, a, from)
+	if err != nil {
+		t.Fatalf("plan() failed: %v", err)
+	}
+	var ops []string
+	for _, action := range plan {
+		ops = append(ops, action.Op+": "+action.String())
+	}
+	expected := []string{
+		`unchanged: article 1 ""`,
+		`create: translation 1/en-us`,
+		`overwrite: translation 1/ja (remote updated at 2024-02-01T00:00:00Z, backed up at 2024-01-01T00:00:00Z)`,
+		`create: article 2 ""`,
+		`create: translation 2/en-us`,
+		`create: translation 2/ja`,
+	}
+	if !reflect.DeepEqual(ops, expected) {
+		t.Errorf("plan() = %q, want %q", ops, expected)
+	}
+
+	if err := c.restore(g, plan); err != nil {
+		t.Fatalf("restore() failed: %v", err)
+	}
+	calls := []string{
+		"CreateTranslation 1",
+		"UpdateTranslation 1 ja",
+		"CreateArticle ja 10",
+		"CreateTranslation 3",
+		"UpdateTranslation 3 ja",
+	}
+	if !reflect.DeepEqual(client.calls, calls) {
+		t.Errorf("restore() called %q, want %q", client.calls, calls)
+	}
+
+	b, err := os.ReadFile(auditLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var results []string
+	for _, line := range strings.Split(strings.TrimSpace(string(b)), "\n") {
+		var r audit.Record
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, fmt.Sprintf("%s %d/%s %s", r.Command, r.ArticleID, r.Locale, r.Result))
+	}
+	records := []string{
+		"restore 1/en-us created",
+		"restore 1/ja updated",
+		"restore 3/ja created",
+		"restore 3/en-us created",
+		"restore 3/ja updated",
+	}
+	if !reflect.DeepEqual(results, records) {
+		t.Errorf("restore() audited %q, want %q", results, records)
+	}
+}
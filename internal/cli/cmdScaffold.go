@@ -0,0 +1,203 @@
+package cli
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+type CommandScaffold struct {
+	CSV         string         `name:"csv" help:"Specify the CSV file of the plan with the title, section, locale and labels columns." type:"existingfile" required:""`
+	Create      bool           `name:"create" help:"It creates the empty draft articles remotely and saves their translations, as the empty command does."`
+	SaveArticle bool           `name:"save-article" help:"It saves the articles in addition to the translations when creating them remotely."`
+	client      zendesk.Client `kong:"-"`
+}
+
+// scaffoldRow is a planned article. The section is a section ID or a key of the sections config.
+type scaffoldRow struct {
+	Line    int
+	Title   string
+	Section string
+	Locale  string
+	Labels  []string
+}
+
+func (c *CommandScaffold) AfterApply(g *Global) error {
+	c.client = g.newClient(g.Config.Subdomain)
+	return nil
+}
+
+func (c *CommandScaffold) Run(g *Global) error {
+	f, err := os.Open(c.CSV)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	rows, err := readScaffoldCSV(f)
+	if err != nil {
+		return fmt.Errorf("%s:%w", c.CSV, err)
+	}
+
+	for _, row := range rows {
+		sectionID, dir, err := g.Config.resolveSection(row.Section)
+		if err != nil {
+			return fmt.Errorf("%s:%d: %w", c.CSV, row.Line, err)
+		}
+		locale := row.Locale
+		if locale == "" {
+			locale = g.Config.DefaultLocale
+		}
+		if c.Create {
+			empty := &CommandEmpty{
+				SectionID:   sectionID,
+				Title:       row.Title,
+				Locale:      locale,
+				Labels:      row.Labels,
+				SaveArticle: c.SaveArticle,
+				client:      c.client,
+				saveDir:     dir,
+			}
+			if err := empty.Run(g); err != nil {
+				return fmt.Errorf("%s:%d: %w", c.CSV, row.Line, err)
+			}
+			slog.Info("created", "title", row.Title)
+			continue
+		}
+
+		path, err := writeArticleStub(g, dir, sectionID, locale, row)
+		if err != nil {
+			return fmt.Errorf("%s:%d: %w", c.CSV, row.Line, err)
+		}
+		if path == "" {
+			continue
+		}
+		slog.Info("scaffolded", "file", path)
+	}
+	return nil
+}
+
+// resolveSection returns the section ID and the directory of the section given by an ID or a key.
+func (c *Config) resolveSection(section string) (int, string, error) {
+	if id, err := strconv.Atoi(section); err == nil {
+		return id, c.ContentsDir, nil
+	}
+	id, ok := c.Sections[section]
+	if !ok {
+		return 0, "", fmt.Errorf("section %s is neither a section ID nor defined in sections", section)
+	}
+	return id, filepath.Join(c.ContentsDir, filepath.FromSlash(section)), nil
+}
+
+// writeArticleStub writes the new article without an ID, or returns an empty path if it already exists.
+func writeArticleStub(g *Global, dir string, sectionID int, locale string, row scaffoldRow) (string, error) {
+	name := slugify(row.Title)
+	if name == "" {
+		return "", fmt.Errorf("title %q cannot be used as the file name", row.Title)
+	}
+	path := filepath.Join(dir, name+".md")
+	if _, err := os.Stat(path); err == nil {
+		slog.Info("skipped as it already exists", "file", path)
+		return "", nil
+	}
+
+	articleTmpl, _, err := g.Config.frontMatterTemplates()
+	if err != nil {
+		return "", err
+	}
+	commentsDisabled := g.Config.DefaultCommentsDisabled
+	a := &zendesk.Article{
+		Draft:            true,
+		CommentsDisabled: &commentsDisabled,
+		Locale:           locale,
+		SectionID:        sectionID,
+		Title:            row.Title,
+		LabelNames:       row.Labels,
+		Brand:            g.Config.Brand,
+	}
+	if err := articleTmpl.ApplyDefaults(a); err != nil {
+		return "", err
+	}
+	if a.PermissionGroupID == 0 {
+		a.PermissionGroupID = g.Config.DefaultPermissionGroupID
+	}
+	if a.UserSegmentID == nil && len(a.UserSegmentIDs) == 0 {
+		a.UserSegmentID = g.Config.DefailtUserSegmentID
+	}
+	if err := a.SaveWithTemplate(path, false, articleTmpl); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// readScaffoldCSV reads the rows of the plan, whose labels are separated by commas or semicolons.
+func readScaffoldCSV(r io.Reader) ([]scaffoldRow, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"title", "section"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("1: %s column is required", name)
+		}
+	}
+
+	var rows []scaffoldRow
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		row := scaffoldRow{
+			Line:    line,
+			Title:   field("title"),
+			Section: field("section"),
+			Locale:  field("locale"),
+		}
+		for _, label := range strings.FieldsFunc(field("labels"), func(r rune) bool { return r == ',' || r == ';' }) {
+			if label = strings.TrimSpace(label); label != "" {
+				row.Labels = append(row.Labels, label)
+			}
+		}
+		if row.Title == "" && row.Section == "" {
+			continue
+		}
+		if row.Title == "" {
+			return nil, fmt.Errorf("%d: title is required", line)
+		}
+		if row.Section == "" {
+			return nil, fmt.Errorf("%d: section is required", line)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
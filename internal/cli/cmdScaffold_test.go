@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+func TestReadScaffoldCSV(t *testing.T) {
+	f, err := os.Open("testdata/plan.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := readScaffoldCSV(f)
+	if err != nil {
+		t.Fatalf("readScaffoldCSV() failed: %v", err)
+	}
+	expected := []scaffoldRow{
+		{Line: 2, Title: "Getting started", Section: "guides", Labels: []string{"setup", "beginner"}},
+		{Line: 3, Title: "Release notes", Section: "1234", Locale: "en"},
+		{Line: 5, Title: "API reference", Section: "guides/api", Locale: "ja", Labels: []string{"api", "reference"}},
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("readScaffoldCSV() = %+v, want %+v", rows, expected)
+	}
+
+	tests := []struct {
+		csv string
+		err string
+	}{
+		{csv: "title,locale\nfoo,ja\n", err: "1: section column is required"},
+		{csv: "title,section\nfoo,\n", err: "2: section is required"},
+		{csv: "title,section\nfoo,1\n,2\n", err: "3: title is required"},
+	}
+	for _, tt := range tests {
+		_, err := readScaffoldCSV(strings.NewReader(tt.csv))
+		if err == nil || err.Error() != tt.err {
+			t.Errorf("readScaffoldCSV(%q) error = %v, want %s", tt.csv, err, tt.err)
+		}
+	}
+}
+
+func TestWriteArticleStub(t *testing.T) {
+	dir := t.TempDir()
+	g := &Global{Config: Config{
+		ContentsDir:              dir,
+		DefaultLocale:            "ja",
+		DefaultPermissionGroupID: 123,
+		Sections:                 map[string]int{"guides": 456},
+	}}
+	sectionID, sectionDir, err := g.Config.resolveSection("guides")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sectionID != 456 || sectionDir != filepath.Join(dir, "guides") {
+		t.Errorf("resolveSection() = %d, %s", sectionID, sectionDir)
+	}
+	if _, _, err := g.Config.resolveSection("unknown"); err == nil {
+		t.Error("resolveSection() should fail for an undefined key")
+	}
+
+	row := scaffoldRow{Title: "Getting started", Section: "guides", Labels: []string{"setup"}}
+	path, err := writeArticleStub(g, sectionDir, sectionID, "ja", row)
+	if err != nil {
+		t.Fatalf("writeArticleStub() failed: %v", err)
+	}
+	if path != filepath.Join(dir, "guides", "getting-started.md") {
+		t.Errorf("writeArticleStub() path = %s", path)
+	}
+	a := &zendesk.Article{}
+	if err := a.FromFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if a.ID != 0 || a.Title != row.Title || a.SectionID != 456 || a.PermissionGroupID != 123 || !a.Draft || !reflect.DeepEqual(a.LabelNames, row.Labels) {
+		t.Errorf("writeArticleStub() wrote %+v", a)
+	}
+
+	// the existing stub is not overwritten.
+	path, err = writeArticleStub(g, sectionDir, sectionID, "ja", row)
+	if err != nil || path != "" {
+		t.Errorf("writeArticleStub() = %q, %v, want skipped", path, err)
+	}
+}
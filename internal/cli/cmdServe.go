@@ -0,0 +1,297 @@
+package cli
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/tukaelu/zgsync/internal/metrics"
+	"github.com/tukaelu/zgsync/internal/state"
+)
+
+const (
+	webhookSignatureHeader          = "X-Zendesk-Webhook-Signature"
+	webhookSignatureTimestampHeader = "X-Zendesk-Webhook-Signature-Timestamp"
+	articleEventPrefix              = "zen:event-type:article."
+	// webhookTolerance is how far the signature timestamp may be from now, which bounds the replays of a signed request.
+	webhookTolerance = 5 * time.Minute
+)
+
+// ignoredArticleEvents are the article events that do not change the content of the article.
+var ignoredArticleEvents = []string{"comment_", "subscription_", "vote_"}
+
+type CommandServe struct {
+	Addr        string `name:"addr" help:"Specify the address to listen on. Listening on other than the loopback address requires --secret." default:"127.0.0.1:8080"`
+	Webhook     bool   `name:"webhook" help:"It serves the endpoint receiving Zendesk webhooks to pull the updated articles." required:""`
+	Path        string `name:"path" help:"Specify the path of the webhook endpoint." default:"/webhook"`
+	MetricsPath string `name:"metrics-path" help:"Specify the path of the endpoint exposing the Prometheus metrics. An empty value disables it." default:"/metrics"`
+	Secret      string `name:"secret" help:"Specify the signing secret of the webhook to verify the requests." env:"ZGSYNC_WEBHOOK_SECRET"`
+	Commit      bool   `name:"commit" help:"It commits the pulled files to the git repository of the contents directory."`
+	Raw         bool   `name:"raw" help:"It pulls raw data without converting it from HTML to Markdown."`
+}
+
+func (c *CommandServe) Run(g *Global) error {
+	if c.Secret == "" && !isLoopback(c.Addr) {
+		return fmt.Errorf("--secret is required to listen on %s, which is not the loopback address", c.Addr)
+	}
+	queue := make(chan int, 100)
+	mux := http.NewServeMux()
+	mux.Handle(c.Path, &webhookHandler{secret: c.Secret, queue: queue, now: time.Now})
+	if c.MetricsPath != "" {
+		mux.Handle(c.MetricsPath, metrics.Default.Handler())
+	}
+
+	srv := &http.Server{Addr: c.Addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for id := range queue {
+			if err := c.pull(g, id); err != nil {
+				slog.Error("failed to pull", "article_id", id, "error", err)
+			}
+		}
+	}()
+
+	errCh := make(chan error, 1)
+	go func() {
+		slog.Info("listening", "addr", c.Addr, "path", c.Path)
+		errCh <- srv.ListenAndServe()
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		err = srv.Shutdown(shutdownCtx)
+	}
+	close(queue)
+	<-done
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// pull pulls the translations of the article tracked in the sync state, or the default locale if none is tracked.
+func (c *CommandServe) pull(g *Global, articleID int) error {
+	s, err := g.LoadState()
+	if err != nil {
+		return err
+	}
+	locales, withArticle := trackedLocales(s, g.Config.Brand, articleID)
+	if len(locales) == 0 {
+		locales = []string{g.Config.DefaultLocale}
+	}
+
+	for _, locale := range locales {
+		p := &CommandPull{Locale: locale, Raw: c.Raw, SaveArticle: withArticle, ArticleIDs: []int{articleID}}
+		if err := p.AfterApply(g); err != nil {
+			return err
+		}
+		if err := p.Run(g); err != nil {
+			return err
+		}
+	}
+	slog.Info("pulled", "article_id", articleID, "locales", locales)
+
+	if !c.Commit {
+		return nil
+	}
+	// the state is loaded again for the files written by the pulls.
+	if s, err = g.LoadState(); err != nil {
+		return err
+	}
+	return commitContents(g.Config.ContentsDir, fmt.Sprintf("Pull article %d from Zendesk", articleID), trackedFiles(s, articleID))
+}
+
+// trackedFiles returns the paths of the article and translation files of the article tracked in the sync state.
+func trackedFiles(s *state.Store, articleID int) []string {
+	var files []string
+	for _, key := range s.Keys() {
+		if e := s.Files[key]; e.ArticleID == articleID && e.Kind != state.KindPost {
+			files = append(files, s.Abs(key))
+		}
+	}
+	return files
+}
+
+// trackedLocales returns the tracked locales of the article, and whether the article file is tracked.
+func trackedLocales(s *state.Store, brand string, articleID int) ([]string, bool) {
+	var locales []string
+	withArticle := false
+	for _, key := range s.Keys() {
+		e := s.Files[key]
+		if e.ArticleID != articleID || e.Brand != brand {
+			continue
+		}
+		if e.Kind == state.KindArticle {
+			withArticle = true
+			continue
+		}
+		if e.Locale != "" {
+			locales = append(locales, e.Locale)
+		}
+	}
+	sort.Strings(locales)
+	return locales, withArticle
+}
+
+// commitContents commits the changes of the files in the git repository of the contents directory.
+func commitContents(contentsDir string, message string, files []string) error {
+	if len(files) == 0 {
+		return nil
+	}
+	paths := append([]string{"--"}, files...)
+	out, err := runCommand("git", append([]string{"-C", contentsDir, "status", "--porcelain"}, paths...), nil)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(string(out)) == "" {
+		return nil
+	}
+	if _, err := runCommand("git", append([]string{"-C", contentsDir, "add"}, paths...), nil); err != nil {
+		return err
+	}
+	_, err = runCommand("git", append([]string{"-C", contentsDir, "commit", "--quiet", "-m", message}, paths...), nil)
+	return err
+}
+
+// isLoopback reports whether the address listens only on the loopback interface.
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// webhookHandler receives the webhooks of the article events and queues the IDs of the articles to pull.
+type webhookHandler struct {
+	secret string
+	queue  chan<- int
+	now    func() time.Time
+}
+
+func (h *webhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if h.secret != "" {
+		ts := r.Header.Get(webhookSignatureTimestampHeader)
+		if !verifyWebhookSignature(h.secret, ts, body, r.Header.Get(webhookSignatureHeader)) {
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+		if !isRecentTimestamp(ts, h.now()) {
+			http.Error(w, "expired signature", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	id, ok, err := parseWebhookEvent(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	select {
+	case h.queue <- id:
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		http.Error(w, "too many pending events", http.StatusServiceUnavailable)
+	}
+}
+
+// verifyWebhookSignature verifies the base64 encoded HMAC-SHA256 of the timestamp followed by the body.
+// refs: https://developer.zendesk.com/documentation/webhooks/verifying/
+func verifyWebhookSignature(secret, timestamp string, body []byte, signature string) bool {
+	if timestamp == "" || signature == "" {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write(body)
+	expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// isRecentTimestamp reports whether the signature timestamp is within webhookTolerance of now.
+func isRecentTimestamp(timestamp string, now time.Time) bool {
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return false
+	}
+	d := now.Sub(t)
+	return d <= webhookTolerance && d >= -webhookTolerance
+}
+
+type webhookEvent struct {
+	Type   string `json:"type"`
+	Detail struct {
+		ID json.Number `json:"id"`
+	} `json:"detail"`
+	ArticleID json.Number `json:"article_id"`
+}
+
+// parseWebhookEvent returns the ID of the article to pull, and false if the event does not require pulling.
+func parseWebhookEvent(body []byte) (int, bool, error) {
+	var ev webhookEvent
+	if err := json.Unmarshal(body, &ev); err != nil {
+		return 0, false, fmt.Errorf("invalid payload: %w", err)
+	}
+
+	raw := ev.ArticleID
+	if ev.Type != "" {
+		name, ok := strings.CutPrefix(ev.Type, articleEventPrefix)
+		if !ok {
+			return 0, false, nil
+		}
+		for _, prefix := range ignoredArticleEvents {
+			if strings.HasPrefix(name, prefix) {
+				return 0, false, nil
+			}
+		}
+		raw = ev.Detail.ID
+	}
+	if raw == "" {
+		return 0, false, fmt.Errorf("article ID is not found in the payload")
+	}
+	id, err := strconv.Atoi(raw.String())
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid article ID %s", raw)
+	}
+	return id, true, nil
+}
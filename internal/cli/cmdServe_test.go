@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tukaelu/zgsync/internal/state"
+)
+
+func TestParseWebhookEvent(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected int
+		ok       bool
+		notError bool
+	}{
+		{"published", `{"type":"zen:event-type:article.published","detail":{"id":"123"}}`, 123, true, true},
+		{"author changed", `{"type":"zen:event-type:article.author_changed","detail":{"id":"456"}}`, 456, true, true},
+		{"vote", `{"type":"zen:event-type:article.vote_created","detail":{"id":"123"}}`, 0, false, true},
+		{"other event", `{"type":"zen:event-type:user.created","detail":{"id":"1"}}`, 0, false, true},
+		{"custom", `{"article_id":789}`, 789, true, true},
+		{"custom string", `{"article_id":"789"}`, 789, true, true},
+		{"no id", `{}`, 0, false, false},
+		{"invalid", `{`, 0, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, ok, err := parseWebhookEvent([]byte(tt.body))
+			if tt.notError == (err != nil) {
+				t.Fatalf("parseWebhookEvent() failed: %v", err)
+			}
+			if id != tt.expected || ok != tt.ok {
+				t.Errorf("parseWebhookEvent() failed: got %v, %v, want %v, %v", id, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func sign(secret, ts, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts + body))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestWebhookHandler(t *testing.T) {
+	body := `{"type":"zen:event-type:article.published","detail":{"id":"123"}}`
+	ts := "2024-01-01T00:00:00Z"
+	old := "2023-12-31T23:50:00Z"
+	now := func() time.Time { return time.Date(2024, 1, 1, 0, 1, 0, 0, time.UTC) }
+	tests := []struct {
+		name      string
+		method    string
+		ts        string
+		signature string
+		expected  int
+		queued    bool
+	}{
+		{"signed", http.MethodPost, ts, sign("secret", ts, body), http.StatusAccepted, true},
+		{"wrong signature", http.MethodPost, ts, sign("other", ts, body), http.StatusUnauthorized, false},
+		{"not signed", http.MethodPost, ts, "", http.StatusUnauthorized, false},
+		{"replayed", http.MethodPost, old, sign("secret", old, body), http.StatusUnauthorized, false},
+		{"get", http.MethodGet, ts, "", http.StatusMethodNotAllowed, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queue := make(chan int, 1)
+			h := &webhookHandler{secret: "secret", queue: queue, now: now}
+			req := httptest.NewRequest(tt.method, "/webhook", strings.NewReader(body))
+			req.Header.Set(webhookSignatureTimestampHeader, tt.ts)
+			if tt.signature != "" {
+				req.Header.Set(webhookSignatureHeader, tt.signature)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.expected {
+				t.Errorf("ServeHTTP() failed: got %v, want %v", rec.Code, tt.expected)
+			}
+			if queued := len(queue) == 1; queued != tt.queued {
+				t.Errorf("ServeHTTP() failed: queued %v, want %v", queued, tt.queued)
+			}
+		})
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	tests := []struct {
+		addr     string
+		expected bool
+	}{
+		{"127.0.0.1:8080", true},
+		{"localhost:8080", true},
+		{"[::1]:8080", true},
+		{":8080", false},
+		{"0.0.0.0:8080", false},
+		{"192.0.2.1:8080", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if got := isLoopback(tt.addr); got != tt.expected {
+				t.Errorf("isLoopback() failed: got %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTrackedLocales(t *testing.T) {
+	s := &state.Store{Files: map[string]*state.Entry{
+		"100.md":          {Kind: state.KindArticle, ArticleID: 100},
+		"100-ja.md":       {Kind: state.KindTranslation, ArticleID: 100, Locale: "ja"},
+		"100-en-us.md":    {Kind: state.KindTranslation, ArticleID: 100, Locale: "en-us"},
+		"200-ja.md":       {Kind: state.KindTranslation, ArticleID: 200, Locale: "ja"},
+		"other/100-fr.md": {Kind: state.KindTranslation, Brand: "other", ArticleID: 100, Locale: "fr"},
+	}}
+	locales, withArticle := trackedLocales(s, "", 100)
+	if strings.Join(locales, ",") != "en-us,ja" || !withArticle {
+		t.Errorf("trackedLocales() failed: got %v, %v", locales, withArticle)
+	}
+	locales, withArticle = trackedLocales(s, "", 200)
+	if strings.Join(locales, ",") != "ja" || withArticle {
+		t.Errorf("trackedLocales() failed: got %v, %v", locales, withArticle)
+	}
+}
+
+func TestCommitContents(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	dir := t.TempDir()
+	git := func(args ...string) string {
+		t.Helper()
+		out, err := runCommand("git", append([]string{"-C", dir}, args...), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(out)
+	}
+	write := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	git("init", "--quiet")
+	pulled := write("100-ja.md", "old\n")
+	write("200-ja.md", "old\n")
+	git("add", "--all")
+	git("commit", "--quiet", "-m", "init")
+
+	write("100-ja.md", "new\n")
+	write("200-ja.md", "edited locally\n")
+	write("draft.md", "new file\n")
+	if err := commitContents(dir, "Pull article 100 from Zendesk", []string{pulled}); err != nil {
+		t.Fatalf("commitContents() failed: %v", err)
+	}
+
+	if got := git("show", "--name-only", "--format=", "HEAD"); got != "100-ja.md\n" {
+		t.Errorf("commitContents() failed: committed %q", got)
+	}
+	if got := git("status", "--porcelain"); got != " M 200-ja.md\n?? draft.md\n" {
+		t.Errorf("commitContents() failed: the other changes are %q", got)
+	}
+
+	// nothing is committed when the pulled files have not changed.
+	if err := commitContents(dir, "Pull article 100 from Zendesk", []string{pulled}); err != nil {
+		t.Fatalf("commitContents() failed: %v", err)
+	}
+	if got := git("rev-list", "--count", "HEAD"); got != "2\n" {
+		t.Errorf("commitContents() failed: got %s commits", got)
+	}
+}
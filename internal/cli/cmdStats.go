@@ -0,0 +1,201 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tukaelu/zgsync/internal/state"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+type CommandStats struct {
+	Locale     string         `name:"locale" short:"l" help:"Specify the locale of the articles. If not specified, the default locale will be used."`
+	Format     string         `name:"format" short:"o" help:"Specify the output format (table, csv or json)." enum:"table,csv,json" default:"table"`
+	StaleDays  int            `name:"stale-days" help:"Articles not edited for more than this number of days are reported as stale." default:"180"`
+	MinRating  float64        `name:"min-rating" help:"Articles whose ratio of up votes is below this value are reported as low-rated." default:"0.5"`
+	MinVotes   int            `name:"min-votes" help:"Specify the number of votes required to judge the rating." default:"5"`
+	Attention  bool           `name:"attention" short:"a" help:"It reports only the articles that need attention."`
+	Columns    []string       `name:"columns" help:"Specify the columns of the table separated by commas: id, votes, rating, draft, outdated, edited_at, attention, author, title and html_url."`
+	Sort       string         `name:"sort" help:"Specify the order of the articles: id, votes, rating or edited_at." enum:"id,votes,rating,edited_at" default:"id"`
+	ArticleIDs []int          `arg:"" optional:"" help:"Specify the article IDs. If not specified, the articles tracked in the sync state will be reported."`
+	client     zendesk.Client `kong:"-"`
+}
+
+type articleStats struct {
+	ID        int      `json:"id"`
+	Title     string   `json:"title"`
+	Author    string   `json:"author"`
+	VoteSum   int      `json:"vote_sum"`
+	VoteCount int      `json:"vote_count"`
+	Rating    *float64 `json:"rating"`
+	Draft     bool     `json:"draft"`
+	Outdated  bool     `json:"outdated"`
+	EditedAt  string   `json:"edited_at"`
+	HtmlURL   string   `json:"html_url"`
+	Attention []string `json:"attention"`
+}
+
+func (c *CommandStats) AfterApply(g *Global) error {
+	c.client = g.newClient(g.Config.Subdomain)
+	return nil
+}
+
+// newStatsTable returns the table of the stats, without html_url and draft by default.
+func newStatsTable() *table {
+	return newTable(
+		[]string{"id", "votes", "rating", "draft", "outdated", "edited_at", "attention", "author", "title", "html_url"},
+		"id", "votes", "rating", "outdated", "edited_at", "attention", "author", "title",
+	)
+}
+
+func (c *CommandStats) Run(g *Global) error {
+	if c.Locale == "" {
+		c.Locale = g.Config.DefaultLocale
+	}
+	t := newStatsTable()
+	if err := t.validate(c.Columns); err != nil {
+		return err
+	}
+
+	ids := c.ArticleIDs
+	if len(ids) == 0 {
+		s, err := g.LoadState()
+		if err != nil {
+			return err
+		}
+		ids = trackedArticleIDs(s, g.Config.Brand)
+	}
+
+	now := time.Now()
+	users := newUserNames(c.client)
+	var stats []articleStats
+	for _, id := range ids {
+		res, err := c.client.ShowArticle(c.Locale, id)
+		if err != nil {
+			return fmt.Errorf("article %d: %w", id, err)
+		}
+		a := &zendesk.Article{}
+		if err := a.FromJson(res); err != nil {
+			return err
+		}
+		st := c.evaluate(a, now)
+		st.Author = users.name(a.AuthorID)
+		if c.Attention && len(st.Attention) == 0 {
+			continue
+		}
+		stats = append(stats, st)
+	}
+	sortStats(stats, c.Sort)
+
+	switch c.Format {
+	case "json":
+		b, err := json.MarshalIndent(stats, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+		return nil
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		_ = w.Write([]string{"id", "title", "author", "vote_sum", "vote_count", "rating", "draft", "outdated", "edited_at", "attention", "html_url"})
+		for _, st := range stats {
+			_ = w.Write([]string{fmt.Sprint(st.ID), st.Title, st.Author, fmt.Sprint(st.VoteSum), fmt.Sprint(st.VoteCount), formatRating(st.Rating), fmt.Sprint(st.Draft), fmt.Sprint(st.Outdated), st.EditedAt, strings.Join(st.Attention, ","), st.HtmlURL})
+		}
+		w.Flush()
+		return w.Error()
+	}
+
+	for _, st := range stats {
+		t.add(fmt.Sprint(st.ID), fmt.Sprintf("%d/%d", st.VoteSum, st.VoteCount), formatRating(st.Rating), fmt.Sprint(st.Draft), fmt.Sprint(st.Outdated), st.EditedAt, strings.Join(st.Attention, ","), st.Author, st.Title, st.HtmlURL)
+	}
+	return t.print(c.Columns)
+}
+
+func (c *CommandStats) evaluate(a *zendesk.Article, now time.Time) articleStats {
+	st := articleStats{
+		ID:        a.ID,
+		Title:     a.Title,
+		VoteSum:   a.VoteSum,
+		VoteCount: a.VoteCount,
+		Draft:     a.Draft,
+		Outdated:  a.Outdated,
+		EditedAt:  a.EditedAt,
+		HtmlURL:   a.HtmlURL,
+		Attention: []string{},
+	}
+	st.Rating = rating(a.VoteSum, a.VoteCount)
+	if st.Rating != nil && a.VoteCount >= c.MinVotes && *st.Rating < c.MinRating {
+		st.Attention = append(st.Attention, "low-rated")
+	}
+	if edited, err := time.Parse(time.RFC3339, a.EditedAt); err == nil && now.Sub(edited) > time.Duration(c.StaleDays)*24*time.Hour {
+		st.Attention = append(st.Attention, "stale")
+	}
+	if a.Outdated {
+		st.Attention = append(st.Attention, "outdated")
+	}
+	return st
+}
+
+// rating returns the ratio of the up votes rounded to two decimal places, or nil if the article has no votes.
+func rating(voteSum, voteCount int) *float64 {
+	if voteCount == 0 {
+		return nil
+	}
+	// vote_sum is the sum of up votes (+1) and down votes (-1).
+	r := math.Round(float64(voteCount+voteSum)/2/float64(voteCount)*100) / 100
+	return &r
+}
+
+// sortStats sorts the stats by the key of --sort, putting the articles that need rewrites first.
+func sortStats(stats []articleStats, key string) {
+	sort.SliceStable(stats, func(i, j int) bool {
+		a, b := stats[i], stats[j]
+		switch key {
+		case "votes":
+			if a.VoteSum != b.VoteSum {
+				return a.VoteSum < b.VoteSum
+			}
+		case "rating":
+			if (a.Rating == nil) != (b.Rating == nil) {
+				return b.Rating == nil
+			}
+			if a.Rating != nil && *a.Rating != *b.Rating {
+				return *a.Rating < *b.Rating
+			}
+		case "edited_at":
+			if a.EditedAt != b.EditedAt {
+				return a.EditedAt < b.EditedAt
+			}
+		}
+		return a.ID < b.ID
+	})
+}
+
+func formatRating(r *float64) string {
+	if r == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", *r)
+}
+
+// trackedArticleIDs returns the IDs of the articles of the brand tracked in the sync state.
+func trackedArticleIDs(s *state.Store, brand string) []int {
+	seen := map[int]bool{}
+	var ids []int
+	for _, key := range s.Keys() {
+		id := s.Files[key].ArticleID
+		if id == 0 || seen[id] || s.Files[key].Brand != brand || s.Files[key].Kind == state.KindPost {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+func TestStatsEvaluate(t *testing.T) {
+	now := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	c := &CommandStats{StaleDays: 180, MinRating: 0.5, MinVotes: 5}
+	tests := []struct {
+		name      string
+		article   zendesk.Article
+		rating    string
+		attention string
+	}{
+		{"no votes", zendesk.Article{EditedAt: "2024-06-01T00:00:00Z"}, "-", ""},
+		{"well rated", zendesk.Article{VoteSum: 8, VoteCount: 10, EditedAt: "2024-06-01T00:00:00Z"}, "0.90", ""},
+		{"low rated", zendesk.Article{VoteSum: -4, VoteCount: 6, EditedAt: "2024-06-01T00:00:00Z"}, "0.17", "low-rated"},
+		{"too few votes", zendesk.Article{VoteSum: -2, VoteCount: 2, EditedAt: "2024-06-01T00:00:00Z"}, "0.00", ""},
+		{"stale and outdated", zendesk.Article{Outdated: true, EditedAt: "2023-01-01T00:00:00Z"}, "-", "stale,outdated"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := c.evaluate(&tt.article, now)
+			if actual := formatRating(st.Rating); actual != tt.rating {
+				t.Errorf("rating failed: got %v, want %v", actual, tt.rating)
+			}
+			if actual := strings.Join(st.Attention, ","); actual != tt.attention {
+				t.Errorf("attention failed: got %v, want %v", actual, tt.attention)
+			}
+		})
+	}
+}
+
+func TestSortStats(t *testing.T) {
+	low, high := 0.2, 0.9
+	tests := []struct {
+		key      string
+		expected string
+	}{
+		{"id", "[1 2 3 4]"},
+		{"votes", "[3 1 4 2]"},
+		{"rating", "[3 1 2 4]"},
+		{"edited_at", "[4 2 3 1]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			stats := []articleStats{
+				{ID: 4, VoteSum: 0, EditedAt: "2024-01-01T00:00:00Z"},
+				{ID: 2, VoteSum: 4, Rating: &high, EditedAt: "2024-02-01T00:00:00Z"},
+				{ID: 1, VoteSum: 0, Rating: &high, EditedAt: "2024-04-01T00:00:00Z"},
+				{ID: 3, VoteSum: -3, Rating: &low, EditedAt: "2024-03-01T00:00:00Z"},
+			}
+			sortStats(stats, tt.key)
+			var got []int
+			for _, st := range stats {
+				got = append(got, st.ID)
+			}
+			if fmt.Sprint(got) != tt.expected {
+				t.Errorf("sortStats() failed: got %v, want %s", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+type CommandSubscribe struct {
+	UserID          int            `name:"user-id" help:"Specify the ID of the user to subscribe. If not specified, the user of the token will be subscribed."`
+	Locale          string         `name:"locale" short:"l" help:"Specify the locale of the subscriptions. If not specified, the default locale will be used."`
+	IncludeComments bool           `name:"include-comments" help:"It subscribes to the comments of the articles as well."`
+	DryRun          bool           `name:"dry-run" help:"It shows the articles to subscribe to without subscribing."`
+	Targets         []string       `arg:"" optional:"" help:"Specify the files or the article IDs to subscribe to."`
+	client          zendesk.Client `kong:"-"`
+}
+
+func (c *CommandSubscribe) AfterApply(g *Global) error {
+	c.client = g.newClient(g.Config.Subdomain)
+	return nil
+}
+
+func (c *CommandSubscribe) Run(g *Global) error {
+	if c.Locale == "" {
+		c.Locale = g.Config.DefaultLocale
+	}
+	locale := g.Config.remoteLocale(c.Locale)
+
+	var ids []int
+	for _, target := range c.Targets {
+		ref, err := resolveFileRef(target)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, ref.ID)
+	}
+	if len(c.Targets) == 0 {
+		s, err := g.LoadState()
+		if err != nil {
+			return err
+		}
+		ids = trackedArticleIDs(s, g.Config.Brand)
+	}
+
+	userID := c.UserID
+	if userID == 0 {
+		res, err := c.client.ShowCurrentUser()
+		if err != nil {
+			return err
+		}
+		u := &zendesk.User{}
+		if err := u.FromJson(res); err != nil {
+			return err
+		}
+		userID = u.ID
+	}
+
+	for _, id := range ids {
+		subscribed, err := c.subscribed(id, userID)
+		if err != nil {
+			return fmt.Errorf("article %d: %w", id, err)
+		}
+		if subscribed {
+			slog.Info("already subscribed", "article_id", id, "user_id", userID)
+			continue
+		}
+		if c.DryRun {
+			slog.Info("would subscribe", "article_id", id, "user_id", userID)
+			continue
+		}
+		sub := &zendesk.Subscription{UserID: userID, SourceLocale: locale, IncludeComments: c.IncludeComments}
+		payload, err := sub.ToPayload()
+		if err != nil {
+			return err
+		}
+		if _, err := c.client.CreateArticleSubscription(id, payload); err != nil {
+			return fmt.Errorf("article %d: %w", id, err)
+		}
+		slog.Info("subscribed", "article_id", id, "user_id", userID)
+	}
+	return nil
+}
+
+// subscribed reports whether the user has subscribed to the article.
+func (c *CommandSubscribe) subscribed(articleID, userID int) (bool, error) {
+	for page := 1; ; page++ {
+		res, err := c.client.ListArticleSubscriptions(articleID, page)
+		if err != nil {
+			return false, err
+		}
+		subs, hasNext, err := zendesk.SubscriptionsFromJson(res)
+		if err != nil {
+			return false, err
+		}
+		for _, s := range subs {
+			if s.UserID == userID {
+				return true, nil
+			}
+		}
+		if !hasNext {
+			return false, nil
+		}
+	}
+}
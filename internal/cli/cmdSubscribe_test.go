@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+// subscribeClient has the user 7 subscribed to the article 2 on the second page of its subscriptions.
+type subscribeClient struct {
+	zendesk.Client
+	created []string
+}
+
+func (c *subscribeClient) ShowCurrentUser() (string, error) {
+	return `{"user":{"id":7,"email":"user@example.com","role":"admin"}}`, nil
+}
+
+func (c *subscribeClient) ListArticleSubscriptions(articleID int, page int) (string, error) {
+	if articleID == 2 && page == 1 {
+		return `{"subscriptions":[{"id":1,"user_id":8}],"next_page":"https://example.zendesk.com/api/v2/help_center/articles/2/subscriptions?page=2"}`, nil
+	}
+	if articleID == 2 {
+		return `{"subscriptions":[{"id":2,"user_id":7}],"next_page":null}`, nil
+	}
+	return `{"subscriptions":[],"next_page":null}`, nil
+}
+
+func (c *subscribeClient) CreateArticleSubscription(articleID int, payload string) (string, error) {
+	c.created = append(c.created, fmt.Sprintf("%d:%s", articleID, payload))
+	return `{"subscription":{"id":3}}`, nil
+}
+
+func TestSubscribe(t *testing.T) {
+	tests := []struct {
+		name     string
+		cmd      CommandSubscribe
+		expected string
+	}{
+		{"user of the token", CommandSubscribe{Targets: []string{"1", "2"}}, `[1:{"subscription":{"user_id":7,"source_locale":"ja"}}]`},
+		{"service account", CommandSubscribe{UserID: 9, Locale: "en-us", IncludeComments: true, Targets: []string{"2"}}, `[2:{"subscription":{"user_id":9,"source_locale":"en-us","include_comments":true}}]`},
+		{"dry run", CommandSubscribe{DryRun: true, Targets: []string{"1"}}, `[]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &subscribeClient{}
+			c := tt.cmd
+			c.client = client
+			g := &Global{Config: Config{ContentsDir: t.TempDir(), DefaultLocale: "ja"}}
+			if err := c.Run(g); err != nil {
+				t.Fatalf("Run() failed: %v", err)
+			}
+			if got := fmt.Sprint(client.created); got != tt.expected {
+				t.Errorf("Run() failed: got %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
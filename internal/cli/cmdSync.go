@@ -0,0 +1,216 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/tukaelu/zgsync/internal/state"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+const (
+	syncPreferLocal  = "prefer-local"
+	syncPreferRemote = "prefer-remote"
+	syncManual       = "manual"
+)
+
+type CommandSync struct {
+	OnConflict string      `name:"on-conflict" enum:"prefer-local,prefer-remote,manual" default:"manual" help:"Specify how to resolve the conflicts: prefer-local, prefer-remote or manual."`
+	KeepGoing  bool        `name:"keep-going" short:"k" help:"It syncs the remaining files even if some files fail."`
+	Message    string      `name:"message" short:"m" help:"Specify the change note of the pushes."`
+	Prune      bool        `name:"prune" help:"It deletes the files of the articles deleted on the remote."`
+	TrashDir   string      `name:"trash-dir" help:"Specify the directory into which --prune moves the files." type:"path"`
+	Confirm    Confirm     `embed:""`
+	Retry      Retry       `embed:""`
+	Files      []string    `arg:"" optional:"" help:"Specify the files or directories to sync. If not specified, the contents directory will be synced." type:"path"`
+	push       CommandPush `kong:"-"`
+}
+
+// orphan is a file tracked in the sync state whose article has been deleted on the remote.
+type orphan struct {
+	file      string
+	articleID int
+}
+
+func (c *CommandSync) AfterApply(g *Global) error {
+	if g.Config.isHugo() {
+		return fmt.Errorf("sync is not supported with the front matter format %s", frontMatterHugo)
+	}
+	files := c.Files
+	if len(files) == 0 {
+		files = []string{g.Config.ContentsDir}
+	}
+	c.push = CommandPush{
+		All:         true,
+		KeepGoing:   c.KeepGoing,
+		Message:     c.Message,
+		OnConflict:  syncConflict(c.OnConflict),
+		OnMismatch:  mismatchAsk,
+		OnDuplicate: "warn",
+		Retry:       c.Retry,
+		Files:       files,
+		pullChanges: true,
+	}
+	c.Confirm.interactive = isTerminal(os.Stdin)
+	return c.push.AfterApply(g)
+}
+
+func (c *CommandSync) Run(g *Global) error {
+	if c.Prune {
+		if err := c.checkOrphans(g); err != nil {
+			return err
+		}
+	}
+	return c.push.Run(g)
+}
+
+// checkOrphans prunes the files of the articles deleted remotely, or excludes them from the push.
+func (c *CommandSync) checkOrphans(g *Global) (err error) {
+	s, err := g.LoadState()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if serr := s.Save(); serr != nil && err == nil {
+			err = serr
+		}
+	}()
+
+	orphans, err := findOrphans(c.push.client, s, g.Config.Brand, c.push.Files)
+	if err != nil {
+		return err
+	}
+	c.push.excluded = map[string]bool{}
+	for _, o := range orphans {
+		slog.Warn("deleted on the remote", "file", o.file, "article_id", o.articleID)
+		c.push.excluded[s.Key(o.file)] = true
+	}
+	if len(orphans) == 0 {
+		return nil
+	}
+	return c.prune(s, orphans)
+}
+
+// findOrphans returns the tracked files in the paths whose articles no longer exist on the remote.
+func findOrphans(client zendesk.Client, s *state.Store, brand string, paths []string) ([]orphan, error) {
+	deleted := map[int]bool{}
+	var orphans []orphan
+	for _, key := range s.Keys() {
+		e := s.Files[key]
+		if e.Kind != state.KindArticle && e.Kind != state.KindTranslation || e.Brand != brand || e.ArticleID == 0 {
+			continue
+		}
+		file := s.Abs(key)
+		if !inPaths(file, paths) {
+			continue
+		}
+		if _, err := os.Stat(file); os.IsNotExist(err) {
+			continue
+		}
+		gone, ok := deleted[e.ArticleID]
+		if !ok {
+			_, err := zendesk.Uncached(client).ShowArticle("", e.ArticleID)
+			if err != nil && !zendesk.IsNotFound(err) {
+				return nil, fmt.Errorf("article %d: %w", e.ArticleID, err)
+			}
+			gone = err != nil
+			deleted[e.ArticleID] = gone
+		}
+		if gone {
+			orphans = append(orphans, orphan{file: file, articleID: e.ArticleID})
+		}
+	}
+	return orphans, nil
+}
+
+func inPaths(file string, paths []string) bool {
+	for _, p := range paths {
+		if abs, err := filepath.Abs(p); err == nil && (abs == file || inDir(abs, file)) {
+			return true
+		}
+	}
+	return false
+}
+
+// prune deletes the files of the orphans, or moves them into --trash-dir, and stops tracking them.
+func (c *CommandSync) prune(s *state.Store, orphans []orphan) error {
+	items := make([]string, 0, len(orphans))
+	for _, o := range orphans {
+		items = append(items, s.Key(o.file))
+	}
+	operation := "Delete"
+	if c.TrashDir != "" {
+		operation = "Move"
+	}
+	ok, err := c.Confirm.confirm(operation, items)
+	if err != nil || !ok {
+		if err == nil {
+			slog.Info("cancelled")
+		}
+		return err
+	}
+	for _, o := range orphans {
+		if c.TrashDir == "" {
+			if err := os.Remove(o.file); err != nil {
+				return err
+			}
+			slog.Info("pruned", "file", o.file)
+		} else {
+			dest := filepath.Join(c.TrashDir, filepath.FromSlash(s.Key(o.file)))
+			if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+				return err
+			}
+			if err := os.Rename(o.file, dest); err != nil {
+				return err
+			}
+			slog.Info("pruned", "file", o.file, "trash", dest)
+		}
+		s.Remove(o.file)
+	}
+	return nil
+}
+
+// syncConflict returns --on-conflict of push resolving the conflicts by the policy of sync.
+func syncConflict(policy string) string {
+	switch policy {
+	case syncPreferLocal:
+		return conflictLocal
+	case syncPreferRemote:
+		return conflictRemote
+	}
+	return conflictAsk
+}
+
+// unchangedSincePull reports whether the file is as it was last pulled and has not been pushed since.
+func unchangedSincePull(s *state.Store, file string) bool {
+	e, ok := s.Lookup(file)
+	if !ok || e.PulledHash == "" || e.PulledAt < e.PushedAt {
+		return false
+	}
+	hash, err := state.HashFile(file)
+	return err == nil && hash == e.PulledHash
+}
+
+// pullChanged overwrites the file not changed locally with the remote translation updated since the last sync.
+func (c *CommandPush) pullChanged(g *Global, file string, articleID int, locale, brand string) error {
+	client, err := c.clientFor(g, brand)
+	if err != nil {
+		return err
+	}
+	res, err := zendesk.Uncached(client).ShowTranslation(articleID, locale)
+	if err != nil {
+		return err
+	}
+	remote := &zendesk.Translation{}
+	if err := remote.FromJson(res); err != nil {
+		return err
+	}
+	if !remoteNewer(c.state, file, remote) {
+		upToDate(file)
+		return nil
+	}
+	slog.Info("updated on the remote", "file", file, "remote_updated_at", remote.UpdatedAt)
+	return c.keepRemote(g, file, remote)
+}
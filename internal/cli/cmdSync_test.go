@@ -0,0 +1,173 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/tukaelu/zgsync/internal/converter"
+	"github.com/tukaelu/zgsync/internal/state"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+func TestSync(t *testing.T) {
+	// 1 is updated remotely, 2 is changed locally and 3 is changed on both sides since the last sync.
+	remote := map[int]string{
+		1: `{"translation":{"source_id":1,"locale":"ja","title":"t1","body":"<p>remote body</p>","updated_at":"2024-02-01T00:00:00Z"}}`,
+		2: `{"translation":{"source_id":2,"locale":"ja","title":"t2","body":"<p>body</p>","updated_at":"2024-01-01T00:00:00Z"}}`,
+		3: `{"translation":{"source_id":3,"locale":"ja","title":"t3","body":"<p>remote body</p>","updated_at":"2024-02-01T00:00:00Z"}}`,
+	}
+	tests := []struct {
+		policy   string
+		updated  string
+		expected map[int]string
+	}{
+		{syncPreferLocal, "[2 3]", map[int]string{1: "remote body", 2: "local body", 3: "local body"}},
+		{syncPreferRemote, "[2]", map[int]string{1: "remote body", 2: "local body", 3: "remote body"}},
+		{syncManual, "[2]", map[int]string{1: "remote body", 2: "local body", 3: "local body"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			dir := t.TempDir()
+			g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja"}}
+			s, err := g.LoadState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for id := 1; id <= 3; id++ {
+				file := filepath.Join(dir, fmt.Sprintf("%d-ja.md", id))
+				if err := os.WriteFile(file, []byte(fmt.Sprintf("---\ntitle: t%d\nlocale: ja\nsource_id: %d\n---\nbody\n", id, id)), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				if err := trackPulled(s, file, state.Entry{Kind: state.KindTranslation, ArticleID: id, Locale: "ja", RemoteUpdatedAt: "2024-01-01T00:00:00Z"}); err != nil {
+					t.Fatal(err)
+				}
+				if id != 1 {
+					if err := os.WriteFile(file, []byte(fmt.Sprintf("---\ntitle: t%d\nlocale: ja\nsource_id: %d\n---\nlocal body\n", id, id)), 0o644); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+			if err := s.Save(); err != nil {
+				t.Fatal(err)
+			}
+
+			client := &syncClient{pushClient: pushClient{remote: remote}}
+			c := &CommandSync{OnConflict: tt.policy}
+			if err := c.AfterApply(g); err != nil {
+				t.Fatal(err)
+			}
+			c.push.client = client
+			c.push.converter = converter.NewConverter()
+			c.push.interactive = false
+			if err := c.Run(g); err != nil {
+				t.Fatalf("Run() failed: %v", err)
+			}
+			sort.Ints(client.updated)
+			if fmt.Sprint(client.updated) != tt.updated {
+				t.Errorf("Run() failed: got %v updated, want %s", client.updated, tt.updated)
+			}
+			for id, expected := range tt.expected {
+				b, err := os.ReadFile(filepath.Join(dir, fmt.Sprintf("%d-ja.md", id)))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !strings.Contains(string(b), expected) {
+					t.Errorf("Run() failed: %q is not in %d-ja.md\n%s", expected, id, b)
+				}
+			}
+		})
+	}
+}
+
+// syncClient has the articles in deleted deleted.
+type syncClient struct {
+	pushClient
+	deleted map[int]bool
+	shown   []int
+}
+
+func (c *syncClient) ShowArticle(locale string, articleID int) (string, error) {
+	c.shown = append(c.shown, articleID)
+	if c.deleted[articleID] {
+		return "", &zendesk.StatusError{StatusCode: 404}
+	}
+	return fmt.Sprintf(`{"article":{"id":%d}}`, articleID), nil
+}
+
+func TestSyncPrune(t *testing.T) {
+	tests := []struct {
+		name  string
+		prune bool
+		trash bool
+	}{
+		{"not checked", false, false},
+		{"deletes", true, false},
+		{"moves into the trash", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja"}}
+			s, err := g.LoadState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, name := range []string{"1-ja.md", "2-ja.md", "2-en.md"} {
+				id := int(name[0] - '0')
+				file := filepath.Join(dir, name)
+				if err := os.WriteFile(file, []byte(fmt.Sprintf("---\ntitle: t\nlocale: %s\nsource_id: %d\n---\nbody\n", name[2:4], id)), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				if err := trackPulled(s, file, state.Entry{Kind: state.KindTranslation, ArticleID: id, Locale: name[2:4]}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := s.Save(); err != nil {
+				t.Fatal(err)
+			}
+
+			c := &CommandSync{Prune: tt.prune, Confirm: Confirm{Yes: true}}
+			if tt.trash {
+				c.TrashDir = filepath.Join(t.TempDir(), "trash")
+			}
+			if err := c.AfterApply(g); err != nil {
+				t.Fatal(err)
+			}
+			client := &syncClient{deleted: map[int]bool{2: true}}
+			c.push.client = client
+			c.push.converter = converter.NewConverter()
+			if err := c.Run(g); err != nil {
+				t.Fatalf("Run() failed: %v", err)
+			}
+			// the articles are looked up only with --prune.
+			if shown := fmt.Sprint(client.shown); tt.prune && shown != "[1 2]" || !tt.prune && shown != "[]" {
+				t.Errorf("Run() failed: got %v shown", client.shown)
+			}
+			if len(client.updated) != 0 {
+				t.Errorf("Run() failed: got %v updated", client.updated)
+			}
+			for _, name := range []string{"2-ja.md", "2-en.md"} {
+				_, err := os.Stat(filepath.Join(dir, name))
+				if exists := err == nil; exists == tt.prune {
+					t.Errorf("Run() failed: %s exists = %v", name, exists)
+				}
+				if tt.trash {
+					if _, err := os.Stat(filepath.Join(c.TrashDir, name)); err != nil {
+						t.Errorf("Run() failed: %s is not moved into the trash: %v", name, err)
+					}
+				}
+			}
+			s, err = g.LoadState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := s.Lookup(filepath.Join(dir, "2-ja.md")); ok == tt.prune {
+				t.Errorf("Run() failed: 2-ja.md is tracked = %v", ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/tukaelu/zgsync/internal/converter"
+	"github.com/tukaelu/zgsync/internal/state"
+	"github.com/tukaelu/zgsync/internal/translate"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+type CommandTranslate struct {
+	To        []string            `name:"to" help:"Specify the target locales separated by commas." required:""`
+	Provider  string              `name:"provider" help:"Specify the machine translation provider." enum:"deepl" default:"deepl"`
+	Force     bool                `name:"force" short:"f" help:"It overwrites the existing translations of the target locales."`
+	Push      bool                `name:"push" help:"It pushes the translations to the remote as drafts."`
+	Raw       bool                `name:"raw" help:"It translates the body as is without converting it between Markdown and HTML."`
+	File      string              `arg:"" help:"Specify the source translation file." type:"existingfile"`
+	provider  translate.Provider  `kong:"-"`
+	converter converter.Converter `kong:"-"`
+	client    zendesk.Client      `kong:"-"`
+}
+
+func (c *CommandTranslate) AfterApply(g *Global) (err error) {
+	c.provider, err = translate.NewProvider(c.Provider, translate.Options{
+		DeepLAPIKey:   g.Config.MachineTranslation.DeepLAPIKey,
+		DeepLEndpoint: g.Config.MachineTranslation.DeepLEndpoint,
+	})
+	if err != nil {
+		return err
+	}
+	c.converter = converter.NewConverter()
+	c.client = g.newClient(g.Config.Subdomain)
+	return nil
+}
+
+func (c *CommandTranslate) Run(g *Global) (err error) {
+	source := &zendesk.Translation{}
+	if err := source.FromFile(c.File); err != nil {
+		return err
+	}
+	if source.SourceID == 0 {
+		return fmt.Errorf("%s is not a translation", c.File)
+	}
+	if source.Locale == "" {
+		source.Locale = g.Config.DefaultLocale
+	}
+	sourceLocale := g.Config.remoteLocale(source.Locale)
+
+	body := source.Body
+	html, err := source.IsHTML()
+	if err != nil {
+		return err
+	}
+	if !c.Raw && !html {
+		if body, err = c.converter.ConvertToHTML(source.Body); err != nil {
+			return err
+		}
+	}
+
+	var s *state.Store
+	if c.Push {
+		if s, err = g.LoadState(); err != nil {
+			return err
+		}
+		defer func() {
+			if serr := s.Save(); serr != nil && err == nil {
+				err = serr
+			}
+		}()
+	}
+
+	for _, to := range c.To {
+		locale := g.Config.remoteLocale(to)
+		dest, err := c.translate(g, source, sourceLocale, body, locale)
+		if err != nil {
+			return fmt.Errorf("%s: %w", to, err)
+		}
+		if dest == "" || !c.Push {
+			continue
+		}
+		if err := c.push(g, s, dest, locale); err != nil {
+			return fmt.Errorf("%s: %w", dest, err)
+		}
+	}
+	return nil
+}
+
+// translate writes the draft translation next to the source, or returns an empty path if it is skipped.
+func (c *CommandTranslate) translate(g *Global, source *zendesk.Translation, sourceLocale, body, locale string) (string, error) {
+	local := g.Config.localLocale(locale)
+	dest, err := findTranslationFile(g, filepath.Dir(c.File), source.SourceID, local)
+	if err != nil {
+		return "", err
+	}
+	if dest != "" && !c.Force {
+		slog.Info("skipped as it already exists", "file", dest)
+		return "", nil
+	}
+
+	// the raw HTML body is translated and written as HTML, and the title is translated as plain text.
+	html, _ := source.IsHTML()
+	title, translated, err := c.translateTexts(source.Title, body, sourceLocale, locale, !c.Raw || html)
+	if err != nil {
+		return "", err
+	}
+	if !c.Raw && !html {
+		if translated, err = c.converter.ConvertToMarkdown(translated); err != nil {
+			return "", err
+		}
+		translated += "\n"
+	}
+
+	t := &zendesk.Translation{SectionID: source.SectionID, SourceID: source.SourceID, Brand: source.Brand}
+	if dest != "" {
+		if err := t.FromFile(dest); err != nil {
+			return "", err
+		}
+	} else if dest, err = newTranslationPath(g, c.File, source, local, title); err != nil {
+		return "", err
+	}
+	t.Title = title
+	t.Locale = local
+	t.Draft = true
+	t.Body = translated
+	if html {
+		t.BodyFormat = zendesk.BodyFormatHTML
+	}
+	t.MachineTranslation = &zendesk.MachineTranslation{
+		Provider:     c.provider.Name(),
+		SourceLocale: sourceLocale,
+		TranslatedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	_, tmpl, err := g.Config.frontMatterTemplates()
+	if err != nil {
+		return "", err
+	}
+	if err := t.SaveWithTemplate(dest, false, tmpl); err != nil {
+		return "", fmt.Errorf("failed to save the translation: %w", err)
+	}
+	slog.Info("translated", "file", c.File, "dest", dest)
+	return dest, nil
+}
+
+// translateTexts translates the title and the body, in a single request unless the body is HTML.
+func (c *CommandTranslate) translateTexts(title, body, source, target string, html bool) (string, string, error) {
+	if !html {
+		texts, err := c.provider.Translate([]string{title, body}, source, target, false)
+		if err != nil {
+			return "", "", err
+		}
+		return texts[0], texts[1], nil
+	}
+	titles, err := c.provider.Translate([]string{title}, source, target, false)
+	if err != nil {
+		return "", "", err
+	}
+	bodies, err := c.provider.Translate([]string{body}, source, target, true)
+	if err != nil {
+		return "", "", err
+	}
+	return titles[0], bodies[0], nil
+}
+
+// push creates the draft translation remotely, or updates it if the translation already exists.
+func (c *CommandTranslate) push(g *Global, s *state.Store, file string, locale string) error {
+	t := &zendesk.Translation{}
+	if err := t.FromFile(file); err != nil {
+		return err
+	}
+	t.Locale = locale
+	// the Markdown body is converted with --raw as well, as it is not HTML.
+	html, err := t.IsHTML()
+	if err != nil {
+		return err
+	}
+	if !html {
+		if t.Body, err = c.converter.ConvertToHTML(t.Body); err != nil {
+			return err
+		}
+	}
+	payload, err := t.ToPayload()
+	if err != nil {
+		return err
+	}
+
+	brand := t.Brand
+	if brand == "" {
+		brand = g.Config.Brand
+	}
+	client := c.client
+	if brand != g.Config.Brand {
+		subdomain, ok := g.Config.Brands[brand]
+		if !ok {
+			return fmt.Errorf("brand %s is not defined in brands", brand)
+		}
+		client = g.newClient(subdomain)
+	}
+
+	// the translation is looked up remotely, as it may have been created without the local file.
+	res, err := zendesk.Uncached(client).ShowTranslation(t.SourceID, locale)
+	switch {
+	case err == nil:
+		res, err = client.UpdateTranslation(t.SourceID, locale, payload)
+	case zendesk.IsNotFound(err):
+		res, err = client.CreateTranslation(t.SourceID, payload)
+	}
+	if err != nil {
+		return err
+	}
+	remote := &zendesk.Translation{}
+	if err := remote.FromJson(res); err != nil {
+		return err
+	}
+	trackPushed(s, file, state.Entry{
+		Kind:            state.KindTranslation,
+		Brand:           brand,
+		ArticleID:       t.SourceID,
+		Locale:          locale,
+		SectionID:       t.SectionID,
+		RemoteUpdatedAt: remote.UpdatedAt,
+	}, payload)
+	slog.Info("pushed as draft", "file", file)
+	return nil
+}
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tukaelu/zgsync/internal/converter"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+// upperProvider translates the texts into upper case, and records whether each call is HTML.
+type upperProvider struct {
+	calls int
+	html  []bool
+}
+
+func (p *upperProvider) Name() string {
+	return "upper"
+}
+
+func (p *upperProvider) Translate(texts []string, source, target string, html bool) ([]string, error) {
+	p.calls++
+	p.html = append(p.html, html)
+	out := make([]string, len(texts))
+	for i, text := range texts {
+		out[i] = strings.ToUpper(text)
+	}
+	return out, nil
+}
+
+func TestTranslate(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "123-ja.md")
+	if err := os.WriteFile(src, []byte("---\ntitle: hello\nlocale: ja\nsection_id: 5\nsource_id: 123\n---\nsome **text**\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	existing := filepath.Join(dir, "123-fr.md")
+	if err := os.WriteFile(existing, []byte("---\ntitle: bonjour\nlocale: fr\nsource_id: 123\n---\ntexte\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja", LocaleAliases: map[string]string{"en": "en-us"}}}
+	p := &upperProvider{}
+	c := &CommandTranslate{To: []string{"en", "fr"}, File: src, provider: p, converter: converter.NewConverter()}
+	if err := c.Run(g); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if p.calls != 2 {
+		t.Errorf("Run() failed: the existing translation should be skipped: %d calls", p.calls)
+	}
+	if len(p.html) != 2 || p.html[0] || !p.html[1] {
+		t.Errorf("Run() failed: the title should be translated as text: %v", p.html)
+	}
+
+	tr := &zendesk.Translation{}
+	if err := tr.FromFile(filepath.Join(dir, "123-en.md")); err != nil {
+		t.Fatal(err)
+	}
+	if tr.Title != "HELLO" || tr.Locale != "en" || !tr.Draft || tr.SourceID != 123 || tr.SectionID != 5 {
+		t.Errorf("Run() failed: unexpected translation %+v", tr)
+	}
+	if tr.Body != "SOME **TEXT**\n" {
+		t.Errorf("Run() failed: unexpected body %q", tr.Body)
+	}
+	if mt := tr.MachineTranslation; mt == nil || mt.Provider != "upper" || mt.SourceLocale != "ja" || mt.TranslatedAt == "" {
+		t.Errorf("Run() failed: unexpected provenance %+v", mt)
+	}
+
+	c.To = []string{"fr"}
+	c.Force = true
+	if err := c.Run(g); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	fr := &zendesk.Translation{}
+	if err := fr.FromFile(existing); err != nil {
+		t.Fatal(err)
+	}
+	if fr.Title != "HELLO" || fr.MachineTranslation == nil {
+		t.Errorf("Run() failed: the existing translation should be overwritten: %+v", fr)
+	}
+}
+
+// translateClient has the translation en-us of article 123 only, and records the writes.
+type translateClient struct {
+	zendesk.Client
+	calls    []string
+	payloads []string
+}
+
+func (c *translateClient) ShowTranslation(articleID int, locale string) (string, error) {
+	if locale != "en-us" {
+		return "", &zendesk.StatusError{StatusCode: http.StatusNotFound}
+	}
+	return `{"translation":{"id":11,"locale":"en-us"}}`, nil
+}
+
+func (c *translateClient) UpdateTranslation(articleID int, locale string, payload string) (string, error) {
+	c.calls = append(c.calls, fmt.Sprintf("UpdateTranslation %d %s", articleID, locale))
+	c.payloads = append(c.payloads, payload)
+	return `{"translation":{"id":11}}`, nil
+}
+
+func (c *translateClient) CreateTranslation(articleID int, payload string) (string, error) {
+	c.calls = append(c.calls, fmt.Sprintf("CreateTranslation %d", articleID))
+	c.payloads = append(c.payloads, payload)
+	return `{"translation":{"id":12}}`, nil
+}
+
+func TestTranslatePush(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "123-ja.md")
+	if err := os.WriteFile(src, []byte("---\ntitle: hello\nlocale: ja\nsection_id: 5\nsource_id: 123\n---\nsome **text**\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja", LocaleAliases: map[string]string{"en": "en-us"}}}
+	client := &translateClient{}
+	c := &CommandTranslate{To: []string{"en", "fr"}, Push: true, Raw: true, File: src, provider: &upperProvider{}, converter: converter.NewConverter(), client: client}
+	if err := c.Run(g); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+
+	want := []string{"UpdateTranslation 123 en-us", "CreateTranslation 123"}
+	if strings.Join(client.calls, ",") != strings.Join(want, ",") {
+		t.Errorf("Run() failed: unexpected calls %v", client.calls)
+	}
+	for _, payload := range client.payloads {
+		if !strings.Contains(payload, `\u003cstrong\u003eTEXT`) {
+			t.Errorf("Run() failed: the Markdown body should be pushed as HTML: %s", payload)
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/adrg/frontmatter"
+	"github.com/tukaelu/zgsync"
+	"github.com/tukaelu/zgsync/internal/converter"
+	"github.com/tukaelu/zgsync/internal/report"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ruleFrontMatter    = "front-matter"
+	ruleEncoding       = "encoding"
+	ruleMissingTitle   = "missing-title"
+	ruleMissingSection = "missing-section"
+	ruleUserSegments   = "user-segments"
+	rulePublishAt      = "invalid-publish-at"
+	ruleConversion     = "conversion"
+	ruleBodyFormat     = "body-format"
+	ruleRequiredField  = "required-field"
+	ruleStrippedMarkup = "stripped-markup"
+	ruleLimits         = "limits"
+	ruleLocale         = "locale"
+)
+
+var validateRules = []report.Rule{
+	{ID: ruleFrontMatter, Description: "The front matter must be valid YAML."},
+	{ID: ruleEncoding, Description: "The file must be encoded in UTF-8, or in UTF-16 with the BOM."},
+	{ID: ruleMissingTitle, Description: "Articles, translations and posts must have a title."},
+	{ID: ruleMissingSection, Description: "New articles must have a section given by section_id, .zgsync.yaml or the sections config."},
+	{ID: ruleUserSegments, Description: "user_segment_id of articles must be one of user_segment_ids if both are given."},
+	{ID: rulePublishAt, Description: "publish_at must be a valid time."},
+	{ID: ruleConversion, Description: "The body must be convertible from Markdown to HTML."},
+	{ID: ruleBodyFormat, Description: "body_format must be markdown or html."},
+	{ID: ruleRequiredField, Description: "The front matter must have the keys of required_fields in the configuration."},
+	{ID: ruleStrippedMarkup, Description: "The HTML should not have the markup which the help center strips, such as scripts, inline event handlers and iframes from the hosts not allowed."},
+	{ID: ruleLimits, Description: "The converted body must not exceed the limits of the configuration, which are warnings unless limits.fail is set."},
+	{ID: ruleLocale, Description: "The locale must be enabled in the help center, which is checked with --check-locales."},
+}
+
+type CommandValidate struct {
+	Report       string              `name:"report" help:"Write the report to the file, or to the standard output if 'junit' or 'sarif' is given."`
+	ReportFormat string              `name:"report-format" help:"Specify the format of the report (junit or sarif). If not specified, it is inferred from the file extension." enum:",junit,sarif" default:""`
+	NoCheckers   bool                `name:"no-checkers" help:"It skips the external checkers of the configuration."`
+	CheckLocales bool                `name:"check-locales" help:"It checks the locales of the files against the help center."`
+	Files        []string            `arg:"" optional:"" help:"Specify the files or directories to validate. If not specified, the contents directory will be validated." type:"path"`
+	converter    converter.Converter `kong:"-"`
+	client       zendesk.Client      `kong:"-"`
+	locales      *zendesk.Locales    `kong:"-"`
+}
+
+func (c *CommandValidate) AfterApply(g *Global) error {
+	c.converter = converter.NewConverter()
+	c.client = g.newClient(g.Config.Subdomain)
+	return nil
+}
+
+func (c *CommandValidate) Run(g *Global) error {
+	paths := c.Files
+	if len(paths) == 0 {
+		paths = []string{g.Config.ContentsDir}
+	}
+	files, err := expandFiles(g.Config.ContentsDir, paths, nil)
+	if err != nil {
+		return err
+	}
+	dirs, err := newDirConfigs(g.Config.ContentsDir)
+	if err != nil {
+		return err
+	}
+	if c.CheckLocales {
+		if c.locales, err = enabledLocales(c.client); err != nil {
+			return fmt.Errorf("failed to fetch the locales of the help center: %w", err)
+		}
+	}
+
+	var names []string
+	var diags []report.Diagnostic
+	for _, file := range files {
+		name := displayPath(g.Config.ContentsDir, file)
+		names = append(names, name)
+		found, err := c.validate(g, dirs, file)
+		if err != nil {
+			return err
+		}
+		if !c.NoCheckers {
+			for _, ch := range g.Config.Checkers {
+				checked, err := ch.check(file)
+				if err != nil {
+					return err
+				}
+				found = append(found, checked...)
+			}
+		}
+		for _, d := range found {
+			d.File = name
+			diags = append(diags, d)
+		}
+	}
+
+	if c.Report != "" {
+		if err := c.writeReport(append(slices.Clone(validateRules), g.Config.checkerRules()...), names, diags); err != nil {
+			return err
+		}
+	}
+
+	errs := 0
+	failed := map[string]bool{}
+	for _, d := range diags {
+		if c.Report != report.FormatJUnit && c.Report != report.FormatSARIF {
+			fmt.Println(d)
+		}
+		if d.Severity == report.SeverityError {
+			errs++
+			failed[d.File] = true
+		}
+	}
+	if errs > 0 {
+		return fmt.Errorf("%d problems found in %d files", errs, len(failed))
+	}
+	return nil
+}
+
+func (c *CommandValidate) writeReport(rules []report.Rule, files []string, diags []report.Diagnostic) error {
+	format := c.ReportFormat
+	if format == "" {
+		var err error
+		if format, err = report.FormatOf(c.Report); err != nil {
+			return err
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	if c.Report != report.FormatJUnit && c.Report != report.FormatSARIF {
+		f, err := os.Create(c.Report)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return report.Write(w, format, "zgsync", zgsync.Version, rules, files, diags)
+}
+
+// validate checks a file and returns the diagnostics without the file name.
+func (c *CommandValidate) validate(g *Global, dirs *dirConfigs, file string) ([]report.Diagnostic, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if b, err = zendesk.NormalizeText(b); err != nil {
+		return []report.Diagnostic{{Line: 1, Rule: ruleEncoding, Severity: report.SeverityError, Message: err.Error()}}, nil
+	}
+	lines, err := frontMatterLines(b)
+	if err != nil {
+		return []report.Diagnostic{{Line: 1, Rule: ruleFrontMatter, Severity: report.SeverityError, Message: err.Error()}}, nil
+	}
+	var diags []report.Diagnostic
+	add := func(key, rule, msg string) {
+		line := lines[key]
+		if line == 0 {
+			line = 1
+		}
+		diags = append(diags, report.Diagnostic{Line: line, Rule: rule, Severity: report.SeverityError, Message: msg})
+	}
+
+	ref := &fileRef{}
+	if _, err := frontmatter.Parse(bytes.NewReader(b), ref); err != nil {
+		add("", ruleFrontMatter, err.Error())
+		return diags, nil
+	}
+	if ref.TopicID != 0 && ref.SourceID == 0 {
+		// the community posts are pushed by posts push, which requires the title only.
+		if ref.Title == "" {
+			add("title", ruleMissingTitle, "title is required")
+		}
+		return diags, nil
+	}
+
+	required := g.Config.RequiredFields.Translation
+	if ref.SourceID == 0 {
+		required = g.Config.RequiredFields.Article
+	}
+	missing, err := missingFields(b, required)
+	if err != nil {
+		add("", ruleFrontMatter, err.Error())
+		return diags, nil
+	}
+	for _, key := range missing {
+		add(key, ruleRequiredField, key+" is required by required_fields")
+	}
+	if c.locales != nil {
+		locale := ref.Locale
+		if locale == "" {
+			dc, err := dirs.For(file)
+			if err != nil {
+				return nil, err
+			}
+			locale = dc.Locale
+		}
+		if locale == "" {
+			locale = g.Config.DefaultLocale
+		}
+		if err := checkEnabledLocale(c.locales, g.Config.remoteLocale(locale)); err != nil {
+			add("locale", ruleLocale, err.Error())
+		}
+	}
+
+	if ref.SourceID == 0 {
+		a, err := g.Config.readArticle(file)
+		if err != nil {
+			add("", ruleFrontMatter, err.Error())
+			return diags, nil
+		}
+		if a.Title == "" {
+			add("title", ruleMissingTitle, "title is required")
+		}
+		if a.UserSegmentID != nil && len(a.UserSegmentIDs) > 0 && !slices.Contains(a.UserSegmentIDs, *a.UserSegmentID) {
+			add("user_segment_id", ruleUserSegments, fmt.Sprintf("user_segment_id %d is not one of user_segment_ids", *a.UserSegmentID))
+		}
+		if a.ID == 0 && a.SectionID == 0 {
+			dc, err := dirs.For(file)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := g.Config.sectionForFile(file); !ok && dc.SectionID == nil {
+				add("section_id", ruleMissingSection, "section_id is required to create the article")
+			}
+		}
+		return diags, nil
+	}
+
+	t, err := g.Config.readTranslation(file)
+	if err != nil {
+		add("", ruleFrontMatter, err.Error())
+		return diags, nil
+	}
+	if t.Title == "" {
+		add("title", ruleMissingTitle, "title is required")
+	}
+	if _, err := t.PublishTime(); err != nil {
+		add("publish_at", rulePublishAt, err.Error())
+	}
+	if html, err := t.IsHTML(); err != nil {
+		add("body_format", ruleBodyFormat, err.Error())
+	} else {
+		body := t.Body
+		if !html {
+			if body, err = c.converter.ConvertToHTML(t.Body); err != nil {
+				add("", ruleConversion, err.Error())
+			}
+		}
+		for _, m := range g.Config.strippedMarkup(body) {
+			diags = append(diags, report.Diagnostic{Line: 1, Rule: ruleStrippedMarkup, Severity: report.SeverityWarning, Message: m + " is stripped by the help center"})
+		}
+		severity := report.SeverityWarning
+		if g.Config.Limits.Fail {
+			severity = report.SeverityError
+		}
+		for _, e := range g.Config.exceededLimits(body) {
+			diags = append(diags, report.Diagnostic{Line: 1, Rule: ruleLimits, Severity: severity, Message: e})
+		}
+	}
+	return diags, nil
+}
+
+// frontMatterLines returns the line numbers in the file of the keys of the front matter.
+func frontMatterLines(b []byte) (map[string]int, error) {
+	var fm yaml.Node
+	format := frontmatter.NewFormat("---", "---", yaml.Unmarshal)
+	if _, err := frontmatter.Parse(bytes.NewReader(b), &fm, format); err != nil {
+		return nil, err
+	}
+	lines := map[string]int{}
+	if len(fm.Content) == 0 || fm.Content[0].Kind != yaml.MappingNode {
+		return lines, nil
+	}
+	m := fm.Content[0]
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		// the front matter starts at the line following the "---" delimiter.
+		lines[m.Content[i].Value] = m.Content[i].Line + 1
+	}
+	return lines, nil
+}
+
+// displayPath returns the path of the file relative to the contents directory if it is under it.
+func displayPath(contentsDir, file string) string {
+	root, err := filepath.Abs(contentsDir)
+	if err != nil || !isUnder(root, file) {
+		return file
+	}
+	rel, err := filepath.Rel(root, file)
+	if err != nil {
+		return file
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tukaelu/zgsync/internal/converter"
+	"github.com/tukaelu/zgsync/internal/report"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+func TestValidate(t *testing.T) {
+	g := &Global{Config: Config{ContentsDir: "testdata/validate", DefaultLocale: "ja"}}
+	dirs, err := newDirConfigs(g.Config.ContentsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &CommandValidate{converter: converter.NewConverter()}
+
+	tests := []struct {
+		file     string
+		expected []report.Diagnostic
+	}{
+		{"testdata/validate/1-ja.md", nil},
+		{
+			"testdata/validate/2-ja.md",
+			[]report.Diagnostic{
+				{Line: 1, Rule: ruleMissingTitle, Severity: report.SeverityError, Message: "title is required"},
+				{Line: 4, Rule: rulePublishAt, Severity: report.SeverityError, Message: "invalid publish_at: tomorrow"},
+			},
+		},
+		{
+			"testdata/validate/3-ja.md",
+			[]report.Diagnostic{
+				{Line: 5, Rule: ruleBodyFormat, Severity: report.SeverityError, Message: "body_format must be markdown or html: xml"},
+			},
+		},
+		{
+			"testdata/validate/4-ja.md",
+			[]report.Diagnostic{
+				{Line: 1, Rule: ruleStrippedMarkup, Severity: report.SeverityWarning, Message: "<button> is stripped by the help center"},
+			},
+		},
+		{
+			"testdata/validate/new.md",
+			[]report.Diagnostic{
+				{Line: 1, Rule: ruleMissingSection, Severity: report.SeverityError, Message: "section_id is required to create the article"},
+			},
+		},
+		{
+			"testdata/validate/segments.md",
+			[]report.Diagnostic{
+				{Line: 5, Rule: ruleUserSegments, Severity: report.SeverityError, Message: "user_segment_id 3 is not one of user_segment_ids"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			actual, err := c.validate(g, dirs, tt.file)
+			if err != nil {
+				t.Fatalf("validate() failed: %v", err)
+			}
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("validate() failed: got %v, want %v", actual, tt.expected)
+			}
+		})
+	}
+
+	actual, err := c.validate(g, dirs, "testdata/validate/broken.md")
+	if err != nil {
+		t.Fatalf("validate() failed: %v", err)
+	}
+	if len(actual) != 1 || actual[0].Rule != ruleFrontMatter {
+		t.Errorf("validate() failed: got %v", actual)
+	}
+
+	g.Config.Markup.UnsafeContent = true
+	if actual, _ := c.validate(g, dirs, "testdata/validate/4-ja.md"); len(actual) != 0 {
+		t.Errorf("validate() failed: the markup should be accepted with unsafe_content: %v", actual)
+	}
+
+	g.Config.Sections = map[string]int{".": 10}
+	if actual, _ := c.validate(g, dirs, "testdata/validate/new.md"); len(actual) != 0 {
+		t.Errorf("validate() failed: the section should be resolved by the sections config: %v", actual)
+	}
+
+	g.Config.RequiredFields = RequiredFields{Article: []string{"user_segment_id", "owner"}, Translation: []string{"locale", "labels"}}
+	actual, _ = c.validate(g, dirs, "testdata/validate/1-ja.md")
+	expected := []report.Diagnostic{{Line: 1, Rule: ruleRequiredField, Severity: report.SeverityError, Message: "labels is required by required_fields"}}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("validate() failed: got %v, want %v", actual, expected)
+	}
+	actual, _ = c.validate(g, dirs, "testdata/validate/segments.md")
+	if len(actual) != 2 || actual[0].Message != "owner is required by required_fields" {
+		t.Errorf("validate() failed: got %v", actual)
+	}
+
+	g.Config.RequiredFields = RequiredFields{}
+	c.locales = &zendesk.Locales{Locales: []string{"en-us", "fr"}, DefaultLocale: "en-us"}
+	actual, _ = c.validate(g, dirs, "testdata/validate/1-ja.md")
+	expected = []report.Diagnostic{{Line: 4, Rule: ruleLocale, Severity: report.SeverityError, Message: "locale: ja is not enabled in the help center, whose locales are en-us, fr"}}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("validate() failed: got %v, want %v", actual, expected)
+	}
+	g.Config.LocaleAliases = map[string]string{"ja": "fr"}
+	if actual, _ := c.validate(g, dirs, "testdata/validate/new.md"); len(actual) != 0 {
+		t.Errorf("validate() failed: the default locale should be mapped with locale_aliases: %v", actual)
+	}
+}
+
+func TestFrontMatterLines(t *testing.T) {
+	lines, err := frontMatterLines([]byte("---\ntitle: foo\n\nlocale: ja\n---\nbody\n"))
+	if err != nil {
+		t.Fatalf("frontMatterLines() failed: %v", err)
+	}
+	expected := map[string]int{"title": 2, "locale": 4}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Errorf("frontMatterLines() failed: got %v, want %v", lines, expected)
+	}
+}
@@ -1,14 +1,112 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/tukaelu/zgsync"
 )
 
-type CommandVersion struct{}
+// latestReleaseURL is the GitHub API of the latest release, which is replaced in the tests.
+var latestReleaseURL = "https://api.github.com/repos/tukaelu/zgsync/releases/latest"
 
-func (c *CommandVersion) Run() error {
-	fmt.Printf("version %s (rev: %s)\n", zgsync.Version, zgsync.Revision)
+type CommandVersion struct {
+	Check bool      `name:"check" help:"It checks whether a newer release is available on GitHub, unless disable_update_check is set."`
+	out   io.Writer `kong:"-"`
+}
+
+// release is the release of the GitHub API.
+// refs: https://docs.github.com/en/rest/releases/releases#get-the-latest-release
+type release struct {
+	TagName string `json:"tag_name"`
+	HtmlURL string `json:"html_url"`
+}
+
+func (c *CommandVersion) Run(g *Global) error {
+	if c.out == nil {
+		c.out = os.Stdout
+	}
+	fmt.Fprintf(c.out, "version %s (rev: %s)\n", zgsync.Version, zgsync.Revision)
+	if !c.Check {
+		return nil
+	}
+
+	// the configuration is not loaded for version, and the check runs without it.
+	if err := g.LoadConfig(); err != nil {
+		slog.Warn("failed to load the configuration", "error", err)
+	}
+	if g.Config.DisableUpdateCheck {
+		fmt.Fprintln(c.out, "the update check is disabled by disable_update_check")
+		return nil
+	}
+
+	r, err := latestRelease()
+	if err != nil {
+		return fmt.Errorf("failed to check the latest release: %w", err)
+	}
+	if compareVersions(strings.TrimPrefix(r.TagName, "v"), zgsync.Version) > 0 {
+		fmt.Fprintf(c.out, "a newer version %s is available: %s\n", strings.TrimPrefix(r.TagName, "v"), r.HtmlURL)
+		return nil
+	}
+	fmt.Fprintln(c.out, "zgsync is up to date")
 	return nil
 }
+
+func latestRelease() (*release, error) {
+	req, err := http.NewRequest(http.MethodGet, latestReleaseURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", "zgsync/"+zgsync.Version)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+	r := &release{}
+	if err := json.NewDecoder(res.Body).Decode(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// compareVersions compares the versions such as 1.2.3 by their numbers, ignoring the pre-release suffix.
+func compareVersions(a, b string) int {
+	as, bs := versionNumbers(a), versionNumbers(b)
+	for i := 0; i < max(len(as), len(bs)); i++ {
+		var x, y int
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		if x != y {
+			return x - y
+		}
+	}
+	return 0
+}
+
+func versionNumbers(v string) []int {
+	v, _, _ = strings.Cut(v, "-")
+	var numbers []int
+	for _, s := range strings.Split(v, ".") {
+		n, _ := strconv.Atoi(s)
+		numbers = append(numbers, n)
+	}
+	return numbers
+}
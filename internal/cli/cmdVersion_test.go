@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tukaelu/zgsync"
+)
+
+func TestVersionCheck(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"tag_name":"v1.2.0","html_url":"https://github.com/tukaelu/zgsync/releases/tag/v1.2.0"}`))
+	}))
+	defer ts.Close()
+	savedURL, savedVersion := latestReleaseURL, zgsync.Version
+	latestReleaseURL = ts.URL
+	defer func() { latestReleaseURL, zgsync.Version = savedURL, savedVersion }()
+
+	tests := []struct {
+		name     string
+		version  string
+		env      string
+		expected string
+	}{
+		{"newer", "1.1.9", "", "a newer version 1.2.0 is available: https://github.com/tukaelu/zgsync/releases/tag/v1.2.0"},
+		{"up to date", "1.2.0", "", "zgsync is up to date"},
+		{"disabled", "1.1.9", "true", "the update check is disabled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("ZGSYNC_DISABLE_UPDATE_CHECK", tt.env)
+			}
+			zgsync.Version = tt.version
+			var out bytes.Buffer
+			g := &Global{ConfigPath: filepath.Join(t.TempDir(), "config.yaml")}
+			c := &CommandVersion{Check: true, out: &out}
+			if err := c.Run(g); err != nil {
+				t.Fatalf("Run() failed: %v", err)
+			}
+			if !strings.Contains(out.String(), tt.expected) {
+				t.Errorf("Run() failed: got %q, want %q", out.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.2.0", "1.1.9", 1},
+		{"1.2", "1.2.0", 0},
+		{"1.10.0", "1.9.0", 1},
+		{"1.2.0-rc.1", "1.2.0", 0},
+		{"0.0.0", "0.1.0", -1},
+	}
+	for _, tt := range tests {
+		got := compareVersions(tt.a, tt.b)
+		if (got > 0) != (tt.expected > 0) || (got < 0) != (tt.expected < 0) {
+			t.Errorf("compareVersions(%s, %s) failed: got %d, want the sign of %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package cli
+
+import (
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/tukaelu/zgsync/internal/xliff"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+const xliffExt = ".xlf"
+
+type CommandXLIFF struct {
+	Export CommandXLIFFExport `cmd:"export" help:"Export the translations of the source locale as XLIFF 2.0 files per target locale."`
+	Import CommandXLIFFImport `cmd:"import" help:"Import the translated XLIFF files as the translations of the target locales."`
+}
+
+type CommandXLIFFExport struct {
+	SourceLocale string   `name:"source-locale" short:"s" help:"Specify the locale of the source translations. If not specified, the default locale will be used."`
+	Targets      []string `name:"target" short:"t" help:"Specify the target locales. It can be repeated." required:""`
+	Out          string   `name:"out" short:"o" help:"Specify the output directory." default:"xliff" type:"path"`
+	Files        []string `arg:"" optional:"" help:"Specify the files or directories to export. If not specified, the contents directory will be exported." type:"path"`
+}
+
+func (c *CommandXLIFFExport) Run(g *Global) error {
+	if c.SourceLocale == "" {
+		c.SourceLocale = g.Config.DefaultLocale
+	}
+	source := g.Config.remoteLocale(c.SourceLocale)
+
+	paths := c.Files
+	if len(paths) == 0 {
+		paths = []string{g.Config.ContentsDir}
+	}
+	files, err := expandFiles(g.Config.ContentsDir, paths, isTranslationFile)
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	for _, file := range files {
+		t := &zendesk.Translation{}
+		if err := t.FromFile(file); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		if t.SourceID == 0 || g.Config.remoteLocale(t.Locale) != source {
+			continue
+		}
+		rel := displayPath(g.Config.ContentsDir, file)
+		if filepath.IsAbs(rel) {
+			return fmt.Errorf("%s is not in the contents directory", file)
+		}
+
+		f := xliff.NewFile("f"+strconv.Itoa(t.SourceID), rel, t.Title, t.Body)
+		for _, target := range c.Targets {
+			doc := &xliff.Document{SrcLang: source, TrgLang: g.Config.remoteLocale(target), Files: []xliff.File{f}}
+			dest := filepath.Join(c.Out, target, strings.TrimSuffix(filepath.FromSlash(rel), filepath.Ext(rel))+xliffExt)
+			if err := writeXLIFF(dest, doc); err != nil {
+				return err
+			}
+			slog.Info("exported", "file", rel, "dest", dest)
+		}
+		count++
+	}
+	slog.Info("exported translations", "count", count, "targets", c.Targets)
+	return nil
+}
+
+func writeXLIFF(path string, doc *xliff.Document) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return xliff.Write(f, doc)
+}
+
+type CommandXLIFFImport struct {
+	Files []string `arg:"" help:"Specify the translated XLIFF files or directories." type:"path"`
+}
+
+func (c *CommandXLIFFImport) Run(g *Global) error {
+	files, err := expandXLIFFFiles(c.Files)
+	if err != nil {
+		return err
+	}
+	_, tmpl, err := g.Config.frontMatterTemplates()
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		r, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		doc, err := xliff.Read(r)
+		r.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		if doc.TrgLang == "" {
+			return fmt.Errorf("%s: trgLang is not specified", file)
+		}
+		locale := g.Config.localLocale(doc.TrgLang)
+		for _, f := range doc.Files {
+			dest, err := importXLIFFFile(g, &f, locale, tmpl)
+			if err != nil {
+				return fmt.Errorf("%s: %w", file, err)
+			}
+			slog.Info("imported", "file", file, "dest", dest)
+		}
+	}
+	return nil
+}
+
+// importXLIFFFile writes the translated file next to its source translation and returns the path.
+func importXLIFFFile(g *Global, f *xliff.File, locale string, tmpl *zendesk.FrontMatterTemplate) (string, error) {
+	if f.Original == "" {
+		return "", fmt.Errorf("original of file %s is not specified", f.ID)
+	}
+	title, body, err := f.Translated()
+	if err != nil {
+		return "", err
+	}
+
+	src := filepath.Join(g.Config.ContentsDir, filepath.FromSlash(f.Original))
+	source := &zendesk.Translation{}
+	if err := source.FromFile(src); err != nil {
+		return "", err
+	}
+	if source.SourceID == 0 {
+		return "", fmt.Errorf("%s is not a translation", f.Original)
+	}
+
+	dest, err := findTranslationFile(g, filepath.Dir(src), source.SourceID, locale)
+	if err != nil {
+		return "", err
+	}
+	var t *zendesk.Translation
+	if dest != "" {
+		t = &zendesk.Translation{}
+		if err := t.FromFile(dest); err != nil {
+			return "", err
+		}
+	} else {
+		t = &zendesk.Translation{
+			Locale:    locale,
+			Draft:     true,
+			SectionID: source.SectionID,
+			SourceID:  source.SourceID,
+			Brand:     source.Brand,
+		}
+		if dest, err = newTranslationPath(g, src, source, locale, title); err != nil {
+			return "", err
+		}
+	}
+	t.Title = title
+	t.Body = body
+	return dest, t.SaveWithTemplate(dest, false, tmpl)
+}
+
+// expandXLIFFFiles resolves the paths to the XLIFF files, walking the directories recursively.
+func expandXLIFFFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, p := range paths {
+		err := filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			if path == p || filepath.Ext(path) == xliffExt {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tukaelu/zgsync/internal/xliff"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+func TestImportXLIFFFile(t *testing.T) {
+	dir := t.TempDir()
+	src := "---\ntitle: はじめに\nlocale: ja\nsection_id: 5\nsource_id: 123\n---\n# 見出し\n"
+	if err := os.WriteFile(filepath.Join(dir, "123-ja.md"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja", LocaleAliases: map[string]string{"en": "en-us"}}}
+
+	translate := func(title, heading string) *xliff.File {
+		f := xliff.NewFile("f123", "123-ja.md", "はじめに", "# 見出し\n")
+		target0, target1 := xliff.Text(title), xliff.Text(heading)
+		f.Units[0].Segments[0].Target = &target0
+		f.Units[1].Segments[0].Target = &target1
+		return &f
+	}
+
+	dest, err := importXLIFFFile(g, translate("Introduction", "# Heading"), "en", nil)
+	if err != nil {
+		t.Fatalf("importXLIFFFile() failed: %v", err)
+	}
+	if expected := filepath.Join(dir, "123-en.md"); dest != expected {
+		t.Fatalf("importXLIFFFile() failed: got %s, want %s", dest, expected)
+	}
+	tr := &zendesk.Translation{}
+	if err := tr.FromFile(dest); err != nil {
+		t.Fatal(err)
+	}
+	if tr.Title != "Introduction" || tr.Locale != "en" || !tr.Draft || tr.SourceID != 123 || tr.SectionID != 5 || tr.Body != "# Heading\n" {
+		t.Errorf("importXLIFFFile() failed: unexpected translation %+v", tr)
+	}
+
+	// the existing translation is updated keeping its front matter.
+	tr.Draft = false
+	if err := tr.Save(dest, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := importXLIFFFile(g, translate("Getting started", "# Heading"), "en", nil); err != nil {
+		t.Fatalf("importXLIFFFile() failed: %v", err)
+	}
+	updated := &zendesk.Translation{}
+	if err := updated.FromFile(dest); err != nil {
+		t.Fatal(err)
+	}
+	if updated.Title != "Getting started" || updated.Draft {
+		t.Errorf("importXLIFFFile() failed: unexpected translation %+v", updated)
+	}
+}
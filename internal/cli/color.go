@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	colorReset  = "\x1b[0m"
+	colorFaint  = "\x1b[2m"
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+	colorCyan   = "\x1b[36m"
+)
+
+// formatColor is the log format of the terminal, which is used instead of text when the colors are enabled.
+const formatColor = "color"
+
+// status is the result of a file shown by the glyph of the colored logs.
+type status struct {
+	glyph string
+	color string
+}
+
+var (
+	statusAdded     = status{"+", colorGreen}
+	statusChanged   = status{"~", colorYellow}
+	statusRemoved   = status{"-", colorRed}
+	statusUnchanged = status{"=", colorFaint}
+	statusInfo      = status{"*", colorCyan}
+	statusWarn      = status{"!", colorYellow}
+	statusError     = status{"x", colorRed}
+)
+
+// statuses are the statuses of the messages reporting the results of the files.
+var statuses = map[string]status{
+	"created":                      statusAdded,
+	"created the section":          statusAdded,
+	"scaffolded":                   statusAdded,
+	"imported":                     statusAdded,
+	"translated":                   statusAdded,
+	"written":                      statusAdded,
+	"updated":                      statusChanged,
+	"pulled":                       statusChanged,
+	"pushed as draft":              statusChanged,
+	"published":                    statusChanged,
+	"moved":                        statusChanged,
+	"outdated":                     statusChanged,
+	"marked as outdated":           statusChanged,
+	"restored":                     statusChanged,
+	"kept the remote":              statusChanged,
+	"unpublished":                  statusRemoved,
+	"cleared":                      statusRemoved,
+	"up to date":                   statusUnchanged,
+	"identical to the remote":      statusUnchanged,
+	"skipped as it is unchanged":   statusUnchanged,
+	"skipped as it already exists": statusUnchanged,
+}
+
+// useColor reports whether w is a terminal and the colors are not disabled by --no-color or NO_COLOR.
+func useColor(w io.Writer, noColor bool) bool {
+	if noColor || os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	return ok && isTerminal(f)
+}
+
+// colorHandler writes the logs for the terminal as `{glyph} {message} key=value...`.
+type colorHandler struct {
+	w     io.Writer
+	mu    *sync.Mutex
+	level slog.Leveler
+	attrs []slog.Attr
+	group string
+}
+
+func newColorHandler(w io.Writer, opts *slog.HandlerOptions) *colorHandler {
+	return &colorHandler{w: w, mu: &sync.Mutex{}, level: opts.Level}
+}
+
+func (h *colorHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *colorHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := *h
+	c.attrs = slices.Clip(h.attrs)
+	for _, a := range attrs {
+		a.Key = h.group + a.Key
+		c.attrs = append(c.attrs, a)
+	}
+	return &c
+}
+
+func (h *colorHandler) WithGroup(name string) slog.Handler {
+	c := *h
+	c.group = h.group + name + "."
+	return &c
+}
+
+func (h *colorHandler) Handle(_ context.Context, r slog.Record) error {
+	st, ok := statuses[r.Message]
+	switch {
+	case r.Level >= slog.LevelError:
+		st = statusError
+	case r.Level >= slog.LevelWarn:
+		st = statusWarn
+	case !ok:
+		st = statusInfo
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s%s%s %s", st.color, st.glyph, colorReset, r.Message)
+	write := func(a slog.Attr) {
+		// the command is obvious on the terminal running it.
+		if a.Key == "command" || a.Equal(slog.Attr{}) {
+			return
+		}
+		v := a.Value.Resolve().String()
+		if v == "" || strings.ContainsAny(v, " \t\n\"=") {
+			v = strconv.Quote(v)
+		}
+		fmt.Fprintf(&b, " %s%s=%s%s", colorFaint, a.Key, colorReset, v)
+	}
+	for _, a := range h.attrs {
+		write(a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		a.Key = h.group + a.Key
+		write(a)
+		return true
+	})
+	b.WriteString("\n")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
@@ -2,22 +2,130 @@ package cli
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/tukaelu/zgsync/internal/converter"
+	"github.com/tukaelu/zgsync/internal/imageopt"
+	"github.com/tukaelu/zgsync/internal/state"
+	"github.com/tukaelu/zgsync/internal/zendesk"
 	"gopkg.in/yaml.v3"
 )
 
+const envPrefix = "ZGSYNC_"
+
+const (
+	lineEndingLF   = "lf"
+	lineEndingCRLF = "crlf"
+)
+
 type Config struct {
-	Subdomain                string `yaml:"subdomain" description:"Zendesk subdomain" required:"true"`
-	Email                    string `yaml:"email" description:"Zendesk email" required:"true"`
-	Token                    string `yaml:"token" description:"Zendesk API token" required:"true"`
-	DefaultCommentsDisabled  bool   `yaml:"default_comments_disabled" description:"Default comments disabled" default:"false"`
-	DefaultLocale            string `yaml:"default_locale" description:"Default locale for articles" required:"true"`
-	DefaultPermissionGroupID int    `yaml:"default_permission_group_id" description:"Default permission group ID" required:"true"`
-	DefailtUserSegmentID     *int   `yaml:"default_user_segment_id" description:"Default user segment ID"`
-	NotifySubscribers        bool   `yaml:"notify_subscribers" description:"Notify subscribers when creating or updating articles" default:"false"`
-	ContentsDir              string `yaml:"contents_dir" description:"Path to the contents directory" default:"."`
+	Subdomain                string               `yaml:"subdomain" description:"Zendesk subdomain" required:"true"`
+	Email                    string               `yaml:"email" description:"Zendesk email" required:"true"`
+	Token                    string               `yaml:"token" description:"Zendesk API token" required:"true"`
+	DefaultCommentsDisabled  bool                 `yaml:"default_comments_disabled" description:"Default comments disabled" default:"false"`
+	DefaultLocale            string               `yaml:"default_locale" description:"Default locale for articles" required:"true"`
+	DefaultPermissionGroupID int                  `yaml:"default_permission_group_id" description:"Default permission group ID" required:"true"`
+	DefailtUserSegmentID     *int                 `yaml:"default_user_segment_id" description:"Default user segment ID"`
+	NotifySubscribers        bool                 `yaml:"notify_subscribers" description:"Notify subscribers when creating or updating articles" default:"false"`
+	ContentsDir              string               `yaml:"contents_dir" description:"Path to the contents directory" default:"."`
+	HierarchyLayout          string               `yaml:"hierarchy_layout" description:"Directory layout template used when pulling with the category and section hierarchy"`
+	FilenameTemplate         string               `yaml:"filename_template" description:"File name template of the translations"`
+	ArticleFilenameTemplate  string               `yaml:"article_filename_template" description:"File name template of the articles"`
+	FrontMatterFormat        string               `yaml:"front_matter_format" description:"Format of the front matter of the local files (zgsync or hugo)"`
+	LocaleAliases            map[string]string    `yaml:"locale_aliases" description:"Zendesk locales by the locale codes used in the local files"`
+	ArticleFrontMatter       yaml.Node            `yaml:"article_front_matter" description:"Front matter skeleton of the articles written by empty and pull"`
+	TranslationFrontMatter   yaml.Node            `yaml:"translation_front_matter" description:"Front matter skeleton of the translations written by empty and pull"`
+	TemplatesDir             string               `yaml:"templates_dir" description:"Path to the directory of the article templates used by new, relative to the contents directory" default:"templates"`
+	PostsDir                 string               `yaml:"posts_dir" description:"Path to the directory of the community posts synced by posts, relative to the contents directory" default:"posts"`
+	Brand                    string               `yaml:"brand" description:"Brand of the help center to sync by default"`
+	Brands                   map[string]string    `yaml:"brands" description:"Subdomains of the help centers by brand"`
+	Sections                 map[string]int       `yaml:"sections" description:"Section IDs of the new articles by path prefix in the contents directory"`
+	Notifications            Notifications        `yaml:"notifications" description:"Endpoints receiving the summary after push"`
+	MachineTranslation       MachineTranslation   `yaml:"machine_translation" description:"Credentials of the machine translation providers"`
+	Images                   Images               `yaml:"images" description:"Optimization of the images uploaded as the attachments"`
+	AuditLog                 AuditLog             `yaml:"audit_log" description:"Local log of the pushes, pulls and restores"`
+	Changelog                Changelog            `yaml:"changelog" description:"Destinations of the change notes given by push --message"`
+	CacheTTL                 string               `yaml:"cache_ttl" description:"Duration such as 10m for which the lookups of the articles, translations, sections and categories are cached, which disables the cache if empty"`
+	DisableUpdateCheck       bool                 `yaml:"disable_update_check" description:"Whether to disable the check of the latest release by version --check"`
+	RequiredFields           RequiredFields       `yaml:"required_fields" description:"Front matter keys which validate and push require of the local files"`
+	Markup                   Markup               `yaml:"markup" description:"Markup which the help center accepts in the articles"`
+	Limits                   Limits               `yaml:"limits" description:"Limits of the converted bodies checked by validate and push"`
+	Checkers                 []Checker            `yaml:"checkers" description:"External checkers run by validate for each file, such as vale or cspell"`
+	LineEndings              string               `yaml:"line_endings" description:"Line endings of the files written by pull and the other commands (lf or crlf), which keep the ones of the existing files if empty"`
+	Environments             map[string]yaml.Node `yaml:"environments" description:"Named environments overriding the keys above"`
+}
+
+// Notifications are the endpoints receiving the summary after push.
+type Notifications struct {
+	SlackWebhookURL string `yaml:"slack_webhook_url" description:"URL of the Slack incoming webhook"`
+	WebhookURL      string `yaml:"webhook_url" description:"URL of the HTTP endpoint receiving the summary as JSON"`
+}
+
+// MachineTranslation are the credentials of the machine translation providers used by translate.
+type MachineTranslation struct {
+	DeepLAPIKey   string `yaml:"deepl_api_key" description:"API key of DeepL"`
+	DeepLEndpoint string `yaml:"deepl_endpoint" description:"Endpoint of the DeepL translate API"`
+}
+
+// Changelog is where the change notes of the pushes are written in addition to the sync state.
+type Changelog struct {
+	Comment   bool   `yaml:"comment" description:"Whether to append the change note to the pushed translations as a hidden HTML comment"`
+	ArticleID int    `yaml:"article_id" description:"ID of the \"What's new\" article to which the change notes are prepended"`
+	Locale    string `yaml:"locale" description:"Locale of the translation of the \"What's new\" article"`
+}
+
+// RequiredFields are the front matter keys which validate and push require of the local files, such as owner.
+type RequiredFields struct {
+	Article     []string `yaml:"article" description:"Keys required of the articles"`
+	Translation []string `yaml:"translation" description:"Keys required of the translations"`
+}
+
+// Markup is the markup which the help center accepts in the articles, against which validate and push check the HTML.
+type Markup struct {
+	UnsafeContent bool     `yaml:"unsafe_content" description:"Whether the help center displays the unsafe content, which disables the check"`
+	IframeHosts   []string `yaml:"iframe_hosts" description:"Hosts of the iframes allowed in addition to the video hosts"`
+}
+
+// Limits are the limits of the converted bodies, which catch the accidental pushes of the generated megafiles.
+type Limits struct {
+	MaxBodyKB int  `yaml:"max_body_kb" description:"Size in kilobytes of the converted body, or -1 to disable the check" default:"512"`
+	MaxImages int  `yaml:"max_images" description:"Number of the images in the body, or -1 to disable the check" default:"100"`
+	Fail      bool `yaml:"fail" description:"Whether to fail the push of the file exceeding the limits instead of warning"`
+}
+
+// strippedMarkup returns the markup of the HTML which the help center strips, unless it displays the unsafe content.
+func (c *Config) strippedMarkup(html string) []string {
+	if c.Markup.UnsafeContent {
+		return nil
+	}
+	return converter.StrippedMarkup(html, append(slices.Clone(converter.DefaultIframeHosts), c.Markup.IframeHosts...))
+}
+
+// AuditLog is the JSON Lines log recording the pushes, pulls and restores.
+type AuditLog struct {
+	Path       string `yaml:"path" description:"Path to the audit log relative to the contents directory"`
+	MaxSizeMB  int    `yaml:"max_size_mb" description:"Size in megabytes at which the audit log is rotated" default:"10"`
+	MaxBackups int    `yaml:"max_backups" description:"Number of the rotated audit logs to keep" default:"3"`
+}
+
+// Images is the optimization of the images uploaded as the attachments.
+type Images struct {
+	Optimize    bool   `yaml:"optimize" description:"Whether to optimize the JPEG and PNG images before uploading them, which strips their metadata such as EXIF"`
+	MaxWidth    int    `yaml:"max_width" description:"Width in pixels above which the images are resized, or 0 not to resize"`
+	Format      string `yaml:"format" description:"Format into which the images are re-encoded (jpeg or png), which keeps the format of each image if empty"`
+	JPEGQuality int    `yaml:"jpeg_quality" description:"Quality of the JPEG images from 1 to 100" default:"85"`
+}
+
+func (i Images) options() imageopt.Options {
+	return imageopt.Options{MaxWidth: i.MaxWidth, Format: i.Format, JPEGQuality: i.JPEGQuality}
 }
 
 func (c *Config) Validation() error {
@@ -36,25 +144,202 @@ func (c *Config) Validation() error {
 	if c.DefaultPermissionGroupID == 0 {
 		return fmt.Errorf("default_permission_group_id is required")
 	}
+	return c.validateValues()
+}
+
+// validateValues checks the values of the config keys which are set, without requiring the keys.
+func (c *Config) validateValues() error {
+	if c.DefaultLocale != "" && !reLocale.MatchString(c.DefaultLocale) {
+		return fmt.Errorf("default_locale: invalid locale %q", c.DefaultLocale)
+	}
+	urls := map[string]string{
+		"notifications.slack_webhook_url":    c.Notifications.SlackWebhookURL,
+		"notifications.webhook_url":          c.Notifications.WebhookURL,
+		"machine_translation.deepl_endpoint": c.MachineTranslation.DeepLEndpoint,
+	}
+	for key, s := range urls {
+		if s == "" {
+			continue
+		}
+		if u, err := url.Parse(s); err != nil || u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("%s: invalid URL %q", key, s)
+		}
+	}
+	for name, subdomain := range c.Brands {
+		if subdomain == "" {
+			return fmt.Errorf("brands: subdomain of %s is required", name)
+		}
+	}
+	if c.Brand != "" {
+		if _, ok := c.Brands[c.Brand]; !ok {
+			return fmt.Errorf("brand %s is not defined in brands", c.Brand)
+		}
+	}
+	switch c.FrontMatterFormat {
+	case "", frontMatterZgsync, frontMatterHugo:
+	default:
+		return fmt.Errorf("front_matter_format must be %s or %s", frontMatterZgsync, frontMatterHugo)
+	}
+	if _, err := c.lineEnding(); err != nil {
+		return err
+	}
+	if err := c.validateCheckers(); err != nil {
+		return err
+	}
+	if _, _, err := c.frontMatterTemplates(); err != nil {
+		return err
+	}
+	if _, err := c.cacheTTL(); err != nil {
+		return err
+	}
+	if err := c.Images.options().Validate(); err != nil {
+		return fmt.Errorf("images: %w", err)
+	}
+	return c.validateLocaleAliases()
+}
+
+// lineEnding returns the line ending of line_endings, which is empty to keep the ones of the existing files.
+func (c *Config) lineEnding() (string, error) {
+	switch c.LineEndings {
+	case "":
+		return "", nil
+	case lineEndingLF:
+		return "\n", nil
+	case lineEndingCRLF:
+		return "\r\n", nil
+	}
+	return "", fmt.Errorf("line_endings must be %s or %s", lineEndingLF, lineEndingCRLF)
+}
+
+// frontMatterTemplates returns the front matter templates of the articles and the translations, or nil.
+func (c *Config) frontMatterTemplates() (article, translation *zendesk.FrontMatterTemplate, err error) {
+	if article, err = zendesk.NewFrontMatterTemplate(&c.ArticleFrontMatter); err != nil {
+		return nil, nil, fmt.Errorf("article_front_matter: %w", err)
+	}
+	if translation, err = zendesk.NewFrontMatterTemplate(&c.TranslationFrontMatter); err != nil {
+		return nil, nil, fmt.Errorf("translation_front_matter: %w", err)
+	}
+	return article, translation, nil
+}
+
+// cacheTTL returns the TTL of the cache, which is zero when the cache is disabled.
+func (c *Config) cacheTTL() (time.Duration, error) {
+	if c.CacheTTL == "" {
+		return 0, nil
+	}
+	ttl, err := time.ParseDuration(c.CacheTTL)
+	if err != nil || ttl < 0 {
+		return 0, fmt.Errorf("cache_ttl must be a duration such as 10m: %s", c.CacheTTL)
+	}
+	return ttl, nil
+}
+
+// templatesDir returns the directory of the article templates.
+func (c *Config) templatesDir() string {
+	dir := c.TemplatesDir
+	if dir == "" {
+		dir = "templates"
+	}
+	if filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(c.ContentsDir, dir)
+}
+
+// postsDir returns the directory of the community posts.
+func (c *Config) postsDir() string {
+	dir := c.PostsDir
+	if dir == "" {
+		dir = "posts"
+	}
+	if filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(c.ContentsDir, dir)
+}
+
+var reLocale = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// validateLocaleAliases checks that the aliases can be mapped in both directions.
+func (c *Config) validateLocaleAliases() error {
+	remotes := map[string]string{}
+	for local, remote := range c.LocaleAliases {
+		if !reLocale.MatchString(local) {
+			return fmt.Errorf("locale_aliases: invalid locale %q", local)
+		}
+		if !reLocale.MatchString(remote) {
+			return fmt.Errorf("locale_aliases: invalid locale %q for %s", remote, local)
+		}
+		if other, ok := remotes[remote]; ok {
+			return fmt.Errorf("locale_aliases: %s and %s are both mapped to %s", min(local, other), max(local, other), remote)
+		}
+		remotes[remote] = local
+	}
+	for local, remote := range c.LocaleAliases {
+		if _, ok := c.LocaleAliases[remote]; ok && remote != local {
+			return fmt.Errorf("locale_aliases: %s is mapped to %s, which is also an alias", local, remote)
+		}
+	}
 	return nil
 }
 
+// remoteLocale returns the Zendesk locale of the locale code used in the local files.
+func (c *Config) remoteLocale(locale string) string {
+	if remote, ok := c.LocaleAliases[locale]; ok {
+		return remote
+	}
+	return locale
+}
+
+// localLocale returns the locale code used in the local files for the Zendesk locale.
+func (c *Config) localLocale(locale string) string {
+	for local, remote := range c.LocaleAliases {
+		if remote == locale {
+			return local
+		}
+	}
+	return locale
+}
+
 func (g *Global) LoadConfig() error {
 	if g.ConfigPath == "" {
 		home, _ := os.UserHomeDir()
 		g.ConfigPath = filepath.Join(home, ".config", "zgsync", "config.yaml")
 	}
 	b, err := os.ReadFile(g.ConfigPath)
-	if err != nil {
+	if err != nil && !hasEnvConfig() {
 		return nil
 	}
-	if err := yaml.Unmarshal(b, &g.Config); err != nil {
+	if err == nil {
+		if b, err = decryptConfig(g.ConfigPath, b, g.AgeKeyFile); err != nil {
+			return err
+		}
+		if err := validateSchema(g.ConfigPath, b, reflect.TypeOf(Config{})); err != nil {
+			return err
+		}
+		if err := yaml.Unmarshal(b, &g.Config); err != nil {
+			return err
+		}
+	}
+	if err := g.Config.applyEnvironment(g.Env); err != nil {
+		return err
+	}
+	if err := g.Config.applyEnv(); err != nil {
 		return err
 	}
 	if g.Config.ContentsDir == "" {
 		g.Config.ContentsDir = "."
 	}
-	return g.Config.Validation()
+	if g.Brand != "" {
+		g.Config.Brand = g.Brand
+	}
+	if err := g.Config.Validation(); err != nil {
+		return err
+	}
+	if g.Config.Brand != "" {
+		g.Config.Subdomain = g.Config.Brands[g.Config.Brand]
+	}
+	return nil
 }
 
 func (g *Global) ConfigExists() error {
@@ -72,3 +357,150 @@ func (g *Global) AbsConfig() string {
 		return abs
 	}
 }
+
+// applyEnvironment overrides the config with the keys defined in the named environment.
+func (c *Config) applyEnvironment(name string) error {
+	if name == "" {
+		return nil
+	}
+	node, ok := c.Environments[name]
+	if !ok {
+		return fmt.Errorf("environment %s is not defined", name)
+	}
+	if err := node.Decode(c); err != nil {
+		return fmt.Errorf("environment %s: %w", name, err)
+	}
+	return nil
+}
+
+// LoadState loads the sync state of the selected environment.
+func (g *Global) LoadState() (*state.Store, error) {
+	return state.LoadNamed(g.Config.ContentsDir, g.Env)
+}
+
+// LoadFailed loads the failed items of the last runs in the selected environment.
+func (g *Global) LoadFailed() (*state.Failed, error) {
+	return state.LoadFailed(g.Config.ContentsDir, g.Env)
+}
+
+// envName returns the environment variable name overriding the config key.
+func envName(key string) string {
+	return envPrefix + strings.ToUpper(key)
+}
+
+// envFields calls fn with the environment keys of the fields of v, descending into the nested keys.
+func envFields(v reflect.Value, prefix string, fn func(key string, f reflect.Value) error) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		key := yamlKey(t.Field(i))
+		if key == "" {
+			continue
+		}
+		f := v.Field(i)
+		if f.Kind() == reflect.Struct && f.Type() != reflect.TypeOf(yaml.Node{}) {
+			if err := envFields(f, prefix+key+"_", fn); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := fn(prefix+key, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasEnvConfig reports whether any config key is given by an environment variable.
+func hasEnvConfig() bool {
+	found := false
+	envFields(reflect.ValueOf(&Config{}).Elem(), "", func(key string, f reflect.Value) error {
+		_, ok := os.LookupEnv(envName(key))
+		found = found || ok
+		return nil
+	})
+	return found
+}
+
+// applyEnv overrides the config keys with the ZGSYNC_{KEY} environment variables.
+func (c *Config) applyEnv() error {
+	return envFields(reflect.ValueOf(c).Elem(), "", func(key string, f reflect.Value) error {
+		name := envName(key)
+		val, ok := os.LookupEnv(name)
+		if !ok {
+			return nil
+		}
+		if err := setFieldFromString(f, val); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		return nil
+	})
+}
+
+func yamlKey(f reflect.StructField) string {
+	if !f.IsExported() {
+		return ""
+	}
+	key, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+	if key == "-" {
+		return ""
+	}
+	return key
+}
+
+func setFieldFromString(f reflect.Value, val string) error {
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(val)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return err
+		}
+		f.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(val)
+		if err != nil {
+			return err
+		}
+		f.SetInt(int64(n))
+	case reflect.Pointer:
+		if val == "" {
+			f.Set(reflect.Zero(f.Type()))
+			return nil
+		}
+		p := reflect.New(f.Type().Elem())
+		if err := setFieldFromString(p.Elem(), val); err != nil {
+			return err
+		}
+		f.Set(p)
+	case reflect.Slice:
+		if f.Type().Elem().Kind() != reflect.String {
+			return setFieldFromYAML(f, val)
+		}
+		var items []string
+		for _, item := range strings.Split(val, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		f.Set(reflect.ValueOf(items))
+	case reflect.Map, reflect.Struct:
+		return setFieldFromYAML(f, val)
+	default:
+		return fmt.Errorf("unsupported type %s", f.Type())
+	}
+	return nil
+}
+
+// setFieldFromYAML sets the maps, the lists of the objects and the front matter skeletons given as YAML or JSON.
+func setFieldFromYAML(f reflect.Value, val string) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(val), &doc); err != nil {
+		return err
+	}
+	f.Set(reflect.Zero(f.Type()))
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	return doc.Content[0].Decode(f.Addr().Interface())
+}
@@ -1,6 +1,12 @@
 package cli
 
-import "testing"
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
 
 func TestLoadConfig(t *testing.T) {
 	refDefaultUserSegmentID := 456
@@ -111,3 +117,322 @@ func TestConfigExists(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadConfigEnv(t *testing.T) {
+	t.Setenv("ZGSYNC_SUBDOMAIN", "override")
+	t.Setenv("ZGSYNC_DEFAULT_LOCALE", "en-us")
+	t.Setenv("ZGSYNC_NOTIFY_SUBSCRIBERS", "true")
+	t.Setenv("ZGSYNC_DEFAULT_PERMISSION_GROUP_ID", "789")
+	t.Setenv("ZGSYNC_DEFAULT_USER_SEGMENT_ID", "12")
+	t.Setenv("ZGSYNC_NOTIFICATIONS_SLACK_WEBHOOK_URL", "https://hooks.slack.com/services/T0000/B0000/XXXXXXXX")
+	t.Setenv("ZGSYNC_LOCALE_ALIASES", `{"en": "en-us"}`)
+
+	var g Global
+	g.ConfigPath = "testdata/config.yaml"
+	if err := g.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+	if g.Config.Subdomain != "override" {
+		t.Errorf("Config.Subdomain failed: got %v, want %v", g.Config.Subdomain, "override")
+	}
+	if g.Config.Email != "hoge@example.com" {
+		t.Errorf("Config.Email failed: got %v, want %v", g.Config.Email, "hoge@example.com")
+	}
+	if g.Config.DefaultLocale != "en-us" {
+		t.Errorf("Config.DefaultLocale failed: got %v, want %v", g.Config.DefaultLocale, "en-us")
+	}
+	if !g.Config.NotifySubscribers {
+		t.Errorf("Config.NotifySubscribers failed: got %v, want %v", g.Config.NotifySubscribers, true)
+	}
+	if g.Config.DefaultPermissionGroupID != 789 {
+		t.Errorf("Config.DefaultPermissionGroupID failed: got %v, want %v", g.Config.DefaultPermissionGroupID, 789)
+	}
+	if g.Config.DefailtUserSegmentID == nil || *g.Config.DefailtUserSegmentID != 12 {
+		t.Errorf("Config.DefailtUserSegmentID failed: got %v, want %v", g.Config.DefailtUserSegmentID, 12)
+	}
+	if g.Config.Notifications.SlackWebhookURL != "https://hooks.slack.com/services/T0000/B0000/XXXXXXXX" {
+		t.Errorf("Config.Notifications.SlackWebhookURL failed: got %v", g.Config.Notifications.SlackWebhookURL)
+	}
+	if g.Config.LocaleAliases["en"] != "en-us" {
+		t.Errorf("Config.LocaleAliases failed: got %v", g.Config.LocaleAliases)
+	}
+}
+
+func TestLoadConfigEnvOnly(t *testing.T) {
+	t.Setenv("ZGSYNC_SUBDOMAIN", "example")
+	t.Setenv("ZGSYNC_EMAIL", "hoge@example.com")
+	t.Setenv("ZGSYNC_TOKEN", "foobarfoobar")
+	t.Setenv("ZGSYNC_DEFAULT_LOCALE", "ja")
+	t.Setenv("ZGSYNC_DEFAULT_PERMISSION_GROUP_ID", "123")
+
+	var g Global
+	g.ConfigPath = "testdata/config_not_exists.yaml"
+	if err := g.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+	if g.Config.Subdomain != "example" {
+		t.Errorf("Config.Subdomain failed: got %v, want %v", g.Config.Subdomain, "example")
+	}
+	if g.Config.ContentsDir != "." {
+		t.Errorf("Config.ContentsDir failed: got %v, want %v", g.Config.ContentsDir, ".")
+	}
+
+	t.Setenv("ZGSYNC_DEFAULT_PERMISSION_GROUP_ID", "abc")
+	if err := g.LoadConfig(); err == nil {
+		t.Errorf("LoadConfig() failed: invalid value should be an error")
+	}
+}
+
+// configLeaves returns the dotted keys of the leaves of the config with their environment variable names.
+func configLeaves(t reflect.Type, prefix, env string) map[string]string {
+	leaves := map[string]string{}
+	for i := 0; i < t.NumField(); i++ {
+		key := yamlKey(t.Field(i))
+		if key == "" {
+			continue
+		}
+		ft := t.Field(i).Type
+		if ft.Kind() == reflect.Struct && ft != reflect.TypeOf(yaml.Node{}) {
+			for k, v := range configLeaves(ft, prefix+key+".", env+strings.ToUpper(key)+"_") {
+				leaves[k] = v
+			}
+			continue
+		}
+		leaves[prefix+key] = env + strings.ToUpper(key)
+	}
+	return leaves
+}
+
+func TestConfigEnvLeaves(t *testing.T) {
+	samples := map[reflect.Kind]string{
+		reflect.String:  "x",
+		reflect.Bool:    "true",
+		reflect.Int:     "1",
+		reflect.Pointer: "1",
+		reflect.Map:     "{a: 1}",
+		reflect.Struct:  "{a: 1}",
+	}
+	leaves := configLeaves(reflect.TypeOf(Config{}), "", envPrefix)
+	names := map[string]string{}
+	for key, name := range leaves {
+		if other, ok := names[name]; ok {
+			t.Errorf("%s and %s are both given by %s", key, other, name)
+		}
+		names[name] = key
+	}
+	for key, name := range leaves {
+		t.Run(key, func(t *testing.T) {
+			var c Config
+			v := reflect.ValueOf(&c).Elem()
+			for _, k := range strings.Split(key, ".") {
+				for i := 0; i < v.NumField(); i++ {
+					if yamlKey(v.Type().Field(i)) == k {
+						v = v.Field(i)
+						break
+					}
+				}
+			}
+			val, ok := samples[v.Kind()]
+			if v.Kind() == reflect.Slice {
+				val, ok = "a,b", true
+				if v.Type().Elem().Kind() != reflect.String {
+					val = "[{}]"
+				}
+			}
+			if !ok {
+				t.Fatalf("no sample of %s", v.Type())
+			}
+			t.Setenv(name, val)
+			if !hasEnvConfig() {
+				t.Errorf("hasEnvConfig() failed: %s is not found", name)
+			}
+			if err := c.applyEnv(); err != nil {
+				t.Fatalf("applyEnv() failed: %v", err)
+			}
+			if v.IsZero() {
+				t.Errorf("applyEnv() failed: %s is not set by %s", key, name)
+			}
+		})
+	}
+}
+
+func TestLoadConfigEnvironments(t *testing.T) {
+	tests := []struct {
+		env               string
+		subdomain         string
+		token             string
+		notifySubscribers bool
+		notError          bool
+	}{
+		{"", "example", "foobarfoobar", true, true},
+		{"sandbox", "example-sandbox", "sandboxtoken", false, true},
+		{"production", "example", "foobarfoobar", true, true},
+		{"staging", "", "", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			var g Global
+			g.ConfigPath = "testdata/config_environments.yaml"
+			g.Env = tt.env
+			err := g.LoadConfig()
+			if tt.notError == (err != nil) {
+				t.Fatalf("LoadConfig() failed: %v", err)
+			}
+			if err != nil {
+				return
+			}
+			if g.Config.Subdomain != tt.subdomain {
+				t.Errorf("Config.Subdomain failed: got %v, want %v", g.Config.Subdomain, tt.subdomain)
+			}
+			if g.Config.Token != tt.token {
+				t.Errorf("Config.Token failed: got %v, want %v", g.Config.Token, tt.token)
+			}
+			if g.Config.Email != "hoge@example.com" {
+				t.Errorf("Config.Email failed: got %v, want %v", g.Config.Email, "hoge@example.com")
+			}
+			if g.Config.NotifySubscribers != tt.notifySubscribers {
+				t.Errorf("Config.NotifySubscribers failed: got %v, want %v", g.Config.NotifySubscribers, tt.notifySubscribers)
+			}
+		})
+	}
+}
+
+func TestLoadConfigSchema(t *testing.T) {
+	tests := []struct {
+		configPath string
+		want       string
+	}{
+		{
+			"testdata/config_unknown_key.yaml",
+			"testdata/config_unknown_key.yaml:4:1: unknown key default_local (did you mean default_locale?)",
+		},
+		{
+			"testdata/config_wrong_type.yaml",
+			"testdata/config_wrong_type.yaml:5:30: default_permission_group_id must be an integer",
+		},
+		{
+			"testdata/config_environment_unknown_key.yaml",
+			"testdata/config_environment_unknown_key.yaml:8:5: unknown key subdomian (did you mean subdomain?)",
+		},
+		{
+			"testdata/config_notifications_unknown_key.yaml",
+			"testdata/config_notifications_unknown_key.yaml:7:3: unknown key slack_webhook (did you mean slack_webhook_url?)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.configPath, func(t *testing.T) {
+			var g Global
+			g.ConfigPath = tt.configPath
+			err := g.LoadConfig()
+			if err == nil {
+				t.Fatalf("LoadConfig() failed: an error is expected")
+			}
+			if err.Error() != tt.want {
+				t.Errorf("LoadConfig() failed: got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNearestKey(t *testing.T) {
+	keys := []string{"subdomain", "email", "token", "default_locale", "contents_dir"}
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"default_local", "default_locale"},
+		{"emial", "email"},
+		{"content_dir", "contents_dir"},
+		{"foo", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := nearestKey(tt.key, keys); got != tt.want {
+				t.Errorf("nearestKey() failed: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocaleAliases(t *testing.T) {
+	c := &Config{LocaleAliases: map[string]string{"en": "en-us", "pt": "pt-br"}}
+	if err := c.validateLocaleAliases(); err != nil {
+		t.Fatalf("validateLocaleAliases() failed: %v", err)
+	}
+	tests := []struct {
+		local  string
+		remote string
+	}{
+		{"en", "en-us"},
+		{"pt", "pt-br"},
+		{"ja", "ja"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.local, func(t *testing.T) {
+			if got := c.remoteLocale(tt.local); got != tt.remote {
+				t.Errorf("remoteLocale() failed: got %v, want %v", got, tt.remote)
+			}
+			if got := c.localLocale(tt.remote); got != tt.local {
+				t.Errorf("localLocale() failed: got %v, want %v", got, tt.local)
+			}
+		})
+	}
+}
+
+func TestValidateLocaleAliases(t *testing.T) {
+	tests := []struct {
+		name    string
+		aliases map[string]string
+		want    string
+	}{
+		{"invalid local", map[string]string{"en us": "en-us"}, `locale_aliases: invalid locale "en us"`},
+		{"invalid remote", map[string]string{"en": ""}, `locale_aliases: invalid locale "" for en`},
+		{"duplicated", map[string]string{"en": "en-us", "us": "en-us"}, "locale_aliases: en and us are both mapped to en-us"},
+		{"chained", map[string]string{"en": "en-us", "en-us": "en-gb"}, "locale_aliases: en is mapped to en-us, which is also an alias"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Config{LocaleAliases: tt.aliases}
+			err := c.validateLocaleAliases()
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("validateLocaleAliases() failed: got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigBrand(t *testing.T) {
+	tests := []struct {
+		brand     string
+		subdomain string
+		notError  bool
+	}{
+		{"", "example", true},
+		{"developer", "example-developer", true},
+		{"partner", "example-partner", true},
+		{"unknown", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.brand, func(t *testing.T) {
+			var g Global
+			g.ConfigPath = "testdata/config_brands.yaml"
+			g.Brand = tt.brand
+			err := g.LoadConfig()
+			if tt.notError == (err != nil) {
+				t.Fatalf("LoadConfig() failed: %v", err)
+			}
+			if err != nil {
+				return
+			}
+			if g.Config.Subdomain != tt.subdomain {
+				t.Errorf("Config.Subdomain failed: got %v, want %v", g.Config.Subdomain, tt.subdomain)
+			}
+			if g.Config.Brand != tt.brand {
+				t.Errorf("Config.Brand failed: got %v, want %v", g.Config.Brand, tt.brand)
+			}
+		})
+	}
+}
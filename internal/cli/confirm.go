@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// confirmThreshold is the number of the affected items above which --yes requires --really.
+const confirmThreshold = 25
+
+// Confirm holds the flags confirming the operations changing the remote objects.
+type Confirm struct {
+	Yes         bool `name:"yes" short:"y" help:"It proceeds without the confirmation."`
+	Really      bool `name:"really" help:"It proceeds with --yes even if more than 25 objects are affected."`
+	interactive bool `kong:"-"`
+}
+
+// confirm lists the affected items and reports whether to proceed with the operation.
+func (c *Confirm) confirm(operation string, items []string) (bool, error) {
+	if len(items) == 0 {
+		return true, nil
+	}
+	many := len(items) > confirmThreshold
+	if c.Yes && (!many || c.Really) {
+		return true, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "%s %d objects:\n", operation, len(items))
+	for _, item := range items {
+		fmt.Fprintf(os.Stderr, "  %s\n", item)
+	}
+	if c.Yes {
+		return false, fmt.Errorf("%d objects are affected, which requires --really with --yes to %s more than %d objects", len(items), strings.ToLower(operation), confirmThreshold)
+	}
+	if !c.interactive {
+		return false, fmt.Errorf("specify --yes to %s when the input is not a terminal", strings.ToLower(operation))
+	}
+
+	if many {
+		answer, err := prompt(fmt.Sprintf("Type %d to %s the %d objects: ", len(items), strings.ToLower(operation), len(items)))
+		if err != nil {
+			return false, err
+		}
+		return answer == strconv.Itoa(len(items)), nil
+	}
+	answer, err := prompt(fmt.Sprintf("%s the %d objects? [y/N] ", operation, len(items)))
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(answer) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestConfirm(t *testing.T) {
+	few := []string{"a", "b"}
+	var many []string
+	for i := 0; i <= confirmThreshold; i++ {
+		many = append(many, fmt.Sprint(i))
+	}
+	tests := []struct {
+		name     string
+		confirm  Confirm
+		items    []string
+		input    string
+		expected bool
+		err      string
+	}{
+		{"nothing", Confirm{}, nil, "", true, ""},
+		{"yes", Confirm{Yes: true}, few, "", true, ""},
+		{"yes for many", Confirm{Yes: true}, many, "", false, "requires --really with --yes"},
+		{"really", Confirm{Yes: true, Really: true}, many, "", true, ""},
+		{"not a terminal", Confirm{}, few, "", false, "specify --yes"},
+		{"answered yes", Confirm{interactive: true}, few, "y\n", true, ""},
+		{"answered no", Confirm{interactive: true}, few, "\n", false, ""},
+		{"typed the count", Confirm{interactive: true}, many, "26\n", true, ""},
+		{"answered yes for many", Confirm{interactive: true}, many, "y\n", false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdin = bufio.NewReader(strings.NewReader(tt.input))
+			defer func() { stdin = bufio.NewReader(os.Stdin) }()
+			ok, err := tt.confirm.confirm("Unpublish", tt.items)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("confirm() failed: got error %v, want %s", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("confirm() failed: %v", err)
+			}
+			if ok != tt.expected {
+				t.Errorf("confirm() failed: got %v, want %v", ok, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/tukaelu/zgsync/internal/state"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+const (
+	conflictAsk    = "ask"
+	conflictLocal  = "local"
+	conflictRemote = "remote"
+	conflictSkip   = "skip"
+)
+
+// remoteNewer reports whether the remote translation has been updated since the last sync of the file.
+func remoteNewer(s *state.Store, file string, remote *zendesk.Translation) bool {
+	e, ok := s.Lookup(file)
+	if !ok || e.RemoteUpdatedAt == "" || remote.UpdatedAt == "" {
+		return false
+	}
+	return remote.UpdatedAt != e.RemoteUpdatedAt
+}
+
+// resolveConflict resolves the conflict of the file by --on-conflict and reports whether the file is pushed.
+func (c *CommandPush) resolveConflict(g *Global, file string, remote *zendesk.Translation) (bool, error) {
+	resolution := c.OnConflict
+	if resolution == conflictAsk && !c.interactive {
+		resolution = conflictSkip
+	}
+	for resolution == conflictAsk {
+		answer, err := prompt(fmt.Sprintf("%s has been updated remotely at %s. [d]iff, keep [l]ocal, keep [r]emote, [s]kip? ", file, remote.UpdatedAt))
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "d", "diff":
+			if err := c.writeConflictDiff(g, file, remote); err != nil {
+				return false, err
+			}
+		case "l", "local":
+			resolution = conflictLocal
+		case "r", "remote":
+			resolution = conflictRemote
+		case "s", "skip":
+			resolution = conflictSkip
+		}
+	}
+
+	switch resolution {
+	case conflictLocal:
+		return true, nil
+	case conflictRemote:
+		return false, c.keepRemote(g, file, remote)
+	default:
+		slog.Warn("skipped as the remote is newer", "file", file, "remote_updated_at", remote.UpdatedAt)
+		return false, nil
+	}
+}
+
+// writeConflictDiff writes the diff from the remote translation to the file to the standard error.
+func (c *CommandPush) writeConflictDiff(g *Global, file string, remote *zendesk.Translation) error {
+	t, err := g.Config.readTranslation(file)
+	if err != nil {
+		return err
+	}
+	body, err := c.remoteBody(t, remote)
+	if err != nil {
+		return err
+	}
+	from := "title: " + remote.Title + "\n\n" + strings.TrimRight(body, "\n")
+	to := "title: " + t.Title + "\n\n" + strings.TrimRight(t.Body, "\n")
+	writeDiff(os.Stderr, "remote", file, from, to, useColor(os.Stderr, g.NoColor))
+	return nil
+}
+
+// keepRemote overwrites the title and the body of the file with the remote translation, as pull does.
+func (c *CommandPush) keepRemote(g *Global, file string, remote *zendesk.Translation) error {
+	if g.Config.isHugo() {
+		return fmt.Errorf("keeping the remote is not supported with the front matter format %s", frontMatterHugo)
+	}
+	var convErr error
+	err := updateLocalTranslation(c.state, file, remote.UpdatedAt, func(t *zendesk.Translation) bool {
+		var body string
+		if body, convErr = c.remoteBody(t, remote); convErr != nil {
+			return false
+		}
+		t.Title = remote.Title
+		t.Body = body
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if convErr != nil {
+		return convErr
+	}
+	slog.Info("kept the remote", "file", file)
+	return nil
+}
+
+// remoteBody returns the body of the remote translation in the body format of the local translation.
+func (c *CommandPush) remoteBody(t *zendesk.Translation, remote *zendesk.Translation) (string, error) {
+	html, err := t.IsHTML()
+	if err != nil || html || c.Raw {
+		return remote.Body, err
+	}
+	return c.converter.ConvertToMarkdown(remote.Body)
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tukaelu/zgsync/internal/converter"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+func TestPushConflict(t *testing.T) {
+	remote := `{"translation":{"source_id":1,"locale":"ja","title":"remote","body":"<p>remote body</p>","updated_at":"2024-02-01T00:00:00Z"}}`
+	tests := []struct {
+		name        string
+		onConflict  string
+		interactive bool
+		input       string
+		updated     bool
+		expected    string
+	}{
+		{"local", conflictLocal, false, "", true, "local body"},
+		{"remote", conflictRemote, false, "", false, "remote body"},
+		{"skip", conflictSkip, false, "", false, "local body"},
+		{"ask without a terminal", conflictAsk, false, "", false, "local body"},
+		{"ask local after the diff", conflictAsk, true, "d\nl\n", true, "local body"},
+		{"ask remote", conflictAsk, true, "r\n", false, "remote body"},
+		{"ask skip", conflictAsk, true, "x\ns\n", false, "local body"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			file := filepath.Join(dir, "1-ja.md")
+			if err := os.WriteFile(file, []byte("---\ntitle: local\nlocale: ja\nsource_id: 1\n---\nlocal body\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja"}, NoColor: true}
+			s, err := g.LoadState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			s.Get(file).RemoteUpdatedAt = "2024-01-01T00:00:00Z"
+			if err := s.Save(); err != nil {
+				t.Fatal(err)
+			}
+
+			stdin = bufio.NewReader(strings.NewReader(tt.input))
+			defer func() { stdin = bufio.NewReader(os.Stdin) }()
+			client := &pushClient{remote: map[int]string{1: remote}}
+			c := &CommandPush{OnConflict: tt.onConflict, interactive: tt.interactive, Files: []string{file}, client: client, clients: map[string]zendesk.Client{}, converter: converter.NewConverter()}
+			if err := c.Run(g); err != nil {
+				t.Fatalf("Run() failed: %v", err)
+			}
+			if (len(client.updated) > 0) != tt.updated {
+				t.Errorf("Run() failed: got %v updated, want updated = %v", client.updated, tt.updated)
+			}
+			b, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(b), tt.expected) {
+				t.Errorf("Run() failed: %q is not in the file\n%s", tt.expected, b)
+			}
+		})
+	}
+}
+
+func TestPushNoConflict(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "1-ja.md")
+	if err := os.WriteFile(file, []byte("---\ntitle: local\nlocale: ja\nsource_id: 1\n---\nlocal body\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja"}}
+	s, err := g.LoadState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Get(file).RemoteUpdatedAt = "2024-01-01T00:00:00Z"
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the remote has not been updated since the last sync.
+	client := &pushClient{remote: map[int]string{1: `{"translation":{"title":"remote","body":"<p>remote body</p>","updated_at":"2024-01-01T00:00:00Z"}}`}}
+	c := &CommandPush{OnConflict: conflictSkip, Files: []string{file}, client: client, clients: map[string]zendesk.Client{}, converter: converter.NewConverter()}
+	if err := c.Run(g); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if len(client.updated) != 1 {
+		t.Errorf("Run() failed: got %v updated, want [1]", client.updated)
+	}
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	sopsCommand = "sops"
+	ageCommand  = "age"
+)
+
+// isSOPS reports whether the YAML document is encrypted by SOPS, which adds the sops metadata key at the top level.
+func isSOPS(b []byte) bool {
+	var doc map[string]yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return false
+	}
+	n, ok := doc["sops"]
+	return ok && n.Kind == yaml.MappingNode
+}
+
+// isAge reports whether the file is encrypted by age, in either the armored or the binary format.
+func isAge(b []byte) bool {
+	return bytes.HasPrefix(b, []byte("-----BEGIN AGE ENCRYPTED FILE-----")) ||
+		bytes.HasPrefix(b, []byte("age-encryption.org/v1\n"))
+}
+
+func isEncrypted(b []byte) bool {
+	return isAge(b) || isSOPS(b)
+}
+
+// decryptConfig returns the plain configuration of the file, decrypting it with sops or age if encrypted.
+func decryptConfig(path string, b []byte, ageKeyFile string) ([]byte, error) {
+	switch {
+	case isAge(b):
+		if ageKeyFile == "" {
+			return nil, fmt.Errorf("%s is encrypted with age, but the age key file is not specified", path)
+		}
+		out, err := runCommand(ageCommand, []string{"--decrypt", "--identity", ageKeyFile, path}, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt %s: %w", path, err)
+		}
+		return out, nil
+	case isSOPS(b):
+		var env []string
+		if ageKeyFile != "" {
+			env = append(env, "SOPS_AGE_KEY_FILE="+ageKeyFile)
+		}
+		out, err := runCommand(sopsCommand, []string{"--decrypt", "--input-type", "yaml", "--output-type", "yaml", path}, env)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt %s: %w", path, err)
+		}
+		return out, nil
+	default:
+		return b, nil
+	}
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func stubRunCommand(t *testing.T, out string, err error) *[]string {
+	t.Helper()
+	var called []string
+	orig := runCommand
+	runCommand = func(name string, args []string, env []string) ([]byte, error) {
+		called = append(append(append(called, name), args...), env...)
+		return []byte(out), err
+	}
+	t.Cleanup(func() { runCommand = orig })
+	return &called
+}
+
+func TestDecryptConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		src        string
+		ageKeyFile string
+		expected   []string
+		notError   bool
+	}{
+		{
+			"plain",
+			"subdomain: example\n",
+			"",
+			nil,
+			true,
+		},
+		{
+			"sops",
+			"token: ENC[...]\nsops:\n  version: 3.8.1\n",
+			"",
+			[]string{"sops", "--decrypt", "--input-type", "yaml", "--output-type", "yaml", "config.yaml"},
+			true,
+		},
+		{
+			"sops with age key file",
+			"token: ENC[...]\nsops:\n  version: 3.8.1\n",
+			"key.txt",
+			[]string{"sops", "--decrypt", "--input-type", "yaml", "--output-type", "yaml", "config.yaml", "SOPS_AGE_KEY_FILE=key.txt"},
+			true,
+		},
+		{
+			"sops key of a scalar",
+			"sops: plain\n",
+			"",
+			nil,
+			true,
+		},
+		{
+			"age",
+			"-----BEGIN AGE ENCRYPTED FILE-----\nYWdl\n-----END AGE ENCRYPTED FILE-----\n",
+			"key.txt",
+			[]string{"age", "--decrypt", "--identity", "key.txt", "config.yaml"},
+			true,
+		},
+		{
+			"age without key file",
+			"age-encryption.org/v1\n-> X25519 abc\n",
+			"",
+			nil,
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := stubRunCommand(t, "decrypted", nil)
+			b, err := decryptConfig("config.yaml", []byte(tt.src), tt.ageKeyFile)
+			if tt.notError == (err != nil) {
+				t.Fatalf("decryptConfig() failed: %v", err)
+			}
+			if !reflect.DeepEqual(*called, tt.expected) {
+				t.Errorf("decryptConfig() failed: got %v, want %v", *called, tt.expected)
+			}
+			if err != nil {
+				return
+			}
+			if tt.expected == nil && string(b) != tt.src {
+				t.Errorf("decryptConfig() failed: got %q, want %q", string(b), tt.src)
+			}
+			if tt.expected != nil && string(b) != "decrypted" {
+				t.Errorf("decryptConfig() failed: got %q, want %q", string(b), "decrypted")
+			}
+		})
+	}
+}
+
+func TestLoadConfigSOPS(t *testing.T) {
+	stubRunCommand(t, "subdomain: example\nemail: hoge@example.com\ntoken: foobarfoobar\ndefault_locale: ja\ndefault_permission_group_id: 123\n", nil)
+
+	var g Global
+	g.ConfigPath = "testdata/config_sops.yaml"
+	if err := g.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+	if g.Config.Token != "foobarfoobar" {
+		t.Errorf("Config.Token failed: got %v, want %v", g.Config.Token, "foobarfoobar")
+	}
+
+	stubRunCommand(t, "", errors.New("exit status 128"))
+	if err := g.LoadConfig(); err == nil {
+		t.Errorf("LoadConfig() failed: a decryption failure should be an error")
+	}
+}
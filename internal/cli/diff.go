@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// diffContext is the number of the unchanged lines shown around the changes.
+const diffContext = 3
+
+// diffLine is a line of a diff, whose op is ' ', '-' or '+'.
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// lineDiff returns the diff from the lines of a to the lines of b by their longest common subsequence.
+func lineDiff(a, b []string) []diffLine {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, diffLine{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, diffLine{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, diffLine{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, diffLine{'+', b[j]})
+	}
+	return lines
+}
+
+// writeDiff writes the diff from a to b with diffContext lines around the changed lines.
+func writeDiff(w io.Writer, from, to, a, b string, color bool) {
+	paint := func(c, s string) string {
+		if !color {
+			return s
+		}
+		return c + s + colorReset
+	}
+	fmt.Fprintln(w, paint(colorRed, "--- "+from))
+	fmt.Fprintln(w, paint(colorGreen, "+++ "+to))
+
+	lines := lineDiff(strings.Split(a, "\n"), strings.Split(b, "\n"))
+	shown := make([]bool, len(lines))
+	for i, l := range lines {
+		if l.op == ' ' {
+			continue
+		}
+		for k := max(0, i-diffContext); k <= min(len(lines)-1, i+diffContext); k++ {
+			shown[k] = true
+		}
+	}
+	gap := false
+	for i, l := range lines {
+		if !shown[i] {
+			gap = true
+			continue
+		}
+		if gap {
+			fmt.Fprintln(w, paint(colorCyan, "@@"))
+			gap = false
+		}
+		s := string(l.op) + l.text
+		switch l.op {
+		case '-':
+			s = paint(colorRed, s)
+		case '+':
+			s = paint(colorGreen, s)
+		}
+		fmt.Fprintln(w, s)
+	}
+}
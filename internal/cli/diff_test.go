@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		expected string
+	}{
+		{"identical", "a\nb", "a\nb", "--- a\n+++ b\n"},
+		{"changed", "a\nb\nc", "a\nx\nc", "--- a\n+++ b\n a\n-b\n+x\n c\n"},
+		{"added", "a", "a\nb", "--- a\n+++ b\n a\n+b\n"},
+		{"hunks", "1\n2\n3\n4\n5\n6\n7\n8\n9\n10", "0\n2\n3\n4\n5\n6\n7\n8\n9\n11", "--- a\n+++ b\n-1\n+0\n 2\n 3\n 4\n@@\n 7\n 8\n 9\n-10\n+11\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			writeDiff(&out, "a", "b", tt.a, tt.b, false)
+			if out.String() != tt.expected {
+				t.Errorf("writeDiff() failed: got\n%s\nwant\n%s", out.String(), tt.expected)
+			}
+		})
+	}
+}
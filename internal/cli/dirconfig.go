@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+const dirConfigFileName = ".zgsync.yaml"
+
+// DirConfig is the per-directory configuration, where the deeper files take precedence.
+type DirConfig struct {
+	SectionID         *int   `yaml:"section_id"`
+	Locale            string `yaml:"locale"`
+	PermissionGroupID *int   `yaml:"permission_group_id"`
+	NotifySubscribers *bool  `yaml:"notify_subscribers"`
+	Brand             string `yaml:"brand"`
+	// Section defines the section of the directory created by push --all. It is not inherited.
+	Section SectionDef `yaml:"section"`
+}
+
+// SectionDef is the definition of a section to create.
+type SectionDef struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	CategoryID  int    `yaml:"category_id"`
+	Position    int    `yaml:"position"`
+	Locale      string `yaml:"locale"`
+}
+
+func (d *DirConfig) merge(o *DirConfig) {
+	if o.SectionID != nil {
+		d.SectionID = o.SectionID
+	}
+	if o.Locale != "" {
+		d.Locale = o.Locale
+	}
+	if o.PermissionGroupID != nil {
+		d.PermissionGroupID = o.PermissionGroupID
+	}
+	if o.NotifySubscribers != nil {
+		d.NotifySubscribers = o.NotifySubscribers
+	}
+	if o.Brand != "" {
+		d.Brand = o.Brand
+	}
+}
+
+// dirConfigs resolves and caches the merged per-directory configurations under the contents directory.
+type dirConfigs struct {
+	root  string
+	mu    sync.Mutex
+	cache map[string]*DirConfig
+}
+
+func newDirConfigs(contentsDir string) (*dirConfigs, error) {
+	root, err := filepath.Abs(contentsDir)
+	if err != nil {
+		return nil, err
+	}
+	return &dirConfigs{root: root, cache: map[string]*DirConfig{}}, nil
+}
+
+// For returns the merged configuration applying to the file.
+func (d *dirConfigs) For(file string) (*DirConfig, error) {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(abs)
+	if !isUnder(d.root, dir) {
+		return &DirConfig{}, nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.forDir(dir)
+}
+
+func (d *dirConfigs) forDir(dir string) (*DirConfig, error) {
+	if dc, ok := d.cache[dir]; ok {
+		return dc, nil
+	}
+
+	dc := &DirConfig{}
+	if dir != d.root {
+		parent, err := d.forDir(filepath.Dir(dir))
+		if err != nil {
+			return nil, err
+		}
+		*dc = *parent
+	}
+
+	local, err := readDirConfig(dir)
+	if err != nil {
+		return nil, err
+	}
+	dc.merge(local)
+	dc.Section = local.Section
+
+	d.cache[dir] = dc
+	return dc, nil
+}
+
+// readDirConfig reads the configuration in the directory without merging the parents.
+func readDirConfig(dir string) (*DirConfig, error) {
+	dc := &DirConfig{}
+	path := filepath.Join(dir, dirConfigFileName)
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return dc, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSchema(path, b, reflect.TypeOf(DirConfig{})); err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(b, dc); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return dc, nil
+}
@@ -0,0 +1,46 @@
+package cli
+
+import "testing"
+
+func TestDirConfigs(t *testing.T) {
+	tests := []struct {
+		file              string
+		sectionID         int
+		locale            string
+		permissionGroupID int
+		notifySubscribers bool
+	}{
+		{"testdata/dirconfig/top.md", 0, "en-us", 1, false},
+		{"testdata/dirconfig/guides/intro.md", 10, "en-us", 1, true},
+		{"testdata/dirconfig/guides/advanced/tuning.md", 20, "ja", 1, true},
+	}
+
+	d, err := newDirConfigs("testdata/dirconfig")
+	if err != nil {
+		t.Fatalf("newDirConfigs() failed: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			dc, err := d.For(tt.file)
+			if err != nil {
+				t.Fatalf("For() failed: %v", err)
+			}
+			sectionID := 0
+			if dc.SectionID != nil {
+				sectionID = *dc.SectionID
+			}
+			if sectionID != tt.sectionID {
+				t.Errorf("DirConfig.SectionID failed: got %v, want %v", sectionID, tt.sectionID)
+			}
+			if dc.Locale != tt.locale {
+				t.Errorf("DirConfig.Locale failed: got %v, want %v", dc.Locale, tt.locale)
+			}
+			if dc.PermissionGroupID == nil || *dc.PermissionGroupID != tt.permissionGroupID {
+				t.Errorf("DirConfig.PermissionGroupID failed: got %v, want %v", dc.PermissionGroupID, tt.permissionGroupID)
+			}
+			if (dc.NotifySubscribers != nil && *dc.NotifySubscribers) != tt.notifySubscribers {
+				t.Errorf("DirConfig.NotifySubscribers failed: got %v, want %v", dc.NotifySubscribers, tt.notifySubscribers)
+			}
+		})
+	}
+}
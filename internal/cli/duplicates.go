@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+const (
+	duplicateWarn = "warn"
+	duplicateFail = "fail"
+)
+
+// titleScope is the section and the locale of the brand where the titles need to be unique.
+type titleScope struct {
+	brand     string
+	locale    string
+	sectionID int
+	dir       string
+}
+
+// titledFile is the file pushed with its title and the ID of its article.
+type titledFile struct {
+	file      string
+	title     string
+	articleID int
+}
+
+// titleSlug returns the slug which Zendesk derives from the title.
+func titleSlug(title string) string {
+	if s := slugify(title); s != "" {
+		return s
+	}
+	return strings.TrimSpace(title)
+}
+
+// checkDuplicates detects the titles and the slugs colliding in the same section and locale.
+func (c *CommandPush) checkDuplicates(g *Global, items []pushItem) error {
+	scopes := map[titleScope][]titledFile{}
+	var order []titleScope
+	pushed := map[string]bool{}
+	for _, item := range items {
+		scope, f, ok := c.titledFile(g, item)
+		if !ok {
+			continue
+		}
+		if _, ok := scopes[scope]; !ok {
+			order = append(order, scope)
+		}
+		scopes[scope] = append(scopes[scope], f)
+		if f.articleID != 0 {
+			pushed[fmt.Sprintf("%s/%d", scope.brand, f.articleID)] = true
+		}
+	}
+
+	var duplicates []string
+	report := func(f titledFile, other string) {
+		slog.Warn("duplicate title", "file", f.file, "title", f.title, "other", other)
+		duplicates = append(duplicates, fmt.Sprintf("%s and %s", f.file, other))
+	}
+	remotes := map[string][]zendesk.Article{}
+	for _, scope := range order {
+		files := scopes[scope]
+		seen := map[string]string{}
+		for _, f := range files {
+			slug := titleSlug(f.title)
+			if other, ok := seen[slug]; ok {
+				report(f, other)
+				continue
+			}
+			seen[slug] = f.file
+		}
+
+		if scope.sectionID == 0 {
+			continue
+		}
+		key := fmt.Sprintf("%s/%d", scope.brand, scope.sectionID)
+		articles, ok := remotes[key]
+		if !ok {
+			var err error
+			if articles, err = c.sectionArticles(g, scope.brand, scope.sectionID); err != nil {
+				return err
+			}
+			remotes[key] = articles
+		}
+		for _, f := range files {
+			for _, a := range articles {
+				if a.Locale != scope.locale || a.ID == f.articleID || pushed[fmt.Sprintf("%s/%d", scope.brand, a.ID)] {
+					continue
+				}
+				if titleSlug(a.Title) == titleSlug(f.title) {
+					report(f, fmt.Sprintf("article %d", a.ID))
+				}
+			}
+		}
+	}
+
+	if len(duplicates) > 0 && c.OnDuplicate == duplicateFail {
+		return fmt.Errorf("%d duplicate titles found: %s", len(duplicates), strings.Join(duplicates, ", "))
+	}
+	return nil
+}
+
+// titledFile reads the title of the file and its scope, and returns false if it cannot be read.
+func (c *CommandPush) titledFile(g *Global, item pushItem) (titleScope, titledFile, bool) {
+	dc, err := c.dirs.For(item.file)
+	if err != nil {
+		return titleScope{}, titledFile{}, false
+	}
+	var scope titleScope
+	f := titledFile{file: item.file}
+	var locale, brand string
+	if item.article {
+		a, err := g.Config.readArticle(item.file)
+		if err != nil {
+			return titleScope{}, titledFile{}, false
+		}
+		f.title, f.articleID, locale, brand, scope.sectionID = a.Title, a.ID, a.Locale, a.Brand, a.SectionID
+	} else {
+		t, err := g.Config.readTranslation(item.file)
+		if err != nil {
+			return titleScope{}, titledFile{}, false
+		}
+		f.title, f.articleID, locale, brand = t.Title, t.SourceID, t.Locale, t.Brand
+	}
+	if strings.TrimSpace(f.title) == "" {
+		return titleScope{}, titledFile{}, false
+	}
+
+	if scope.sectionID == 0 && dc.SectionID != nil {
+		scope.sectionID = *dc.SectionID
+	}
+	if scope.sectionID == 0 {
+		if id, ok := g.Config.sectionForFile(item.file); ok {
+			scope.sectionID = id
+		}
+	}
+	if scope.sectionID == 0 {
+		scope.dir = filepath.Dir(item.file)
+	}
+	if locale == "" {
+		locale = dc.Locale
+	}
+	if locale == "" {
+		locale = g.Config.DefaultLocale
+	}
+	scope.locale = g.Config.remoteLocale(locale)
+	scope.brand = brandOf(g, brand, dc)
+	return scope, f, true
+}
+
+// sectionArticles returns the remote articles of the section, or none if the section does not exist yet.
+func (c *CommandPush) sectionArticles(g *Global, brand string, sectionID int) ([]zendesk.Article, error) {
+	client, err := c.clientFor(g, brand)
+	if err != nil {
+		return nil, err
+	}
+	var articles []zendesk.Article
+	for page := 1; ; page++ {
+		res, err := client.ListSectionArticles(sectionID, page)
+		if zendesk.IsNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		list, hasNext, err := zendesk.ArticlesFromJson(res)
+		if err != nil {
+			return nil, err
+		}
+		articles = append(articles, list...)
+		if !hasNext {
+			return articles, nil
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+// duplicateClient has the articles Billing in ja and en-us, and Setup in ja of the section 5.
+type duplicateClient struct {
+	zendesk.Client
+}
+
+func (c *duplicateClient) ListSectionArticles(sectionID int, page int) (string, error) {
+	if sectionID != 5 {
+		return "", &zendesk.StatusError{StatusCode: 404}
+	}
+	return `{"articles":[{"id":9,"title":"Billing","locale":"ja"},{"id":10,"title":"Billing","locale":"en-us"},{"id":11,"title":"Setup","locale":"ja"}],"next_page":null}`, nil
+}
+
+func TestCheckDuplicates(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		expected string
+	}{
+		{
+			"unique",
+			map[string]string{
+				"a/1.md": "---\ntitle: Setup\nid: 1\nlocale: ja\n---\n",
+				"a/2.md": "---\ntitle: Billing\nid: 2\nlocale: ja\n---\n",
+				"b/3.md": "---\ntitle: Setup\nid: 3\nlocale: ja\n---\n",
+				"c/4.md": "---\ntitle: Setup\nid: 4\nlocale: en-us\nsection_id: 5\n---\n",
+			},
+			"",
+		},
+		{
+			"same slug in the directory",
+			map[string]string{
+				"a/1.md": "---\ntitle: Setup guide\nid: 1\nlocale: ja\n---\n",
+				"a/2.md": "---\ntitle: 'setup guide!'\nid: 2\nlocale: ja\n---\n",
+			},
+			"1 duplicate titles found: a/2.md and a/1.md",
+		},
+		{
+			"remote article of the section",
+			map[string]string{
+				"a/1.md": "---\ntitle: Billing\nid: 1\nlocale: ja\nsection_id: 5\n---\n",
+				"a/2.md": "---\ntitle: Other\nlocale: ja\nsection_id: 6\n---\n",
+			},
+			"1 duplicate titles found: a/1.md and article 9",
+		},
+		{
+			"remote article pushed together",
+			map[string]string{
+				"a/1.md":  "---\ntitle: Setup\nid: 1\nlocale: ja\nsection_id: 5\n---\n",
+				"a/11.md": "---\ntitle: Installation\nid: 11\nlocale: ja\nsection_id: 5\n---\n",
+			},
+			"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			var items []pushItem
+			for _, name := range []string{"a/1.md", "a/2.md", "a/11.md", "b/3.md", "c/4.md"} {
+				content, ok := tt.files[name]
+				if !ok {
+					continue
+				}
+				file := filepath.Join(dir, name)
+				if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				items = append(items, pushItem{file: file, article: true})
+			}
+			g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja"}}
+			dirs, err := newDirConfigs(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := &CommandPush{OnDuplicate: duplicateFail, client: &duplicateClient{}, clients: map[string]zendesk.Client{}, dirs: dirs}
+
+			var got string
+			if err := c.checkDuplicates(g, items); err != nil {
+				got = strings.ReplaceAll(err.Error(), dir+string(filepath.Separator), "")
+			}
+			if got != tt.expected {
+				t.Errorf("checkDuplicates() failed: got %q, want %q", got, tt.expected)
+			}
+
+			c.OnDuplicate = duplicateWarn
+			if err := c.checkDuplicates(g, items); err != nil {
+				t.Errorf("checkDuplicates() failed: %v", err)
+			}
+		})
+	}
+}
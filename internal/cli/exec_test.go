@@ -0,0 +1,23 @@
+package cli
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestRunCommandFailure(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not installed")
+	}
+	// the output of the checkers exiting with a non-zero status is kept with the standard error.
+	out, err := runCommand("sh", []string{"-c", "echo 1-ja.md:3: typo; echo failed >&2; exit 1"}, nil)
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || !strings.HasSuffix(err.Error(), ": failed") {
+		t.Errorf("runCommand() failed: got the error %v", err)
+	}
+	if string(out) != "1-ja.md:3: typo\n" {
+		t.Errorf("runCommand() failed: got %q", out)
+	}
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/adrg/frontmatter"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+	"gopkg.in/yaml.v3"
+)
+
+// missingFields returns the required keys which the front matter of the file does not have or leaves empty.
+func missingFields(b []byte, required []string) ([]string, error) {
+	if len(required) == 0 {
+		return nil, nil
+	}
+	var fm yaml.Node
+	format := frontmatter.NewFormat("---", "---", yaml.Unmarshal)
+	if _, err := frontmatter.Parse(bytes.NewReader(b), &fm, format); err != nil {
+		return nil, err
+	}
+	values := map[string]*yaml.Node{}
+	if len(fm.Content) > 0 && fm.Content[0].Kind == yaml.MappingNode {
+		m := fm.Content[0]
+		for i := 0; i+1 < len(m.Content); i += 2 {
+			values[m.Content[i].Value] = m.Content[i+1]
+		}
+	}
+	var missing []string
+	for _, key := range required {
+		if v, ok := values[key]; !ok || isEmptyNode(v) {
+			missing = append(missing, key)
+		}
+	}
+	return missing, nil
+}
+
+func isEmptyNode(n *yaml.Node) bool {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		return n.Tag == "!!null" || (n.Tag == "!!str" && n.Value == "")
+	case yaml.SequenceNode, yaml.MappingNode:
+		return len(n.Content) == 0
+	}
+	return false
+}
+
+// checkRequiredFields returns the error of the keys of required_fields missing in the file, with a message by key.
+func (c *Config) checkRequiredFields(file string, article bool) error {
+	required := c.RequiredFields.Translation
+	if article {
+		required = c.RequiredFields.Article
+	}
+	if len(required) == 0 {
+		return nil
+	}
+	b, err := zendesk.ReadText(file)
+	if err != nil {
+		return err
+	}
+	missing, err := missingFields(b, required)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, key := range missing {
+		errs = append(errs, fmt.Errorf("%s is required by required_fields", key))
+	}
+	return errors.Join(errs...)
+}
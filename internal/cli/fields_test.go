@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMissingFields(t *testing.T) {
+	content := "---\ntitle: t\nowner: docs\nlabels: []\nsection_id:\nsummary: ''\nuser_segment_ids:\n  - 1\n---\nbody\n"
+	tests := []struct {
+		name     string
+		required []string
+		expected []string
+	}{
+		{"none", nil, nil},
+		{"present", []string{"owner", "user_segment_ids"}, nil},
+		{"empty", []string{"labels", "section_id", "summary"}, []string{"labels", "section_id", "summary"}},
+		{"absent", []string{"title", "content_tag_ids"}, []string{"content_tag_ids"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := missingFields([]byte(content), tt.required)
+			if err != nil {
+				t.Fatalf("missingFields() failed: %v", err)
+			}
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("missingFields() failed: got %v, want %v", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCheckRequiredFields(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "1-ja.md")
+	if err := os.WriteFile(file, []byte("---\ntitle: t\nlocale: ja\nsource_id: 1\n---\nbody\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c := &Config{RequiredFields: RequiredFields{Article: []string{"section_id"}, Translation: []string{"owner", "locale", "labels"}}}
+	err := c.checkRequiredFields(file, false)
+	if err == nil || err.Error() != "owner is required by required_fields\nlabels is required by required_fields" {
+		t.Errorf("checkRequiredFields() failed: unexpected error %v", err)
+	}
+	if err := (&Config{}).checkRequiredFields(file, false); err != nil {
+		t.Errorf("checkRequiredFields() failed: %v", err)
+	}
+}
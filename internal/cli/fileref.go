@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"github.com/adrg/frontmatter"
+	"github.com/tukaelu/zgsync/internal/state"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+// fileRef holds the front matter fields identifying the remote article or the community post.
+type fileRef struct {
+	ID        int    `yaml:"id" toml:"id"`
+	SourceID  int    `yaml:"source_id" toml:"source_id"`
+	SectionID int    `yaml:"section_id" toml:"section_id"`
+	TopicID   int    `yaml:"topic_id" toml:"topic_id"`
+	Title     string `yaml:"title" toml:"title"`
+	Locale    string `yaml:"locale" toml:"locale"`
+	Brand     string `yaml:"brand" toml:"brand"`
+	HtmlURL   string `yaml:"html_url" toml:"html_url"`
+	Path      string `yaml:"-" toml:"-"`
+	Kind      string `yaml:"-" toml:"-"`
+}
+
+// resolveFileRef resolves the target given as either an article ID or a file path.
+func resolveFileRef(target string) (*fileRef, error) {
+	if id, err := strconv.Atoi(target); err == nil {
+		return &fileRef{ID: id}, nil
+	}
+	return readFileRef(target)
+}
+
+func readFileRef(path string) (*fileRef, error) {
+	ref, err := parseFileRef(path)
+	if err != nil {
+		return nil, err
+	}
+	if ref.SourceID != 0 {
+		ref.ID = ref.SourceID
+		ref.Kind = state.KindTranslation
+	}
+	if ref.ID == 0 {
+		return nil, fmt.Errorf("article ID is not found in %s", path)
+	}
+	return ref, nil
+}
+
+// parseFileRef parses the front matter of the file as it is, without requiring the article ID.
+func parseFileRef(path string) (*fileRef, error) {
+	b, err := zendesk.ReadText(path)
+	if err != nil {
+		return nil, err
+	}
+	ref := &fileRef{Path: path, Kind: state.KindArticle}
+	if _, err := frontmatter.Parse(bytes.NewReader(b), ref); err != nil {
+		return nil, err
+	}
+	if ref.TopicID != 0 && ref.SourceID == 0 {
+		ref.Kind = state.KindPost
+	}
+	return ref, nil
+}
@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tukaelu/zgsync/internal/ignore"
+	"github.com/tukaelu/zgsync/internal/state"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+// expandFiles resolves the paths to absolute file paths, walking the directories for Markdown files.
+func expandFiles(contentsDir string, paths []string, filter func(path string) bool) ([]string, error) {
+	root, err := filepath.Abs(contentsDir)
+	if err != nil {
+		return nil, err
+	}
+	matcher, err := ignore.Load(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, p := range paths {
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			return nil, err
+		}
+		fi, err := os.Stat(abs)
+		if err != nil {
+			return nil, err
+		}
+		if !fi.IsDir() {
+			files = append(files, abs)
+			continue
+		}
+
+		base := root
+		if !isUnder(root, abs) {
+			base = abs
+		}
+		err = filepath.WalkDir(abs, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(base, path)
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if path != abs && (strings.HasPrefix(d.Name(), ".") || matcher.Match(rel, true)) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if filepath.Ext(path) != ".md" || matcher.Match(rel, false) {
+				return nil
+			}
+			if filter != nil && !filter(path) {
+				return nil
+			}
+			files = append(files, path)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+func isUnder(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// isTranslationFile reports whether the file has the front matter of a translation.
+func isTranslationFile(path string) bool {
+	ref, err := readFileRef(path)
+	return err == nil && ref.Kind == state.KindTranslation
+}
+
+// findTranslationFile returns the translation file of the article and the locale in the directory.
+func findTranslationFile(g *Global, dir string, articleID int, locale string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".md" {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		ref, err := readFileRef(path)
+		if err != nil || ref.Kind != state.KindTranslation || ref.ID != articleID {
+			continue
+		}
+		if g.Config.remoteLocale(ref.Locale) == g.Config.remoteLocale(locale) {
+			return path, nil
+		}
+	}
+	return "", nil
+}
+
+// articleTranslationFiles returns the translation files next to the article file, the locale of the article first.
+func articleTranslationFiles(g *Global, file string, articleID int, locale string) ([]string, error) {
+	dir := filepath.Dir(file)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	prefix := strings.TrimSuffix(filepath.Base(file), ".md") + "."
+
+	var files []string
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".md" || e.Name() == filepath.Base(file) {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		ref, err := parseFileRef(path)
+		if err != nil {
+			continue
+		}
+		paired := ref.SourceID == 0 && ref.ID == 0 && strings.HasPrefix(e.Name(), prefix)
+		if ref.SourceID != 0 && ref.SourceID == articleID || paired {
+			if g.Config.remoteLocale(ref.Locale) == locale {
+				files = append([]string{path}, files...)
+			} else {
+				files = append(files, path)
+			}
+		}
+	}
+	return files, nil
+}
+
+// newTranslationPath returns the path of a new translation of the locale next to the source translation.
+func newTranslationPath(g *Global, src string, source *zendesk.Translation, locale, title string) (string, error) {
+	hierarchy := filepath.Base(src) == source.Locale+".md"
+	names, err := newFileNamer(g.Config.FilenameTemplate, g.Config.ArticleFilenameTemplate, hierarchy)
+	if err != nil {
+		return "", err
+	}
+	name, err := names.Translation(layoutData{
+		ArticleID:   source.SourceID,
+		ArticleSlug: slugify(title),
+		Title:       pathElement(title),
+		Locale:      locale,
+		SectionID:   source.SectionID,
+	})
+	if err != nil {
+		return "", err
+	}
+	return layoutPath(filepath.Dir(src), name)
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExpandFiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		paths    []string
+		expected []string
+	}{
+		{
+			"directory honors .zgsyncignore",
+			[]string{"testdata/contents"},
+			[]string{"testdata/contents/1-ja.md", "testdata/contents/10/3-ja.md"},
+		},
+		{
+			"explicit file is not ignored",
+			[]string{"testdata/contents/README.md"},
+			[]string{"testdata/contents/README.md"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := expandFiles("testdata/contents", tt.paths, nil)
+			if err != nil {
+				t.Fatalf("expandFiles() failed: %v", err)
+			}
+			if len(files) != len(tt.expected) {
+				t.Fatalf("expandFiles() failed: got %v, want %v", files, tt.expected)
+			}
+			for i, f := range files {
+				want, _ := filepath.Abs(tt.expected[i])
+				if f != want {
+					t.Errorf("expandFiles() failed: got %v, want %v", f, want)
+				}
+			}
+		})
+	}
+}
+
+func TestArticleTranslationFiles(t *testing.T) {
+	g := &Global{Config: Config{DefaultLocale: "ja", LocaleAliases: map[string]string{"en": "en-us"}}}
+	tests := []struct {
+		name      string
+		file      string
+		articleID int
+		locale    string
+		expected  []string
+	}{
+		{
+			"new article is paired by the file name",
+			"testdata/paired/guide.md",
+			0,
+			"ja",
+			[]string{"testdata/paired/guide.ja.md", "testdata/paired/guide.en-us.md"},
+		},
+		{
+			"article is paired by source_id",
+			"testdata/paired/200.md",
+			200,
+			"en-us",
+			[]string{"testdata/paired/200-en-us.md", "testdata/paired/200-ja.md"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := articleTranslationFiles(g, tt.file, tt.articleID, tt.locale)
+			if err != nil {
+				t.Fatalf("articleTranslationFiles() failed: %v", err)
+			}
+			if !reflect.DeepEqual(files, tt.expected) {
+				t.Errorf("articleTranslationFiles() failed: got %v, want %v", files, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+// localePathPattern matches the locale in the path of the request.
+var localePathPattern = regexp.MustCompile(`/help_center/([a-z]{2,3}(?:-[a-z0-9]+)*)/|/translations/([a-z]{2,3}(?:-[a-z0-9]+)*)$`)
+
+// hint returns the guidance to fix the common failures of the API, or an empty string for the others.
+func hint(err error) string {
+	var se *zendesk.StatusError
+	if !errors.As(err, &se) {
+		return ""
+	}
+	mentions := func(words ...string) bool {
+		s := strings.ToLower(se.Code + " " + se.Description)
+		for _, w := range words {
+			if strings.Contains(s, w) {
+				return true
+			}
+		}
+		return false
+	}
+	_, permissionGroup := se.Details["permission_group_id"]
+	_, userSegment := se.Details["user_segment_id"]
+	_, userSegments := se.Details["user_segment_ids"]
+	_, locale := se.Details["locale"]
+
+	switch {
+	case se.StatusCode == http.StatusUnauthorized:
+		return "check email and token in the configuration or ZGSYNC_EMAIL and ZGSYNC_TOKEN, and that token access is enabled under Admin Center → Apps and integrations → Zendesk API"
+	case se.StatusCode == http.StatusForbidden:
+		return "the user of the token needs to be a Guide admin, or an agent who can manage the articles of the section under Guide admin → Settings → Permissions"
+	case permissionGroup || mentions("permission group", "permission_group"):
+		return "check permission_group_id or default_permission_group_id against the permission groups under Guide admin → Settings → Permissions"
+	case locale || mentions("locale"):
+		l := "the locale"
+		if m := localePathPattern.FindStringSubmatch(se.Path); m != nil {
+			l = "locale " + m[1] + m[2]
+		}
+		return fmt.Sprintf("enable %s under Guide admin → Settings → Language settings, or map it with locale_aliases", l)
+	case isBlank(se.Details["title"]):
+		return "set title in the front matter, as the title cannot be blank"
+	case userSegment || userSegments || mentions("user segment", "user_segment"):
+		return "check user_segment_id, user_segment_ids or default_user_segment_id: the user segments must exist and apply to the brand of the help center"
+	}
+	return ""
+}
+
+func isBlank(details []zendesk.ErrorDetail) bool {
+	for _, d := range details {
+		if d.Error == "BlankValue" || strings.Contains(strings.ToLower(d.Description), "blank") {
+			return true
+		}
+	}
+	return false
+}
+
+// withHint appends the hint for the error to it so that it is shown with the error on exit.
+func withHint(err error) error {
+	if h := hint(err); h != "" {
+		return fmt.Errorf("%w\nhint: %s", err, h)
+	}
+	return err
+}
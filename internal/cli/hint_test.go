@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+func TestHint(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"unauthorized", &zendesk.StatusError{StatusCode: 401}, "check email and token"},
+		{"permission group", &zendesk.StatusError{StatusCode: 422, Details: map[string][]zendesk.ErrorDetail{"permission_group_id": {{Error: "InvalidValue"}}}}, "check permission_group_id"},
+		{"locale of the path", fmt.Errorf("article 1: %w", &zendesk.StatusError{StatusCode: 400, Code: "InvalidLocale", Path: "/api/v2/help_center/articles/1/translations/pt-br"}), "enable locale pt-br under Guide admin"},
+		{"locale of the section", &zendesk.StatusError{StatusCode: 400, Description: "Locale is not enabled", Path: "/api/v2/help_center/fr/sections/1"}, "enable locale fr under"},
+		{"blank title", &zendesk.StatusError{StatusCode: 422, Details: map[string][]zendesk.ErrorDetail{"title": {{Error: "BlankValue", Description: "Title: cannot be blank"}}}}, "set title in the front matter"},
+		{"user segment", &zendesk.StatusError{StatusCode: 422, Details: map[string][]zendesk.ErrorDetail{"user_segment_ids": {{Error: "InvalidValue"}}}}, "check user_segment_id"},
+		{"not found", &zendesk.StatusError{StatusCode: 404}, ""},
+		{"other error", errors.New("invalid payload"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := hint(tt.err)
+			if tt.expected == "" && got != "" || !strings.Contains(got, tt.expected) {
+				t.Errorf("hint() failed: got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+	if err := withHint(&zendesk.StatusError{StatusCode: 401}); !strings.Contains(err.Error(), "unexpected status code: 401\nhint: check email") {
+		t.Errorf("withHint() failed: got %q", err)
+	}
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+const (
+	frontMatterZgsync = "zgsync"
+	frontMatterHugo   = "hugo"
+)
+
+func (c *Config) isHugo() bool {
+	return c.FrontMatterFormat == frontMatterHugo
+}
+
+// readTranslation reads the translation file in the front matter format of the config.
+func (c *Config) readTranslation(file string) (*zendesk.Translation, error) {
+	if c.isHugo() {
+		p := &zendesk.HugoPage{}
+		if err := p.FromFile(file); err != nil {
+			return nil, err
+		}
+		return p.Translation(), nil
+	}
+	t := &zendesk.Translation{}
+	if err := t.FromFile(file); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// readArticle reads the article file in the front matter format of the config.
+func (c *Config) readArticle(file string) (*zendesk.Article, error) {
+	if c.isHugo() {
+		p := &zendesk.HugoPage{}
+		if err := p.FromFile(file); err != nil {
+			return nil, err
+		}
+		return p.Article(), nil
+	}
+	a := &zendesk.Article{}
+	if err := a.FromFile(file); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// mergeHugoArticle returns the remote article overlaid with the fields mapped from the Hugo page.
+func mergeHugoArticle(client zendesk.Client, locale string, page *zendesk.Article) (*zendesk.Article, error) {
+	// the cached article would overwrite the remote changes with the stale fields.
+	res, err := zendesk.Uncached(client).ShowArticle(locale, page.ID)
+	if err != nil {
+		return nil, err
+	}
+	a := &zendesk.Article{}
+	if err := a.FromJson(res); err != nil {
+		return nil, err
+	}
+	a.Locale = locale
+	a.Brand = page.Brand
+	if page.Title != "" {
+		a.Title = page.Title
+	}
+	if page.SectionID != 0 {
+		a.SectionID = page.SectionID
+	}
+	if page.Position != 0 {
+		a.Position = page.Position
+	}
+	if page.LabelNames != nil {
+		a.LabelNames = page.LabelNames
+	}
+	return a, nil
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+// showArticleClient returns the article JSON from ShowArticle. The other methods are not implemented.
+type showArticleClient struct {
+	zendesk.Client
+	res string
+}
+
+func (c *showArticleClient) ShowArticle(locale string, articleID int) (string, error) {
+	return c.res, nil
+}
+
+func TestMergeHugoArticle(t *testing.T) {
+	client := &showArticleClient{res: `{"article":{"id":123,"title":"old","locale":"ja","section_id":1,"position":5,"permission_group_id":7,"user_segment_id":8,"label_names":["a"]}}`}
+	page := &zendesk.Article{ID: 123, Title: "new", Position: 20, LabelNames: []string{"intro"}}
+
+	a, err := mergeHugoArticle(client, "ja", page)
+	if err != nil {
+		t.Fatalf("mergeHugoArticle() failed: %v", err)
+	}
+	if a.Title != "new" || a.Position != 20 || a.SectionID != 1 || a.PermissionGroupID != 7 || !reflect.DeepEqual(a.LabelNames, []string{"intro"}) {
+		t.Errorf("mergeHugoArticle() failed: unexpected article %+v", a)
+	}
+	if a.UserSegmentID == nil || *a.UserSegmentID != 8 {
+		t.Errorf("mergeHugoArticle() failed: the user segment should be kept: %v", a.UserSegmentID)
+	}
+}
+
+func TestReadTranslationHugo(t *testing.T) {
+	c := &Config{FrontMatterFormat: frontMatterHugo}
+	tr, err := c.readTranslation("testdata/hugo/about.ja.md")
+	if err != nil {
+		t.Fatalf("readTranslation() failed: %v", err)
+	}
+	if tr.SourceID != 12345 || tr.Locale != "ja" || !tr.Draft {
+		t.Errorf("readTranslation() failed: unexpected translation %+v", tr)
+	}
+	ref, err := readFileRef("testdata/hugo/about.ja.md")
+	if err != nil || ref.ID != 12345 {
+		t.Errorf("readFileRef() failed: %v %+v", err, ref)
+	}
+}
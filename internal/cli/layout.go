@@ -0,0 +1,198 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+const (
+	defaultHierarchyLayout           = "{{.CategorySlug}}/{{.SectionSlug}}/{{.ArticleSlug}}"
+	defaultFilenameTemplate          = "{{.ArticleID}}-{{.Locale}}.md"
+	defaultArticleFilenameTemplate   = "{{.ArticleID}}.md"
+	hierarchyFilenameTemplate        = "{{.Locale}}.md"
+	hierarchyArticleFilenameTemplate = "article.md"
+)
+
+type layoutData struct {
+	ArticleID    int
+	ArticleSlug  string
+	Title        string
+	Locale       string
+	SectionID    int
+	SectionSlug  string
+	CategoryID   int
+	CategorySlug string
+}
+
+func newLayoutData(a *zendesk.Article, locale string) layoutData {
+	return layoutData{
+		ArticleID:   a.ID,
+		ArticleSlug: slugify(a.Title),
+		Title:       pathElement(a.Title),
+		Locale:      locale,
+		SectionID:   a.SectionID,
+	}
+}
+
+func parseLayout(name string, text string) (*template.Template, error) {
+	return template.New(name).Funcs(template.FuncMap{"slug": slugify}).Option("missingkey=error").Parse(text)
+}
+
+func renderLayout(tmpl *template.Template, data layoutData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return filepath.FromSlash(buf.String()), nil
+}
+
+// layoutPath joins the path rendered by a layout to the root, failing if it is outside of the root.
+func layoutPath(root, rendered string) (string, error) {
+	path := filepath.Join(root, rendered)
+	if !isUnder(root, path) {
+		return "", fmt.Errorf("%s rendered by the layout is outside of %s", rendered, root)
+	}
+	return path, nil
+}
+
+// pathElement replaces the path separators in a value of the layouts, so that it renders a single path element.
+func pathElement(s string) string {
+	return strings.NewReplacer("/", "-", "\\", "-").Replace(s)
+}
+
+// fileNamer renders the file names of pulled translations and articles.
+type fileNamer struct {
+	translation *template.Template
+	article     *template.Template
+}
+
+func newFileNamer(translation, article string, hierarchy bool) (*fileNamer, error) {
+	if translation == "" {
+		translation = defaultFilenameTemplate
+		if hierarchy {
+			translation = hierarchyFilenameTemplate
+		}
+	}
+	if article == "" {
+		article = defaultArticleFilenameTemplate
+		if hierarchy {
+			article = hierarchyArticleFilenameTemplate
+		}
+	}
+	tt, err := parseLayout("filename_template", translation)
+	if err != nil {
+		return nil, err
+	}
+	at, err := parseLayout("article_filename_template", article)
+	if err != nil {
+		return nil, err
+	}
+	return &fileNamer{translation: tt, article: at}, nil
+}
+
+func (n *fileNamer) Translation(data layoutData) (string, error) {
+	return renderLayout(n.translation, data)
+}
+
+func (n *fileNamer) Article(data layoutData) (string, error) {
+	return renderLayout(n.article, data)
+}
+
+// hierarchyResolver resolves the directory mirroring the category/section hierarchy of an article.
+type hierarchyResolver struct {
+	client     zendesk.Client
+	locale     string
+	tmpl       *template.Template
+	sections   map[int]*zendesk.Section
+	categories map[int]*zendesk.Category
+}
+
+func newHierarchyResolver(client zendesk.Client, locale string, layout string) (*hierarchyResolver, error) {
+	if layout == "" {
+		layout = defaultHierarchyLayout
+	}
+	tmpl, err := parseLayout("hierarchy_layout", layout)
+	if err != nil {
+		return nil, err
+	}
+	return &hierarchyResolver{
+		client:     client,
+		locale:     locale,
+		tmpl:       tmpl,
+		sections:   map[int]*zendesk.Section{},
+		categories: map[int]*zendesk.Category{},
+	}, nil
+}
+
+// Dir fills the section and category of the data and renders the directory.
+func (r *hierarchyResolver) Dir(data *layoutData) (string, error) {
+	s, err := r.section(data.SectionID)
+	if err != nil {
+		return "", err
+	}
+	c, err := r.category(s.CategoryID)
+	if err != nil {
+		return "", err
+	}
+
+	data.SectionSlug = slugify(s.Name)
+	data.CategoryID = c.ID
+	data.CategorySlug = slugify(c.Name)
+	return renderLayout(r.tmpl, *data)
+}
+
+func (r *hierarchyResolver) section(sectionID int) (*zendesk.Section, error) {
+	if s, ok := r.sections[sectionID]; ok {
+		return s, nil
+	}
+	res, err := r.client.ShowSection(r.locale, sectionID)
+	if err != nil {
+		return nil, err
+	}
+	s := &zendesk.Section{}
+	if err := s.FromJson(res); err != nil {
+		return nil, err
+	}
+	r.sections[sectionID] = s
+	return s, nil
+}
+
+func (r *hierarchyResolver) category(categoryID int) (*zendesk.Category, error) {
+	if c, ok := r.categories[categoryID]; ok {
+		return c, nil
+	}
+	res, err := r.client.ShowCategory(r.locale, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	c := &zendesk.Category{}
+	if err := c.FromJson(res); err != nil {
+		return nil, err
+	}
+	r.categories[categoryID] = c
+	return c, nil
+}
+
+// slugify converts a name into a lowercase, hyphen-separated path element, keeping non-ASCII letters.
+func slugify(s string) string {
+	var sb strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+			hyphen = false
+			continue
+		}
+		if !hyphen && sb.Len() > 0 {
+			sb.WriteRune('-')
+			hyphen = true
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "-")
+}
@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"How to use zgsync", "how-to-use-zgsync"},
+		{"  FAQ: Billing & Payments!  ", "faq-billing-payments"},
+		{"zgsyncの使い方", "zgsyncの使い方"},
+		{"v1.2.3", "v1-2-3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := slugify(tt.name); actual != tt.expected {
+				t.Errorf("slugify() failed: got %v, want %v", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFileNamer(t *testing.T) {
+	data := layoutData{
+		ArticleID: 123,
+		Title:     "How to use zgsync",
+		Locale:    "en-us",
+		SectionID: 456,
+	}
+	tests := []struct {
+		name        string
+		translation string
+		article     string
+		hierarchy   bool
+		expectedT   string
+		expectedA   string
+	}{
+		{"default", "", "", false, "123-en-us.md", "123.md"},
+		{"hierarchy", "", "", true, "en-us.md", "article.md"},
+		{
+			"custom",
+			"{{.SectionID}}/{{.ArticleID}}-{{slug .Title}}.{{.Locale}}.md",
+			"{{.SectionID}}/{{.ArticleID}}-{{slug .Title}}.md",
+			false,
+			"456/123-how-to-use-zgsync.en-us.md",
+			"456/123-how-to-use-zgsync.md",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := newFileNamer(tt.translation, tt.article, tt.hierarchy)
+			if err != nil {
+				t.Fatalf("newFileNamer() failed: %v", err)
+			}
+			if actual, _ := n.Translation(data); actual != filepath.FromSlash(tt.expectedT) {
+				t.Errorf("fileNamer.Translation() failed: got %v, want %v", actual, tt.expectedT)
+			}
+			if actual, _ := n.Article(data); actual != filepath.FromSlash(tt.expectedA) {
+				t.Errorf("fileNamer.Article() failed: got %v, want %v", actual, tt.expectedA)
+			}
+		})
+	}
+}
+
+func TestLayoutPath(t *testing.T) {
+	root := filepath.Join("contents", "ja")
+	tests := []struct {
+		rendered string
+		expected string
+	}{
+		{"123-ja.md", filepath.Join(root, "123-ja.md")},
+		{filepath.Join("456", "123-ja.md"), filepath.Join(root, "456", "123-ja.md")},
+		{filepath.Join("..", "ja", "123-ja.md"), filepath.Join(root, "123-ja.md")},
+		{filepath.Join("..", "123-ja.md"), ""},
+		{filepath.Join("456", "..", "..", "..", "etc", "passwd"), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rendered, func(t *testing.T) {
+			actual, err := layoutPath(root, tt.rendered)
+			if (err != nil) != (tt.expected == "") || actual != tt.expected {
+				t.Errorf("layoutPath() failed: got %q, %v, want %q", actual, err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLayoutTitle(t *testing.T) {
+	n, err := newFileNamer("{{.Title}}.{{.Locale}}.md", "", false)
+	if err != nil {
+		t.Fatalf("newFileNamer() failed: %v", err)
+	}
+	data := newLayoutData(&zendesk.Article{ID: 123, Title: `../../a/b\c`}, "ja")
+	name, err := n.Translation(data)
+	if err != nil {
+		t.Fatalf("fileNamer.Translation() failed: %v", err)
+	}
+	if name != `..-..-a-b-c.ja.md` {
+		t.Errorf("fileNamer.Translation() failed: got %q", name)
+	}
+	if _, err := layoutPath("contents", name); err != nil {
+		t.Errorf("layoutPath() failed: %v", err)
+	}
+}
+
+type hierarchyClient struct {
+	zendesk.Client
+}
+
+func (c *hierarchyClient) ShowSection(locale string, sectionID int) (string, error) {
+	return `{"section":{"id":10,"name":"../Getting Started","category_id":20}}`, nil
+}
+
+func (c *hierarchyClient) ShowCategory(locale string, categoryID int) (string, error) {
+	return `{"category":{"id":20,"name":"FAQ/..\\Billing"}}`, nil
+}
+
+func TestHierarchyResolver(t *testing.T) {
+	tests := []struct {
+		layout   string
+		expected string
+	}{
+		{"", "faq-billing/getting-started/how-to-use-zgsync"},
+		{"{{.CategoryID}}/{{.SectionID}}", "20/10"},
+		{"{{slug .Title}}", "how-to-use-zgsync"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.layout, func(t *testing.T) {
+			r, err := newHierarchyResolver(&hierarchyClient{}, "ja", tt.layout)
+			if err != nil {
+				t.Fatalf("newHierarchyResolver() failed: %v", err)
+			}
+			data := newLayoutData(&zendesk.Article{ID: 123, Title: "How to use zgsync", SectionID: 10}, "ja")
+			actual, err := r.Dir(&data)
+			if err != nil {
+				t.Fatalf("hierarchyResolver.Dir() failed: %v", err)
+			}
+			if actual != filepath.FromSlash(tt.expected) {
+				t.Errorf("hierarchyResolver.Dir() failed: got %v, want %v", actual, tt.expected)
+			}
+		})
+	}
+
+	// the names of the sections and the categories are given only as the slugs.
+	r, err := newHierarchyResolver(&hierarchyClient{}, "ja", "{{.SectionName}}")
+	if err != nil {
+		t.Fatalf("newHierarchyResolver() failed: %v", err)
+	}
+	data := newLayoutData(&zendesk.Article{ID: 123, SectionID: 10}, "ja")
+	if _, err := r.Dir(&data); err == nil {
+		t.Errorf("hierarchyResolver.Dir() failed: got no error for .SectionName")
+	}
+}
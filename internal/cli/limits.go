@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	defaultMaxBodyKB = 512
+	defaultMaxImages = 100
+)
+
+// exceededLimits returns the limits which the converted body exceeds. A negative limit disables the check.
+func (c *Config) exceededLimits(body string) []string {
+	maxBodyKB, maxImages := c.Limits.MaxBodyKB, c.Limits.MaxImages
+	if maxBodyKB == 0 {
+		maxBodyKB = defaultMaxBodyKB
+	}
+	if maxImages == 0 {
+		maxImages = defaultMaxImages
+	}
+	var exceeded []string
+	if size := len(body); maxBodyKB > 0 && size > maxBodyKB<<10 {
+		exceeded = append(exceeded, fmt.Sprintf("the body is %d KB, which exceeds max_body_kb %d", (size+1<<10-1)>>10, maxBodyKB))
+	}
+	if n := countImages(body); maxImages > 0 && n > maxImages {
+		exceeded = append(exceeded, fmt.Sprintf("the body has %d images, which exceeds max_images %d", n, maxImages))
+	}
+	return exceeded
+}
+
+// checkLimits logs the exceeded limits as warnings, or returns them as the error with fail.
+func (c *Config) checkLimits(file, body string) error {
+	exceeded := c.exceededLimits(body)
+	if c.Limits.Fail {
+		var errs []error
+		for _, e := range exceeded {
+			errs = append(errs, errors.New(e))
+		}
+		return errors.Join(errs...)
+	}
+	for _, e := range exceeded {
+		slog.Warn("limit exceeded", "file", file, "limit", e)
+	}
+	return nil
+}
+
+// countImages returns the number of the img tags in the HTML.
+func countImages(body string) int {
+	n := 0
+	z := html.NewTokenizer(strings.NewReader(body))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return n
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if name, _ := z.TagName(); string(name) == "img" {
+				n++
+			}
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExceededLimits(t *testing.T) {
+	images := strings.Repeat(`<p><img src="a.png"></p>`, 3)
+	tests := []struct {
+		name     string
+		limits   Limits
+		body     string
+		expected []string
+	}{
+		{"within the defaults", Limits{}, images, nil},
+		{"body size", Limits{MaxBodyKB: 1}, strings.Repeat("a", 1025), []string{"the body is 2 KB, which exceeds max_body_kb 1"}},
+		{"images", Limits{MaxImages: 2}, images, []string{"the body has 3 images, which exceeds max_images 2"}},
+		{"default body size", Limits{}, strings.Repeat("a", 600<<10), []string{"the body is 600 KB, which exceeds max_body_kb 512"}},
+		{"disabled", Limits{MaxBodyKB: -1, MaxImages: -1}, strings.Repeat(`<img src="a.png"/>`, 200<<10), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Config{Limits: tt.limits}
+			if actual := c.exceededLimits(tt.body); !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("exceededLimits() failed: got %q, want %q", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCheckLimits(t *testing.T) {
+	c := &Config{Limits: Limits{MaxBodyKB: 1, MaxImages: 1}}
+	body := `<img src="a.png"><img src="b.png">` + strings.Repeat("a", 2048)
+	if err := c.checkLimits("1-ja.md", body); err != nil {
+		t.Errorf("checkLimits() failed: %v", err)
+	}
+	c.Limits.Fail = true
+	err := c.checkLimits("1-ja.md", body)
+	if err == nil || err.Error() != "the body is 3 KB, which exceeds max_body_kb 1\nthe body has 2 images, which exceeds max_images 1" {
+		t.Errorf("checkLimits() failed: unexpected error %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+// articleLinkPattern matches the links to the articles such as https://example.zendesk.com/hc/en-us/articles/123.
+var articleLinkPattern = regexp.MustCompile(`https://([a-z0-9-]+)\.zendesk\.com/hc/(?:([a-z]{2,3}(?:-[a-z0-9]+)*)/)?articles/(\d+)`)
+
+// checkLinks warns of the links to the articles of the configured help centers which are deleted or in draft.
+func (c *CommandPush) checkLinks(g *Global, file, body string) error {
+	brands := map[string]string{g.Config.Subdomain: ""}
+	for brand, subdomain := range g.Config.Brands {
+		if subdomain != g.Config.Subdomain {
+			brands[subdomain] = brand
+		}
+	}
+	for _, m := range articleLinkPattern.FindAllStringSubmatch(body, -1) {
+		brand, ok := brands[m[1]]
+		if !ok {
+			continue
+		}
+		id, err := strconv.Atoi(m[3])
+		if err != nil {
+			continue
+		}
+		problem, err := c.linkProblem(g, brand, id, m[2])
+		if err != nil {
+			return err
+		}
+		if problem != "" {
+			slog.Warn("broken link", "file", file, "link", m[0], "problem", problem)
+		}
+	}
+	return nil
+}
+
+// linkProblem returns why the link to the article is broken, or an empty string.
+func (c *CommandPush) linkProblem(g *Global, brand string, id int, locale string) (string, error) {
+	key := fmt.Sprintf("%s/%d/%s", brand, id, locale)
+	if problem, ok := c.links[key]; ok {
+		return problem, nil
+	}
+	client, err := c.clientFor(g, brand)
+	if err != nil {
+		return "", err
+	}
+
+	var res string
+	var draft bool
+	if locale == "" {
+		if res, err = client.ShowArticle("", id); err == nil {
+			a := &zendesk.Article{}
+			if err := a.FromJson(res); err != nil {
+				return "", err
+			}
+			draft = a.Draft
+		}
+	} else {
+		if res, err = client.ShowTranslation(id, locale); err == nil {
+			t := &zendesk.Translation{}
+			if err := t.FromJson(res); err != nil {
+				return "", err
+			}
+			draft = t.Draft
+		}
+	}
+	var problem string
+	switch {
+	case zendesk.IsNotFound(err) && locale == "":
+		problem = fmt.Sprintf("article %d does not exist", id)
+	case zendesk.IsNotFound(err):
+		problem = fmt.Sprintf("article %d does not exist in %s", id, locale)
+	case err != nil:
+		return "", err
+	case draft:
+		problem = fmt.Sprintf("article %d is a draft", id)
+	}
+	if c.links == nil {
+		c.links = map[string]string{}
+	}
+	c.links[key] = problem
+	return problem, nil
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+// linkClient has the published article 1, the draft article 2, and the article 3 whose en-us translation is a draft
+// and which has no fr translation. The others do not exist.
+type linkClient struct {
+	zendesk.Client
+	shown []string
+}
+
+func (c *linkClient) ShowArticle(locale string, articleID int) (string, error) {
+	c.shown = append(c.shown, fmt.Sprintf("article/%d", articleID))
+	if articleID > 3 {
+		return "", &zendesk.StatusError{StatusCode: 404}
+	}
+	return fmt.Sprintf(`{"article":{"id":%d,"draft":%t}}`, articleID, articleID == 2), nil
+}
+
+func (c *linkClient) ShowTranslation(articleID int, locale string) (string, error) {
+	c.shown = append(c.shown, fmt.Sprintf("translation/%d/%s", articleID, locale))
+	if articleID > 3 || locale == "fr" {
+		return "", &zendesk.StatusError{StatusCode: 404}
+	}
+	return fmt.Sprintf(`{"translation":{"source_id":%d,"locale":%q,"draft":%t}}`, articleID, locale, articleID == 2 || locale == "en-us"), nil
+}
+
+func TestLinkProblem(t *testing.T) {
+	tests := []struct {
+		id       int
+		locale   string
+		expected string
+	}{
+		{1, "", ""},
+		{1, "ja", ""},
+		{2, "", "article 2 is a draft"},
+		{3, "en-us", "article 3 is a draft"},
+		{3, "fr", "article 3 does not exist in fr"},
+		{4, "", "article 4 does not exist"},
+	}
+	g := &Global{Config: Config{Subdomain: "example"}}
+	c := &CommandPush{client: &linkClient{}, clients: map[string]zendesk.Client{}}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d/%s", tt.id, tt.locale), func(t *testing.T) {
+			actual, err := c.linkProblem(g, "", tt.id, tt.locale)
+			if err != nil {
+				t.Fatalf("linkProblem() failed: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("linkProblem() failed: got %q, want %q", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCheckLinks(t *testing.T) {
+	g := &Global{Config: Config{Subdomain: "example", Brands: map[string]string{"example": "example", "sub": "sub-example"}}}
+	client, sub := &linkClient{}, &linkClient{}
+	c := &CommandPush{client: client, clients: map[string]zendesk.Client{"sub": sub}}
+	body := `<a href="https://example.zendesk.com/hc/ja/articles/4-Removed">a</a>
+<a href="https://example.zendesk.com/hc/ja/articles/4-Removed#section">b</a>
+<a href="https://example.zendesk.com/hc/articles/2">c</a>
+<a href="https://sub-example.zendesk.com/hc/en-us/articles/3-Draft">d</a>
+<a href="https://other.zendesk.com/hc/en-us/articles/4">e</a>`
+	if err := c.checkLinks(g, "1-ja.md", body); err != nil {
+		t.Fatalf("checkLinks() failed: %v", err)
+	}
+	if fmt.Sprint(client.shown) != "[translation/4/ja article/2]" {
+		t.Errorf("checkLinks() failed: got %v fetched", client.shown)
+	}
+	if fmt.Sprint(sub.shown) != "[translation/3/en-us]" {
+		t.Errorf("checkLinks() failed: got %v fetched from the brand", sub.shown)
+	}
+	if c.links["/4/ja"] != "article 4 does not exist in ja" || c.links["sub/3/en-us"] != "article 3 is a draft" {
+		t.Errorf("checkLinks() failed: got %v", c.links)
+	}
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/tukaelu/zgsync/internal/zendesk/httplog"
+)
+
+// levelQuiet is the level writing the summaries and the warnings only, without the logs of each file.
+const levelQuiet = "quiet"
+
+// newLogger returns the logger writing the logs at or above the level in the format with the command.
+func newLogger(w io.Writer, level, format, command string) *slog.Logger {
+	var l slog.Level
+	switch level {
+	case "trace":
+		l = httplog.LevelTrace
+	case "debug":
+		l = slog.LevelDebug
+	case "warn":
+		l = slog.LevelWarn
+	case "error":
+		l = slog.LevelError
+	default:
+		l = slog.LevelInfo
+	}
+	opts := &slog.HandlerOptions{Level: l, ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+		if a.Key == slog.LevelKey && a.Value.Any() == httplog.LevelTrace {
+			a.Value = slog.StringValue("TRACE")
+		}
+		return a
+	}}
+
+	var h slog.Handler
+	switch format {
+	case "json":
+		h = slog.NewJSONHandler(w, opts)
+	case formatColor:
+		h = newColorHandler(w, opts)
+	default:
+		h = slog.NewTextHandler(w, opts)
+	}
+	if level == levelQuiet {
+		h = &quietHandler{h}
+	}
+	logger := slog.New(h)
+	if command != "" {
+		// the arguments of the command such as the files are not included.
+		var words []string
+		for _, word := range strings.Fields(command) {
+			if strings.HasPrefix(word, "<") {
+				break
+			}
+			words = append(words, word)
+		}
+		logger = logger.With("command", strings.Join(words, " "))
+	}
+	return logger
+}
+
+// quietHandler drops the informational logs except the summaries.
+type quietHandler struct {
+	slog.Handler
+}
+
+func (h *quietHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < slog.LevelWarn && r.Message != "summary" {
+		return nil
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *quietHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &quietHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h *quietHandler) WithGroup(name string) slog.Handler {
+	return &quietHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/tukaelu/zgsync/internal/zendesk/httplog"
+)
+
+func TestNewLogger(t *testing.T) {
+	tests := []struct {
+		name    string
+		level   string
+		format  string
+		command string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "text",
+			level:   "info",
+			format:  "text",
+			command: "push <files>",
+			want:    []string{"level=INFO msg=created command=push file=a.md article_id=1", "level=WARN msg=warned"},
+			notWant: []string{"msg=debugged", "msg=traced"},
+		},
+		{
+			name:    "debug",
+			level:   "debug",
+			format:  "text",
+			command: "pull <article-ids>",
+			want:    []string{"msg=debugged command=pull"},
+		},
+		{
+			name:    "error",
+			level:   "error",
+			format:  "text",
+			command: "push <files>",
+			notWant: []string{"msg=created", "msg=warned"},
+		},
+		{
+			name:    "trace",
+			level:   "trace",
+			format:  "text",
+			command: "pull <article-ids>",
+			want:    []string{"level=TRACE msg=traced", "msg=debugged"},
+		},
+		{
+			name:    "quiet",
+			level:   levelQuiet,
+			format:  "text",
+			command: "push <files>",
+			want:    []string{"msg=summary command=push files=1", "msg=warned"},
+			notWant: []string{"msg=created", "msg=debugged"},
+		},
+		{
+			name:    "subcommand",
+			level:   "info",
+			format:  "text",
+			command: "config show",
+			want:    []string{`command="config show"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			logger := newLogger(&b, tt.level, tt.format, tt.command)
+			logger.Log(context.Background(), httplog.LevelTrace, "traced")
+			logger.Debug("debugged")
+			logger.Info("created", "file", "a.md", "article_id", 1)
+			logger.Info("summary", "files", 1)
+			logger.Warn("warned")
+			for _, s := range tt.want {
+				if !strings.Contains(b.String(), s) {
+					t.Errorf("%q is not logged in %q", s, b.String())
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(b.String(), s) {
+					t.Errorf("%q is logged in %q", s, b.String())
+				}
+			}
+		})
+	}
+}
+
+func TestNewLoggerJSON(t *testing.T) {
+	var b bytes.Buffer
+	newLogger(&b, "info", "json", "push <files>").Info("created", "file", "a.md", "article_id", 1)
+
+	var got map[string]any
+	if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{"level": "INFO", "msg": "created", "command": "push", "file": "a.md", "article_id": float64(1)}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %v, want %v", k, got[k], v)
+		}
+	}
+}
+
+func TestNewLoggerColor(t *testing.T) {
+	var b bytes.Buffer
+	logger := newLogger(&b, "info", formatColor, "push <files>")
+	logger.Info("created", "file", "a b.md", "article_id", 1)
+	logger.Info("up to date", "file", "c.md")
+	logger.Info("listening")
+	logger.Warn("retrying")
+	logger.Error("failed", "error", errors.New("unexpected status code: 422"))
+
+	want := []string{
+		"\x1b[32m+\x1b[0m created \x1b[2mfile=\x1b[0m\"a b.md\" \x1b[2marticle_id=\x1b[0m1\n",
+		"\x1b[2m=\x1b[0m up to date \x1b[2mfile=\x1b[0mc.md\n",
+		"\x1b[36m*\x1b[0m listening\n",
+		"\x1b[33m!\x1b[0m retrying\n",
+		"\x1b[31mx\x1b[0m failed \x1b[2merror=\x1b[0m\"unexpected status code: 422\"\n",
+	}
+	if got := b.String(); got != strings.Join(want, "") {
+		t.Errorf("newLogger() failed: got %q", got)
+	}
+}
+
+func TestUseColor(t *testing.T) {
+	var b bytes.Buffer
+	if useColor(&b, false) {
+		t.Error("useColor() should be false for a buffer")
+	}
+	f, err := os.CreateTemp(t.TempDir(), "log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if useColor(f, false) {
+		t.Error("useColor() should be false for a file")
+	}
+}
+
+func TestLogLevel(t *testing.T) {
+	tests := []struct {
+		g        Global
+		expected string
+	}{
+		{Global{LogLevel: "warn"}, "warn"},
+		{Global{LogLevel: "warn", Quiet: true}, levelQuiet},
+		{Global{LogLevel: "info", Verbose: 1}, "debug"},
+		{Global{LogLevel: "info", Verbose: 2}, "trace"},
+	}
+	for _, tt := range tests {
+		if got := tt.g.logLevel(); got != tt.expected {
+			t.Errorf("logLevel() failed: got %s, want %s", got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// sectionForPath returns the section ID mapped to the longest slash-separated path prefix of rel.
+func sectionForPath(mapping map[string]int, rel string) (int, bool) {
+	rel = filepath.ToSlash(rel)
+	best := -1
+	sectionID := 0
+	for prefix, id := range mapping {
+		p := strings.Trim(filepath.ToSlash(prefix), "/")
+		if p != "" && p != "." && rel != p && !strings.HasPrefix(rel, p+"/") {
+			continue
+		}
+		if p == "." {
+			p = ""
+		}
+		if len(p) > best {
+			best = len(p)
+			sectionID = id
+		}
+	}
+	return sectionID, best >= 0
+}
+
+// sectionForFile returns the section ID mapped by the sections config to the file.
+func (c *Config) sectionForFile(file string) (int, bool) {
+	if len(c.Sections) == 0 {
+		return 0, false
+	}
+	root, err := filepath.Abs(c.ContentsDir)
+	if err != nil {
+		return 0, false
+	}
+	abs, err := filepath.Abs(file)
+	if err != nil || !isUnder(root, abs) {
+		return 0, false
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil {
+		return 0, false
+	}
+	return sectionForPath(c.Sections, rel)
+}
@@ -0,0 +1,64 @@
+package cli
+
+import "testing"
+
+func TestSectionForPath(t *testing.T) {
+	mapping := map[string]int{
+		".":                1,
+		"guides":           10,
+		"guides/advanced/": 20,
+		"faq":              30,
+	}
+	tests := []struct {
+		rel      string
+		expected int
+	}{
+		{"top.md", 1},
+		{"guides/intro.md", 10},
+		{"guides/advanced/tuning.md", 20},
+		{"guides-old/intro.md", 1},
+		{"faq", 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rel, func(t *testing.T) {
+			actual, ok := sectionForPath(mapping, tt.rel)
+			if !ok || actual != tt.expected {
+				t.Errorf("sectionForPath() failed: got %v, want %v", actual, tt.expected)
+			}
+		})
+	}
+
+	if _, ok := sectionForPath(map[string]int{"faq": 30}, "guides/intro.md"); ok {
+		t.Errorf("sectionForPath() failed: unmapped path should not match")
+	}
+}
+
+func TestSectionForFile(t *testing.T) {
+	c := &Config{
+		ContentsDir: "testdata/contents",
+		Sections: map[string]int{
+			"guides":          10,
+			"guides/advanced": 20,
+		},
+	}
+	tests := []struct {
+		file     string
+		expected int
+		ok       bool
+	}{
+		{"testdata/contents/guides/intro.md", 10, true},
+		{"testdata/contents/guides/advanced/tuning.md", 20, true},
+		{"testdata/contents/faq/top.md", 0, false},
+		{"testdata/other/guides/intro.md", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			actual, ok := c.sectionForFile(tt.file)
+			if ok != tt.ok || actual != tt.expected {
+				t.Errorf("sectionForFile() failed: got %v, %v, want %v, %v", actual, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
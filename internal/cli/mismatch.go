@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/tukaelu/zgsync/internal/state"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+const (
+	mismatchAsk    = "ask"
+	mismatchRepair = "repair"
+	mismatchPush   = "push"
+	mismatchSkip   = "skip"
+)
+
+// remoteSection returns the remote section of the article, fetched once a run.
+func (c *CommandPush) remoteSection(client zendesk.Client, brand string, articleID int) (int, error) {
+	key := fmt.Sprintf("%s/%d", brand, articleID)
+	if id, ok := c.sections[key]; ok {
+		return id, nil
+	}
+	// the article moved remotely is not missed by the cached lookup.
+	res, err := zendesk.Uncached(client).ShowArticle("", articleID)
+	if zendesk.IsNotFound(err) {
+		return 0, fmt.Errorf("article %d does not exist remotely, as it may have been deleted: remove the ID from the front matter to create it again", articleID)
+	}
+	if err != nil {
+		return 0, err
+	}
+	remote := &zendesk.Article{}
+	if err := remote.FromJson(res); err != nil {
+		return 0, err
+	}
+	if c.sections == nil {
+		c.sections = map[string]int{}
+	}
+	c.sections[key] = remote.SectionID
+	return remote.SectionID, nil
+}
+
+// movedRemotely reports whether the article has been moved remotely to another section than section_id.
+func movedRemotely(s *state.Store, file string, local, remote int) bool {
+	if local == remote {
+		return false
+	}
+	e, ok := s.Lookup(file)
+	return !ok || e.SectionID != remote
+}
+
+// resolveMismatch resolves the section_id moved remotely by --on-mismatch and reports whether the file is pushed.
+func (c *CommandPush) resolveMismatch(g *Global, file string, article bool, local, remote int) (int, bool, error) {
+	resolution := c.OnMismatch
+	if resolution == mismatchAsk && !c.interactive {
+		resolution = mismatchSkip
+	}
+	for resolution == mismatchAsk {
+		answer, err := prompt(fmt.Sprintf("%s has section_id %d, but the article is in section %d remotely. [r]epair the front matter, [p]ush anyway, [s]kip? ", file, local, remote))
+		if err != nil {
+			return 0, false, err
+		}
+		switch strings.ToLower(answer) {
+		case "r", "repair":
+			resolution = mismatchRepair
+		case "p", "push":
+			resolution = mismatchPush
+		case "s", "skip":
+			resolution = mismatchSkip
+		}
+	}
+
+	switch resolution {
+	case mismatchRepair:
+		return remote, true, c.repairSection(g, file, article, remote)
+	case mismatchPush:
+		return local, true, nil
+	default:
+		slog.Warn("skipped as the article has been moved remotely", "file", file, "section_id", local, "remote_section_id", remote)
+		return 0, false, nil
+	}
+}
+
+// repairSection replaces section_id of the front matter of the file with the section of the article remotely.
+func (c *CommandPush) repairSection(g *Global, file string, article bool, sectionID int) error {
+	if g.Config.isHugo() {
+		return fmt.Errorf("repairing the front matter is not supported with the front matter format %s", frontMatterHugo)
+	}
+	articleTmpl, translationTmpl, err := g.Config.frontMatterTemplates()
+	if err != nil {
+		return err
+	}
+	if article {
+		err = repairArticleSection(g, file, sectionID, articleTmpl)
+	} else {
+		err = repairTranslationSection(g, file, sectionID, translationTmpl)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to repair the front matter: %w", err)
+	}
+	slog.Info("repaired section_id", "file", file, "section_id", sectionID)
+	return nil
+}
+
+func repairArticleSection(g *Global, file string, sectionID int, tmpl *zendesk.FrontMatterTemplate) error {
+	a, err := g.Config.readArticle(file)
+	if err != nil {
+		return err
+	}
+	a.SectionID = sectionID
+	return a.SaveWithTemplate(file, false, tmpl)
+}
+
+func repairTranslationSection(g *Global, file string, sectionID int, tmpl *zendesk.FrontMatterTemplate) error {
+	t, err := g.Config.readTranslation(file)
+	if err != nil {
+		return err
+	}
+	t.SectionID = sectionID
+	return t.SaveWithTemplate(file, false, tmpl)
+}
@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tukaelu/zgsync/internal/converter"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+// mismatchClient has the article 1 moved to the section 20 remotely, and no article 2 as it has been deleted.
+type mismatchClient struct {
+	pushClient
+	articles []string
+}
+
+func (c *mismatchClient) ShowArticle(locale string, articleID int) (string, error) {
+	if articleID != 1 {
+		return "", &zendesk.StatusError{StatusCode: 404}
+	}
+	return `{"article":{"id":1,"section_id":20}}`, nil
+}
+
+func (c *mismatchClient) ShowSection(locale string, sectionID int) (string, error) {
+	return fmt.Sprintf(`{"section":{"id":%d}}`, sectionID), nil
+}
+
+func (c *mismatchClient) UpdateArticle(locale string, articleID int, payload string) (string, error) {
+	c.articles = append(c.articles, payload)
+	return `{"article":{"id":1,"section_id":20}}`, nil
+}
+
+func TestPushMismatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		onMismatch  string
+		interactive bool
+		input       string
+		id          int
+		tracked     int
+		pushed      string
+		expected    string
+		err         string
+	}{
+		{"repair", mismatchRepair, false, "", 1, 0, `"section_id":20`, "section_id: 20", ""},
+		{"push", mismatchPush, false, "", 1, 0, `"section_id":10`, "section_id: 10", ""},
+		{"skip", mismatchSkip, false, "", 1, 0, "", "section_id: 10", ""},
+		{"ask without a terminal", mismatchAsk, false, "", 1, 0, "", "section_id: 10", ""},
+		{"ask repair", mismatchAsk, true, "x\nr\n", 1, 0, `"section_id":20`, "section_id: 20", ""},
+		{"moved locally", mismatchSkip, false, "", 1, 20, `"section_id":10`, "section_id: 10", ""},
+		{"deleted", mismatchPush, false, "", 2, 0, "", "section_id: 10", "article 2 does not exist remotely, as it may have been deleted: remove the ID from the front matter to create it again"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			file := filepath.Join(dir, "article.md")
+			if err := os.WriteFile(file, []byte(fmt.Sprintf("---\nid: %d\ntitle: t\nlocale: ja\nsection_id: 10\n---\n", tt.id)), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja"}}
+			if tt.tracked != 0 {
+				s, err := g.LoadState()
+				if err != nil {
+					t.Fatal(err)
+				}
+				s.Get(file).SectionID = tt.tracked
+				if err := s.Save(); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			stdin = bufio.NewReader(strings.NewReader(tt.input))
+			defer func() { stdin = bufio.NewReader(os.Stdin) }()
+			client := &mismatchClient{}
+			c := &CommandPush{Article: true, OnMismatch: tt.onMismatch, interactive: tt.interactive, Files: []string{file}, client: client, clients: map[string]zendesk.Client{}, converter: converter.NewConverter()}
+			var got string
+			if err := c.Run(g); err != nil {
+				got = err.Error()
+			}
+			if !strings.Contains(got, tt.err) || (tt.err == "") != (got == "") {
+				t.Errorf("Run() failed: got error %q, want %q", got, tt.err)
+			}
+			if tt.pushed == "" && len(client.articles) > 0 {
+				t.Errorf("Run() failed: got %v pushed, want none", client.articles)
+			}
+			if tt.pushed != "" && (len(client.articles) != 1 || !strings.Contains(client.articles[0], tt.pushed)) {
+				t.Errorf("Run() failed: got %v pushed, want %s", client.articles, tt.pushed)
+			}
+			b, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(b), tt.expected) {
+				t.Errorf("Run() failed: %q is not in the file\n%s", tt.expected, b)
+			}
+		})
+	}
+}
+
+func TestPushTranslationMismatch(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "1-ja.md")
+	if err := os.WriteFile(file, []byte("---\ntitle: t\nlocale: ja\nsource_id: 1\nsection_id: 10\n---\nbody\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja"}}
+	client := &mismatchClient{}
+	c := &CommandPush{OnMismatch: mismatchRepair, Force: true, Files: []string{file}, client: client, clients: map[string]zendesk.Client{}, converter: converter.NewConverter()}
+	if err := c.Run(g); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if len(client.updated) != 1 {
+		t.Errorf("Run() failed: got %v updated, want [1]", client.updated)
+	}
+	b, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "section_id: 20") {
+		t.Errorf("Run() failed: section_id is not repaired\n%s", b)
+	}
+	if e, ok := c.state.Lookup(file); !ok || e.SectionID != 20 {
+		t.Errorf("Run() failed: got the tracked section %+v, want 20", e)
+	}
+}
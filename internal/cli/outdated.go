@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/tukaelu/zgsync/internal/state"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+const outdatedPayload = `{"translation":{"outdated":true}}`
+
+// flagOutdated marks the translations of the other locales as outdated, or warns of them.
+func (c *CommandPush) flagOutdated(client zendesk.Client, brand string, articleID int, locale string) error {
+	tracked, _ := trackedLocales(c.state, brand, articleID)
+	others := slices.DeleteFunc(tracked, func(l string) bool { return l == locale })
+	if len(others) == 0 && !c.MarkOutdated {
+		return nil
+	}
+	res, err := client.ShowArticle(locale, articleID)
+	if err != nil {
+		return err
+	}
+	a := &zendesk.Article{}
+	if err := a.FromJson(res); err != nil {
+		return err
+	}
+	if a.SourceLocale != locale {
+		return nil
+	}
+	if !c.MarkOutdated {
+		slog.Warn("the translations of the other locales may become outdated", "article_id", articleID, "locales", strings.Join(others, ","))
+		return nil
+	}
+
+	translations, err := listTranslations(client, articleID)
+	if err != nil {
+		return err
+	}
+	for _, t := range translations {
+		if t.Locale == locale || t.Outdated {
+			continue
+		}
+		res, err := client.UpdateTranslation(articleID, t.Locale, outdatedPayload)
+		if err != nil {
+			return err
+		}
+		remote := &zendesk.Translation{}
+		if err := remote.FromJson(res); err != nil {
+			return err
+		}
+		var path string
+		if key, _, ok := c.state.Find(state.KindTranslation, brand, articleID, t.Locale); ok {
+			path = c.state.Abs(key)
+		}
+		err = updateLocalTranslation(c.state, path, remote.UpdatedAt, func(t *zendesk.Translation) bool {
+			if t.Outdated {
+				return false
+			}
+			t.Outdated = true
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		slog.Info("marked as outdated", "article_id", articleID, "locale", t.Locale)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+// guideUser is the user of the token with the user segments which the user belongs to.
+type guideUser struct {
+	zendesk.User
+	segments []int
+}
+
+// permissions caches the user and the permission groups by the brand.
+type permissions struct {
+	users    map[string]*guideUser
+	groups   map[string]*zendesk.PermissionGroup
+	articles map[string]int
+}
+
+// user returns the user of the token of the brand, with the user segments of the agents.
+func (p *permissions) user(client zendesk.Client, brand string) (*guideUser, error) {
+	if u, ok := p.users[brand]; ok {
+		return u, nil
+	}
+	res, err := client.ShowCurrentUser()
+	if err != nil {
+		return nil, err
+	}
+	u := &guideUser{}
+	if err := u.FromJson(res); err != nil {
+		return nil, err
+	}
+	if u.Role == "agent" {
+		for page := 1; ; page++ {
+			res, err := client.ListUserSegmentsForUser(u.ID, page)
+			if err != nil {
+				return nil, err
+			}
+			ids, hasNext, err := zendesk.UserSegmentIDsFromJson(res)
+			if err != nil {
+				return nil, err
+			}
+			u.segments = append(u.segments, ids...)
+			if !hasNext {
+				break
+			}
+		}
+	}
+	if p.users == nil {
+		p.users = map[string]*guideUser{}
+	}
+	p.users[brand] = u
+	return u, nil
+}
+
+// group returns the permission group of the help center of the brand.
+func (p *permissions) group(client zendesk.Client, brand string, id int) (*zendesk.PermissionGroup, error) {
+	key := fmt.Sprintf("%s/%d", brand, id)
+	if g, ok := p.groups[key]; ok {
+		return g, nil
+	}
+	res, err := client.ShowPermissionGroup(id)
+	if err != nil {
+		return nil, err
+	}
+	g := &zendesk.PermissionGroup{}
+	if err := g.FromJson(res); err != nil {
+		return nil, err
+	}
+	if p.groups == nil {
+		p.groups = map[string]*zendesk.PermissionGroup{}
+	}
+	p.groups[key] = g
+	return g, nil
+}
+
+// articleGroup returns the ID of the permission group of the remote article.
+func (p *permissions) articleGroup(client zendesk.Client, brand string, articleID int) (int, error) {
+	key := fmt.Sprintf("%s/%d", brand, articleID)
+	if id, ok := p.articles[key]; ok {
+		return id, nil
+	}
+	res, err := client.ShowArticle("", articleID)
+	if err != nil {
+		return 0, err
+	}
+	a := &zendesk.Article{}
+	if err := a.FromJson(res); err != nil {
+		return 0, err
+	}
+	if p.articles == nil {
+		p.articles = map[string]int{}
+	}
+	p.articles[key] = a.PermissionGroupID
+	return a.PermissionGroupID, nil
+}
+
+// checkPermission verifies that the user can manage the articles of the permission group before writing.
+func (c *CommandPush) checkPermission(client zendesk.Client, brand string, permissionGroupID, articleID int) error {
+	if c.SkipPermissionCheck {
+		return nil
+	}
+	u, err := c.perms.user(client, brand)
+	if err != nil {
+		return err
+	}
+	switch u.Role {
+	case "admin":
+		return nil
+	case "agent":
+	default:
+		return fmt.Errorf("insufficient Guide permissions: %s is an end user, and only agents and admins can manage the articles", u.Email)
+	}
+
+	if permissionGroupID == 0 && articleID != 0 {
+		if permissionGroupID, err = c.perms.articleGroup(client, brand, articleID); err != nil {
+			return err
+		}
+	}
+	if permissionGroupID == 0 {
+		return nil
+	}
+	group, err := c.perms.group(client, brand, permissionGroupID)
+	if err != nil {
+		return err
+	}
+	for _, id := range u.segments {
+		if slices.Contains(group.Edit, id) || slices.Contains(group.Publish, id) {
+			return nil
+		}
+	}
+	return fmt.Errorf("insufficient Guide permissions: %s is not in the user segments that can edit or publish the articles of permission group %d (%s)", u.Email, group.ID, group.Name)
+}
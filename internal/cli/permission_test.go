@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+// permClient has the user of the role in the user segments 1 and 2, the permission group 10 which the user
+// segment 2 can edit, the permission group 20 which the user segment 3 can publish, and the articles whose
+// permission group is the ID times 10.
+type permClient struct {
+	zendesk.Client
+	role  string
+	shown []string
+}
+
+func (c *permClient) ShowCurrentUser() (string, error) {
+	c.shown = append(c.shown, "user")
+	return fmt.Sprintf(`{"user":{"id":7,"email":"user@example.com","role":%q}}`, c.role), nil
+}
+
+func (c *permClient) ListUserSegmentsForUser(userID int, page int) (string, error) {
+	c.shown = append(c.shown, fmt.Sprintf("user_segments/%d", page))
+	if page == 1 {
+		return `{"user_segments":[{"id":1}],"next_page":"https://example.zendesk.com/api/v2/help_center/users/7/user_segments?page=2"}`, nil
+	}
+	return `{"user_segments":[{"id":2}],"next_page":null}`, nil
+}
+
+func (c *permClient) ShowPermissionGroup(permissionGroupID int) (string, error) {
+	c.shown = append(c.shown, fmt.Sprintf("permission_group/%d", permissionGroupID))
+	switch permissionGroupID {
+	case 10:
+		return `{"permission_group":{"id":10,"name":"Agents","edit":[2],"publish":[]}}`, nil
+	case 20:
+		return `{"permission_group":{"id":20,"name":"Managers","edit":[],"publish":[3]}}`, nil
+	}
+	return "", &zendesk.StatusError{StatusCode: 404}
+}
+
+func (c *permClient) ShowArticle(locale string, articleID int) (string, error) {
+	c.shown = append(c.shown, fmt.Sprintf("article/%d", articleID))
+	return fmt.Sprintf(`{"article":{"id":%d,"permission_group_id":%d}}`, articleID, articleID*10), nil
+}
+
+func TestCheckPermission(t *testing.T) {
+	tests := []struct {
+		name              string
+		role              string
+		skip              bool
+		permissionGroupID int
+		articleID         int
+		expected          string
+		shown             string
+	}{
+		{"admin", "admin", false, 20, 0, "", "[user]"},
+		{"end user", "end-user", false, 10, 0, "insufficient Guide permissions: user@example.com is an end user, and only agents and admins can manage the articles", "[user]"},
+		{"agent can edit", "agent", false, 10, 0, "", "[user user_segments/1 user_segments/2 permission_group/10]"},
+		{"agent cannot edit", "agent", false, 20, 0, "insufficient Guide permissions: user@example.com is not in the user segments that can edit or publish the articles of permission group 20 (Managers)", "[user user_segments/1 user_segments/2 permission_group/20]"},
+		{"permission group of the article", "agent", false, 0, 2, "insufficient Guide permissions: user@example.com is not in the user segments that can edit or publish the articles of permission group 20 (Managers)", "[user user_segments/1 user_segments/2 article/2 permission_group/20]"},
+		{"new article without permission group", "agent", false, 0, 0, "", "[user user_segments/1 user_segments/2]"},
+		{"skipped", "end-user", true, 20, 0, "", "[]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &permClient{role: tt.role}
+			c := &CommandPush{SkipPermissionCheck: tt.skip}
+			var got string
+			if err := c.checkPermission(client, "", tt.permissionGroupID, tt.articleID); err != nil {
+				got = err.Error()
+			}
+			if got != tt.expected {
+				t.Errorf("checkPermission() failed: got %q, want %q", got, tt.expected)
+			}
+			// the permissions are fetched once a run.
+			c.checkPermission(client, "", tt.permissionGroupID, tt.articleID)
+			if fmt.Sprint(client.shown) != tt.shown {
+				t.Errorf("checkPermission() failed: got %v fetched, want %s", client.shown, tt.shown)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+// pickerLimit is the number of the matching sections listed by the picker.
+const pickerLimit = 20
+
+// sectionChoice is a section of the picker with the path of its category and parent sections.
+type sectionChoice struct {
+	ID   int
+	Path string
+}
+
+// fetchSectionTree lists the sections with their paths such as `Category > Parent > Section`.
+func fetchSectionTree(client zendesk.Client, locale string) ([]sectionChoice, error) {
+	var categories []zendesk.Category
+	for page := 1; ; page++ {
+		res, err := client.ListCategories(locale, page)
+		if err != nil {
+			return nil, err
+		}
+		cs, next, err := zendesk.CategoriesFromJson(res)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, cs...)
+		if !next {
+			break
+		}
+	}
+	var sections []zendesk.Section
+	for page := 1; ; page++ {
+		res, err := client.ListSections(locale, page)
+		if err != nil {
+			return nil, err
+		}
+		ss, next, err := zendesk.SectionsFromJson(res)
+		if err != nil {
+			return nil, err
+		}
+		sections = append(sections, ss...)
+		if !next {
+			break
+		}
+	}
+
+	categoryOrder := map[int]int{}
+	categoryNames := map[int]string{}
+	slices.SortStableFunc(categories, func(a, b zendesk.Category) int { return a.Position - b.Position })
+	for i, c := range categories {
+		categoryOrder[c.ID] = i
+		categoryNames[c.ID] = c.Name
+	}
+	slices.SortStableFunc(sections, func(a, b zendesk.Section) int { return a.Position - b.Position })
+	byID := map[int]zendesk.Section{}
+	sectionOrder := map[int]int{}
+	for i, s := range sections {
+		byID[s.ID] = s
+		sectionOrder[s.ID] = i
+	}
+
+	// path returns the names from the category to the section, and the positions of them to sort the tree.
+	var path func(s zendesk.Section, depth int) ([]string, []int)
+	path = func(s zendesk.Section, depth int) ([]string, []int) {
+		parent, ok := zendesk.Section{}, false
+		if s.ParentSectionID != nil {
+			parent, ok = byID[*s.ParentSectionID]
+		}
+		// the depth guards against a cycle of the parents.
+		if !ok || depth > len(sections) {
+			return []string{categoryNames[s.CategoryID], s.Name}, []int{categoryOrder[s.CategoryID], sectionOrder[s.ID]}
+		}
+		names, order := path(parent, depth+1)
+		return append(names, s.Name), append(order, sectionOrder[s.ID])
+	}
+
+	choices := make([]sectionChoice, 0, len(sections))
+	orders := map[int][]int{}
+	for _, s := range sections {
+		names, order := path(s, 0)
+		choices = append(choices, sectionChoice{ID: s.ID, Path: strings.Join(names, " > ")})
+		orders[s.ID] = order
+	}
+	slices.SortStableFunc(choices, func(a, b sectionChoice) int { return slices.Compare(orders[a.ID], orders[b.ID]) })
+	return choices, nil
+}
+
+// fuzzyScore reports whether the query matches the target fuzzily, with its score.
+func fuzzyScore(query, target string) (int, bool) {
+	q := []rune(strings.ToLower(strings.Join(strings.Fields(query), "")))
+	t := []rune(strings.ToLower(target))
+	score, qi := 0, 0
+	prev := -2
+	for ti := 0; ti < len(t) && qi < len(q); ti++ {
+		if t[ti] != q[qi] {
+			continue
+		}
+		score++
+		if ti == prev+1 {
+			score += 2
+		}
+		if ti == 0 || !unicode.IsLetter(t[ti-1]) && !unicode.IsDigit(t[ti-1]) {
+			score += 3
+		}
+		prev = ti
+		qi++
+	}
+	return score, qi == len(q)
+}
+
+// filterSections returns the sections matching the query fuzzily, the best matches first.
+func filterSections(choices []sectionChoice, query string) []sectionChoice {
+	type match struct {
+		choice sectionChoice
+		score  int
+	}
+	var matches []match
+	for _, c := range choices {
+		if score, ok := fuzzyScore(query, c.Path); ok {
+			matches = append(matches, match{c, score})
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b match) int { return b.score - a.score })
+	filtered := make([]sectionChoice, len(matches))
+	for i, m := range matches {
+		filtered[i] = m.choice
+	}
+	return filtered
+}
+
+// errNoSection is returned when no section is picked.
+var errNoSection = errors.New("no section is selected")
+
+// pickSection lets the user search the section tree and pick a section on the terminal.
+func pickSection(client zendesk.Client, locale string, optional bool) (int, error) {
+	choices, err := fetchSectionTree(client, locale)
+	if err != nil {
+		return 0, err
+	}
+	if len(choices) == 0 {
+		return 0, fmt.Errorf("no section exists in the help center")
+	}
+
+	query := ""
+	for {
+		matches := filterSections(choices, query)
+		for i, c := range matches[:min(len(matches), pickerLimit)] {
+			fmt.Fprintf(os.Stderr, "%3d) %s (%d)\n", i+1, c.Path, c.ID)
+		}
+		if len(matches) > pickerLimit {
+			fmt.Fprintf(os.Stderr, "     ... %d more\n", len(matches)-pickerLimit)
+		}
+		if len(matches) == 0 {
+			fmt.Fprintf(os.Stderr, "no section matches %q\n", query)
+		}
+
+		message := "Search a section, or pick it by the number: "
+		if optional {
+			message = "Search a section, or pick it by the number (empty for none): "
+		}
+		answer, err := prompt(message)
+		if err != nil {
+			return 0, errNoSection
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= min(len(matches), pickerLimit) {
+			return matches[n-1].ID, nil
+		}
+		switch {
+		case answer != "":
+			query = answer
+		case len(matches) == 1 && query != "":
+			return matches[0].ID, nil
+		case optional:
+			return 0, nil
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+// treeClient has the categories Guides and FAQ, whose sections are listed on two pages.
+type treeClient struct {
+	zendesk.Client
+}
+
+func (c *treeClient) ListCategories(locale string, page int) (string, error) {
+	return `{"categories":[{"id":2,"name":"FAQ","position":2},{"id":1,"name":"Guides","position":1}],"next_page":null}`, nil
+}
+
+func (c *treeClient) ListSections(locale string, page int) (string, error) {
+	if page == 1 {
+		return `{"sections":[{"id":20,"category_id":2,"name":"Billing","position":1},{"id":11,"category_id":1,"name":"Advanced","position":2,"parent_section_id":10}],"next_page":"https://example.zendesk.com/api/v2/help_center/sections?page=2"}`, nil
+	}
+	return `{"sections":[{"id":10,"category_id":1,"name":"Getting started","position":1}],"next_page":null}`, nil
+}
+
+func TestFetchSectionTree(t *testing.T) {
+	choices, err := fetchSectionTree(&treeClient{}, "ja")
+	if err != nil {
+		t.Fatalf("fetchSectionTree() failed: %v", err)
+	}
+	expected := "[{10 Guides > Getting started} {11 Guides > Getting started > Advanced} {20 FAQ > Billing}]"
+	if fmt.Sprint(choices) != expected {
+		t.Errorf("fetchSectionTree() failed: got %v, want %s", choices, expected)
+	}
+}
+
+func TestFilterSections(t *testing.T) {
+	choices := []sectionChoice{
+		{10, "Guides > Getting started"},
+		{11, "Guides > Getting started > Advanced"},
+		{20, "FAQ > Billing"},
+	}
+	tests := []struct {
+		query    string
+		expected []int
+	}{
+		{"", []int{10, 11, 20}},
+		{"adv", []int{11}},
+		{"gs", []int{10, 11}},
+		{"faq bill", []int{20}},
+		{"BILL", []int{20}},
+		{"xyz", nil},
+	}
+	for _, tt := range tests {
+		var got []int
+		for _, c := range filterSections(choices, tt.query) {
+			got = append(got, c.ID)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.expected) {
+			t.Errorf("filterSections(%q) failed: got %v, want %v", tt.query, got, tt.expected)
+		}
+	}
+}
+
+func TestPickSection(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		optional bool
+		expected int
+		err      bool
+	}{
+		{"by the number", "3\n", false, 20, false},
+		{"by the only match", "bill\n\n", false, 20, false},
+		{"by the number of the matches", "started\n2\n", false, 11, false},
+		{"none", "\n", true, 0, false},
+		{"required", "\n", false, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdin = bufio.NewReader(strings.NewReader(tt.input))
+			defer func() { stdin = bufio.NewReader(os.Stdin) }()
+			id, err := pickSection(&treeClient{}, "ja", tt.optional)
+			if (err != nil) != tt.err {
+				t.Fatalf("pickSection() failed: unexpected error %v", err)
+			}
+			if id != tt.expected {
+				t.Errorf("pickSection() failed: got %d, want %d", id, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEmptyWithoutSection(t *testing.T) {
+	g := &Global{Config: Config{ContentsDir: t.TempDir(), DefaultLocale: "ja"}}
+	c := &CommandEmpty{Title: "title", client: &treeClient{}}
+	if err := c.Run(g); err == nil || !strings.Contains(err.Error(), "--section-id is required") {
+		t.Errorf("Run() failed: unexpected error %v", err)
+	}
+}
@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+const (
+	// lowRatePercent is the remaining rate limit in percent below which the concurrency is halved.
+	lowRatePercent = 20
+	// highRatePercent is the remaining rate limit in percent above which the concurrency is increased again.
+	highRatePercent = 50
+	// latencyFactor is the ratio of the average latency to the baseline above which the concurrency is halved.
+	latencyFactor = 2
+	// maxRequeues is the number of times an operation rate limited is queued again before it fails.
+	maxRequeues = 5
+	// requeueWait is the wait before the operation rate limited is queued again without Retry-After.
+	requeueWait = time.Second
+)
+
+// ratePool runs the operations in parallel with the concurrency adjusted by AIMD.
+type ratePool struct {
+	client zendesk.Client
+	max    int
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	limit  int
+	active int
+	queue  []int
+	// waiting is the number of the rate limited IDs to be queued again after the wait.
+	waiting  int
+	requeued map[int]int
+	// acked is the number of the operations finished since the concurrency was last changed.
+	acked     int
+	decreased bool
+	// latency is the moving average of the latencies of the operations, and baseline is the lowest of it.
+	latency  time.Duration
+	baseline time.Duration
+}
+
+func newRatePool(client zendesk.Client, concurrency int) *ratePool {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	p := &ratePool{client: client, max: concurrency, limit: 1}
+	p.cond = sync.NewCond(&p.mu)
+	return p
+}
+
+// run calls op for the IDs, queuing the calls failed by the rate limit again.
+func (p *ratePool) run(ids []int, op func(id int) error) error {
+	var wg sync.WaitGroup
+	var first error
+	p.queue = append([]int{}, ids...)
+	p.requeued = map[int]int{}
+	for {
+		p.mu.Lock()
+		for first == nil && (p.active >= p.limit || len(p.queue) == 0) && (p.active > 0 || p.waiting > 0) {
+			p.cond.Wait()
+		}
+		if first != nil || len(p.queue) == 0 {
+			p.mu.Unlock()
+			break
+		}
+		id := p.queue[0]
+		p.queue = p.queue[1:]
+		p.active++
+		p.mu.Unlock()
+
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			start := time.Now()
+			err := op(id)
+			latency := time.Since(start)
+
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			p.active--
+			p.adjust(err, latency)
+			if zendesk.IsRateLimited(err) && p.requeued[id] < maxRequeues {
+				p.requeue(id, err)
+			} else if err != nil && first == nil {
+				first = err
+			}
+			p.cond.Broadcast()
+		}(id)
+	}
+	wg.Wait()
+	return first
+}
+
+// requeue queues the ID again after the wait of Retry-After of the error. It is called with the lock held.
+func (p *ratePool) requeue(id int, err error) {
+	p.requeued[id]++
+	p.waiting++
+	wait := requeueWait
+	var se *zendesk.StatusError
+	if errors.As(err, &se) && se.RetryAfter > 0 {
+		wait = se.RetryAfter
+	}
+	slog.Warn("queued again as rate limited", "id", id, "wait", wait)
+	go func() {
+		sleep(wait)
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.waiting--
+		p.queue = append(p.queue, id)
+		p.cond.Broadcast()
+	}()
+}
+
+// report halves the concurrency for a request retried by the rate limit within an operation.
+func (p *ratePool) report(err error) {
+	if !zendesk.IsRateLimited(err) {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.decrease("rate limited")
+}
+
+// adjust scales the concurrency by the result and the latency of the operation and the rate limit of the client.
+func (p *ratePool) adjust(err error, latency time.Duration) {
+	p.acked++
+	if zendesk.IsRateLimited(err) {
+		p.decrease("rate limited")
+		return
+	}
+	if err == nil && latency > 0 {
+		if p.latency == 0 {
+			p.latency = latency
+		} else {
+			p.latency = (p.latency*4 + latency) / 5
+		}
+		if p.baseline == 0 || p.latency < p.baseline {
+			p.baseline = p.latency
+		}
+	}
+
+	recovered := true
+	if rl, ok := p.client.(zendesk.RateLimited); ok {
+		if r, ok := rl.RateLimit(); ok && r.Limit > 0 {
+			if r.Remaining*100 < r.Limit*lowRatePercent {
+				p.decrease("rate limit is low", "rate_limit_remaining", r.Remaining)
+				return
+			}
+			recovered = r.Remaining*100 >= r.Limit*highRatePercent
+		}
+	}
+	if p.latency > latencyFactor*p.baseline {
+		p.decrease("latency is high", "latency", p.latency.Round(time.Millisecond), "baseline", p.baseline.Round(time.Millisecond))
+		return
+	}
+	if recovered && p.acked >= p.limit && p.limit < p.max {
+		p.scale(p.limit+1, "no congestion", "latency", p.latency.Round(time.Millisecond))
+	}
+}
+
+// decrease halves the concurrency. It is not halved again by the operations started before it was halved.
+func (p *ratePool) decrease(reason string, args ...any) {
+	if p.decreased && p.acked < p.limit {
+		return
+	}
+	p.decreased = true
+	p.scale(max(1, p.limit/2), reason, args...)
+}
+
+func (p *ratePool) scale(limit int, reason string, args ...any) {
+	if limit == p.limit {
+		return
+	}
+	args = append([]any{"concurrency", limit, "reason", reason}, args...)
+	if limit < p.limit {
+		slog.Info("scaling down", args...)
+	} else {
+		slog.Debug("scaling up", args...)
+	}
+	p.limit = limit
+	p.acked = 0
+}
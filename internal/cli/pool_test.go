@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+// rateClient reports the rate limit.
+type rateClient struct {
+	zendesk.Client
+	rate *zendesk.RateLimit
+}
+
+func (c *rateClient) RateLimit() (zendesk.RateLimit, bool) {
+	if c.rate == nil {
+		return zendesk.RateLimit{}, false
+	}
+	return *c.rate, true
+}
+
+func TestRatePoolAdjust(t *testing.T) {
+	tests := []struct {
+		name     string
+		limit    int
+		acked    int
+		rate     *zendesk.RateLimit
+		err      error
+		latency  time.Duration
+		expected int
+	}{
+		{"not reported", 4, 0, nil, nil, 0, 4},
+		{"after the window", 4, 3, nil, nil, 0, 5},
+		{"enough", 4, 0, &zendesk.RateLimit{Limit: 700, Remaining: 300}, nil, 0, 4},
+		{"not recovered", 4, 3, &zendesk.RateLimit{Limit: 700, Remaining: 300}, nil, 0, 4},
+		{"low", 4, 0, &zendesk.RateLimit{Limit: 700, Remaining: 100}, nil, 0, 2},
+		{"at least one", 1, 0, &zendesk.RateLimit{Limit: 700, Remaining: 0}, nil, 0, 1},
+		{"rate limited", 4, 0, &zendesk.RateLimit{Limit: 700, Remaining: 600}, &zendesk.StatusError{StatusCode: 429}, 0, 2},
+		{"recovered", 2, 1, &zendesk.RateLimit{Limit: 700, Remaining: 600}, nil, 0, 3},
+		{"up to the maximum", 8, 7, &zendesk.RateLimit{Limit: 700, Remaining: 700}, nil, 0, 8},
+		{"latency is high", 4, 3, nil, nil, time.Second, 2},
+		{"latency is normal", 4, 3, nil, nil, 15 * time.Millisecond, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newRatePool(&rateClient{rate: tt.rate}, 8)
+			p.limit = tt.limit
+			p.acked = tt.acked
+			p.latency, p.baseline = 10*time.Millisecond, 10*time.Millisecond
+			p.adjust(tt.err, tt.latency)
+			if p.limit != tt.expected {
+				t.Errorf("adjust() failed: got %d, want %d", p.limit, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRatePoolDecreaseOnce(t *testing.T) {
+	p := newRatePool(&rateClient{}, 8)
+	p.limit = 8
+	// the operations started before the concurrency was halved do not halve it again.
+	for i := 0; i < 4; i++ {
+		p.adjust(&zendesk.StatusError{StatusCode: 429}, 0)
+	}
+	if p.limit != 4 {
+		t.Errorf("adjust() failed: got %d, want 4", p.limit)
+	}
+	p.adjust(&zendesk.StatusError{StatusCode: 429}, 0)
+	if p.limit != 2 {
+		t.Errorf("adjust() failed: got %d, want 2", p.limit)
+	}
+}
+
+func TestRatePoolRun(t *testing.T) {
+	var mu sync.Mutex
+	var called []int
+	p := newRatePool(&rateClient{}, 3)
+	err := p.run([]int{1, 2, 3, 4, 5}, func(id int) error {
+		mu.Lock()
+		defer mu.Unlock()
+		called = append(called, id)
+		return nil
+	})
+	slices.Sort(called)
+	if err != nil || !slices.Equal(called, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("run() failed: got %v and %v", called, err)
+	}
+
+	// the rest are not called after the failure.
+	called = nil
+	failure := errors.New("failure")
+	p = newRatePool(&rateClient{}, 1)
+	err = p.run([]int{1, 2, 3}, func(id int) error {
+		called = append(called, id)
+		if id == 2 {
+			return failure
+		}
+		return nil
+	})
+	if !errors.Is(err, failure) || !slices.Equal(called, []int{1, 2}) {
+		t.Errorf("run() failed: got %v and %v", called, err)
+	}
+}
+
+func TestRatePoolRequeue(t *testing.T) {
+	sleep = func(time.Duration) {}
+	defer func() { sleep = time.Sleep }()
+
+	// the operation rate limited is called again after the others.
+	var called []int
+	limited := false
+	p := newRatePool(&rateClient{}, 1)
+	err := p.run([]int{1, 2, 3}, func(id int) error {
+		called = append(called, id)
+		if id == 2 && !limited {
+			limited = true
+			return &zendesk.StatusError{StatusCode: 429}
+		}
+		return nil
+	})
+	if err != nil || !slices.Equal(called, []int{1, 2, 3, 2}) {
+		t.Errorf("run() failed: got %v and %v", called, err)
+	}
+
+	// it fails after being queued again maxRequeues times.
+	calls := 0
+	p = newRatePool(&rateClient{}, 1)
+	err = p.run([]int{1}, func(id int) error {
+		calls++
+		return &zendesk.StatusError{StatusCode: 429}
+	})
+	if !zendesk.IsRateLimited(err) || calls != maxRequeues+1 {
+		t.Errorf("run() failed: got %d calls and %v", calls, err)
+	}
+}
+
+func TestRatePoolReport(t *testing.T) {
+	p := newRatePool(&rateClient{}, 8)
+	p.limit = 8
+	// the rate limit retried within the operation halves the concurrency, and the other errors do not.
+	p.report(&zendesk.StatusError{StatusCode: 503})
+	if p.limit != 8 {
+		t.Errorf("report() failed: got %d, want 8", p.limit)
+	}
+	p.report(&zendesk.StatusError{StatusCode: 429})
+	if p.limit != 4 {
+		t.Errorf("report() failed: got %d, want 4", p.limit)
+	}
+}
@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/tukaelu/zgsync/internal/state"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+// pushItem is a file to push as either an article or a translation.
+type pushItem struct {
+	file    string
+	article bool
+}
+
+// classifyFile returns the kind of the file pushed by push --all, or an empty string.
+func classifyFile(path string) string {
+	ref, err := parseFileRef(path)
+	switch {
+	case err != nil, ref.Kind == state.KindPost:
+		return ""
+	case ref.SourceID != 0:
+		return state.KindTranslation
+	case ref.ID != 0:
+		return state.KindArticle
+	case ref.Title == "":
+		return ""
+	case isPairedTranslation(path):
+		return state.KindTranslation
+	}
+	return state.KindArticle
+}
+
+// isPairedTranslation reports whether the file is named {article name}.{locale}.md next to the article file.
+func isPairedTranslation(path string) bool {
+	base := strings.TrimSuffix(filepath.Base(path), ".md")
+	i := strings.LastIndex(base, ".")
+	if i <= 0 {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(filepath.Dir(path), base[:i]+".md"))
+	return err == nil
+}
+
+// orderFiles orders the files so that all the articles are pushed before the translations.
+func orderFiles(files []string) []pushItem {
+	var articles, translations []pushItem
+	for _, file := range files {
+		switch classifyFile(file) {
+		case state.KindArticle:
+			articles = append(articles, pushItem{file: file, article: true})
+		case state.KindTranslation:
+			translations = append(translations, pushItem{file: file})
+		}
+	}
+	return append(articles, translations...)
+}
+
+// createSections creates the sections of the directory configurations which do not have section_id yet.
+func (c *CommandPush) createSections(g *Global, files []string) error {
+	seen := map[string]bool{}
+	var dirs []string
+	for _, file := range files {
+		for dir := filepath.Dir(file); isUnder(c.dirs.root, dir) && !seen[dir]; dir = filepath.Dir(dir) {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+			if dir == c.dirs.root {
+				break
+			}
+		}
+	}
+	sort.Strings(dirs)
+
+	for _, dir := range dirs {
+		local, err := readDirConfig(dir)
+		if err != nil {
+			return err
+		}
+		def := local.Section
+		if def.Name == "" || local.SectionID != nil {
+			continue
+		}
+		path := filepath.Join(dir, dirConfigFileName)
+		if def.CategoryID == 0 {
+			return fmt.Errorf("%s: category_id of the section is not specified", path)
+		}
+		dc, err := c.dirs.forDir(dir)
+		if err != nil {
+			return err
+		}
+		locale := def.Locale
+		if locale == "" {
+			locale = dc.Locale
+		}
+		if locale == "" {
+			locale = g.Config.DefaultLocale
+		}
+		locale = g.Config.remoteLocale(locale)
+		s := &zendesk.Section{Name: def.Name, Description: def.Description, Position: def.Position, Locale: locale, CategoryID: def.CategoryID}
+
+		if c.DryRun {
+			dryRun(s, path)
+			continue
+		}
+		client, err := c.clientFor(g, brandOf(g, "", dc))
+		if err != nil {
+			return err
+		}
+		payload, err := s.ToPayload()
+		if err != nil {
+			return err
+		}
+		var res string
+		err = c.Retry.do(func() (err error) {
+			res, err = client.CreateSection(locale, def.CategoryID, payload)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if err := s.FromJson(res); err != nil {
+			return err
+		}
+		if err := appendSectionID(path, s.ID); err != nil {
+			return err
+		}
+		// the directories beneath inherit the section ID.
+		c.dirs.cache = map[string]*DirConfig{}
+		slog.Info("created the section", "file", path, "section_id", s.ID)
+	}
+	return nil
+}
+
+// appendSectionID appends section_id to the directory configuration, keeping the rest of the file as it is.
+func appendSectionID(path string, sectionID int) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if len(b) > 0 && b[len(b)-1] != '\n' {
+		b = append(b, '\n')
+	}
+	b = append(b, fmt.Sprintf("section_id: %d\n", sectionID)...)
+	return os.WriteFile(path, b, 0o644)
+}
+
+var mdLinkPattern = regexp.MustCompile(`href="([^":]+?\.md)(#[^"]*)?"`)
+
+// resolveLinks rewrites the links to the local Markdown files into the URLs of the articles.
+func resolveLinks(g *Global, file, body, locale, brand string) string {
+	subdomain := g.Config.Subdomain
+	if s, ok := g.Config.Brands[brand]; ok {
+		subdomain = s
+	}
+	return mdLinkPattern.ReplaceAllStringFunc(body, func(m string) string {
+		sub := mdLinkPattern.FindStringSubmatch(m)
+		p, err := url.PathUnescape(sub[1])
+		if err != nil {
+			return m
+		}
+		target := filepath.Join(filepath.Dir(file), filepath.FromSlash(p))
+		ref, err := readFileRef(target)
+		if err != nil {
+			slog.Warn("link is not resolved", "file", file, "link", sub[1], "error", err)
+			return m
+		}
+		l := locale
+		if ref.Kind == state.KindTranslation && ref.Locale != "" {
+			l = g.Config.remoteLocale(ref.Locale)
+		}
+		return fmt.Sprintf(`href="`+zendesk.BaseURL+`/hc/%s/articles/%d%s"`, subdomain, l, ref.ID, sub[2])
+	})
+}
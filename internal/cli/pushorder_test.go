@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+func TestOrderFiles(t *testing.T) {
+	dir := filepath.Join("testdata", "paired")
+	files := []string{
+		filepath.Join(dir, "200-en-us.md"),
+		filepath.Join(dir, "200.md"),
+		filepath.Join(dir, "guide.ja.md"),
+		filepath.Join(dir, "guide.md"),
+		filepath.Join(dir, "guide.old.md"),
+		filepath.Join(dir, "other.ja.md"),
+	}
+	expected := []pushItem{
+		{file: filepath.Join(dir, "200.md"), article: true},
+		{file: filepath.Join(dir, "guide.md"), article: true},
+		{file: filepath.Join(dir, "guide.old.md"), article: true},
+		{file: filepath.Join(dir, "other.ja.md"), article: true},
+		{file: filepath.Join(dir, "200-en-us.md")},
+		{file: filepath.Join(dir, "guide.ja.md")},
+	}
+	if got := orderFiles(files); !reflect.DeepEqual(got, expected) {
+		t.Errorf("orderFiles() failed: got %v, want %v", got, expected)
+	}
+}
+
+// sectionClient creates sections with the IDs from 500.
+type sectionClient struct {
+	zendesk.Client
+	created []string
+}
+
+func (c *sectionClient) CreateSection(locale string, categoryID int, payload string) (string, error) {
+	c.created = append(c.created, fmt.Sprintf("%s/%d %s", locale, categoryID, payload))
+	return fmt.Sprintf(`{"section":{"id":%d}}`, 500+len(c.created)), nil
+}
+
+func TestCreateSections(t *testing.T) {
+	dir := t.TempDir()
+	for path, content := range map[string]string{
+		"sso/.zgsync.yaml":          "section:\n  name: SSO\n  category_id: 1\n",
+		"sso/saml/.zgsync.yaml":     "section:\n  name: SAML\n  category_id: 1\n  locale: en-us\n",
+		"sso/saml/setup.md":         "---\ntitle: Setup\n---\n",
+		"billing/.zgsync.yaml":      "section:\n  name: Billing\n  category_id: 2\nsection_id: 30\n",
+		"billing/invoice.md":        "---\ntitle: Invoice\n---\n",
+		"unrelated/.zgsync.yaml":    "section:\n  name: Unrelated\n  category_id: 3\n",
+		"unrelated/nothing_here.md": "---\ntitle: Nothing\n---\n",
+	} {
+		path = filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja"}}
+	dirs, err := newDirConfigs(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &sectionClient{}
+	c := &CommandPush{client: client, clients: map[string]zendesk.Client{}, dirs: dirs}
+	files := []string{filepath.Join(dir, "sso", "saml", "setup.md"), filepath.Join(dir, "billing", "invoice.md")}
+	if err := c.createSections(g, files); err != nil {
+		t.Fatalf("createSections() failed: %v", err)
+	}
+
+	expected := []string{
+		`ja/1 {"section":{"category_id":1,"locale":"ja","name":"SSO"}}`,
+		`en-us/1 {"section":{"category_id":1,"locale":"en-us","name":"SAML"}}`,
+	}
+	if !reflect.DeepEqual(client.created, expected) {
+		t.Errorf("createSections() failed: got %v, want %v", client.created, expected)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "sso", "saml", ".zgsync.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "section:\n  name: SAML\n  category_id: 1\n  locale: en-us\nsection_id: 502\n" {
+		t.Errorf("createSections() failed: unexpected configuration %q", b)
+	}
+	dc, err := c.dirs.For(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dc.SectionID == nil || *dc.SectionID != 502 {
+		t.Errorf("createSections() failed: got section_id %v, want 502", dc.SectionID)
+	}
+}
+
+func TestResolveLinks(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"setup.md":    "---\ntitle: Setup\nid: 1\nlocale: ja\n---\n",
+		"setup.en.md": "---\ntitle: Setup\nsource_id: 1\nlocale: en-us\n---\n",
+		"new.md":      "---\ntitle: New\nlocale: ja\n---\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	g := &Global{Config: Config{Subdomain: "example", DefaultLocale: "ja", Brands: map[string]string{"sub": "sub-example"}}}
+
+	tests := []struct {
+		name     string
+		body     string
+		brand    string
+		expected string
+	}{
+		{"article", `<a href="setup.md">`, "", `<a href="https://example.zendesk.com/hc/ja/articles/1">`},
+		{"translation", `<a href="setup.en.md#faq">`, "", `<a href="https://example.zendesk.com/hc/en-us/articles/1#faq">`},
+		{"brand", `<a href="./setup.md">`, "sub", `<a href="https://sub-example.zendesk.com/hc/ja/articles/1">`},
+		{"not pushed yet", `<a href="new.md">`, "", `<a href="new.md">`},
+		{"missing", `<a href="missing.md">`, "", `<a href="missing.md">`},
+		{"absolute", `<a href="https://example.com/setup.md">`, "", `<a href="https://example.com/setup.md">`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolveLinks(g, filepath.Join(dir, "page.md"), tt.body, "ja", tt.brand); got != tt.expected {
+				t.Errorf("resolveLinks() failed: got %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"os"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// pushPipeline converts the translation files by the workers ahead of their pushes.
+type pushPipeline struct {
+	slots map[int]*pushSlot
+	sem   chan struct{}
+	quit  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// pushSlot is the translation prepared for an item.
+type pushSlot struct {
+	done chan struct{}
+	// modTime is the modification time of the file before it was read.
+	modTime time.Time
+	p       *preparedTranslation
+	err     error
+	taken   bool
+}
+
+// startPipeline starts converting the translation items. The article items are pushed as before.
+func (c *CommandPush) startPipeline(g *Global, items []pushItem) *pushPipeline {
+	workers := runtime.GOMAXPROCS(0)
+	pp := &pushPipeline{
+		slots: map[int]*pushSlot{},
+		sem:   make(chan struct{}, 2*workers),
+		quit:  make(chan struct{}),
+	}
+	var indexes []int
+	for i, item := range items {
+		if !item.article {
+			pp.slots[i] = &pushSlot{done: make(chan struct{})}
+			indexes = append(indexes, i)
+		}
+	}
+
+	jobs := make(chan int)
+	pp.wg.Add(1)
+	go func() {
+		defer pp.wg.Done()
+		defer close(jobs)
+		for _, i := range indexes {
+			select {
+			case pp.sem <- struct{}{}:
+			case <-pp.quit:
+				return
+			}
+			jobs <- i
+		}
+	}()
+	for w := 0; w < workers; w++ {
+		pp.wg.Add(1)
+		go func() {
+			defer pp.wg.Done()
+			for i := range jobs {
+				s := pp.slots[i]
+				if fi, err := os.Stat(items[i].file); err == nil {
+					s.modTime = fi.ModTime()
+				}
+				s.p, s.err = c.prepareTranslation(g, items[i].file)
+				close(s.done)
+			}
+		}()
+	}
+	return pp
+}
+
+// take waits for the prepared translation, preparing it again if the file has been modified.
+func (c *CommandPush) take(g *Global, pp *pushPipeline, i int, file string) (*preparedTranslation, error) {
+	s := pp.slots[i]
+	<-s.done
+	if !s.taken {
+		s.taken = true
+		<-pp.sem
+	}
+	if fi, err := os.Stat(file); err != nil || !fi.ModTime().Equal(s.modTime) {
+		return c.prepareTranslation(g, file)
+	}
+	return s.p, s.err
+}
+
+// stop stops converting the items left and waits for the workers.
+func (pp *pushPipeline) stop() {
+	close(pp.quit)
+	pp.wg.Wait()
+}
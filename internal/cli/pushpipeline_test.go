@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tukaelu/zgsync/internal/converter"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+func TestPushPipeline(t *testing.T) {
+	dir := t.TempDir()
+	var files []string
+	for id := 1; id <= 20; id++ {
+		file := filepath.Join(dir, fmt.Sprintf("%d-ja.md", id))
+		if err := os.WriteFile(file, []byte(fmt.Sprintf("---\ntitle: t%d\nlocale: ja\nsource_id: %d\n---\n**body %d**\n", id, id, id)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, file)
+	}
+	g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja"}}
+	client := &pushClient{}
+	c := &CommandPush{Files: []string{dir}, client: client, clients: map[string]zendesk.Client{}, converter: converter.NewConverter()}
+	if err := c.Run(g); err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	// the files are pushed in order with their own payloads.
+	if len(client.payloads) != len(files) {
+		t.Fatalf("Run() failed: got %d payloads, want %d", len(client.payloads), len(files))
+	}
+	for i, id := range client.updated {
+		var pushed zendesk.Translation
+		if err := pushed.FromJson(client.payloads[i]); err != nil {
+			t.Fatal(err)
+		}
+		if pushed.Body != fmt.Sprintf("<p><strong>body %d</strong></p>\n", id) {
+			t.Errorf("Run() failed: unexpected body of %d: %q", id, pushed.Body)
+		}
+	}
+	if fmt.Sprint(client.updated[:3]) != "[1 10 11]" {
+		t.Errorf("Run() failed: got %v, want in the order of the files", client.updated)
+	}
+}
+
+func TestPushPipelineModified(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "1-ja.md")
+	if err := os.WriteFile(file, []byte("---\ntitle: t1\nlocale: ja\n---\nbody\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja"}}
+	c := &CommandPush{converter: converter.NewConverter()}
+	var err error
+	if c.dirs, err = newDirConfigs(dir); err != nil {
+		t.Fatal(err)
+	}
+	pp := c.startPipeline(g, []pushItem{{file: file}})
+	defer pp.stop()
+	<-pp.slots[0].done
+	if pp.slots[0].err == nil {
+		t.Fatal("prepareTranslation() should fail without source_id")
+	}
+
+	// source_id is written back after the file was read.
+	if err := os.WriteFile(file, []byte("---\ntitle: t1\nlocale: ja\nsource_id: 1\n---\nbody\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(file, time.Time{}, time.Now().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	p, err := c.take(g, pp, 0, file)
+	if err != nil {
+		t.Fatalf("take() failed: %v", err)
+	}
+	if p.t.SourceID != 1 {
+		t.Errorf("take() failed: got source_id %d, want 1", p.t.SourceID)
+	}
+}
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+// refCache caches whether the remote objects referred to by the files exist.
+type refCache map[string]bool
+
+// check verifies that the object referred to by the field exists, caching it by the brand.
+func (r *refCache) check(brand, field, kind string, id int, show func() (string, error)) error {
+	if *r == nil {
+		*r = refCache{}
+	}
+	key := fmt.Sprintf("%s/%s/%d", brand, kind, id)
+	exists, ok := (*r)[key]
+	if !ok {
+		_, err := show()
+		if err != nil && !zendesk.IsNotFound(err) {
+			return err
+		}
+		exists = err == nil
+		(*r)[key] = exists
+	}
+	if !exists {
+		return fmt.Errorf("%s: %s %d does not exist", field, kind, id)
+	}
+	return nil
+}
+
+// found records that the object of the kind exists.
+func (r *refCache) found(brand, kind string, id int) {
+	if *r == nil {
+		*r = refCache{}
+	}
+	(*r)[fmt.Sprintf("%s/%s/%d", brand, kind, id)] = true
+}
+
+// checkArticleRefs verifies the section, the permission group and the user segments of the article.
+func (c *CommandPush) checkArticleRefs(client zendesk.Client, brand string, a *zendesk.Article) error {
+	if a.SectionID != 0 {
+		err := c.refs.check(brand, "section_id", "section", a.SectionID, func() (string, error) {
+			return client.ShowSection("", a.SectionID)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if a.PermissionGroupID != 0 {
+		err := c.refs.check(brand, "permission_group_id", "permission group", a.PermissionGroupID, func() (string, error) {
+			return client.ShowPermissionGroup(a.PermissionGroupID)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return c.checkUserSegments(client, brand, a)
+}
+
+// checkUserSegments verifies that the user segments of the article exist.
+func (c *CommandPush) checkUserSegments(client zendesk.Client, brand string, a *zendesk.Article) error {
+	field := "user_segment_ids"
+	ids := a.UserSegmentIDs
+	if len(ids) == 0 && a.UserSegmentID != nil {
+		field = "user_segment_id"
+		ids = []int{*a.UserSegmentID}
+	}
+	for _, id := range ids {
+		err := c.refs.check(brand, field, "user segment", id, func() (string, error) {
+			return client.ShowUserSegment(id)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkSourceID verifies that the article given by source_id of the translation exists.
+func (c *CommandPush) checkSourceID(client zendesk.Client, brand string, sourceID int) error {
+	return c.refs.check(brand, "source_id", "article", sourceID, func() (string, error) {
+		return client.ShowArticle("", sourceID)
+	})
+}
+
+// checkLocale verifies that the locale of the file is enabled in the help center of the brand.
+func (c *CommandPush) checkLocale(client zendesk.Client, brand, locale string) error {
+	if c.locales == nil {
+		c.locales = map[string]*zendesk.Locales{}
+	}
+	enabled, ok := c.locales[brand]
+	if !ok {
+		var err error
+		if enabled, err = enabledLocales(client); err != nil {
+			return err
+		}
+		c.locales[brand] = enabled
+	}
+	return checkEnabledLocale(enabled, locale)
+}
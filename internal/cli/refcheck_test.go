@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+// refClient has the objects whose IDs are up to 2, and records the objects fetched.
+type refClient struct {
+	zendesk.Client
+	shown []string
+}
+
+func (c *refClient) show(kind string, id int) (string, error) {
+	c.shown = append(c.shown, fmt.Sprintf("%s/%d", kind, id))
+	if id > 2 {
+		return "", &zendesk.StatusError{StatusCode: 404}
+	}
+	return fmt.Sprintf(`{%q:{"id":%d}}`, kind, id), nil
+}
+
+func (c *refClient) ShowSection(locale string, sectionID int) (string, error) {
+	return c.show("section", sectionID)
+}
+
+func (c *refClient) ShowPermissionGroup(permissionGroupID int) (string, error) {
+	return c.show("permission_group", permissionGroupID)
+}
+
+func (c *refClient) ShowUserSegment(userSegmentID int) (string, error) {
+	return c.show("user_segment", userSegmentID)
+}
+
+func (c *refClient) ShowArticle(locale string, articleID int) (string, error) {
+	return c.show("article", articleID)
+}
+
+func TestCheckArticleRefs(t *testing.T) {
+	id := 2
+	tests := []struct {
+		name     string
+		article  *zendesk.Article
+		expected string
+	}{
+		{"existing", &zendesk.Article{SectionID: 1, PermissionGroupID: 1, UserSegmentIDs: []int{1, 2}}, ""},
+		{"user_segment_id", &zendesk.Article{UserSegmentID: &id}, ""},
+		{"everyone", &zendesk.Article{}, ""},
+		{"section", &zendesk.Article{SectionID: 3, PermissionGroupID: 1}, "section_id: section 3 does not exist"},
+		{"permission group", &zendesk.Article{SectionID: 1, PermissionGroupID: 4}, "permission_group_id: permission group 4 does not exist"},
+		{"user segment", &zendesk.Article{UserSegmentIDs: []int{1, 3}}, "user_segment_ids: user segment 3 does not exist"},
+	}
+	client := &refClient{}
+	c := &CommandPush{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			if err := c.checkArticleRefs(client, "", tt.article); err != nil {
+				got = err.Error()
+			}
+			if got != tt.expected {
+				t.Errorf("checkArticleRefs() failed: got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+	expected := "[section/1 permission_group/1 user_segment/1 user_segment/2 section/3 permission_group/4 user_segment/3]"
+	if fmt.Sprint(client.shown) != expected {
+		t.Errorf("checkArticleRefs() failed: the results are not cached: got %v, want %s", client.shown, expected)
+	}
+}
+
+func TestCheckSourceID(t *testing.T) {
+	client := &refClient{}
+	c := &CommandPush{}
+	c.refs.found("", "article", 5)
+	if err := c.checkSourceID(client, "", 5); err != nil {
+		t.Errorf("checkSourceID() failed: %v", err)
+	}
+	if err := c.checkSourceID(client, "", 3); fmt.Sprint(err) != "source_id: article 3 does not exist" {
+		t.Errorf("checkSourceID() failed: got %v", err)
+	}
+	// the same article of another brand is checked separately.
+	if err := c.checkSourceID(client, "other", 1); err != nil {
+		t.Errorf("checkSourceID() failed: %v", err)
+	}
+	if fmt.Sprint(client.shown) != "[article/3 article/1]" {
+		t.Errorf("checkSourceID() failed: got %v", client.shown)
+	}
+}
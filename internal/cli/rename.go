@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/tukaelu/zgsync/internal/state"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+// detectMoves carries the tracking of the files renamed or moved locally over to their new paths.
+func (c *CommandPush) detectMoves(g *Global, files []string) error {
+	for _, file := range files {
+		if _, ok := c.state.Lookup(file); ok {
+			continue
+		}
+		ref, err := readFileRef(file)
+		if err != nil || ref.Kind == state.KindPost {
+			continue
+		}
+		var locale string
+		if ref.Kind == state.KindTranslation {
+			if locale, err = c.fileLocale(g, file, ref.Locale); err != nil {
+				return err
+			}
+		}
+		dc, err := c.dirs.For(file)
+		if err != nil {
+			return err
+		}
+		key, e, ok := c.state.Find(ref.Kind, brandOf(g, ref.Brand, dc), ref.ID, locale)
+		if !ok {
+			continue
+		}
+		old := c.state.Abs(key)
+		if _, err := os.Stat(old); !os.IsNotExist(err) {
+			continue
+		}
+		if c.DryRun {
+			slog.Info("moved", "from", old, "to", file)
+			continue
+		}
+		c.state.Move(old, file)
+		slog.Info("moved", "from", old, "to", file)
+
+		if ref.Kind == state.KindTranslation && ref.SectionID == 0 {
+			if err := c.moveSection(g, file, e); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *CommandPush) fileLocale(g *Global, file, locale string) (string, error) {
+	if locale == "" {
+		dc, err := c.dirs.For(file)
+		if err != nil {
+			return "", err
+		}
+		locale = dc.Locale
+	}
+	if locale == "" {
+		locale = g.Config.DefaultLocale
+	}
+	return g.Config.remoteLocale(locale), nil
+}
+
+// moveSection moves the article into the section mapped to its new directory with --move-sections.
+func (c *CommandPush) moveSection(g *Global, file string, e *state.Entry) error {
+	dc, err := c.dirs.For(file)
+	if err != nil {
+		return err
+	}
+	var sectionID int
+	if dc.SectionID != nil {
+		sectionID = *dc.SectionID
+	} else if id, ok := g.Config.sectionForFile(file); ok {
+		sectionID = id
+	}
+	if sectionID == 0 || e.SectionID == 0 || sectionID == e.SectionID {
+		return nil
+	}
+	if !c.MoveSections {
+		slog.Warn("moved into the directory of another section", "file", file, "article_id", e.ArticleID, "section_id", e.SectionID, "directory_section_id", sectionID)
+		return nil
+	}
+
+	client, err := c.clientFor(g, e.Brand)
+	if err != nil {
+		return err
+	}
+	res, err := client.MoveArticle(e.ArticleID, sectionID)
+	if err != nil {
+		return err
+	}
+	a := &zendesk.Article{}
+	if err := a.FromJson(res); err != nil {
+		return err
+	}
+	slog.Info("moved the article", "article_id", e.ArticleID, "from", e.SectionID, "to", a.SectionID)
+
+	// section_id of the article file is rewritten, so that the next push does not move the article back.
+	from := e.SectionID
+	for _, key := range c.state.Keys() {
+		o := c.state.Files[key]
+		if o.ArticleID != e.ArticleID || o.Brand != e.Brand || o.Kind == state.KindPost {
+			continue
+		}
+		o.SectionID = a.SectionID
+		if o.Kind == state.KindArticle {
+			if err := rewriteSectionID(g, c.state.Abs(key), from, a.SectionID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// rewriteSectionID replaces section_id of the article file if it is from.
+func rewriteSectionID(g *Global, file string, from, to int) error {
+	a := &zendesk.Article{}
+	if err := a.FromFile(file); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if a.SectionID != from {
+		return nil
+	}
+	a.SectionID = to
+	tmpl, _, err := g.Config.frontMatterTemplates()
+	if err != nil {
+		return err
+	}
+	return a.SaveWithTemplate(file, false, tmpl)
+}
@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tukaelu/zgsync/internal/state"
+	"github.com/tukaelu/zgsync/internal/zendesk"
+	"gopkg.in/yaml.v3"
+)
+
+type moveClient struct {
+	zendesk.Client
+	moved []string
+}
+
+func (c *moveClient) MoveArticle(articleID int, sectionID int) (string, error) {
+	c.moved = append(c.moved, fmt.Sprintf("%d:%d", articleID, sectionID))
+	return fmt.Sprintf(`{"article":{"id":%d,"section_id":%d}}`, articleID, sectionID), nil
+}
+
+func TestDetectMoves(t *testing.T) {
+	tests := []struct {
+		name         string
+		moveSections bool
+		moved        string
+		sectionID    int
+	}{
+		{"warns of the section", false, "[]", 10},
+		{"moves the article", true, "[1:20]", 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			write := func(name, content string) string {
+				p := filepath.Join(dir, filepath.FromSlash(name))
+				if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return p
+			}
+			write("new/.zgsync.yaml", "section_id: 20\n")
+			article := write("1.md", "---\nid: 1\ntitle: t\nsection_id: 10\n---\n")
+			file := write("new/1-ja.md", "---\ntitle: t\nlocale: ja\nsource_id: 1\n---\nbody\n")
+			untracked := write("new/2-ja.md", "---\ntitle: t\nlocale: ja\nsource_id: 2\n---\nbody\n")
+			kept := write("3-ja.md", "---\ntitle: t\nlocale: ja\nsource_id: 3\n---\nbody\n")
+			// the article 1 of the other brand is another article with the same ID.
+			otherArticle := write("other/1.md", "---\nid: 1\ntitle: t\nsection_id: 10\n---\n")
+
+			g := &Global{Config: Config{ContentsDir: dir, DefaultLocale: "ja"}}
+			if err := yaml.Unmarshal([]byte("title: \"\"\nowner: docs\n"), &g.Config.ArticleFrontMatter); err != nil {
+				t.Fatal(err)
+			}
+			s, err := g.LoadState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			*s.Get(filepath.Join(dir, "a", "1-ja.md")) = state.Entry{Kind: state.KindTranslation, Brand: "other", ArticleID: 1, Locale: "ja", SectionID: 10}
+			*s.Get(otherArticle) = state.Entry{Kind: state.KindArticle, Brand: "other", ArticleID: 1, SectionID: 10}
+			*s.Get(filepath.Join(dir, "old", "1-ja.md")) = state.Entry{Kind: state.KindTranslation, ArticleID: 1, Locale: "ja", SectionID: 10, PulledHash: "h"}
+			*s.Get(article) = state.Entry{Kind: state.KindArticle, ArticleID: 1, SectionID: 10}
+			*s.Get(filepath.Join(dir, "old", "3-ja.md")) = state.Entry{Kind: state.KindTranslation, ArticleID: 3, Locale: "ja"}
+			*s.Get(kept) = state.Entry{Kind: state.KindTranslation, ArticleID: 3, Locale: "ja"}
+			dirs, err := newDirConfigs(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			client := &moveClient{}
+			c := &CommandPush{MoveSections: tt.moveSections, client: client, state: s, dirs: dirs}
+
+			if err := c.detectMoves(g, []string{file, untracked, kept}); err != nil {
+				t.Fatalf("detectMoves() failed: %v", err)
+			}
+			e, ok := s.Lookup(file)
+			if !ok || e.PulledHash != "h" || e.SectionID != tt.sectionID {
+				t.Errorf("detectMoves() failed: got %+v, %v", e, ok)
+			}
+			if _, ok := s.Lookup(filepath.Join(dir, "old", "1-ja.md")); ok {
+				t.Error("detectMoves() failed: the old path is still tracked")
+			}
+			if _, ok := s.Lookup(untracked); ok {
+				t.Error("detectMoves() failed: the untracked file is tracked")
+			}
+			if _, ok := s.Lookup(filepath.Join(dir, "old", "3-ja.md")); !ok {
+				t.Error("detectMoves() failed: the entry of the other file is moved")
+			}
+			if e, ok := s.Lookup(filepath.Join(dir, "a", "1-ja.md")); !ok || e.SectionID != 10 {
+				t.Errorf("detectMoves() failed: the entry of the other brand is changed: %+v, %v", e, ok)
+			}
+			if e, ok := s.Lookup(otherArticle); !ok || e.SectionID != 10 {
+				t.Errorf("detectMoves() failed: the article of the other brand is changed: %+v, %v", e, ok)
+			}
+			if fmt.Sprint(client.moved) != tt.moved {
+				t.Errorf("detectMoves() failed: got %v moved", client.moved)
+			}
+			b, err := os.ReadFile(article)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(b), fmt.Sprintf("section_id: %d\n", tt.sectionID)) {
+				t.Errorf("detectMoves() failed: section_id of the article file is not %d\n%s", tt.sectionID, b)
+			}
+			if tt.moveSections && !strings.Contains(string(b), "owner: docs") {
+				t.Errorf("detectMoves() failed: the front matter template is not applied\n%s", b)
+			}
+			if b, _ := os.ReadFile(otherArticle); !strings.Contains(string(b), "section_id: 10\n") {
+				t.Errorf("detectMoves() failed: the article file of the other brand is rewritten\n%s", b)
+			}
+		})
+	}
+}
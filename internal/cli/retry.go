@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+// sleep is replaced in the tests.
+var sleep = time.Sleep
+
+// Retry holds the flags retrying the operation on a file or an article when it fails with a transient error.
+type Retry struct {
+	MaxRetries   int           `name:"max-retries" help:"Specify the number of times to retry the failed requests." default:"0"`
+	RetryBackoff time.Duration `name:"retry-backoff" help:"Specify the wait before the first retry, which is doubled for each retry." default:"1s"`
+	// observe is called with the errors retried, so that the pool running the operations sees the rate limits.
+	observe func(err error) `kong:"-"`
+}
+
+// do calls op, and calls it again after the backoff while it fails with a transient error up to the maximum retries.
+func (r *Retry) do(op func() error) error {
+	backoff := r.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		err := op()
+		if err == nil || attempt >= r.MaxRetries || !zendesk.IsTransient(err) {
+			return err
+		}
+		// the wait given by the rate limited response is respected if it is longer than the backoff.
+		wait := backoff
+		var se *zendesk.StatusError
+		if errors.As(err, &se) && se.RetryAfter > wait {
+			wait = se.RetryAfter
+		}
+		if r.observe != nil {
+			r.observe(err)
+		}
+		slog.Warn("retrying", "attempt", attempt+1, "backoff", wait, "error", err)
+		sleep(wait)
+		backoff *= 2
+	}
+}
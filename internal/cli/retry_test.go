@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+func TestRetry(t *testing.T) {
+	transient := &zendesk.StatusError{StatusCode: 503}
+	tests := []struct {
+		name     string
+		retry    Retry
+		errs     []error
+		calls    int
+		waits    []time.Duration
+		expected error
+	}{
+		{"succeeds after retries", Retry{MaxRetries: 3, RetryBackoff: time.Second}, []error{transient, transient, nil}, 3, []time.Duration{time.Second, 2 * time.Second}, nil},
+		{"gives up", Retry{MaxRetries: 1, RetryBackoff: time.Second}, []error{transient, transient, nil}, 2, []time.Duration{time.Second}, transient},
+		{"no retries by default", Retry{RetryBackoff: time.Second}, []error{transient, nil}, 1, nil, transient},
+		{"retry after", Retry{MaxRetries: 3, RetryBackoff: time.Second}, []error{&zendesk.StatusError{StatusCode: 429, RetryAfter: 5 * time.Second}, transient, nil}, 3, []time.Duration{5 * time.Second, 2 * time.Second}, nil},
+		{"permanent error", Retry{MaxRetries: 3, RetryBackoff: time.Second}, []error{&zendesk.StatusError{StatusCode: 422}, nil}, 1, nil, &zendesk.StatusError{StatusCode: 422}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var waits []time.Duration
+			sleep = func(d time.Duration) { waits = append(waits, d) }
+			defer func() { sleep = time.Sleep }()
+
+			calls := 0
+			err := tt.retry.do(func() error {
+				calls++
+				return tt.errs[calls-1]
+			})
+			if calls != tt.calls {
+				t.Errorf("do() failed: got %d calls, want %d", calls, tt.calls)
+			}
+			if !reflect.DeepEqual(waits, tt.waits) {
+				t.Errorf("do() failed: got %v waits, want %v", waits, tt.waits)
+			}
+			var se *zendesk.StatusError
+			if (tt.expected == nil) != (err == nil) || (err != nil && (!errors.As(err, &se) || se.Error() != tt.expected.Error())) {
+				t.Errorf("do() failed: got %v, want %v", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRetryObserve(t *testing.T) {
+	sleep = func(time.Duration) {}
+	defer func() { sleep = time.Sleep }()
+
+	var observed []error
+	limited := &zendesk.StatusError{StatusCode: 429}
+	r := Retry{MaxRetries: 3, observe: func(err error) { observed = append(observed, err) }}
+	errs := []error{limited, limited, nil}
+	calls := 0
+	if err := r.do(func() error {
+		calls++
+		return errs[calls-1]
+	}); err != nil {
+		t.Fatalf("do() failed: %v", err)
+	}
+	if len(observed) != 2 {
+		t.Errorf("do() failed: got %v observed, want the errors retried", observed)
+	}
+}
@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"fmt"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+// validateSchema validates the YAML document against the yaml tags of the struct type.
+func validateSchema(path string, b []byte, t reflect.Type) error {
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal(b, doc); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	return validateMapping(path, doc.Content[0], t, true)
+}
+
+func validateMapping(path string, m *yaml.Node, t reflect.Type, root bool) error {
+	if m.Kind == yaml.ScalarNode && m.Tag == "!!null" {
+		return nil
+	}
+	if m.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s:%d:%d: expected a mapping of the config keys", path, m.Line, m.Column)
+	}
+
+	fields := map[string]reflect.StructField{}
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		key := yamlKey(t.Field(i))
+		if key == "" || (key == "environments" && !root) {
+			continue
+		}
+		fields[key] = t.Field(i)
+		keys = append(keys, key)
+	}
+
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		k, v := m.Content[i], m.Content[i+1]
+		f, ok := fields[k.Value]
+		if !ok {
+			msg := fmt.Sprintf("%s:%d:%d: unknown key %s", path, k.Line, k.Column, k.Value)
+			if s := nearestKey(k.Value, keys); s != "" {
+				msg += fmt.Sprintf(" (did you mean %s?)", s)
+			}
+			return fmt.Errorf("%s", msg)
+		}
+		if k.Value == "environments" {
+			if err := validateEnvironments(path, v, t); err != nil {
+				return err
+			}
+			continue
+		}
+		if f.Type.Kind() == reflect.Struct && f.Type != reflect.TypeOf(yaml.Node{}) {
+			if err := validateMapping(path, v, f.Type, false); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := v.Decode(reflect.New(f.Type).Interface()); err != nil {
+			return fmt.Errorf("%s:%d:%d: %s must be %s", path, v.Line, v.Column, k.Value, typeName(f.Type))
+		}
+	}
+	return nil
+}
+
+func validateEnvironments(path string, m *yaml.Node, t reflect.Type) error {
+	if m.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s:%d:%d: environments must be a mapping of the environment names", path, m.Line, m.Column)
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if err := validateMapping(path, m.Content[i+1], t, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func typeName(t reflect.Type) string {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int:
+		return "an integer"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice:
+		return "a list"
+	case reflect.Map:
+		return "a mapping"
+	default:
+		return t.String()
+	}
+}
+
+// nearestKey returns the key closest to the unknown key, or an empty string if none is close enough.
+func nearestKey(key string, keys []string) string {
+	best, bestDist := "", len(key)/2+1
+	for _, k := range keys {
+		if d := editDistance(key, k); d < bestDist {
+			best, bestDist = k, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// spinnerDelay is the time before the spinner is shown, so that the fast requests do not flicker.
+	spinnerDelay = 300 * time.Millisecond
+	// spinnerInterval is the interval of the frames of the spinner.
+	spinnerInterval = 100 * time.Millisecond
+)
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// statusLine writes the logs to the terminal, keeping the status line below them.
+type statusLine struct {
+	w       io.Writer
+	mu      sync.Mutex
+	enabled bool
+	line    string
+}
+
+// stderr is the standard error shared by the logs and the spinners.
+var stderr = &statusLine{w: os.Stderr}
+
+func (s *statusLine) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.line == "" {
+		return s.w.Write(p)
+	}
+	fmt.Fprint(s.w, "\r\x1b[K")
+	n, err := s.w.Write(p)
+	fmt.Fprint(s.w, s.line)
+	return n, err
+}
+
+// set replaces the status line, and erases it if line is empty.
+func (s *statusLine) set(line string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.line == "" && line == "" {
+		return
+	}
+	s.line = line
+	fmt.Fprint(s.w, "\r\x1b[K"+line)
+}
+
+// spin shows the spinner on the status line until the returned function is called.
+func spin(message string) (stop func()) {
+	if !stderr.enabled {
+		return func() {}
+	}
+	start := time.Now()
+	quit := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		timer := time.NewTimer(spinnerDelay)
+		defer timer.Stop()
+		for frame := 0; ; frame++ {
+			select {
+			case <-quit:
+				stderr.set("")
+				return
+			case <-timer.C:
+			}
+			elapsed := time.Since(start).Truncate(100 * time.Millisecond)
+			stderr.set(fmt.Sprintf("%s %s (%s)", spinnerFrames[frame%len(spinnerFrames)], message, elapsed))
+			timer.Reset(spinnerInterval)
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(quit)
+			<-done
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatusLine(t *testing.T) {
+	var b bytes.Buffer
+	s := &statusLine{w: &b}
+	s.Write([]byte("first\n"))
+	s.set("⠋ pulling")
+	s.Write([]byte("second\n"))
+	s.set("")
+	expected := "first\n\r\x1b[K⠋ pulling\r\x1b[Ksecond\n⠋ pulling\r\x1b[K"
+	if b.String() != expected {
+		t.Errorf("statusLine failed: got %q, want %q", b.String(), expected)
+	}
+}
+
+func TestSpin(t *testing.T) {
+	var b bytes.Buffer
+	saved := stderr
+	stderr = &statusLine{w: &b, enabled: true}
+	defer func() { stderr = saved }()
+
+	stop := spin("pulling article 1")
+	time.Sleep(spinnerDelay + spinnerInterval)
+	stop()
+	stop()
+	if !strings.Contains(b.String(), " pulling article 1 (") {
+		t.Errorf("spin() failed: the spinner is not shown in %q", b.String())
+	}
+	if !strings.HasSuffix(b.String(), "\r\x1b[K") {
+		t.Errorf("spin() failed: the spinner is not erased in %q", b.String())
+	}
+
+	// the spinner is not shown for the fast requests.
+	b.Reset()
+	spin("pulling article 2")()
+	if b.Len() != 0 {
+		t.Errorf("spin() failed: got %q, want nothing", b.String())
+	}
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"os"
+	"time"
+
+	"github.com/tukaelu/zgsync/internal/state"
+)
+
+// trackPulled records a file that was written from the remote.
+func trackPulled(s *state.Store, file string, remote state.Entry) error {
+	hash, err := state.HashFile(file)
+	if err != nil {
+		return err
+	}
+	e := s.Get(file)
+	e.Kind = remote.Kind
+	e.Brand = remote.Brand
+	e.ArticleID = remote.ArticleID
+	e.Locale = remote.Locale
+	e.SectionID = remote.SectionID
+	e.RemoteUpdatedAt = remote.RemoteUpdatedAt
+	e.PulledHash = hash
+	e.PulledAt = time.Now().UTC().Format(time.RFC3339)
+	return nil
+}
+
+// trackPushed records a file whose payload was written to the remote.
+func trackPushed(s *state.Store, file string, remote state.Entry, payload string) {
+	e := s.Get(file)
+	e.Kind = remote.Kind
+	e.Brand = remote.Brand
+	e.ArticleID = remote.ArticleID
+	e.Locale = remote.Locale
+	if remote.SectionID != 0 {
+		e.SectionID = remote.SectionID
+	}
+	e.RemoteUpdatedAt = remote.RemoteUpdatedAt
+	e.PushedHash = state.PayloadHash(payload)
+	e.PushedAt = time.Now().UTC().Format(time.RFC3339)
+}
+
+// planPush plans the push of the payload of the file. exists reports whether the remote object exists.
+func planPush(s *state.Store, file string, payload string, exists bool) state.Step {
+	return s.PlanPush(state.Candidate{Path: file, Hash: state.PayloadHash(payload), RemoteExists: exists})[0]
+}
+
+// planPull plans the pull of the remote object updated at updatedAt into the file.
+func planPull(s *state.Store, file string, updatedAt string) (state.Step, error) {
+	hash, err := state.HashFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return state.Step{}, err
+	}
+	return s.PlanPull(state.Candidate{Path: file, Hash: hash, RemoteExists: true, RemoteUpdatedAt: updatedAt})[0], nil
+}
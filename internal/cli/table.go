@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// table is the output of the list-style commands, whose columns are selected by --columns.
+type table struct {
+	// columns are the names of all the columns, such as updated_at, and defaults are the ones shown by default.
+	columns  []string
+	defaults []string
+	rows     [][]string
+}
+
+func newTable(columns []string, defaults ...string) *table {
+	if len(defaults) == 0 {
+		defaults = columns
+	}
+	return &table{columns: columns, defaults: defaults}
+}
+
+// add adds the row with the cells in the order of all the columns.
+func (t *table) add(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// validate returns an error if any of the selected columns does not exist, so that it fails before the requests.
+func (t *table) validate(columns []string) error {
+	for _, c := range columns {
+		if !slices.Contains(t.columns, c) {
+			return fmt.Errorf("unknown column %s: available columns are %s", c, strings.Join(t.columns, ","))
+		}
+	}
+	return nil
+}
+
+// print writes the table to the standard output, aligned on the terminal and as TSV when it is piped.
+func (t *table) print(columns []string) error {
+	return t.write(os.Stdout, columns, isTerminal(os.Stdout))
+}
+
+// write writes the selected columns of the table, or the default ones.
+func (t *table) write(w io.Writer, columns []string, aligned bool) error {
+	if len(columns) == 0 {
+		columns = t.defaults
+	}
+	if err := t.validate(columns); err != nil {
+		return err
+	}
+	indexes := make([]int, len(columns))
+	for i, c := range columns {
+		indexes[i] = slices.Index(t.columns, c)
+	}
+
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = c
+		if aligned {
+			header[i] = strings.ToUpper(c)
+		}
+	}
+	lines := [][]string{header}
+	for _, row := range t.rows {
+		line := make([]string, len(indexes))
+		for i, index := range indexes {
+			if index < len(row) {
+				line[i] = strings.Join(strings.Fields(row[index]), " ")
+			}
+		}
+		lines = append(lines, line)
+	}
+
+	if !aligned {
+		for _, line := range lines {
+			if _, err := fmt.Fprintln(w, strings.Join(line, "\t")); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, line := range lines {
+		fmt.Fprintln(tw, strings.Join(line, "\t"))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTableWrite(t *testing.T) {
+	tbl := newTable([]string{"id", "title", "updated_at"}, "id", "title")
+	tbl.add("1", "Getting started", "2024-01-01T00:00:00Z")
+	tbl.add("22", "Tabs\tand\nlines", "2024-02-01T00:00:00Z")
+
+	tests := []struct {
+		name     string
+		columns  []string
+		aligned  bool
+		expected string
+	}{
+		{"default columns", nil, true, "ID  TITLE\n1   Getting started\n22  Tabs and lines\n"},
+		{"selected columns", []string{"updated_at", "id"}, true, "UPDATED_AT            ID\n2024-01-01T00:00:00Z  1\n2024-02-01T00:00:00Z  22\n"},
+		{"tsv", []string{"id", "title"}, false, "id\ttitle\n1\tGetting started\n22\tTabs and lines\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := tbl.write(&b, tt.columns, tt.aligned); err != nil {
+				t.Fatalf("write() failed: %v", err)
+			}
+			if b.String() != tt.expected {
+				t.Errorf("write() failed: got %q, want %q", b.String(), tt.expected)
+			}
+		})
+	}
+
+	if err := tbl.write(&bytes.Buffer{}, []string{"id", "url"}, false); err == nil {
+		t.Error("write() should fail with an unknown column")
+	}
+}
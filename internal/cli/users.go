@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+// userNames resolves the IDs of the users such as author_id to their names, which are fetched once a run.
+type userNames struct {
+	client zendesk.Client
+	names  map[int]string
+}
+
+func newUserNames(client zendesk.Client) *userNames {
+	return &userNames{client: client, names: map[int]string{}}
+}
+
+// name returns the name of the user, or the ID if the user cannot be fetched.
+func (u *userNames) name(id int) string {
+	if id == 0 {
+		return ""
+	}
+	if name, ok := u.names[id]; ok {
+		return name
+	}
+	name := fmt.Sprint(id)
+	res, err := u.client.ShowUser(id)
+	if err == nil {
+		user := &zendesk.User{}
+		if err = user.FromJson(res); err == nil && user.Name != "" {
+			name = user.Name
+		}
+	}
+	if err != nil {
+		slog.Debug("failed to fetch the user", "user_id", id, "error", err)
+	}
+	u.names[id] = name
+	return name
+}
+
+// authorID returns the ID of the user of author_email, fetched once a run.
+func (c *CommandPush) authorID(client zendesk.Client, brand, email string) (int, error) {
+	key := brand + "/" + strings.ToLower(email)
+	if id, ok := c.authors[key]; ok {
+		return id, nil
+	}
+	res, err := client.SearchUsers("email:" + email)
+	if err != nil {
+		return 0, err
+	}
+	users, err := zendesk.UsersFromJson(res)
+	if err != nil {
+		return 0, err
+	}
+	for _, u := range users {
+		if strings.EqualFold(u.Email, email) {
+			if c.authors == nil {
+				c.authors = map[string]int{}
+			}
+			c.authors[key] = u.ID
+			return u.ID, nil
+		}
+	}
+	return 0, fmt.Errorf("author_email: no user has the email %s", email)
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/tukaelu/zgsync/internal/zendesk"
+)
+
+// usersClient has the user 7, and fails to show the other users.
+type usersClient struct {
+	zendesk.Client
+	requests []string
+}
+
+func (c *usersClient) ShowUser(userID int) (string, error) {
+	c.requests = append(c.requests, fmt.Sprintf("user/%d", userID))
+	if userID != 7 {
+		return "", &zendesk.StatusError{StatusCode: 403}
+	}
+	return `{"user":{"id":7,"name":"Jane Doe","email":"jane@example.com","role":"agent"}}`, nil
+}
+
+func (c *usersClient) SearchUsers(query string) (string, error) {
+	c.requests = append(c.requests, "search/"+query)
+	if query != "email:Jane@example.com" {
+		return `{"users":[]}`, nil
+	}
+	return `{"users":[{"id":7,"name":"Jane Doe","email":"jane@example.com"}]}`, nil
+}
+
+func TestUserNames(t *testing.T) {
+	client := &usersClient{}
+	u := newUserNames(client)
+	var got []string
+	for _, id := range []int{7, 8, 7, 0} {
+		got = append(got, u.name(id))
+	}
+	if fmt.Sprint(got) != "[Jane Doe 8 Jane Doe ]" {
+		t.Errorf("name() failed: got %q", got)
+	}
+	if fmt.Sprint(client.requests) != "[user/7 user/8]" {
+		t.Errorf("name() failed: got %v requested", client.requests)
+	}
+}
+
+func TestAuthorID(t *testing.T) {
+	client := &usersClient{}
+	c := &CommandPush{}
+	for i := 0; i < 2; i++ {
+		id, err := c.authorID(client, "", "Jane@example.com")
+		if err != nil || id != 7 {
+			t.Errorf("authorID() failed: got %d, %v", id, err)
+		}
+	}
+	if _, err := c.authorID(client, "", "john@example.com"); err == nil || err.Error() != "author_email: no user has the email john@example.com" {
+		t.Errorf("authorID() failed: got %v", err)
+	}
+	if fmt.Sprint(client.requests) != "[search/email:Jane@example.com search/email:john@example.com]" {
+		t.Errorf("authorID() failed: got %v requested", client.requests)
+	}
+}
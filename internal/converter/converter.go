@@ -1,7 +1,6 @@
 package converter
 
 import (
-	"bytes"
 	"strconv"
 	"strings"
 
@@ -58,9 +57,11 @@ func NewConverter() Converter {
 }
 
 func (c *converterImpl) ConvertToHTML(markdown string) (string, error) {
-	var buf bytes.Buffer
-	err := c.markdown.Convert([]byte(markdown), &buf)
-	return buf.String(), err
+	// the HTML is written to the builder growing from the size of the Markdown, which is not copied at the end.
+	var sb strings.Builder
+	sb.Grow(len(markdown))
+	err := c.markdown.Convert([]byte(markdown), &sb)
+	return sb.String(), err
 }
 
 func (c *converterImpl) ConvertToMarkdown(html string) (string, error) {
@@ -0,0 +1,88 @@
+package converter
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// DefaultIframeHosts are the hosts of the videos which the help center keeps embedded in the articles without
+// displaying the unsafe content.
+var DefaultIframeHosts = []string{"youtube.com", "youtube-nocookie.com", "vimeo.com", "wistia.com", "wistia.net", "loom.com"}
+
+// strippedTags are the tags which the help center strips as unsafe content with their content.
+var strippedTags = []string{"script", "noscript", "object", "embed", "applet", "form", "input", "button", "select", "textarea", "style", "link", "meta", "base"}
+
+// urlAttributes are the attributes whose javascript: URLs are stripped.
+var urlAttributes = []string{"href", "src", "action", "formaction", "xlink:href"}
+
+// StrippedMarkup returns the markup of the HTML which the help center strips unless it displays the unsafe content:
+// the scripts and the other active tags, the inline event handlers, the javascript: URLs, and the iframes from the
+// hosts other than iframeHosts and their subdomains. Each markup is returned once in the order of the HTML.
+func StrippedMarkup(body string, iframeHosts []string) []string {
+	var found []string
+	add := func(format string, args ...any) {
+		if m := fmt.Sprintf(format, args...); !slices.Contains(found, m) {
+			found = append(found, m)
+		}
+	}
+	z := html.NewTokenizer(strings.NewReader(body))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return found
+		case html.StartTagToken, html.SelfClosingTagToken:
+		default:
+			continue
+		}
+		tok := z.Token()
+		if slices.Contains(strippedTags, tok.Data) {
+			add("<%s>", tok.Data)
+			continue
+		}
+		for _, a := range tok.Attr {
+			key := strings.ToLower(a.Key)
+			switch {
+			case strings.HasPrefix(key, "on"):
+				add("%s attribute of <%s>", key, tok.Data)
+			case slices.Contains(urlAttributes, key) && isJavaScriptURL(a.Val):
+				add("javascript: URL in %s of <%s>", key, tok.Data)
+			case tok.Data == "iframe" && key == "src" && !isAllowedHost(a.Val, iframeHosts):
+				add("<iframe> from %s", iframeHost(a.Val))
+			}
+		}
+	}
+}
+
+func isJavaScriptURL(s string) bool {
+	// the browsers ignore the whitespace and the control characters in the scheme.
+	s = strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, s)
+	return strings.HasPrefix(strings.ToLower(s), "javascript:")
+}
+
+func iframeHost(src string) string {
+	u, err := url.Parse(strings.TrimSpace(src))
+	if err != nil || u.Hostname() == "" {
+		return src
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+func isAllowedHost(src string, hosts []string) bool {
+	host := iframeHost(src)
+	for _, h := range hosts {
+		h = strings.ToLower(h)
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package converter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStrippedMarkup(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected []string
+	}{
+		{"safe", `<p><a href="https://example.com">a</a><img src="a.png" alt=""></p>`, nil},
+		{"script", "<p>a</p>\n<script>alert(1)</script>\n<script src=\"a.js\"></script>", []string{"<script>"}},
+		{"form", `<form action="/search"><input name="q"><button>Search</button></form>`, []string{"<form>", "<input>", "<button>"}},
+		{"event handler", `<a href="#" onClick="track()">a</a><img src="a.png" onerror="x()">`, []string{"onclick attribute of <a>", "onerror attribute of <img>"}},
+		{"javascript URL", `<a href=" Java&#x09;Script:alert(1)">a</a>`, []string{"javascript: URL in href of <a>"}},
+		{"allowed iframe", `<iframe src="https://www.youtube.com/embed/abc"></iframe><iframe src="https://fast.wistia.net/embed/iframe/abc"></iframe><iframe src="https://player.example.com/v/1"></iframe>`, nil},
+		{"iframe", `<iframe src="https://evil.example.org/embed"></iframe><iframe src="https://notyoutube.com/embed"></iframe>`, []string{"<iframe> from evil.example.org", "<iframe> from notyoutube.com"}},
+	}
+	hosts := append(DefaultIframeHosts, "player.example.com")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := StrippedMarkup(tt.body, hosts)
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("StrippedMarkup() failed: got %q, want %q", actual, tt.expected)
+			}
+		})
+	}
+}
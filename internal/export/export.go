@@ -0,0 +1,119 @@
+package export
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+type Page struct {
+	ArticleID int
+	Locale    string
+	SectionID int
+	Title     string
+	Body      template.HTML
+}
+
+func (p Page) FileName() string {
+	return fmt.Sprintf("%d-%s.html", p.ArticleID, p.Locale)
+}
+
+type Section struct {
+	ID    int
+	Name  string
+	Pages []Page
+}
+
+type site struct {
+	Title    string
+	Sections []Section
+	Page     *Page
+}
+
+const layout = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{if .Page}}{{.Page.Title}} - {{end}}{{.Title}}</title>
+<style>
+body { display: flex; margin: 0; font-family: sans-serif; line-height: 1.6; }
+nav { width: 280px; padding: 1em; background: #f5f5f5; min-height: 100vh; box-sizing: border-box; }
+nav h2 { font-size: 1em; margin-bottom: 0.2em; }
+nav ul { padding-left: 1.2em; margin-top: 0; }
+main { flex: 1; padding: 1em 2em; max-width: 960px; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; }
+pre { background: #f5f5f5; padding: 1em; overflow-x: auto; }
+</style>
+</head>
+<body>
+<nav>
+<a href="index.html">{{.Title}}</a>
+{{range .Sections}}<h2>{{.Name}}</h2>
+<ul>
+{{range .Pages}}<li><a href="{{.FileName}}">{{.Title}}</a>{{if ne .Locale ""}} ({{.Locale}}){{end}}</li>
+{{end}}</ul>
+{{end}}</nav>
+<main>
+{{if .Page}}<h1>{{.Page.Title}}</h1>
+{{.Page.Body}}{{else}}<h1>{{.Title}}</h1>
+{{range .Sections}}<h2>{{.Name}}</h2>
+<ul>
+{{range .Pages}}<li><a href="{{.FileName}}">{{.Title}}</a> ({{.Locale}})</li>
+{{end}}</ul>
+{{end}}{{end}}</main>
+</body>
+</html>
+`
+
+var tmpl = template.Must(template.New("layout").Parse(layout))
+
+// Write renders the index and the pages grouped by section into outDir.
+// Sections without a name in sectionNames are labeled with their ID.
+func Write(outDir string, title string, pages []Page, sectionNames map[int]string) error {
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return err
+	}
+
+	bySection := map[int][]Page{}
+	for _, p := range pages {
+		bySection[p.SectionID] = append(bySection[p.SectionID], p)
+	}
+	var sections []Section
+	for id, ps := range bySection {
+		sort.Slice(ps, func(i, j int) bool {
+			if ps[i].Title != ps[j].Title {
+				return ps[i].Title < ps[j].Title
+			}
+			return ps[i].Locale < ps[j].Locale
+		})
+		name := sectionNames[id]
+		if name == "" {
+			name = fmt.Sprintf("Section %d", id)
+		}
+		sections = append(sections, Section{ID: id, Name: name, Pages: ps})
+	}
+	sort.Slice(sections, func(i, j int) bool { return sections[i].Name < sections[j].Name })
+
+	if err := render(filepath.Join(outDir, "index.html"), site{Title: title, Sections: sections}); err != nil {
+		return err
+	}
+	for i := range pages {
+		p := pages[i]
+		if err := render(filepath.Join(outDir, p.FileName()), site{Title: title, Sections: sections, Page: &p}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func render(path string, s site) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return tmpl.Execute(f, s)
+}
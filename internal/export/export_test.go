@@ -0,0 +1,41 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	pages := []Page{
+		{ArticleID: 1, Locale: "ja", SectionID: 10, Title: "First", Body: "<p>first body</p>"},
+		{ArticleID: 2, Locale: "ja", SectionID: 20, Title: "Second", Body: "<p>second body</p>"},
+	}
+	if err := Write(dir, "Help Center", pages, map[int]string{10: "Getting Started"}); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+
+	tests := []struct {
+		file     string
+		contains []string
+	}{
+		{"index.html", []string{"Getting Started", "Section 20", `href="1-ja.html"`, `href="2-ja.html"`}},
+		{"1-ja.html", []string{"<h1>First</h1>", "<p>first body</p>", `href="2-ja.html"`}},
+		{"2-ja.html", []string{"<h1>Second</h1>", "<p>second body</p>"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			b, err := os.ReadFile(filepath.Join(dir, tt.file))
+			if err != nil {
+				t.Fatalf("ReadFile() failed: %v", err)
+			}
+			for _, c := range tt.contains {
+				if !strings.Contains(string(b), c) {
+					t.Errorf("Write() failed: %s does not contain %q", tt.file, c)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package ignore
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	m, err := New([]string{
+		"# comment",
+		"",
+		"README.md",
+		"drafts/",
+		"/templates",
+		"**/tmp/*.md",
+		"*.bak.md",
+		"!keep.bak.md",
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	tests := []struct {
+		path     string
+		isDir    bool
+		expected bool
+	}{
+		{"README.md", false, true},
+		{"123/README.md", false, true},
+		{"drafts", true, true},
+		{"123/drafts", true, true},
+		{"drafts", false, false},
+		{"templates", true, true},
+		{"123/templates", true, false},
+		{"tmp/a.md", false, true},
+		{"a/b/tmp/a.md", false, true},
+		{"a.bak.md", false, true},
+		{"keep.bak.md", false, false},
+		{"123-ja.md", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if actual := m.Match(tt.path, tt.isDir); actual != tt.expected {
+				t.Errorf("Match(%q) failed: got %v, want %v", tt.path, actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	m, err := Load("testdata")
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if !m.Match("draft.md", false) {
+		t.Errorf("Match() failed: draft.md should be ignored")
+	}
+
+	m, err = Load("testdata/not_exists")
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if m.Match("draft.md", false) {
+		t.Errorf("Match() failed: nothing should be ignored without an ignore file")
+	}
+}
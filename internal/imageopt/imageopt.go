@@ -0,0 +1,266 @@
+// Package imageopt optimizes the images uploaded to the help center: it resizes the images wider than the
+// maximum width, re-encodes them, and strips their metadata such as EXIF.
+package imageopt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"path"
+	"strings"
+)
+
+const (
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+
+	DefaultJPEGQuality = 85
+)
+
+// Options are the thresholds of the optimization.
+type Options struct {
+	// MaxWidth is the width in pixels above which the images are resized, or 0 not to resize.
+	MaxWidth int
+	// Format is the format into which the images are re-encoded, which keeps the format of each image if empty.
+	Format string
+	// JPEGQuality is the quality of the JPEG images from 1 to 100, or DefaultJPEGQuality if 0.
+	JPEGQuality int
+}
+
+// Validate reports the invalid options.
+func (o Options) Validate() error {
+	switch o.Format {
+	case "", FormatJPEG, FormatPNG:
+	default:
+		return fmt.Errorf("format must be %s or %s", FormatJPEG, FormatPNG)
+	}
+	if o.MaxWidth < 0 {
+		return fmt.Errorf("max_width must not be negative")
+	}
+	if o.JPEGQuality < 0 || o.JPEGQuality > 100 {
+		return fmt.Errorf("jpeg_quality must be from 1 to 100")
+	}
+	return nil
+}
+
+// Optimize optimizes the JPEG or PNG image of the file name. It returns the optimized image with the file name
+// of its format, or the image as it is if it is not a JPEG or PNG image. The image re-encoded without any resize
+// or conversion is returned only if it has lost the metadata or got smaller.
+func (o Options) Optimize(name string, b []byte) ([]byte, string, error) {
+	img, format, err := image.Decode(bytes.NewReader(b))
+	if err != nil || (format != FormatJPEG && format != FormatPNG) {
+		// the other files are uploaded as they are.
+		return b, name, nil
+	}
+
+	changed := false
+	if format == FormatJPEG {
+		if orientation := jpegOrientation(b); orientation > 1 {
+			img = orient(img, orientation)
+			changed = true
+		}
+	}
+	if o.MaxWidth > 0 && img.Bounds().Dx() > o.MaxWidth {
+		img = resize(img, o.MaxWidth)
+		changed = true
+	}
+	to := format
+	if o.Format != "" && o.Format != format {
+		to = o.Format
+		name = strings.TrimSuffix(name, path.Ext(name)) + extension(to)
+		changed = true
+	}
+
+	var buf bytes.Buffer
+	if err := o.encode(&buf, img, to); err != nil {
+		return nil, "", err
+	}
+	if !changed && buf.Len() >= len(b) && !hasMetadata(b, format) {
+		return b, name, nil
+	}
+	return buf.Bytes(), name, nil
+}
+
+func (o Options) encode(buf *bytes.Buffer, img image.Image, format string) error {
+	if format == FormatPNG {
+		enc := &png.Encoder{CompressionLevel: png.BestCompression}
+		return enc.Encode(buf, img)
+	}
+	quality := o.JPEGQuality
+	if quality == 0 {
+		quality = DefaultJPEGQuality
+	}
+	// the transparent pixels are put on white, as JPEG has no alpha channel.
+	dst := image.NewRGBA(img.Bounds())
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, img.Bounds().Min, draw.Over)
+	return jpeg.Encode(buf, dst, &jpeg.Options{Quality: quality})
+}
+
+func extension(format string) string {
+	if format == FormatJPEG {
+		return ".jpg"
+	}
+	return "." + format
+}
+
+// resize scales the image down to the width keeping the aspect ratio, averaging the source pixels covered by
+// each pixel.
+func resize(img image.Image, width int) image.Image {
+	src := image.NewRGBA(image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy()))
+	draw.Draw(src, src.Bounds(), img, img.Bounds().Min, draw.Src)
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+	height := max(1, (sh*width+sw/2)/sw)
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0, y1 := y*sh/height, max((y+1)*sh/height, y*sh/height+1)
+		for x := 0; x < width; x++ {
+			x0, x1 := x*sw/width, max((x+1)*sw/width, x*sw/width+1)
+			var r, g, b, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				i := src.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += uint32(src.Pix[i])
+					g += uint32(src.Pix[i+1])
+					b += uint32(src.Pix[i+2])
+					a += uint32(src.Pix[i+3])
+					n++
+					i += 4
+				}
+			}
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// orient transforms the image by the EXIF orientation, which is lost with the metadata.
+func orient(img image.Image, orientation int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if orientation >= 5 {
+		w, h = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = w-1-x, y
+			case 3:
+				dx, dy = w-1-x, h-1-y
+			case 4:
+				dx, dy = x, h-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = w-1-y, x
+			case 7:
+				dx, dy = w-1-y, h-1-x
+			case 8:
+				dx, dy = y, h-1-x
+			default:
+				dx, dy = x, y
+			}
+			dst.Set(dx, dy, img.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+// jpegOrientation returns the orientation in the EXIF of the JPEG image, or 0 if it is not found.
+func jpegOrientation(b []byte) int {
+	for _, seg := range jpegSegments(b) {
+		if seg.marker != 0xe1 || !bytes.HasPrefix(seg.data, []byte("Exif\x00\x00")) {
+			continue
+		}
+		tiff := seg.data[6:]
+		if len(tiff) < 8 {
+			return 0
+		}
+		var order binary.ByteOrder
+		switch string(tiff[:2]) {
+		case "II":
+			order = binary.LittleEndian
+		case "MM":
+			order = binary.BigEndian
+		default:
+			return 0
+		}
+		ifd := int(order.Uint32(tiff[4:8]))
+		if ifd+2 > len(tiff) {
+			return 0
+		}
+		n := int(order.Uint16(tiff[ifd:]))
+		for i := 0; i < n; i++ {
+			entry := ifd + 2 + i*12
+			if entry+12 > len(tiff) {
+				return 0
+			}
+			if order.Uint16(tiff[entry:]) == 0x0112 {
+				return int(order.Uint16(tiff[entry+8:]))
+			}
+		}
+		return 0
+	}
+	return 0
+}
+
+type jpegSegment struct {
+	marker byte
+	data   []byte
+}
+
+// jpegSegments returns the segments of the JPEG image before the image data.
+func jpegSegments(b []byte) []jpegSegment {
+	if len(b) < 2 || b[0] != 0xff || b[1] != 0xd8 {
+		return nil
+	}
+	var segs []jpegSegment
+	for i := 2; i+4 <= len(b) && b[i] == 0xff; {
+		marker := b[i+1]
+		if marker == 0xda {
+			break
+		}
+		n := int(binary.BigEndian.Uint16(b[i+2:]))
+		if n < 2 || i+2+n > len(b) {
+			break
+		}
+		segs = append(segs, jpegSegment{marker: marker, data: b[i+4 : i+2+n]})
+		i += 2 + n
+	}
+	return segs
+}
+
+// hasMetadata reports whether the image has the metadata such as EXIF, which the re-encoded image does not have.
+func hasMetadata(b []byte, format string) bool {
+	if format == FormatJPEG {
+		for _, seg := range jpegSegments(b) {
+			// APP1 to APP15 hold EXIF, XMP, ICC profiles and the others, and COM holds the comments.
+			if seg.marker >= 0xe1 && seg.marker <= 0xef || seg.marker == 0xfe {
+				return true
+			}
+		}
+		return false
+	}
+	for i := 8; i+8 <= len(b); {
+		n := int(binary.BigEndian.Uint32(b[i:]))
+		switch string(b[i+4 : i+8]) {
+		case "eXIf", "tEXt", "iTXt", "zTXt", "tIME":
+			return true
+		}
+		i += 12 + n
+	}
+	return false
+}
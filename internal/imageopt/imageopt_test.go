@@ -0,0 +1,113 @@
+package imageopt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func newImage(w, h int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 255 / w), G: uint8(y * 255 / h), B: 128, A: 255})
+		}
+	}
+	return img
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// withExif inserts the APP1 segment with the orientation after SOI of the JPEG image.
+func withExif(t *testing.T, img image.Image, orientation uint16) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08\x00\x01")
+	entry := make([]byte, 12)
+	binary.BigEndian.PutUint16(entry[0:], 0x0112)
+	binary.BigEndian.PutUint16(entry[2:], 3)
+	binary.BigEndian.PutUint32(entry[4:], 1)
+	binary.BigEndian.PutUint16(entry[8:], orientation)
+	data := append(append([]byte("Exif\x00\x00"), tiff...), entry...)
+	data = append(data, 0, 0, 0, 0)
+	seg := []byte{0xff, 0xe1, 0, 0}
+	binary.BigEndian.PutUint16(seg[2:], uint16(len(data)+2))
+	b := buf.Bytes()
+	return append(append(append([]byte{}, b[:2]...), append(seg, data...)...), b[2:]...)
+}
+
+func TestOptimize(t *testing.T) {
+	large := encodePNG(t, newImage(400, 200))
+	exif := withExif(t, newImage(40, 20), 6)
+	tests := []struct {
+		name     string
+		options  Options
+		file     string
+		b        []byte
+		expected string
+		format   string
+		width    int
+		height   int
+	}{
+		{"resize", Options{MaxWidth: 100}, "a.png", large, "a.png", FormatPNG, 100, 50},
+		{"narrow", Options{MaxWidth: 1000}, "a.png", large, "a.png", FormatPNG, 400, 200},
+		{"convert", Options{Format: FormatJPEG}, "a.png", large, "a.jpg", FormatJPEG, 400, 200},
+		{"orientation", Options{}, "b.jpg", exif, "b.jpg", FormatJPEG, 20, 40},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, name, err := tt.options.Optimize(tt.file, tt.b)
+			if err != nil {
+				t.Fatalf("Optimize() failed: %v", err)
+			}
+			if name != tt.expected {
+				t.Errorf("Optimize() name = %s, want %s", name, tt.expected)
+			}
+			cfg, format, err := image.DecodeConfig(bytes.NewReader(b))
+			if err != nil {
+				t.Fatalf("Optimize() returned an invalid image: %v", err)
+			}
+			if format != tt.format || cfg.Width != tt.width || cfg.Height != tt.height {
+				t.Errorf("Optimize() = %s %dx%d, want %s %dx%d", format, cfg.Width, cfg.Height, tt.format, tt.width, tt.height)
+			}
+			if hasMetadata(b, format) {
+				t.Errorf("Optimize() left the metadata")
+			}
+		})
+	}
+}
+
+func TestOptimizeOther(t *testing.T) {
+	b := []byte("%PDF-1.4")
+	got, name, err := Options{MaxWidth: 100}.Optimize("manual.pdf", b)
+	if err != nil || name != "manual.pdf" || !bytes.Equal(got, b) {
+		t.Errorf("Optimize() = %q, %s, %v", got, name, err)
+	}
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	if got := jpegOrientation(withExif(t, newImage(4, 2), 8)); got != 8 {
+		t.Errorf("jpegOrientation() = %d, want 8", got)
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, newImage(4, 2), nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := jpegOrientation(buf.Bytes()); got != 0 {
+		t.Errorf("jpegOrientation() = %d, want 0", got)
+	}
+}
@@ -0,0 +1,138 @@
+package importer
+
+import (
+	"html"
+	"io"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Attachment is a file of the export referenced by a page.
+type Attachment struct {
+	// Src is the slash-separated path of the file relative to the root of the export.
+	Src string
+	// Path is the slash-separated path of the file relative to the page as referenced in the body.
+	Path string
+}
+
+// ReadConfluence reads the pages of a Confluence space exported as HTML in the directory or the zip archive.
+// The space overview and the assets of the export are skipped.
+func ReadConfluence(src string) ([]*Document, error) {
+	var docs []*Document
+	err := walk(src, func(p string, r io.Reader) error {
+		ext := strings.ToLower(path.Ext(p))
+		if ext != ".html" || path.Base(p) == "index.html" || isConfluenceAsset(p) {
+			return nil
+		}
+		doc, err := ParseConfluence(p, r)
+		if err != nil {
+			return err
+		}
+		docs = append(docs, doc)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].Path < docs[j].Path })
+	return docs, nil
+}
+
+// ParseConfluence extracts the title, the ancestors, the body and the attachments of a page of
+// the Confluence HTML export. The title is taken from the title heading without the space name,
+// and the ancestors from the breadcrumbs.
+func ParseConfluence(p string, r io.Reader) (*Document, error) {
+	dom, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	title := strings.TrimSpace(dom.Find("#title-text").First().Text())
+	if title == "" {
+		title = strings.TrimSpace(dom.Find("head title").First().Text())
+	}
+	// the title is prefixed with the name of the space.
+	if _, after, ok := strings.Cut(title, " : "); ok {
+		title = strings.TrimSpace(after)
+	}
+	if title == "" {
+		base := path.Base(p)
+		title = strings.TrimSuffix(base, path.Ext(base))
+	}
+
+	var tree []string
+	dom.Find("#breadcrumbs a").Each(func(_ int, a *goquery.Selection) {
+		if href, _ := a.Attr("href"); href == "index.html" {
+			return
+		}
+		tree = append(tree, strings.TrimSpace(a.Text()))
+	})
+
+	body := dom.Find("#main-content").First()
+	if body.Length() == 0 {
+		body = dom.Find("body")
+	}
+	normalizeConfluenceCode(body)
+
+	var attachments []Attachment
+	seen := map[string]bool{}
+	body.Find("img[src], a[href]").Each(func(_ int, s *goquery.Selection) {
+		ref := s.AttrOr("src", s.AttrOr("href", ""))
+		u, err := url.Parse(ref)
+		if err != nil || u.IsAbs() || u.Path == "" {
+			return
+		}
+		// the paths escaping the attachments directory would be copied outside the output directory.
+		local := path.Clean(u.Path)
+		if !strings.HasPrefix(local, "attachments/") || seen[local] {
+			return
+		}
+		seen[local] = true
+		attachments = append(attachments, Attachment{Src: path.Join(path.Dir(p), local), Path: local})
+	})
+
+	b, err := body.Html()
+	if err != nil {
+		return nil, err
+	}
+	return &Document{
+		Path:        p,
+		Title:       title,
+		Tree:        tree,
+		Body:        strings.TrimSpace(b),
+		Attachments: attachments,
+	}, nil
+}
+
+// normalizeConfluenceCode replaces the code macros with plain code blocks keeping the language.
+func normalizeConfluenceCode(s *goquery.Selection) {
+	s.Find("div.code.panel").Each(func(_ int, panel *goquery.Selection) {
+		pre := panel.Find("pre").First()
+		lang := ""
+		for _, param := range strings.Split(pre.AttrOr("data-syntaxhighlighter-params", ""), ";") {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(param), "brush:"); ok {
+				lang = strings.TrimSpace(v)
+			}
+		}
+		class := ""
+		if lang != "" {
+			class = ` class="language-` + lang + `"`
+		}
+		panel.ReplaceWithHtml("<pre><code" + class + ">" + html.EscapeString(pre.Text()) + "</code></pre>")
+	})
+}
+
+// isConfluenceAsset reports whether the file belongs to the assets of the export rather than the pages.
+func isConfluenceAsset(p string) bool {
+	for _, dir := range strings.Split(path.Dir(p), "/") {
+		switch dir {
+		case "attachments", "images", "styles":
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadConfluence(t *testing.T) {
+	docs, err := ReadConfluence("testdata/confluence")
+	if err != nil {
+		t.Fatalf("ReadConfluence() failed: %v", err)
+	}
+	expected := []Document{
+		{
+			Path:  "DOC/Home_65538.html",
+			Title: "Home",
+			Body:  "<p>Welcome to the documentation.</p>",
+		},
+		{
+			Path:  "DOC/Setup_65540.html",
+			Title: "Setup : Linux",
+			Tree:  []string{"Home", "Guides"},
+			Body: `<p>Install the package.</p><p><span class="confluence-embedded-file-wrapper"><img class="confluence-embedded-image" src="attachments/65540/65541.png" data-image-src="attachments/65540/65541.png"/></span></p>` +
+				`<pre><code class="language-bash">apt install foo &amp;&amp; foo --version</code></pre><p><a href="attachments/65540/65542.pdf?api=v2">Manual</a></p>`,
+			Attachments: []Attachment{
+				{Src: "DOC/attachments/65540/65541.png", Path: "attachments/65540/65541.png"},
+				{Src: "DOC/attachments/65540/65542.pdf", Path: "attachments/65540/65542.pdf"},
+			},
+		},
+	}
+	if len(docs) != len(expected) {
+		t.Fatalf("ReadConfluence() failed: got %d documents, want %d", len(docs), len(expected))
+	}
+	for i, doc := range docs {
+		if !reflect.DeepEqual(*doc, expected[i]) {
+			t.Errorf("ReadConfluence() failed:\ngot  %+v\nwant %+v", *doc, expected[i])
+		}
+	}
+}
+
+func TestExtract(t *testing.T) {
+	dir := t.TempDir()
+	dest := filepath.Join(dir, "guides", "attachments", "65541.png")
+	missing, err := Extract("testdata/confluence", map[string]string{
+		"DOC/attachments/65540/65541.png": dest,
+		"DOC/attachments/65540/65542.pdf": filepath.Join(dir, "65542.pdf"),
+	})
+	if err != nil {
+		t.Fatalf("Extract() failed: %v", err)
+	}
+	if !reflect.DeepEqual(missing, []string{"DOC/attachments/65540/65542.pdf"}) {
+		t.Errorf("Extract() missing = %v", missing)
+	}
+	b, err := os.ReadFile(dest)
+	if err != nil || string(b) != "png" {
+		t.Errorf("Extract() copied %q, %v", b, err)
+	}
+}
+
+func TestParseConfluenceEscapingAttachment(t *testing.T) {
+	page := `<html><body><div id="main-content"><img src="attachments/../../../../x"><a href="attachments/65540/../65541.png">a</a></div></body></html>`
+	doc, err := ParseConfluence("DOC/Page_1.html", strings.NewReader(page))
+	if err != nil {
+		t.Fatalf("ParseConfluence() failed: %v", err)
+	}
+	expected := []Attachment{{Src: "DOC/attachments/65541.png", Path: "attachments/65541.png"}}
+	if !reflect.DeepEqual(doc.Attachments, expected) {
+		t.Errorf("ParseConfluence() attachments = %+v, want %+v", doc.Attachments, expected)
+	}
+}
@@ -0,0 +1,176 @@
+package importer
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Document is a page read from an export to be converted into a translation.
+type Document struct {
+	// Path is the slash-separated path of the page relative to the root of the export.
+	Path  string
+	Title string
+	// Tree is the titles of the ancestor pages from the root, if the export has a page tree.
+	Tree []string
+	// Body is the HTML of the page body, or the Markdown if Markdown is true.
+	Body     string
+	Markdown bool
+	// Attachments are the files referenced by the body to be copied next to the translation.
+	Attachments []Attachment
+}
+
+// ReadHTML reads every HTML page in the directory or the zip archive.
+func ReadHTML(src string) ([]*Document, error) {
+	var docs []*Document
+	err := walk(src, func(p string, r io.Reader) error {
+		ext := strings.ToLower(path.Ext(p))
+		if ext != ".html" && ext != ".htm" {
+			return nil
+		}
+		doc, err := ParseHTML(p, r)
+		if err != nil {
+			return err
+		}
+		docs = append(docs, doc)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].Path < docs[j].Path })
+	return docs, nil
+}
+
+// ParseHTML extracts the title and the body of an HTML page.
+// The title is taken from <title>, or from the first <h1> when <title> is missing.
+// The <h1> used as or identical to the title is removed from the body.
+func ParseHTML(p string, r io.Reader) (*Document, error) {
+	dom, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	title := strings.TrimSpace(dom.Find("head title").First().Text())
+	h1 := dom.Find("h1").First()
+	if h1.Length() > 0 {
+		h1Text := strings.TrimSpace(h1.Text())
+		if title == "" {
+			title = h1Text
+		}
+		if h1Text == title {
+			h1.Remove()
+		}
+	}
+	if title == "" {
+		base := path.Base(p)
+		title = strings.TrimSuffix(base, path.Ext(base))
+	}
+
+	body := dom.Find("body")
+	if body.Length() == 0 {
+		body = dom.Selection
+	}
+	html, err := body.Html()
+	if err != nil {
+		return nil, err
+	}
+	return &Document{Path: p, Title: title, Body: strings.TrimSpace(html)}, nil
+}
+
+// Extract copies the files of the directory or the zip archive to the destinations keyed by their
+// slash-separated relative paths. The caller checks that the destinations are in the output directory.
+// It returns the paths of the files not found in the export.
+func Extract(src string, files map[string]string) ([]string, error) {
+	found := map[string]bool{}
+	err := walk(src, func(p string, r io.Reader) error {
+		dest, ok := files[p]
+		if !ok {
+			return nil
+		}
+		found[p] = true
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			return err
+		}
+		f, err := os.Create(dest)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, r); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	})
+	if err != nil {
+		return nil, err
+	}
+	var missing []string
+	for p := range files {
+		if !found[p] {
+			missing = append(missing, p)
+		}
+	}
+	sort.Strings(missing)
+	return missing, nil
+}
+
+// walk calls fn for every file in the directory or the zip archive with its slash-separated relative path.
+func walk(src string, fn func(p string, r io.Reader) error) error {
+	fi, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	if !fi.IsDir() {
+		zr, err := zip.OpenReader(src)
+		if err != nil {
+			return err
+		}
+		defer zr.Close()
+		for _, zf := range zr.File {
+			if zf.FileInfo().IsDir() {
+				continue
+			}
+			// the entries outside the root of the archive would be written outside the output directory.
+			name := path.Clean(zf.Name)
+			if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+				return fmt.Errorf("%s: the file is outside the archive", zf.Name)
+			}
+			rc, err := zf.Open()
+			if err != nil {
+				return err
+			}
+			err = fn(name, rc)
+			rc.Close()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return fn(filepath.ToSlash(rel), f)
+	})
+}
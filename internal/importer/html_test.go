@@ -0,0 +1,94 @@
+package importer
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadHTML(t *testing.T) {
+	zipPath := filepath.Join(t.TempDir(), "export.zip")
+	createZip(t, zipPath, "testdata/html", []string{"index.html", "guides/setup.htm", "guides/notes.txt"})
+
+	for _, src := range []string{"testdata/html", zipPath} {
+		t.Run(src, func(t *testing.T) {
+			docs, err := ReadHTML(src)
+			if err != nil {
+				t.Fatalf("ReadHTML() failed: %v", err)
+			}
+			expected := []Document{
+				{Path: "guides/setup.htm", Title: "Setup guide", Body: "<h2>Install</h2>\n<p>Run the installer.</p>"},
+				{Path: "index.html", Title: "Welcome", Body: "<p>Hello, <strong>world</strong>.</p>"},
+			}
+			if len(docs) != len(expected) {
+				t.Fatalf("ReadHTML() failed: got %d documents, want %d", len(docs), len(expected))
+			}
+			for i, doc := range docs {
+				if doc.Path != expected[i].Path {
+					t.Errorf("Document.Path failed: got %v, want %v", doc.Path, expected[i].Path)
+				}
+				if doc.Title != expected[i].Title {
+					t.Errorf("Document.Title failed: got %v, want %v", doc.Title, expected[i].Title)
+				}
+				if doc.Body != expected[i].Body {
+					t.Errorf("Document.Body failed: got %q, want %q", doc.Body, expected[i].Body)
+				}
+			}
+		})
+	}
+}
+
+func TestReadHTMLOutsideArchive(t *testing.T) {
+	for _, name := range []string{"../../../home/u/.bashrc.html", "/etc/motd.html", "guides/../../index.html"} {
+		t.Run(name, func(t *testing.T) {
+			zipPath := filepath.Join(t.TempDir(), "export.zip")
+			f, err := os.Create(zipPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			zw := zip.NewWriter(f)
+			w, err := zw.Create(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := w.Write([]byte("<h1>Evil</h1>")); err != nil {
+				t.Fatal(err)
+			}
+			if err := zw.Close(); err != nil {
+				t.Fatal(err)
+			}
+			f.Close()
+
+			if _, err := ReadHTML(zipPath); err == nil {
+				t.Errorf("ReadHTML() should fail for %s", name)
+			}
+		})
+	}
+}
+
+func createZip(t *testing.T, zipPath string, root string, files []string) {
+	t.Helper()
+	f, err := os.Create(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	for _, name := range files {
+		b, err := os.ReadFile(filepath.Join(root, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
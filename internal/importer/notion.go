@@ -0,0 +1,201 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// notionID matches the ID Notion appends to the names of the exported pages.
+	notionID       = regexp.MustCompile(`\s+[0-9a-f]{32}$`)
+	notionLink     = regexp.MustCompile(`(!?)\[([^\]]*)\]\(([^)\s]+)\)`)
+	notionProperty = regexp.MustCompile(`^[^\s:][^:]*: `)
+	notionSummary  = regexp.MustCompile(`^\s*<summary>(.*)</summary>\s*$`)
+)
+
+// ReadNotion reads the pages of a Notion workspace exported as Markdown and CSV in the directory or
+// the zip archive. The pages are Markdown, and the databases are read through the pages of their rows.
+func ReadNotion(src string) ([]*Document, error) {
+	pages := map[string]string{}
+	databases := map[string]bool{}
+	err := walk(src, func(p string, r io.Reader) error {
+		switch strings.ToLower(path.Ext(p)) {
+		case ".md":
+			b, err := io.ReadAll(r)
+			if err != nil {
+				return err
+			}
+			pages[p] = string(b)
+		case ".csv":
+			databases[strings.TrimSuffix(p, path.Ext(p))] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make([]*Document, 0, len(pages))
+	for p, content := range pages {
+		// the rows of a database are placed in the directory named after the database.
+		doc, err := ParseNotion(p, content, databases[path.Dir(p)])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+		docs = append(docs, doc)
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].Path < docs[j].Path })
+	return docs, nil
+}
+
+// ParseNotion extracts the title, the ancestors, the body and the attachments of a page of the Notion
+// Markdown export. The properties listed below the title of a database row are removed, callouts are
+// converted into blockquotes, toggles into bold paragraphs, the attachments are moved into the
+// attachments directory, and the links to the other pages and the databases of the export are
+// replaced with their text as they have no URLs in the help center.
+func ParseNotion(p string, content string, row bool) (*Document, error) {
+	base := path.Base(p)
+	title := notionName(strings.TrimSuffix(base, path.Ext(base)))
+
+	var tree []string
+	if dir := path.Dir(p); dir != "." {
+		for _, name := range strings.Split(dir, "/") {
+			tree = append(tree, notionName(name))
+		}
+	}
+
+	var (
+		lines       []string
+		attachments []Attachment
+		seen        = map[string]bool{}
+		fenced      bool
+		callout     bool
+		heading     = true
+		properties  = row
+		listed      bool
+	)
+	sc := bufio.NewScanner(strings.NewReader(content))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := sc.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if heading {
+			if trimmed == "" {
+				continue
+			}
+			heading = false
+			if h, ok := strings.CutPrefix(trimmed, "# "); ok {
+				title = strings.TrimSpace(h)
+				continue
+			}
+		}
+		// the properties are the lines following the title up to the first blank line.
+		if properties {
+			if trimmed == "" && !listed {
+				continue
+			}
+			if trimmed != "" && notionProperty.MatchString(line) {
+				listed = true
+				continue
+			}
+			properties = false
+			if trimmed == "" {
+				continue
+			}
+		}
+
+		if strings.HasPrefix(trimmed, "```") {
+			fenced = !fenced
+		}
+		if fenced || strings.HasPrefix(trimmed, "```") {
+			lines = append(lines, line)
+			continue
+		}
+
+		switch {
+		case trimmed == "<aside>":
+			callout = true
+			continue
+		case trimmed == "</aside>":
+			callout = false
+			// drop the trailing blank lines of the callout.
+			for len(lines) > 0 && lines[len(lines)-1] == ">" {
+				lines = lines[:len(lines)-1]
+			}
+			lines = append(lines, "")
+			continue
+		case trimmed == "<details>" || trimmed == "</details>":
+			continue
+		}
+		if m := notionSummary.FindStringSubmatch(line); m != nil {
+			line = "**" + strings.TrimSpace(m[1]) + "**"
+		}
+
+		line = notionLink.ReplaceAllStringFunc(line, func(link string) string {
+			m := notionLink.FindStringSubmatch(link)
+			u, err := url.Parse(m[3])
+			if err != nil || u.IsAbs() || u.Path == "" || strings.HasPrefix(m[3], "#") {
+				return link
+			}
+			ref := u.Path
+			switch strings.ToLower(path.Ext(ref)) {
+			case ".md", ".csv":
+				return m[2]
+			}
+			// the parent elements are dropped, so that the attachment stays in the attachments directory.
+			var elems []string
+			for _, e := range strings.Split(ref, "/") {
+				if e != "" && e != "." && e != ".." {
+					elems = append(elems, e)
+				}
+			}
+			if len(elems) == 0 {
+				return link
+			}
+			for i := range elems[:len(elems)-1] {
+				elems[i] = notionName(elems[i])
+			}
+			local := path.Join(append([]string{"attachments"}, elems...)...)
+			src := path.Join(path.Dir(p), ref)
+			if !seen[src] {
+				seen[src] = true
+				attachments = append(attachments, Attachment{Src: src, Path: local})
+			}
+			return m[1] + "[" + m[2] + "](" + (&url.URL{Path: local}).EscapedPath() + ")"
+		})
+
+		if callout {
+			if trimmed == "" {
+				line = ">"
+			} else {
+				line = "> " + line
+			}
+		}
+		lines = append(lines, line)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	body := strings.TrimSpace(strings.Join(lines, "\n"))
+	return &Document{
+		Path:        p,
+		Title:       title,
+		Tree:        tree,
+		Body:        body,
+		Markdown:    true,
+		Attachments: attachments,
+	}, nil
+}
+
+// notionName returns the name of the exported file or directory without the ID.
+func notionName(name string) string {
+	return notionID.ReplaceAllString(name, "")
+}
@@ -0,0 +1,65 @@
+package importer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadNotion(t *testing.T) {
+	docs, err := ReadNotion("testdata/notion")
+	if err != nil {
+		t.Fatalf("ReadNotion() failed: %v", err)
+	}
+	expected := []Document{
+		{
+			Path:     "Guides 0123456789abcdef0123456789abcdef.md",
+			Title:    "Guides",
+			Body:     "Start with the Setup page, or see the Tasks.\n\n> 💡 Read the [FAQ](https://example.com/faq) first.",
+			Markdown: true,
+		},
+		{
+			Path:     "Guides 0123456789abcdef0123456789abcdef/Setup fedcba9876543210fedcba9876543210.md",
+			Title:    "Setup",
+			Tree:     []string{"Guides"},
+			Body:     "![Screenshot](attachments/Setup/screen.png)\n\n**Advanced**\n\nRun `setup --advanced`.\n\n\n```markdown\n[Not a link](Other%200123456789abcdef0123456789abcdef.md)\n```",
+			Markdown: true,
+			Attachments: []Attachment{
+				{
+					Src:  "Guides 0123456789abcdef0123456789abcdef/Setup fedcba9876543210fedcba9876543210/screen.png",
+					Path: "attachments/Setup/screen.png",
+				},
+			},
+		},
+		{
+			Path:     "Tasks 89abcdef0123456789abcdef01234567/Write docs 76543210fedcba9876543210fedcba98.md",
+			Title:    "Write docs",
+			Tree:     []string{"Tasks"},
+			Body:     "Note: the docs are written in Markdown.",
+			Markdown: true,
+		},
+	}
+	if len(docs) != len(expected) {
+		t.Fatalf("ReadNotion() failed: got %d documents, want %d", len(docs), len(expected))
+	}
+	for i, doc := range docs {
+		if !reflect.DeepEqual(*doc, expected[i]) {
+			t.Errorf("ReadNotion() failed:\ngot  %#v\nwant %#v", *doc, expected[i])
+		}
+	}
+}
+
+func TestParseNotionEscapingAttachment(t *testing.T) {
+	doc, err := ParseNotion("Page 0123456789abcdef0123456789abcdef.md", "# Page\n\n![x](../../../x.png) [y](Page/../../y.pdf)\n", false)
+	if err != nil {
+		t.Fatalf("ParseNotion() failed: %v", err)
+	}
+	for _, a := range doc.Attachments {
+		if !strings.HasPrefix(a.Path, "attachments/") || strings.Contains(a.Path, "..") {
+			t.Errorf("ParseNotion() attachment escapes the attachments directory: %+v", a)
+		}
+	}
+	if len(doc.Attachments) != 2 {
+		t.Errorf("ParseNotion() attachments = %+v", doc.Attachments)
+	}
+}
@@ -0,0 +1,78 @@
+// Package metrics provides the counters of the sync pipeline exposed in the Prometheus text format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// Counter is a monotonically increasing value.
+type Counter struct {
+	name  string
+	help  string
+	value atomic.Uint64
+}
+
+func (c *Counter) Inc() {
+	c.value.Add(1)
+}
+
+func (c *Counter) Value() uint64 {
+	return c.value.Load()
+}
+
+// Registry is a set of counters written in the order of registration.
+type Registry struct {
+	mu       sync.Mutex
+	counters []*Counter
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// NewCounter registers a counter with the name and the help text.
+func (r *Registry) NewCounter(name, help string) *Counter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c := &Counter{name: name, help: help}
+	r.counters = append(r.counters, c)
+	return c
+}
+
+// WriteTo writes the counters in the Prometheus text exposition format.
+// refs: https://prometheus.io/docs/instrumenting/exposition_formats/#text-based-format
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var total int64
+	for _, c := range r.counters {
+		n, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n%s %d\n", c.name, c.help, c.name, c.name, c.Value())
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// Handler returns the handler serving the counters of the registry.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_, _ = r.WriteTo(w)
+	})
+}
+
+var Default = NewRegistry()
+
+var (
+	Pushes             = Default.NewCounter("zgsync_pushes_total", "Number of articles and translations pushed to Zendesk.")
+	Pulls              = Default.NewCounter("zgsync_pulls_total", "Number of articles and translations pulled from Zendesk.")
+	APIErrors          = Default.NewCounter("zgsync_api_errors_total", "Number of failed requests to the Zendesk API.")
+	RateLimits         = Default.NewCounter("zgsync_rate_limited_total", "Number of requests to the Zendesk API rejected by the rate limit.")
+	ConversionFailures = Default.NewCounter("zgsync_conversion_failures_total", "Number of failures converting between Markdown and HTML.")
+)
@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistryHandler(t *testing.T) {
+	r := NewRegistry()
+	pushes := r.NewCounter("test_pushes_total", "Number of pushes.")
+	r.NewCounter("test_errors_total", "Number of errors.")
+	pushes.Inc()
+	pushes.Inc()
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	expected := strings.Join([]string{
+		"# HELP test_pushes_total Number of pushes.",
+		"# TYPE test_pushes_total counter",
+		"test_pushes_total 2",
+		"# HELP test_errors_total Number of errors.",
+		"# TYPE test_errors_total counter",
+		"test_errors_total 0",
+		"",
+	}, "\n")
+	if got := rec.Body.String(); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("unexpected content type %q", ct)
+	}
+}
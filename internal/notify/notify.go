@@ -0,0 +1,84 @@
+// Package notify posts the summary of a sync to Slack or a generic HTTP endpoint.
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	ActionCreated = "created"
+	ActionUpdated = "updated"
+)
+
+// Result is a file synced with Zendesk.
+type Result struct {
+	File      string `json:"file"`
+	Action    string `json:"action"`
+	ArticleID int    `json:"article_id,omitempty"`
+	Locale    string `json:"locale,omitempty"`
+	Title     string `json:"title,omitempty"`
+	URL       string `json:"url,omitempty"`
+}
+
+// Summary is the payload posted to the generic endpoint.
+type Summary struct {
+	Command   string   `json:"command"`
+	Subdomain string   `json:"subdomain"`
+	Results   []Result `json:"results"`
+	Errors    []string `json:"errors,omitempty"`
+}
+
+// Text returns the summary as a plain text message.
+func (s *Summary) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "zgsync %s to %s: %d files", s.Command, s.Subdomain, len(s.Results))
+	if len(s.Errors) > 0 {
+		fmt.Fprintf(&b, ", %d errors", len(s.Errors))
+	}
+	b.WriteString("\n")
+	for _, r := range s.Results {
+		if r.URL != "" {
+			fmt.Fprintf(&b, "%s: %s (%s)\n", r.Action, r.File, r.URL)
+		} else {
+			fmt.Fprintf(&b, "%s: %s\n", r.Action, r.File)
+		}
+	}
+	for _, e := range s.Errors {
+		fmt.Fprintf(&b, "error: %s\n", e)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+var client = &http.Client{Timeout: 10 * time.Second}
+
+// Slack posts the summary as a message to the Slack incoming webhook.
+// refs: https://api.slack.com/messaging/webhooks
+func Slack(url string, s *Summary) error {
+	return post(url, map[string]string{"text": s.Text()})
+}
+
+// Webhook posts the summary as JSON to the endpoint.
+func Webhook(url string, s *Summary) error {
+	return post(url, s)
+}
+
+func post(url string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	res, err := client.Post(url, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+	return nil
+}
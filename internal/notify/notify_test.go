@@ -0,0 +1,84 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newSummary() *Summary {
+	return &Summary{
+		Command:   "push",
+		Subdomain: "example",
+		Results: []Result{
+			{File: "123-ja.md", Action: ActionUpdated, URL: "https://example.zendesk.com/hc/ja/articles/123"},
+			{File: "new.md", Action: ActionCreated},
+		},
+		Errors: []string{"unexpected status code: 404"},
+	}
+}
+
+func TestSummaryText(t *testing.T) {
+	expected := `zgsync push to example: 2 files, 1 errors
+updated: 123-ja.md (https://example.zendesk.com/hc/ja/articles/123)
+created: new.md
+error: unexpected status code: 404`
+	if got := newSummary().Text(); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestSend(t *testing.T) {
+	tests := []struct {
+		name     string
+		send     func(url string, s *Summary) error
+		status   int
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "slack",
+			send:     Slack,
+			status:   http.StatusOK,
+			expected: `{"text":"zgsync push to example: 2 files, 1 errors\nupdated: 123-ja.md (https://example.zendesk.com/hc/ja/articles/123)\ncreated: new.md\nerror: unexpected status code: 404"}`,
+		},
+		{
+			name:     "webhook",
+			send:     Webhook,
+			status:   http.StatusNoContent,
+			expected: `{"command":"push","subdomain":"example","results":[{"file":"123-ja.md","action":"updated","url":"https://example.zendesk.com/hc/ja/articles/123"},{"file":"new.md","action":"created"}],"errors":["unexpected status code: 404"]}`,
+		},
+		{
+			name:    "error status",
+			send:    Webhook,
+			status:  http.StatusInternalServerError,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body []byte
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ = io.ReadAll(r.Body)
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			err := tt.send(srv.URL, newSummary())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !json.Valid(body) {
+				t.Fatalf("invalid JSON: %s", body)
+			}
+			if string(body) != tt.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, body)
+			}
+		})
+	}
+}
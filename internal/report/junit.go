@@ -0,0 +1,80 @@
+package report
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string         `xml:"name,attr"`
+	ClassName string         `xml:"classname,attr"`
+	Failures  []junitFailure `xml:"failure"`
+	SystemOut string         `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes a JUnit XML report with a test case per file.
+// Errors are reported as failures, and warnings are written to the system-out of the test case.
+func WriteJUnit(w io.Writer, tool string, files []string, diags []Diagnostic) error {
+	byFile := map[string][]Diagnostic{}
+	for _, d := range diags {
+		byFile[d.File] = append(byFile[d.File], d)
+	}
+
+	suite := junitTestSuite{Name: tool}
+	for _, file := range files {
+		tc := junitTestCase{Name: file, ClassName: tool}
+		var warnings []string
+		for _, d := range byFile[file] {
+			if d.Severity != SeverityError {
+				warnings = append(warnings, d.String())
+				continue
+			}
+			tc.Failures = append(tc.Failures, junitFailure{Message: d.Message, Type: d.Rule, Text: d.String()})
+		}
+		tc.SystemOut = strings.Join(warnings, "\n")
+		if len(tc.Failures) > 0 {
+			suite.Failures++
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	suite.Tests = len(suite.Cases)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	err := enc.Encode(junitTestSuites{
+		Name:     tool,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Suites:   []junitTestSuite{suite},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
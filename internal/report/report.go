@@ -0,0 +1,60 @@
+// Package report writes the diagnostics of the validate command in the formats understood by CI systems.
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+const (
+	FormatJUnit = "junit"
+	FormatSARIF = "sarif"
+)
+
+// Diagnostic is a problem found in a file.
+type Diagnostic struct {
+	File     string
+	Line     int
+	Rule     string
+	Severity string
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s:%d: %s: %s [%s]", d.File, d.Line, d.Severity, d.Message, d.Rule)
+}
+
+// Rule describes a check producing diagnostics.
+type Rule struct {
+	ID          string
+	Description string
+}
+
+// FormatOf returns the format of the report file inferred from its extension.
+func FormatOf(path string) (string, error) {
+	lower := strings.ToLower(path)
+	switch {
+	case lower == FormatJUnit || strings.HasSuffix(lower, ".xml"):
+		return FormatJUnit, nil
+	case lower == FormatSARIF || strings.HasSuffix(lower, ".sarif") || strings.HasSuffix(lower, ".json"):
+		return FormatSARIF, nil
+	}
+	return "", fmt.Errorf("cannot infer the report format of %s", path)
+}
+
+// Write writes the report in the format. Files are the checked files including the ones without diagnostics.
+func Write(w io.Writer, format string, tool string, version string, rules []Rule, files []string, diags []Diagnostic) error {
+	switch format {
+	case FormatJUnit:
+		return WriteJUnit(w, tool, files, diags)
+	case FormatSARIF:
+		return WriteSARIF(w, tool, version, rules, diags)
+	}
+	return fmt.Errorf("unknown report format %s", format)
+}
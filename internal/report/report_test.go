@@ -0,0 +1,83 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+var testDiags = []Diagnostic{
+	{File: "guides/intro-ja.md", Line: 2, Rule: "missing-title", Severity: SeverityError, Message: "title is required"},
+	{File: "guides/intro-ja.md", Line: 1, Rule: "style", Severity: SeverityWarning, Message: "too long"},
+}
+
+var testRules = []Rule{
+	{ID: "missing-title", Description: "Articles must have a title."},
+	{ID: "style", Description: "Style."},
+}
+
+func TestFormatOf(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+		notError bool
+	}{
+		{"junit", FormatJUnit, true},
+		{"report/junit.xml", FormatJUnit, true},
+		{"sarif", FormatSARIF, true},
+		{"results.sarif", FormatSARIF, true},
+		{"results.json", FormatSARIF, true},
+		{"results.txt", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			actual, err := FormatOf(tt.path)
+			if tt.notError == (err != nil) {
+				t.Fatalf("FormatOf() failed: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("FormatOf() failed: got %v, want %v", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJUnit(&buf, "zgsync", []string{"guides/intro-ja.md", "guides/setup-ja.md"}, testDiags); err != nil {
+		t.Fatalf("WriteJUnit() failed: %v", err)
+	}
+	expected, err := os.ReadFile("testdata/junit.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != string(expected) {
+		t.Errorf("WriteJUnit() failed: got %s, want %s", buf.String(), string(expected))
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, "zgsync", "1.0.0", testRules, testDiags); err != nil {
+		t.Fatalf("WriteSARIF() failed: %v", err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("WriteSARIF() failed: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("WriteSARIF() failed: got %s", buf.String())
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != 2 || len(run.Results) != 2 {
+		t.Fatalf("WriteSARIF() failed: got %s", buf.String())
+	}
+	r := run.Results[0]
+	if r.RuleID != "missing-title" || r.Level != "error" || r.Locations[0].PhysicalLocation.ArtifactLocation.URI != "guides/intro-ja.md" || r.Locations[0].PhysicalLocation.Region.StartLine != 2 {
+		t.Errorf("WriteSARIF() failed: got %+v", r)
+	}
+	if run.Results[1].Level != "warning" {
+		t.Errorf("WriteSARIF() failed: got %+v", run.Results[1])
+	}
+}
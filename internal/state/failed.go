@@ -0,0 +1,96 @@
+package state
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+const FailedFileName = "failed.json"
+
+// Failed is the set of the items that the last runs of push and pull did not complete,
+// persisted in {contents_dir}/.zgsync/failed.json to be retried.
+type Failed struct {
+	Push FailedItems `json:"push,omitempty"`
+	Pull FailedItems `json:"pull,omitempty"`
+	path string
+}
+
+// FailedItem is a file that failed to push, keyed like the sync state, or an article that failed to pull.
+// Error is empty for the items left by the run stopped at another failure.
+type FailedItem struct {
+	File      string `json:"file,omitempty"`
+	ArticleID int    `json:"article_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+type FailedItems []FailedItem
+
+// LoadFailed loads the failed items of a named environment, which are kept in .zgsync/failed-{name}.json.
+// An empty name refers to the default failed.json.
+func LoadFailed(contentsDir string, name string) (*Failed, error) {
+	root, err := filepath.Abs(contentsDir)
+	if err != nil {
+		return nil, err
+	}
+	f := &Failed{path: filepath.Join(root, DirName, FailedFileName)}
+	if name != "" {
+		f.path = filepath.Join(root, DirName, "failed-"+name+".json")
+	}
+
+	b, err := os.ReadFile(f.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return f, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(b, f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *Failed) Path() string {
+	return f.path
+}
+
+// Save writes the failed items, or removes the file if there is none.
+func (f *Failed) Save() error {
+	if len(f.Push) == 0 && len(f.Pull) == 0 {
+		if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(f.path, append(b, '\n'), 0o644)
+}
+
+// Put adds the item, replacing the item of the same file and article.
+func (l *FailedItems) Put(item FailedItem) {
+	for i, it := range *l {
+		if it.File == item.File && it.ArticleID == item.ArticleID {
+			(*l)[i] = item
+			return
+		}
+	}
+	*l = append(*l, item)
+}
+
+// Delete removes the item of the file and the article.
+func (l *FailedItems) Delete(file string, articleID int) {
+	items := (*l)[:0]
+	for _, it := range *l {
+		if it.File != file || it.ArticleID != articleID {
+			items = append(items, it)
+		}
+	}
+	*l = items
+}
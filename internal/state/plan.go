@@ -0,0 +1,88 @@
+package state
+
+// Action is what a sync does with a file.
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionSkip   Action = "skip"
+)
+
+// Candidate is a file compared with the sync state to plan a push or a pull.
+type Candidate struct {
+	Path string
+	// Hash is the hash of the local content: the payload hash for a push and the file hash for a pull.
+	// It is empty when the local file does not exist.
+	Hash string
+	// RemoteExists reports whether the remote object exists.
+	RemoteExists bool
+	// RemoteUpdatedAt is updated_at of the remote object, which is empty when it is not known.
+	RemoteUpdatedAt string
+}
+
+// Step is the action planned for a file with the reason for it.
+type Step struct {
+	Path   string
+	Action Action
+	Reason string
+}
+
+// Plan is the steps of a sync in the order of the candidates.
+type Plan []Step
+
+// Count returns the number of the steps of the action.
+func (p Plan) Count(action Action) int {
+	n := 0
+	for _, step := range p {
+		if step.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// PlanPush plans the push of the candidates. The remote objects that do not exist are created, and the files whose
+// payload is identical to the one last pushed are skipped.
+func (s *Store) PlanPush(candidates ...Candidate) Plan {
+	plan := make(Plan, 0, len(candidates))
+	for _, c := range candidates {
+		step := Step{Path: c.Path, Action: ActionUpdate, Reason: "changed since the last push"}
+		e, ok := s.Lookup(c.Path)
+		switch {
+		case !c.RemoteExists:
+			step.Action, step.Reason = ActionCreate, "not on the remote"
+		case !ok || e.PushedHash == "":
+			step.Reason = "not pushed yet"
+		case e.PushedHash == c.Hash:
+			step.Action, step.Reason = ActionSkip, "unchanged since the last push"
+		}
+		plan = append(plan, step)
+	}
+	return plan
+}
+
+// PlanPull plans the pull of the candidates. The local files that do not exist are created, and the files are skipped
+// when neither the remote object nor the file has changed since the last pull. The remote objects whose updated_at
+// is not known are always pulled.
+func (s *Store) PlanPull(candidates ...Candidate) Plan {
+	plan := make(Plan, 0, len(candidates))
+	for _, c := range candidates {
+		step := Step{Path: c.Path, Action: ActionUpdate}
+		e, ok := s.Lookup(c.Path)
+		switch {
+		case c.Hash == "":
+			step.Action, step.Reason = ActionCreate, "no local file"
+		case !ok || e.PulledHash == "":
+			step.Reason = "not pulled yet"
+		case c.RemoteUpdatedAt == "" || e.RemoteUpdatedAt != c.RemoteUpdatedAt:
+			step.Reason = "updated on the remote"
+		case e.PulledHash != c.Hash:
+			step.Reason = "changed locally"
+		default:
+			step.Action, step.Reason = ActionSkip, "unchanged since the last pull"
+		}
+		plan = append(plan, step)
+	}
+	return plan
+}
@@ -0,0 +1,215 @@
+package state
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	DirName  = ".zgsync"
+	FileName = "state.json"
+	version  = 1
+)
+
+const (
+	KindArticle     = "article"
+	KindTranslation = "translation"
+	// KindPost is the community post, whose ID is tracked as the article ID.
+	KindPost = "post"
+)
+
+// Entry tracks the sync state of a single local file.
+type Entry struct {
+	Kind            string `json:"kind"`
+	Brand           string `json:"brand,omitempty"`
+	ArticleID       int    `json:"article_id"`
+	Locale          string `json:"locale,omitempty"`
+	SectionID       int    `json:"section_id,omitempty"`
+	PushedHash      string `json:"pushed_hash,omitempty"`
+	PulledHash      string `json:"pulled_hash,omitempty"`
+	RemoteUpdatedAt string `json:"remote_updated_at,omitempty"`
+	PushedAt        string `json:"pushed_at,omitempty"`
+	PulledAt        string `json:"pulled_at,omitempty"`
+	// Assets are the files of the assets directory uploaded for the translation, keyed by their link in the body.
+	Assets map[string]Asset `json:"assets,omitempty"`
+}
+
+// Asset is a local file uploaded as an attachment of the article.
+type Asset struct {
+	Hash string `json:"hash"`
+	URL  string `json:"url"`
+}
+
+// Change is a change note given to a push, with the files pushed by it.
+type Change struct {
+	Time    string   `json:"time"`
+	Message string   `json:"message"`
+	Files   []string `json:"files,omitempty"`
+}
+
+// Store is the local sync state persisted in {contents_dir}/.zgsync/state.json.
+// Files are keyed by their slash-separated path relative to the contents directory.
+type Store struct {
+	Version int               `json:"version"`
+	Files   map[string]*Entry `json:"files"`
+	Cursors map[string]string `json:"cursors,omitempty"`
+	// Changelog is the change notes of the pushes in the order of the pushes.
+	Changelog []Change `json:"changelog,omitempty"`
+	root      string
+	name      string
+}
+
+func Load(contentsDir string) (*Store, error) {
+	return LoadNamed(contentsDir, "")
+}
+
+// LoadNamed loads the state of a named environment, which is kept in .zgsync/state-{name}.json.
+// An empty name refers to the default state.json.
+func LoadNamed(contentsDir string, name string) (*Store, error) {
+	root, err := filepath.Abs(contentsDir)
+	if err != nil {
+		return nil, err
+	}
+	s := &Store{
+		Version: version,
+		Files:   map[string]*Entry{},
+		Cursors: map[string]string{},
+		root:    root,
+		name:    name,
+	}
+
+	b, err := os.ReadFile(s.Path())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, err
+	}
+	if s.Files == nil {
+		s.Files = map[string]*Entry{}
+	}
+	if s.Cursors == nil {
+		s.Cursors = map[string]string{}
+	}
+	return s, nil
+}
+
+func (s *Store) Path() string {
+	if s.name != "" {
+		return filepath.Join(s.root, DirName, "state-"+s.name+".json")
+	}
+	return filepath.Join(s.root, DirName, FileName)
+}
+
+func (s *Store) Save() error {
+	if err := os.MkdirAll(filepath.Dir(s.Path()), 0o755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.Path() + ".tmp"
+	if err := os.WriteFile(tmp, append(b, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.Path())
+}
+
+// Key converts a file path into the key used in the store.
+func (s *Store) Key(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	rel, err := filepath.Rel(s.root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(abs)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// Get returns the entry of the file, creating an empty one if it is not tracked yet.
+func (s *Store) Get(path string) *Entry {
+	key := s.Key(path)
+	e, ok := s.Files[key]
+	if !ok {
+		e = &Entry{}
+		s.Files[key] = e
+	}
+	return e
+}
+
+func (s *Store) Lookup(path string) (*Entry, bool) {
+	e, ok := s.Files[s.Key(path)]
+	return e, ok
+}
+
+// Find returns the key of the file tracking the given article and locale in the help center of the brand.
+// An empty locale matches the article file itself.
+func (s *Store) Find(kind string, brand string, articleID int, locale string) (string, *Entry, bool) {
+	for _, key := range s.Keys() {
+		e := s.Files[key]
+		if e.Kind == kind && e.Brand == brand && e.ArticleID == articleID && e.Locale == locale {
+			return key, e, true
+		}
+	}
+	return "", nil, false
+}
+
+// Move re-keys the entry of a file that was moved or renamed.
+func (s *Store) Move(oldPath, newPath string) {
+	oldKey := s.Key(oldPath)
+	if e, ok := s.Files[oldKey]; ok {
+		delete(s.Files, oldKey)
+		s.Files[s.Key(newPath)] = e
+	}
+}
+
+func (s *Store) Remove(path string) {
+	delete(s.Files, s.Key(path))
+}
+
+// Keys returns the tracked keys in a stable order.
+func (s *Store) Keys() []string {
+	keys := make([]string, 0, len(s.Files))
+	for k := range s.Files {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Abs returns the absolute path of a key.
+func (s *Store) Abs(key string) string {
+	if filepath.IsAbs(key) {
+		return filepath.FromSlash(key)
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key))
+}
+
+func Hash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// PayloadHash returns the hash of a JSON payload, ignoring differences in line endings.
+func PayloadHash(payload string) string {
+	return Hash(strings.ReplaceAll(payload, `\r\n`, `\n`))
+}
+
+func HashFile(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return Hash(string(b)), nil
+}
@@ -0,0 +1,176 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestStoreSaveAndLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	s, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if len(s.Files) != 0 {
+		t.Errorf("Load() failed: got %v entries, want 0", len(s.Files))
+	}
+
+	e := s.Get(filepath.Join(dir, "123", "456-ja.md"))
+	e.Kind = KindTranslation
+	e.ArticleID = 456
+	e.Locale = "ja"
+	e.PushedHash = Hash("body")
+	s.Cursors["pull"] = "2024-01-01T00:00:00Z"
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	s, err = Load(dir)
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	e, ok := s.Lookup(filepath.Join(dir, "123", "456-ja.md"))
+	if !ok {
+		t.Fatalf("Lookup() failed: entry not found")
+	}
+	if e.PushedHash != Hash("body") {
+		t.Errorf("Entry.PushedHash failed: got %v, want %v", e.PushedHash, Hash("body"))
+	}
+	key, _, ok := s.Find(KindTranslation, "", 456, "ja")
+	if !ok || key != "123/456-ja.md" {
+		t.Errorf("Find() failed: got %v, want %v", key, "123/456-ja.md")
+	}
+	if _, _, ok := s.Find(KindTranslation, "other", 456, "ja"); ok {
+		t.Errorf("Find() failed: the file of the other brand is found")
+	}
+	if s.Cursors["pull"] != "2024-01-01T00:00:00Z" {
+		t.Errorf("Store.Cursors failed: got %v", s.Cursors["pull"])
+	}
+}
+
+func TestPayloadHash(t *testing.T) {
+	lf := `{"translation":{"body":"<p>a</p>\n<p>b</p>\n"}}`
+	crlf := `{"translation":{"body":"<p>a</p>\r\n<p>b</p>\r\n"}}`
+	if PayloadHash(lf) != PayloadHash(crlf) {
+		t.Errorf("PayloadHash() failed: line endings should be ignored")
+	}
+	if PayloadHash(lf) == PayloadHash(`{"translation":{"body":"<p>a</p>\n"}}`) {
+		t.Errorf("PayloadHash() failed: different payloads should not match")
+	}
+}
+
+func TestStoreMove(t *testing.T) {
+	s, err := Load(t.TempDir())
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	s.Get("1/123-ja.md").ArticleID = 123
+	s.Move("1/123-ja.md", "2/123-ja.md")
+
+	if _, ok := s.Lookup("1/123-ja.md"); ok {
+		t.Errorf("Move() failed: old entry still exists")
+	}
+	if e, ok := s.Lookup("2/123-ja.md"); !ok || e.ArticleID != 123 {
+		t.Errorf("Move() failed: new entry not found")
+	}
+}
+
+func TestFailedSaveAndLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	f, err := LoadFailed(dir, "")
+	if err != nil {
+		t.Fatalf("LoadFailed() failed: %v", err)
+	}
+	f.Push.Put(FailedItem{File: "1-ja.md", Error: "unexpected status code: 500"})
+	f.Push.Put(FailedItem{File: "2-ja.md"})
+	f.Push.Put(FailedItem{File: "1-ja.md", Error: "unexpected status code: 422"})
+	f.Pull.Put(FailedItem{ArticleID: 3, Error: "unexpected status code: 404"})
+	if err := f.Save(); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	f, err = LoadFailed(dir, "")
+	if err != nil {
+		t.Fatalf("LoadFailed() failed: %v", err)
+	}
+	want := FailedItems{{File: "1-ja.md", Error: "unexpected status code: 422"}, {File: "2-ja.md"}}
+	if !reflect.DeepEqual(f.Push, want) {
+		t.Errorf("Failed.Push failed: got %v, want %v", f.Push, want)
+	}
+	if len(f.Pull) != 1 || f.Pull[0].ArticleID != 3 {
+		t.Errorf("Failed.Pull failed: got %v", f.Pull)
+	}
+
+	f.Push.Delete("1-ja.md", 0)
+	f.Push.Delete("2-ja.md", 0)
+	f.Pull.Delete("", 3)
+	if err := f.Save(); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	if _, err := os.Stat(f.Path()); !os.IsNotExist(err) {
+		t.Errorf("Save() failed: %s should be removed when empty", f.Path())
+	}
+}
+
+func TestPlanPush(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Get(filepath.Join(dir, "pushed.md")).PushedHash = "a"
+	s.Get(filepath.Join(dir, "pulled.md")).PulledHash = "a"
+
+	plan := s.PlanPush(
+		Candidate{Path: filepath.Join(dir, "new.md"), Hash: "a"},
+		Candidate{Path: filepath.Join(dir, "pushed.md"), Hash: "a", RemoteExists: true},
+		Candidate{Path: filepath.Join(dir, "pushed.md"), Hash: "b", RemoteExists: true},
+		Candidate{Path: filepath.Join(dir, "pulled.md"), Hash: "a", RemoteExists: true},
+	)
+	var actions []Action
+	for _, step := range plan {
+		actions = append(actions, step.Action)
+	}
+	want := []Action{ActionCreate, ActionSkip, ActionUpdate, ActionUpdate}
+	if !reflect.DeepEqual(actions, want) {
+		t.Errorf("PlanPush() failed: got %v, want %v", actions, want)
+	}
+	if plan.Count(ActionUpdate) != 2 {
+		t.Errorf("Count() failed: got %d, want 2", plan.Count(ActionUpdate))
+	}
+}
+
+func TestPlanPull(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := s.Get(filepath.Join(dir, "pulled.md"))
+	e.PulledHash = "a"
+	e.RemoteUpdatedAt = "2024-01-01T00:00:00Z"
+
+	tests := []struct {
+		name      string
+		candidate Candidate
+		expected  Action
+	}{
+		{"no local file", Candidate{Path: filepath.Join(dir, "pulled.md"), RemoteUpdatedAt: "2024-01-01T00:00:00Z"}, ActionCreate},
+		{"not tracked", Candidate{Path: filepath.Join(dir, "other.md"), Hash: "a", RemoteUpdatedAt: "2024-01-01T00:00:00Z"}, ActionUpdate},
+		{"unchanged", Candidate{Path: filepath.Join(dir, "pulled.md"), Hash: "a", RemoteUpdatedAt: "2024-01-01T00:00:00Z"}, ActionSkip},
+		{"updated on the remote", Candidate{Path: filepath.Join(dir, "pulled.md"), Hash: "a", RemoteUpdatedAt: "2024-02-01T00:00:00Z"}, ActionUpdate},
+		{"unknown updated_at", Candidate{Path: filepath.Join(dir, "pulled.md"), Hash: "a"}, ActionUpdate},
+		{"changed locally", Candidate{Path: filepath.Join(dir, "pulled.md"), Hash: "b", RemoteUpdatedAt: "2024-01-01T00:00:00Z"}, ActionUpdate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.PlanPull(tt.candidate)[0].Action; got != tt.expected {
+				t.Errorf("PlanPull() failed: got %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package translate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	deepLEndpoint     = "https://api.deepl.com/v2/translate"
+	deepLFreeEndpoint = "https://api-free.deepl.com/v2/translate"
+)
+
+// deepLTargetVariants are the target languages of DeepL that keep the region or the script.
+// The languages without the region are mapped to a variant, as EN and PT are deprecated as the targets.
+var deepLTargetVariants = map[string]string{
+	"en":    "EN-US",
+	"en-gb": "EN-GB",
+	"en-us": "EN-US",
+	"pt":    "PT-PT",
+	"pt-br": "PT-BR",
+	"pt-pt": "PT-PT",
+	"zh-cn": "ZH-HANS",
+	"zh-tw": "ZH-HANT",
+}
+
+type DeepL struct {
+	apiKey   string
+	endpoint string
+	client   *http.Client
+}
+
+// NewDeepL returns the DeepL provider. The endpoint of the free API is used for the keys ending with :fx
+// unless the endpoint is specified.
+func NewDeepL(apiKey, endpoint string) *DeepL {
+	if endpoint == "" {
+		endpoint = deepLEndpoint
+		if strings.HasSuffix(apiKey, ":fx") {
+			endpoint = deepLFreeEndpoint
+		}
+	}
+	return &DeepL{apiKey: apiKey, endpoint: endpoint, client: &http.Client{Timeout: 60 * time.Second}}
+}
+
+func (d *DeepL) Name() string {
+	return ProviderDeepL
+}
+
+type deepLRequest struct {
+	Text        []string `json:"text"`
+	SourceLang  string   `json:"source_lang,omitempty"`
+	TargetLang  string   `json:"target_lang"`
+	TagHandling string   `json:"tag_handling,omitempty"`
+}
+
+type deepLResponse struct {
+	Translations []struct {
+		Text string `json:"text"`
+	} `json:"translations"`
+}
+
+// refs: https://developers.deepl.com/docs/api-reference/translate
+func (d *DeepL) Translate(texts []string, source, target string, html bool) ([]string, error) {
+	req := deepLRequest{Text: texts, SourceLang: deepLSourceLang(source), TargetLang: deepLTargetLang(target)}
+	if html {
+		req.TagHandling = "html"
+	}
+	b, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	r, err := http.NewRequest(http.MethodPost, d.endpoint, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Authorization", "DeepL-Auth-Key "+d.apiKey)
+
+	res, err := d.client.Do(r)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("deepl: unexpected status code: %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var out deepLResponse
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("deepl: %w", err)
+	}
+	if len(out.Translations) != len(texts) {
+		return nil, fmt.Errorf("deepl: %d translations returned for %d texts", len(out.Translations), len(texts))
+	}
+	translated := make([]string, len(texts))
+	for i, t := range out.Translations {
+		translated[i] = t.Text
+	}
+	return translated, nil
+}
+
+// deepLSourceLang returns the source language of DeepL, which has no regional variants.
+func deepLSourceLang(locale string) string {
+	lang, _, _ := strings.Cut(locale, "-")
+	return strings.ToUpper(lang)
+}
+
+func deepLTargetLang(locale string) string {
+	if lang, ok := deepLTargetVariants[strings.ToLower(locale)]; ok {
+		return lang
+	}
+	return deepLSourceLang(locale)
+}
@@ -0,0 +1,90 @@
+package translate
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDeepLTranslate(t *testing.T) {
+	var got deepLRequest
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("invalid request: %v", err)
+		}
+		var res deepLResponse
+		for _, text := range got.Text {
+			res.Translations = append(res.Translations, struct {
+				Text string `json:"text"`
+			}{Text: strings.ToUpper(text)})
+		}
+		_ = json.NewEncoder(w).Encode(res)
+	}))
+	defer srv.Close()
+
+	d := NewDeepL("secret", srv.URL)
+	actual, err := d.Translate([]string{"title", "<p>body</p>"}, "ja", "en-us", true)
+	if err != nil {
+		t.Fatalf("Translate() failed: %v", err)
+	}
+	if expected := []string{"TITLE", "<P>BODY</P>"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Translate() failed: got %v, want %v", actual, expected)
+	}
+	if auth != "DeepL-Auth-Key secret" {
+		t.Errorf("unexpected authorization %q", auth)
+	}
+	expected := deepLRequest{Text: []string{"title", "<p>body</p>"}, SourceLang: "JA", TargetLang: "EN-US", TagHandling: "html"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected request: got %+v, want %+v", got, expected)
+	}
+}
+
+func TestDeepLTranslateError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"Quota exceeded"}`, 456)
+	}))
+	defer srv.Close()
+
+	_, err := NewDeepL("secret", srv.URL).Translate([]string{"title"}, "ja", "fr", false)
+	if err == nil || !strings.Contains(err.Error(), "456") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestDeepLLang(t *testing.T) {
+	tests := []struct {
+		locale string
+		source string
+		target string
+	}{
+		{"ja", "JA", "JA"},
+		{"en", "EN", "EN-US"},
+		{"en-us", "EN", "EN-US"},
+		{"de-de", "DE", "DE"},
+		{"pt", "PT", "PT-PT"},
+		{"pt-br", "PT", "PT-BR"},
+		{"zh-tw", "ZH", "ZH-HANT"},
+	}
+	for _, tt := range tests {
+		if s := deepLSourceLang(tt.locale); s != tt.source {
+			t.Errorf("deepLSourceLang(%s): got %s, want %s", tt.locale, s, tt.source)
+		}
+		if s := deepLTargetLang(tt.locale); s != tt.target {
+			t.Errorf("deepLTargetLang(%s): got %s, want %s", tt.locale, s, tt.target)
+		}
+	}
+}
+
+func TestNewDeepLEndpoint(t *testing.T) {
+	if d := NewDeepL("key:fx", ""); d.endpoint != deepLFreeEndpoint {
+		t.Errorf("unexpected endpoint %s", d.endpoint)
+	}
+	if d := NewDeepL("key", ""); d.endpoint != deepLEndpoint {
+		t.Errorf("unexpected endpoint %s", d.endpoint)
+	}
+}
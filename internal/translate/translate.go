@@ -0,0 +1,35 @@
+// Package translate translates texts with machine translation providers.
+package translate
+
+import (
+	"fmt"
+	"strings"
+)
+
+const ProviderDeepL = "deepl"
+
+// Provider translates texts from the source locale to the target locale.
+// The locales are the locales of Zendesk such as ja or en-us.
+type Provider interface {
+	Name() string
+	Translate(texts []string, source, target string, html bool) ([]string, error)
+}
+
+// Options are the credentials and the endpoints of the providers.
+type Options struct {
+	DeepLAPIKey   string
+	DeepLEndpoint string
+}
+
+// NewProvider returns the provider of the name.
+func NewProvider(name string, opts Options) (Provider, error) {
+	switch strings.ToLower(name) {
+	case ProviderDeepL:
+		if opts.DeepLAPIKey == "" {
+			return nil, fmt.Errorf("the API key of DeepL is not specified")
+		}
+		return NewDeepL(opts.DeepLAPIKey, opts.DeepLEndpoint), nil
+	default:
+		return nil, fmt.Errorf("unsupported provider %s", name)
+	}
+}
@@ -0,0 +1,172 @@
+// Package xliff reads and writes the translations as XLIFF 2.0 documents.
+// refs: https://docs.oasis-open.org/xliff/xliff-core/v2.0/xliff-core-v2.0.html
+package xliff
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	Namespace = "urn:oasis:names:tc:xliff:document:2.0"
+	Version   = "2.0"
+
+	// TitleUnitID is the ID of the unit holding the title of the translation.
+	TitleUnitID = "title"
+)
+
+type Document struct {
+	XMLName xml.Name `xml:"urn:oasis:names:tc:xliff:document:2.0 xliff"`
+	Version string   `xml:"version,attr"`
+	SrcLang string   `xml:"srcLang,attr"`
+	TrgLang string   `xml:"trgLang,attr,omitempty"`
+	Files   []File   `xml:"file"`
+}
+
+type File struct {
+	ID       string `xml:"id,attr"`
+	Original string `xml:"original,attr,omitempty"`
+	Units    []Unit `xml:"unit"`
+}
+
+type Unit struct {
+	ID        string    `xml:"id,attr"`
+	Translate string    `xml:"translate,attr,omitempty"`
+	Segments  []Segment `xml:"segment"`
+}
+
+type Segment struct {
+	Source Text  `xml:"source"`
+	Target *Text `xml:"target,omitempty"`
+}
+
+// Text is the content of a source or a target, written with the line breaks preserved.
+type Text string
+
+func (t Text) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Space: "http://www.w3.org/XML/1998/namespace", Local: "space"}, Value: "preserve"})
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	// CharData keeps the line breaks as is, unlike the character data of the fields.
+	if err := e.EncodeToken(xml.CharData(t)); err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
+}
+
+// NewFile returns the file of the title and the blocks of the Markdown body.
+// Fenced code blocks are marked as not to be translated.
+func NewFile(id, original, title, body string) File {
+	f := File{ID: id, Original: original}
+	f.Units = append(f.Units, Unit{ID: TitleUnitID, Segments: []Segment{{Source: Text(title)}}})
+	for i, block := range SplitBlocks(body) {
+		u := Unit{ID: fmt.Sprintf("body-%d", i+1), Segments: []Segment{{Source: Text(block)}}}
+		if isFence(block) {
+			u.Translate = "no"
+		}
+		f.Units = append(f.Units, u)
+	}
+	return f
+}
+
+// Translated returns the translated title and Markdown body of the file.
+// The units not to be translated fall back to the source, and the other units must have a target.
+func (f *File) Translated() (title string, body string, err error) {
+	var blocks []string
+	for _, u := range f.Units {
+		var text strings.Builder
+		for _, s := range u.Segments {
+			switch {
+			case s.Target != nil:
+				text.WriteString(string(*s.Target))
+			case u.Translate == "no":
+				text.WriteString(string(s.Source))
+			default:
+				return "", "", fmt.Errorf("unit %s of %s is not translated", u.ID, f.Original)
+			}
+		}
+		if u.ID == TitleUnitID {
+			title = text.String()
+			continue
+		}
+		blocks = append(blocks, text.String())
+	}
+	if len(blocks) == 0 {
+		return title, "", nil
+	}
+	return title, strings.Join(blocks, "\n\n") + "\n", nil
+}
+
+// SplitBlocks splits the Markdown into the blocks separated by blank lines, keeping fenced code blocks whole.
+func SplitBlocks(md string) []string {
+	var blocks []string
+	var cur []string
+	fence := ""
+	flush := func() {
+		if len(cur) > 0 {
+			blocks = append(blocks, strings.Join(cur, "\n"))
+			cur = nil
+		}
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			cur = append(cur, line)
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+				flush()
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			flush()
+			fence = trimmed[:3]
+			cur = append(cur, line)
+			continue
+		}
+		if trimmed == "" {
+			flush()
+			continue
+		}
+		cur = append(cur, line)
+	}
+	flush()
+	return blocks
+}
+
+func isFence(block string) bool {
+	trimmed := strings.TrimSpace(block)
+	return strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")
+}
+
+// Write writes the document with the XML declaration.
+func Write(w io.Writer, doc *Document) error {
+	if doc.Version == "" {
+		doc.Version = Version
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	if err := e.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// Read reads the document, which must be XLIFF 2.0.
+func Read(r io.Reader) (*Document, error) {
+	doc := &Document{}
+	if err := xml.NewDecoder(r).Decode(doc); err != nil {
+		return nil, fmt.Errorf("invalid XLIFF: %w", err)
+	}
+	if doc.Version != Version {
+		return nil, fmt.Errorf("unsupported XLIFF version %q", doc.Version)
+	}
+	return doc, nil
+}
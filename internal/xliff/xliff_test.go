@@ -0,0 +1,113 @@
+package xliff
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testBody = "# 見出し\n\n本文です。\n次の行 & <tag>\n\n```sh\n$ zgsync pull\n\n$ zgsync push\n```\n"
+
+func TestWrite(t *testing.T) {
+	doc := &Document{SrcLang: "ja", TrgLang: "en-us", Files: []File{NewFile("f123", "123-ja.md", "はじめに", testBody)}}
+	var buf bytes.Buffer
+	if err := Write(&buf, doc); err != nil {
+		t.Fatal(err)
+	}
+	expected, err := os.ReadFile("testdata/123-ja.xlf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != string(expected) {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestReadTranslated(t *testing.T) {
+	f, err := os.Open("testdata/123-ja.translated.xlf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	doc, err := Read(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.SrcLang != "ja" || doc.TrgLang != "en-us" || len(doc.Files) != 1 {
+		t.Fatalf("unexpected document: %+v", doc)
+	}
+	title, body, err := doc.Files[0].Translated()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if title != "Introduction" {
+		t.Errorf("unexpected title %q", title)
+	}
+	expected := "# Heading\n\nThis is the body.\nThe next line & <tag>\n\n```sh\n$ zgsync pull\n\n$ zgsync push\n```\n"
+	if body != expected {
+		t.Errorf("expected:\n%q\ngot:\n%q", expected, body)
+	}
+}
+
+func TestTranslatedMissingTarget(t *testing.T) {
+	f, err := os.Open("testdata/123-ja.xlf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	doc, err := Read(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = doc.Files[0].Translated()
+	if err == nil || err.Error() != "unit title of 123-ja.md is not translated" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestReadUnsupportedVersion(t *testing.T) {
+	_, err := Read(strings.NewReader(`<xliff xmlns="urn:oasis:names:tc:xliff:document:1.2" version="1.2"></xliff>`))
+	if err == nil {
+		t.Error("an error is expected")
+	}
+}
+
+func TestSplitBlocks(t *testing.T) {
+	tests := []struct {
+		name     string
+		md       string
+		expected []string
+	}{
+		{
+			name:     "paragraphs",
+			md:       "a\nb\n\n\nc\n",
+			expected: []string{"a\nb", "c"},
+		},
+		{
+			name:     "fenced code with blank lines",
+			md:       "text\n~~~\nx\n\ny\n~~~\nafter\n",
+			expected: []string{"text", "~~~\nx\n\ny\n~~~", "after"},
+		},
+		{
+			name:     "crlf",
+			md:       "a\r\n\r\nb",
+			expected: []string{"a", "b"},
+		},
+		{
+			name:     "empty",
+			md:       "",
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitBlocks(tt.md); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
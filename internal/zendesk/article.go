@@ -1,44 +1,50 @@
 package zendesk
 
 import (
-	"bytes"
 	"encoding/json"
 	"io"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/adrg/frontmatter"
-	"gopkg.in/yaml.v3"
 )
 
 // refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/articles/
 type Article struct {
-	AuthorID          int      `json:"author_id,omitempty" yaml:"author_id"`
-	Body              string   `json:"body,omitempty" yaml:"-"`
-	CommentsDisabled  bool     `json:"comments_disabled,omitempty" yaml:"comments_disabled"`
-	ContentTagIDs     []string `json:"content_tag_ids,omitempty" yaml:"content_tag_ids"`
-	CreatedAt         string   `json:"created_at,omitempty" yaml:"created_at"`
-	Draft             bool     `json:"draft,omitempty" yaml:"draft"`
-	EditedAt          string   `json:"edited_at,omitempty" yaml:"edited_at"`
-	HtmlURL           string   `json:"html_url,omitempty" yaml:"html_url"`
-	ID                int      `json:"id,omitempty" yaml:"id"`
-	LabelNames        []string `json:"label_names,omitempty" yaml:"label_names"`
-	Locale            string   `json:"locale" yaml:"locale"`
-	Outdated          bool     `json:"outdated,omitempty" yaml:"outdated"`
-	OutdatedLocales   []string `json:"outdated_locales,omitempty" yaml:"outdated_locales"`
-	PermissionGroupID int      `json:"permission_group_id,omitempty" yaml:"permission_group_id"`
-	Position          int      `json:"position,omitempty" yaml:"position"`
-	Promoted          bool     `json:"promoted,omitempty" yaml:"promoted"`
-	SectionID         int      `json:"section_id,omitempty" yaml:"section_id"`
-	SourceLocale      string   `json:"source_locale,omitempty" yaml:"source_locale"`
-	Title             string   `json:"title" yaml:"title"`
-	UpdatedAt         string   `json:"updated_at,omitempty" yaml:"updated_at"`
-	Url               string   `json:"url,omitempty" yaml:"url"`
-	UserSegmentID     *int     `json:"user_segment_id" yaml:"user_segment_id"`
-	UserSegmentIDs    []int    `json:"user_segment_ids,omitempty" yaml:"user_segment_ids"`
-	VoteCount         int      `json:"vote_count,omitempty" yaml:"vote_count"`
-	VoteSum           int      `json:"vote_sum,omitempty" yaml:"vote_sum"`
+	AuthorID          int           `json:"author_id,omitempty" yaml:"author_id"`
+	AuthorEmail       string        `json:"-" yaml:"author_email,omitempty"`
+	Brand             string        `json:"-" yaml:"brand,omitempty"`
+	Body              string        `json:"body,omitempty" yaml:"-"`
+	CommentsDisabled  *bool         `json:"comments_disabled,omitempty" yaml:"comments_disabled,omitempty"`
+	ContentTagIDs     []string      `json:"content_tag_ids,omitempty" yaml:"content_tag_ids"`
+	CreatedAt         string        `json:"created_at,omitempty" yaml:"created_at" readonly:""`
+	Draft             bool          `json:"draft,omitempty" yaml:"draft"`
+	EditedAt          string        `json:"edited_at,omitempty" yaml:"edited_at" readonly:""`
+	HtmlURL           string        `json:"html_url,omitempty" yaml:"html_url" readonly:""`
+	ID                int           `json:"id,omitempty" yaml:"id"`
+	LabelNames        []string      `json:"label_names,omitempty" yaml:"label_names"`
+	Locale            string        `json:"locale" yaml:"locale"`
+	NotifySubscribers *bool         `json:"-" yaml:"notify_subscribers,omitempty"`
+	Outdated          bool          `json:"outdated,omitempty" yaml:"outdated" readonly:""`
+	OutdatedLocales   []string      `json:"outdated_locales,omitempty" yaml:"outdated_locales" readonly:""`
+	PermissionGroupID int           `json:"permission_group_id,omitempty" yaml:"permission_group_id"`
+	Position          int           `json:"position,omitempty" yaml:"position"`
+	Promoted          *bool         `json:"promoted,omitempty" yaml:"promoted,omitempty"`
+	SectionID         int           `json:"section_id,omitempty" yaml:"section_id"`
+	Slug              string        `json:"-" yaml:"slug,omitempty" readonly:""`
+	SourceLocale      string        `json:"source_locale,omitempty" yaml:"source_locale" readonly:""`
+	Title             string        `json:"title" yaml:"title"`
+	Translations      []Translation `json:"translations,omitempty" yaml:"-"`
+	UpdatedAt         string        `json:"updated_at,omitempty" yaml:"updated_at" readonly:""`
+	Url               string        `json:"url,omitempty" yaml:"url" readonly:""`
+	UserSegmentID     *int          `json:"user_segment_id" yaml:"user_segment_id"`
+	UserSegmentIDs    []int         `json:"user_segment_ids,omitempty" yaml:"user_segment_ids"`
+	VoteCount         int           `json:"vote_count,omitempty" yaml:"vote_count" readonly:""`
+	VoteSum           int           `json:"vote_sum,omitempty" yaml:"vote_sum" readonly:""`
 }
 
 type wrappedArticle struct {
@@ -46,6 +52,12 @@ type wrappedArticle struct {
 	NotifySubscribers bool    `json:"notify_subscribers,omitempty" default:"false"`
 }
 
+type wrappedArticles struct {
+	Articles []Article `json:"articles"`
+	NextPage *string   `json:"next_page"`
+	EndTime  int64     `json:"end_time,omitempty"`
+}
+
 func (a *Article) FromFile(path string) error {
 	f, err := os.Open(path)
 	if err != nil {
@@ -53,17 +65,12 @@ func (a *Article) FromFile(path string) error {
 	}
 	defer f.Close()
 
-	b, err := io.ReadAll(f)
+	r, err := newTextReader(f)
 	if err != nil {
 		return err
 	}
-
-	r := bytes.NewReader(b)
 	_, err = frontmatter.Parse(r, &a)
-	if err != nil {
-		return err
-	}
-	return nil
+	return err
 }
 
 func (a *Article) FromJson(jsonStr string) error {
@@ -76,44 +83,121 @@ func (a *Article) FromJson(jsonStr string) error {
 	return nil
 }
 
-func (a *Article) Save(path string, appendFileName bool) error {
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		if err := os.MkdirAll(path, 0o755); err != nil {
-			return err
-		}
+// ArticlesFromJson returns the articles of the page of the list and whether the next page exists.
+func ArticlesFromJson(jsonStr string) ([]Article, bool, error) {
+	wrapped := wrappedArticles{}
+	err := json.Unmarshal([]byte(jsonStr), &wrapped)
+	if err != nil {
+		return nil, false, err
 	}
+	return wrapped.Articles, wrapped.NextPage != nil && *wrapped.NextPage != "", nil
+}
 
-	if appendFileName {
-		path = filepath.Join(path, strconv.Itoa(a.ID)+".md")
-	}
-	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+// IncrementalArticlesFromJson returns the articles of the page of the incremental export, the start time
+// of the next page and whether the next page exists.
+func IncrementalArticlesFromJson(jsonStr string) ([]Article, int64, bool, error) {
+	wrapped := wrappedArticles{}
+	err := json.Unmarshal([]byte(jsonStr), &wrapped)
 	if err != nil {
-		return err
+		return nil, 0, false, err
 	}
-	defer f.Close()
+	return wrapped.Articles, wrapped.EndTime, wrapped.NextPage != nil && *wrapped.NextPage != "", nil
+}
 
-	if _, err := f.WriteString("---\n"); err != nil {
-		return err
+func (a *Article) Save(path string, appendFileName bool) error {
+	return a.SaveWithTemplate(path, appendFileName, nil)
+}
+
+// SaveWithTemplate saves the article with the front matter ordered by the template.
+func (a *Article) SaveWithTemplate(path string, appendFileName bool, tmpl *FrontMatterTemplate) error {
+	dir := path
+	if !appendFileName {
+		dir = filepath.Dir(path)
 	}
-	ye := yaml.NewEncoder(f)
-	ye.SetIndent(2)
-	if err := ye.Encode(a); err != nil {
-		return err
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
 	}
-	if _, err := f.WriteString("---\n"); err != nil {
-		return err
+
+	if appendFileName {
+		path = filepath.Join(path, a.FileName())
 	}
-	return nil
+	return writeFile(path, func(w io.Writer) error {
+		return writeFrontMatter(w, a, path, tmpl)
+	})
 }
 
+func (a *Article) FileName() string {
+	return strconv.Itoa(a.ID) + ".md"
+}
+
+// ToPayload returns the payload to create or update the article. The settings not specified in the front matter
+// are omitted so that they are kept as they are, and content_tag_ids specified as empty removes the content tags.
+// The read-only fields such as html_url and updated_at are omitted, and user_segment_id is omitted when
+// user_segment_ids is given.
 func (a *Article) ToPayload(notify bool) (string, error) {
+	article := *a
+	article.CreatedAt = ""
+	article.EditedAt = ""
+	article.HtmlURL = ""
+	article.Outdated = false
+	article.OutdatedLocales = nil
+	article.SourceLocale = ""
+	article.Translations = nil
+	article.UpdatedAt = ""
+	article.Url = ""
+	article.VoteCount = 0
+	article.VoteSum = 0
 	wrapped := wrappedArticle{
-		Article:           *a,
+		Article:           article,
 		NotifySubscribers: notify,
 	}
 	b, err := json.Marshal(wrapped)
 	if err != nil {
 		return "", err
 	}
+	clearTags := a.ContentTagIDs != nil && len(a.ContentTagIDs) == 0
+	if !clearTags && len(a.UserSegmentIDs) == 0 {
+		return string(b), nil
+	}
+	var v map[string]map[string]any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return "", err
+	}
+	if clearTags {
+		v["article"]["content_tag_ids"] = []string{}
+	}
+	if len(a.UserSegmentIDs) > 0 {
+		delete(v["article"], "user_segment_id")
+	}
+	if b, err = json.Marshal(v); err != nil {
+		return "", err
+	}
 	return string(b), nil
 }
+
+// SlugFromURL returns the slug of the article in html_url, which follows the article ID as {id}-{slug}.
+// Zendesk derives the slug from the title, and it cannot be set by the API.
+func SlugFromURL(htmlURL string) string {
+	u, err := url.Parse(htmlURL)
+	if err != nil {
+		return ""
+	}
+	id, slug, ok := strings.Cut(path.Base(u.Path), "-")
+	if _, err := strconv.Atoi(id); !ok || err != nil {
+		return ""
+	}
+	return slug
+}
+
+// Translation returns the translation of the locale in the translations sideloaded to the list of the articles.
+func (a *Article) Translation(locale string) (*Translation, bool) {
+	for i := range a.Translations {
+		if a.Translations[i].Locale == locale {
+			t := a.Translations[i]
+			return &t, true
+		}
+	}
+	return nil, false
+}
@@ -2,11 +2,13 @@ package zendesk
 
 import (
 	"os"
+	"strings"
 	"testing"
 )
 
 func TestArticleFromFile(t *testing.T) {
 	refUserSegmentID := 123
+	refNotifySubscribers := true
 	tests := []struct {
 		filepath string
 		expected Article
@@ -24,6 +26,7 @@ func TestArticleFromFile(t *testing.T) {
 			"testdata/article-en.md",
 			Article{
 				Locale:            "en_us",
+				NotifySubscribers: &refNotifySubscribers,
 				PermissionGroupID: 56,
 				Title:             "How to use zgsync",
 				UserSegmentID:     &refUserSegmentID,
@@ -52,12 +55,18 @@ func TestArticleFromFile(t *testing.T) {
 			if len(article.UserSegmentIDs) != len(tt.expected.UserSegmentIDs) {
 				t.Errorf("article.UserSegmentIds failed: got %v, want %v", article.UserSegmentIDs, tt.expected.UserSegmentIDs)
 			}
+			if (article.NotifySubscribers == nil) != (tt.expected.NotifySubscribers == nil) ||
+				(article.NotifySubscribers != nil && *article.NotifySubscribers != *tt.expected.NotifySubscribers) {
+				t.Errorf("article.NotifySubscribers failed: got %v, want %v", article.NotifySubscribers, tt.expected.NotifySubscribers)
+			}
 		})
 	}
 }
 
 func TestArticleFromJson(t *testing.T) {
 	refUserSegmentID := 12
+	refCommentsDisabled := true
+	refPromoted := false
 	tests := []struct {
 		filepath string
 		expected Article
@@ -66,13 +75,13 @@ func TestArticleFromJson(t *testing.T) {
 			"testdata/article.json",
 			Article{
 				AuthorID:          3465,
-				CommentsDisabled:  true,
+				CommentsDisabled:  &refCommentsDisabled,
 				ContentTagIDs:     []string{"01GT23D51Y", "01GT23FWWN"},
 				ID:                37486578,
 				Locale:            "en_us",
 				PermissionGroupID: 123,
 				Position:          42,
-				Promoted:          false,
+				Promoted:          &refPromoted,
 				Title:             "How to use zgsync",
 				UserSegmentID:     &refUserSegmentID,
 			},
@@ -89,7 +98,7 @@ func TestArticleFromJson(t *testing.T) {
 			if article.AuthorID != tt.expected.AuthorID {
 				t.Errorf("article.AuthorID failed: got %v, want %v", article.AuthorID, tt.expected.AuthorID)
 			}
-			if article.CommentsDisabled != tt.expected.CommentsDisabled {
+			if *article.CommentsDisabled != *tt.expected.CommentsDisabled {
 				t.Errorf("article.CommentsDisabled failed: got %v, want %v", article.CommentsDisabled, tt.expected.CommentsDisabled)
 			}
 			if len(article.ContentTagIDs) != len(tt.expected.ContentTagIDs) {
@@ -107,7 +116,7 @@ func TestArticleFromJson(t *testing.T) {
 			if article.Position != tt.expected.Position {
 				t.Errorf("article.Position failed: got %v, want %v", article.Position, tt.expected.Position)
 			}
-			if article.Promoted != tt.expected.Promoted {
+			if *article.Promoted != *tt.expected.Promoted {
 				t.Errorf("article.Promoted failed: got %v, want %v", article.Promoted, tt.expected.Promoted)
 			}
 			if article.Title != tt.expected.Title {
@@ -119,3 +128,84 @@ func TestArticleFromJson(t *testing.T) {
 		})
 	}
 }
+
+func TestArticleToPayload(t *testing.T) {
+	tests := []struct {
+		filepath string
+		expected string
+	}{
+		{
+			"testdata/article-settings.md",
+			`{"article":{"comments_disabled":false,"content_tag_ids":[],"locale":"ja","position":3,"promoted":true,"section_id":10,"title":"設定","user_segment_id":null}}`,
+		},
+		{
+			"testdata/article-ja.md",
+			`{"article":{"locale":"ja","permission_group_id":12345,"title":"zgsyncの使い方","user_segment_ids":[123,456]}}`,
+		},
+		{
+			"testdata/article-readonly.md",
+			`{"article":{"id":100,"locale":"ja","title":"読み取り専用","user_segment_id":null}}`,
+		},
+		{
+			"testdata/article-segments.md",
+			`{"article":{"id":100,"locale":"ja","title":"セグメント","user_segment_ids":[1,2]}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filepath, func(t *testing.T) {
+			article := &Article{}
+			if err := article.FromFile(tt.filepath); err != nil {
+				t.Fatalf("ArticleFromFile() failed: %v", err)
+			}
+			payload, err := article.ToPayload(false)
+			if err != nil {
+				t.Fatalf("Article.ToPayload() failed: %v", err)
+			}
+			if payload != tt.expected {
+				t.Errorf("Article.ToPayload() failed: got %s, want %s", payload, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSlugFromURL(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{"https://example.zendesk.com/hc/en-us/articles/123-Getting-started", "Getting-started"},
+		{"https://example.zendesk.com/hc/ja/articles/123-%E4%BD%BF%E3%81%84%E6%96%B9", "使い方"},
+		{"https://example.zendesk.com/hc/en-us/articles/123-FAQ?page=2", "FAQ"},
+		{"https://example.zendesk.com/hc/en-us/articles/123", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if got := SlugFromURL(tt.url); got != tt.expected {
+				t.Errorf("SlugFromURL() failed: got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestArticleTranslation(t *testing.T) {
+	articles, _, err := ArticlesFromJson(`{"articles":[{"id":1,"title":"a","locale":"ja","translations":[{"locale":"en-us","title":"en"},{"locale":"ja","title":"ja","body":"<p>body</p>"}]}]}`)
+	if err != nil {
+		t.Fatalf("ArticlesFromJson() failed: %v", err)
+	}
+	tr, ok := articles[0].Translation("ja")
+	if !ok || tr.Title != "ja" || tr.Body != "<p>body</p>" {
+		t.Errorf("Translation() failed: got %+v, %v", tr, ok)
+	}
+	if _, ok := articles[0].Translation("fr"); ok {
+		t.Error("Translation() should not find the locale not sideloaded")
+	}
+	payload, err := articles[0].ToPayload(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(payload, "translations") {
+		t.Errorf("ToPayload() failed: the translations are included: %s", payload)
+	}
+}
@@ -0,0 +1,29 @@
+package zendesk
+
+import "encoding/json"
+
+// ArticleAttachment is a file attached to an article, such as an image referenced by the body.
+// refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/article_attachments/
+type ArticleAttachment struct {
+	ID          int    `json:"id"`
+	ArticleID   int    `json:"article_id"`
+	FileName    string `json:"file_name"`
+	ContentURL  string `json:"content_url"`
+	ContentType string `json:"content_type"`
+	Size        int    `json:"size"`
+	Inline      bool   `json:"inline"`
+}
+
+type wrappedArticleAttachment struct {
+	ArticleAttachment ArticleAttachment `json:"article_attachment"`
+}
+
+func (a *ArticleAttachment) FromJson(jsonStr string) error {
+	wrapped := wrappedArticleAttachment{}
+	err := json.Unmarshal([]byte(jsonStr), &wrapped)
+	if err != nil {
+		return err
+	}
+	*a = wrapped.ArticleAttachment
+	return nil
+}
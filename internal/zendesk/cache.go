@@ -0,0 +1,132 @@
+package zendesk
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// Cache stores the responses of the lookups by slash-separated keys.
+type Cache interface {
+	Get(key string) (string, bool)
+	Put(key string, value string) error
+	// Delete removes the value of the key and the values of the keys under it.
+	Delete(key string) error
+}
+
+// cachedClient serves the lookups of the articles, the translations, the sections, the categories and the users from
+// the cache.
+// The cached article and its translations are removed when they are written through the client.
+type cachedClient struct {
+	Client
+	cache  Cache
+	prefix string
+	// uncached is set to look up the articles and the translations on the help center, caching the responses.
+	uncached bool
+}
+
+// NewCachedClient returns the client caching the lookups of the client. The keys are prefixed by the subdomain,
+// so that the help centers of the brands can share the cache.
+func NewCachedClient(client Client, subdomain string, cache Cache) Client {
+	return &cachedClient{Client: client, cache: cache, prefix: subdomain}
+}
+
+// Uncached returns the client looking up the articles and the translations on the help center without the cache, for
+// the comparisons with the remote such as the conflict checks. The responses still refresh the cache, and the other
+// lookups are served from the cache.
+func Uncached(client Client) Client {
+	c, ok := client.(*cachedClient)
+	if !ok {
+		return client
+	}
+	uncached := *c
+	uncached.uncached = true
+	return &uncached
+}
+
+func (c *cachedClient) key(kind string, id int, locale string) string {
+	return fmt.Sprintf("%s/%s/%d/%s", c.prefix, kind, id, locale)
+}
+
+func (c *cachedClient) lookup(key string, cached bool, show func() (string, error)) (string, error) {
+	if cached {
+		if res, ok := c.cache.Get(key); ok {
+			return res, nil
+		}
+	}
+	res, err := show()
+	if err != nil {
+		return "", err
+	}
+	if err := c.cache.Put(key, res); err != nil {
+		slog.Debug("failed to cache", "key", key, "error", err)
+	}
+	return res, nil
+}
+
+// invalidate removes the cached article and its translations of all the locales.
+func (c *cachedClient) invalidate(articleID int) {
+	for _, kind := range []string{"articles", "translations"} {
+		key := fmt.Sprintf("%s/%s/%d", c.prefix, kind, articleID)
+		if err := c.cache.Delete(key); err != nil {
+			slog.Debug("failed to invalidate the cache", "key", key, "error", err)
+		}
+	}
+}
+
+func (c *cachedClient) ShowArticle(locale string, articleID int) (string, error) {
+	return c.lookup(c.key("articles", articleID, locale), !c.uncached, func() (string, error) {
+		return c.Client.ShowArticle(locale, articleID)
+	})
+}
+
+func (c *cachedClient) ShowTranslation(articleID int, locale string) (string, error) {
+	return c.lookup(c.key("translations", articleID, locale), !c.uncached, func() (string, error) {
+		return c.Client.ShowTranslation(articleID, locale)
+	})
+}
+
+func (c *cachedClient) ShowSection(locale string, sectionID int) (string, error) {
+	return c.lookup(c.key("sections", sectionID, locale), true, func() (string, error) {
+		return c.Client.ShowSection(locale, sectionID)
+	})
+}
+
+func (c *cachedClient) ShowCategory(locale string, categoryID int) (string, error) {
+	return c.lookup(c.key("categories", categoryID, locale), true, func() (string, error) {
+		return c.Client.ShowCategory(locale, categoryID)
+	})
+}
+
+func (c *cachedClient) ShowUser(userID int) (string, error) {
+	return c.lookup(c.key("users", userID, ""), true, func() (string, error) {
+		return c.Client.ShowUser(userID)
+	})
+}
+
+func (c *cachedClient) UpdateArticle(locale string, articleID int, payload string) (string, error) {
+	defer c.invalidate(articleID)
+	return c.Client.UpdateArticle(locale, articleID, payload)
+}
+
+func (c *cachedClient) MoveArticle(articleID int, sectionID int) (string, error) {
+	defer c.invalidate(articleID)
+	return c.Client.MoveArticle(articleID, sectionID)
+}
+
+func (c *cachedClient) CreateTranslation(articleID int, payload string) (string, error) {
+	defer c.invalidate(articleID)
+	return c.Client.CreateTranslation(articleID, payload)
+}
+
+func (c *cachedClient) UpdateTranslation(articleID int, locale string, payload string) (string, error) {
+	defer c.invalidate(articleID)
+	return c.Client.UpdateTranslation(articleID, locale, payload)
+}
+
+// RateLimit reports the rate limit of the client.
+func (c *cachedClient) RateLimit() (RateLimit, bool) {
+	if rl, ok := c.Client.(RateLimited); ok {
+		return rl.RateLimit()
+	}
+	return RateLimit{}, false
+}
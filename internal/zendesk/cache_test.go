@@ -0,0 +1,95 @@
+package zendesk
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// mapCache is the cache in memory.
+type mapCache map[string]string
+
+func (m mapCache) Get(key string) (string, bool) {
+	v, ok := m[key]
+	return v, ok
+}
+
+func (m mapCache) Put(key string, value string) error {
+	m[key] = value
+	return nil
+}
+
+func (m mapCache) Delete(key string) error {
+	for k := range m {
+		if k == key || strings.HasPrefix(k, key+"/") {
+			delete(m, k)
+		}
+	}
+	return nil
+}
+
+// countingClient counts the lookups of the translations.
+type countingClient struct {
+	Client
+	shows int
+}
+
+func (c *countingClient) ShowTranslation(articleID int, locale string) (string, error) {
+	c.shows++
+	return fmt.Sprintf(`{"translation":{"source_id":%d,"locale":%q}}`, articleID, locale), nil
+}
+
+func (c *countingClient) UpdateTranslation(articleID int, locale string, payload string) (string, error) {
+	return payload, nil
+}
+
+func TestCachedClient(t *testing.T) {
+	cache := mapCache{}
+	inner := &countingClient{}
+	client := NewCachedClient(inner, "example", cache)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.ShowTranslation(1, "ja"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := client.ShowTranslation(2, "ja"); err != nil {
+		t.Fatal(err)
+	}
+	if inner.shows != 2 {
+		t.Errorf("ShowTranslation() failed: got %d lookups, want 2", inner.shows)
+	}
+	if _, ok := cache["example/translations/1/ja"]; !ok {
+		t.Errorf("ShowTranslation() failed: not cached in %v", cache)
+	}
+
+	if _, err := client.UpdateTranslation(1, "en-us", "{}"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache["example/translations/1/ja"]; ok {
+		t.Error("UpdateTranslation() should invalidate the translations of the article")
+	}
+	if _, ok := cache["example/translations/2/ja"]; !ok {
+		t.Error("UpdateTranslation() should keep the translations of the other articles")
+	}
+}
+
+func TestUncached(t *testing.T) {
+	cache := mapCache{"example/translations/1/ja": `{"translation":{"title":"stale"}}`}
+	inner := &countingClient{}
+	client := Uncached(NewCachedClient(inner, "example", cache))
+
+	res, err := client.ShowTranslation(1, "ja")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inner.shows != 1 || strings.Contains(res, "stale") {
+		t.Errorf("ShowTranslation() failed: got %s from %d lookups", res, inner.shows)
+	}
+	if cache["example/translations/1/ja"] != res {
+		t.Errorf("ShowTranslation() should refresh the cache: got %v", cache)
+	}
+	if Uncached(inner) != Client(inner) {
+		t.Error("Uncached() should return the client without the cache as it is")
+	}
+}
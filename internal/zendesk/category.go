@@ -0,0 +1,47 @@
+package zendesk
+
+import "encoding/json"
+
+// refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/categories/
+type Category struct {
+	CreatedAt    string `json:"created_at,omitempty"`
+	Description  string `json:"description,omitempty"`
+	HtmlURL      string `json:"html_url,omitempty"`
+	ID           int    `json:"id,omitempty"`
+	Locale       string `json:"locale,omitempty"`
+	Name         string `json:"name"`
+	Outdated     bool   `json:"outdated,omitempty"`
+	Position     int    `json:"position,omitempty"`
+	SourceLocale string `json:"source_locale,omitempty"`
+	UpdatedAt    string `json:"updated_at,omitempty"`
+	URL          string `json:"url,omitempty"`
+}
+
+type wrappedCategory struct {
+	Category Category `json:"category"`
+}
+
+type wrappedCategories struct {
+	Categories []Category `json:"categories"`
+	NextPage   *string    `json:"next_page"`
+}
+
+func (c *Category) FromJson(jsonStr string) error {
+	wrapped := wrappedCategory{}
+	err := json.Unmarshal([]byte(jsonStr), &wrapped)
+	if err != nil {
+		return err
+	}
+	*c = wrapped.Category
+	return nil
+}
+
+// CategoriesFromJson returns the categories of the page of the list and whether the next page exists.
+func CategoriesFromJson(jsonStr string) ([]Category, bool, error) {
+	wrapped := wrappedCategories{}
+	err := json.Unmarshal([]byte(jsonStr), &wrapped)
+	if err != nil {
+		return nil, false, err
+	}
+	return wrapped.Categories, wrapped.NextPage != nil && *wrapped.NextPage != "", nil
+}
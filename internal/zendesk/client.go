@@ -1,12 +1,21 @@
 package zendesk
 
 import (
+	"bytes"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
+	"mime/multipart"
+	"net"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/tukaelu/zgsync/internal/metrics"
 	_ "github.com/tukaelu/zgsync/internal/zendesk/httplog"
 )
 
@@ -18,15 +27,106 @@ type Client interface {
 	CreateArticle(locale string, sectionID int, payload string) (string, error)
 	UpdateArticle(locale string, articleID int, payload string) (string, error)
 	ShowArticle(locale string, articleID int) (string, error)
+	ListArticles(page int) (string, error)
+	ListArticlesWithTranslations(locale string, page int) (string, error)
+	ListSectionArticles(sectionID int, page int) (string, error)
+	ListArticlesSince(startTime int64) (string, error)
+	MoveArticle(articleID int, sectionID int) (string, error)
 	CreateTranslation(articleID int, payload string) (string, error)
 	UpdateTranslation(articleID int, locale string, payload string) (string, error)
 	ShowTranslation(articleID int, locale string) (string, error)
+	ListTranslations(articleID int, page int) (string, error)
+	CreateSection(locale string, categoryID int, payload string) (string, error)
+	ShowSection(locale string, sectionID int) (string, error)
+	ListSections(locale string, page int) (string, error)
+	ShowCategory(locale string, categoryID int) (string, error)
+	ListCategories(locale string, page int) (string, error)
+	ShowUserSegment(userSegmentID int) (string, error)
+	ShowPermissionGroup(permissionGroupID int) (string, error)
+	ListLocales() (string, error)
+	ShowCurrentUser() (string, error)
+	ShowUser(userID int) (string, error)
+	SearchUsers(query string) (string, error)
+	ListUserSegmentsForUser(userID int, page int) (string, error)
+	ListArticleSubscriptions(articleID int, page int) (string, error)
+	CreateArticleSubscription(articleID int, payload string) (string, error)
+	ListArticleComments(articleID int, page int) (string, error)
+	DeleteArticleComment(articleID int, commentID int) error
+	ListPosts(topicID int, page int) (string, error)
+	ShowPost(postID int) (string, error)
+	CreatePost(payload string) (string, error)
+	UpdatePost(postID int, payload string) (string, error)
+	CreateArticleAttachment(articleID int, fileName string, content io.Reader, inline bool) (string, error)
+}
+
+// StatusError is returned when the API responds with an unexpected status code.
+type StatusError struct {
+	StatusCode int
+	// RetryAfter is the wait given by the Retry-After header of the rate limited response.
+	RetryAfter time.Duration
+	// Path is the path of the request, such as /api/v2/help_center/ja/articles/123.
+	Path string
+	// Code, Description and Details are given by the body of the error response, where Details are
+	// the validation errors of the fields.
+	Code        string
+	Description string
+	Details     map[string][]ErrorDetail
+}
+
+func (e *StatusError) Error() string {
+	msg := fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+	if details := e.detailMessages(); len(details) > 0 {
+		return msg + ": " + strings.Join(details, "; ")
+	}
+	if e.Description != "" {
+		return msg + ": " + e.Description
+	}
+	return msg
+}
+
+// IsNotFound reports whether the error is the response of an object not found.
+func IsNotFound(err error) bool {
+	var se *StatusError
+	return errors.As(err, &se) && se.StatusCode == http.StatusNotFound
+}
+
+// IsTransient reports whether the error is temporary and the request may succeed if retried,
+// that is, the rate limit, a server error or a network error.
+func IsTransient(err error) bool {
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.StatusCode == http.StatusTooManyRequests || se.StatusCode >= http.StatusInternalServerError
+	}
+	var ne net.Error
+	return errors.As(err, &ne)
+}
+
+// RateLimit is the rate limit of the API reported by the X-Rate-Limit headers of the last response.
+type RateLimit struct {
+	Limit     int
+	Remaining int
+}
+
+// RateLimited is implemented by the clients reporting the rate limit.
+type RateLimited interface {
+	// RateLimit returns the rate limit of the last response, and false if it is not reported yet.
+	RateLimit() (RateLimit, bool)
+}
+
+// IsRateLimited reports whether the error is the response rejected by the rate limit.
+func IsRateLimited(err error) bool {
+	var se *StatusError
+	return errors.As(err, &se) && se.StatusCode == http.StatusTooManyRequests
 }
 
 type clientImpl struct {
 	subdomain string
+	base      string
 	email     string
 	token     string
+
+	mu        sync.Mutex
+	rateLimit *RateLimit
 }
 
 func NewClient(subdomain, email, token string) Client {
@@ -37,6 +137,16 @@ func NewClient(subdomain, email, token string) Client {
 	}
 }
 
+// NewClientWithBaseURL returns the client of the API served at the base URL instead of the subdomain,
+// such as a mock server.
+func NewClientWithBaseURL(baseURL, email, token string) Client {
+	return &clientImpl{
+		base:  strings.TrimSuffix(baseURL, "/"),
+		email: email,
+		token: token,
+	}
+}
+
 // refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/articles/#create-article
 func (c *clientImpl) CreateArticle(locale string, sectionID int, payload string) (string, error) {
 	endpoint := fmt.Sprintf(
@@ -60,15 +170,65 @@ func (c *clientImpl) UpdateArticle(locale string, articleID int, payload string)
 }
 
 // refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/articles/#show-article
+// An empty locale shows the article in the source locale.
 func (c *clientImpl) ShowArticle(locale string, articleID int) (string, error) {
 	endpoint := fmt.Sprintf(
-		"/api/v2/help_center/%s/articles/%d",
-		locale,
+		"/api/v2/help_center%s/articles/%d",
+		localePath(locale),
 		articleID,
 	)
 	return c.doRequest(http.MethodGet, endpoint, nil)
 }
 
+// refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/articles/#list-articles
+func (c *clientImpl) ListArticles(page int) (string, error) {
+	endpoint := fmt.Sprintf(
+		"/api/v2/help_center/articles?per_page=100&page=%d",
+		page,
+	)
+	return c.doRequest(http.MethodGet, endpoint, nil)
+}
+
+// ListArticlesWithTranslations lists the articles of the locale with their translations sideloaded.
+// refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/articles/#list-articles
+func (c *clientImpl) ListArticlesWithTranslations(locale string, page int) (string, error) {
+	endpoint := fmt.Sprintf(
+		"/api/v2/help_center%s/articles?include=translations&per_page=100&page=%d",
+		localePath(locale),
+		page,
+	)
+	return c.doRequest(http.MethodGet, endpoint, nil)
+}
+
+// refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/articles/#list-articles
+func (c *clientImpl) ListSectionArticles(sectionID int, page int) (string, error) {
+	endpoint := fmt.Sprintf(
+		"/api/v2/help_center/sections/%d/articles?per_page=100&page=%d",
+		sectionID,
+		page,
+	)
+	return c.doRequest(http.MethodGet, endpoint, nil)
+}
+
+// refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/incremental_export/#incremental-article-export
+func (c *clientImpl) ListArticlesSince(startTime int64) (string, error) {
+	endpoint := fmt.Sprintf(
+		"/api/v2/help_center/incremental/articles?start_time=%d",
+		startTime,
+	)
+	return c.doRequest(http.MethodGet, endpoint, nil)
+}
+
+// refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/articles/#update-article
+func (c *clientImpl) MoveArticle(articleID int, sectionID int) (string, error) {
+	endpoint := fmt.Sprintf(
+		"/api/v2/help_center/articles/%d",
+		articleID,
+	)
+	_payload := strings.NewReader(fmt.Sprintf(`{"article":{"section_id":%d}}`, sectionID))
+	return c.doRequest(http.MethodPut, endpoint, _payload)
+}
+
 // refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/translations/#create-translation
 func (c *clientImpl) CreateTranslation(articleID int, payload string) (string, error) {
 	endpoint := fmt.Sprintf(
@@ -100,7 +260,242 @@ func (c *clientImpl) ShowTranslation(articleID int, locale string) (string, erro
 	return c.doRequest(http.MethodGet, endpoint, nil)
 }
 
+// refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/translations/#list-translations
+func (c *clientImpl) ListTranslations(articleID int, page int) (string, error) {
+	endpoint := fmt.Sprintf(
+		"/api/v2/help_center/articles/%d/translations?per_page=100&page=%d",
+		articleID,
+		page,
+	)
+	return c.doRequest(http.MethodGet, endpoint, nil)
+}
+
+// refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/sections/#create-section
+func (c *clientImpl) CreateSection(locale string, categoryID int, payload string) (string, error) {
+	endpoint := fmt.Sprintf(
+		"/api/v2/help_center/%s/categories/%d/sections.json",
+		locale,
+		categoryID,
+	)
+	_payload := strings.NewReader(payload)
+	return c.doRequest(http.MethodPost, endpoint, _payload)
+}
+
+// refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/sections/#show-section
+// An empty locale shows the section in the source locale.
+func (c *clientImpl) ShowSection(locale string, sectionID int) (string, error) {
+	endpoint := fmt.Sprintf(
+		"/api/v2/help_center%s/sections/%d",
+		localePath(locale),
+		sectionID,
+	)
+	return c.doRequest(http.MethodGet, endpoint, nil)
+}
+
+// refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/sections/#list-sections
+func (c *clientImpl) ListSections(locale string, page int) (string, error) {
+	endpoint := fmt.Sprintf(
+		"/api/v2/help_center%s/sections?per_page=100&page=%d",
+		localePath(locale),
+		page,
+	)
+	return c.doRequest(http.MethodGet, endpoint, nil)
+}
+
+// refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/categories/#show-category
+func (c *clientImpl) ShowCategory(locale string, categoryID int) (string, error) {
+	endpoint := fmt.Sprintf(
+		"/api/v2/help_center%s/categories/%d",
+		localePath(locale),
+		categoryID,
+	)
+	return c.doRequest(http.MethodGet, endpoint, nil)
+}
+
+// refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/categories/#list-categories
+func (c *clientImpl) ListCategories(locale string, page int) (string, error) {
+	endpoint := fmt.Sprintf(
+		"/api/v2/help_center%s/categories?per_page=100&page=%d",
+		localePath(locale),
+		page,
+	)
+	return c.doRequest(http.MethodGet, endpoint, nil)
+}
+
+// refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/user_segments/#show-user-segment
+func (c *clientImpl) ShowUserSegment(userSegmentID int) (string, error) {
+	endpoint := fmt.Sprintf(
+		"/api/v2/help_center/user_segments/%d",
+		userSegmentID,
+	)
+	return c.doRequest(http.MethodGet, endpoint, nil)
+}
+
+// refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/permission_groups/#show-permission-group
+func (c *clientImpl) ShowPermissionGroup(permissionGroupID int) (string, error) {
+	endpoint := fmt.Sprintf(
+		"/api/v2/guide/permission_groups/%d",
+		permissionGroupID,
+	)
+	return c.doRequest(http.MethodGet, endpoint, nil)
+}
+
+// refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/help_center_locales/#list-all-enabled-locales-and-default-locale
+func (c *clientImpl) ListLocales() (string, error) {
+	return c.doRequest(http.MethodGet, "/api/v2/help_center/locales", nil)
+}
+
+// refs: https://developer.zendesk.com/api-reference/ticketing/users/users/#show-self
+func (c *clientImpl) ShowCurrentUser() (string, error) {
+	return c.doRequest(http.MethodGet, "/api/v2/users/me", nil)
+}
+
+// refs: https://developer.zendesk.com/api-reference/ticketing/users/users/#show-user
+func (c *clientImpl) ShowUser(userID int) (string, error) {
+	endpoint := fmt.Sprintf(
+		"/api/v2/users/%d",
+		userID,
+	)
+	return c.doRequest(http.MethodGet, endpoint, nil)
+}
+
+// refs: https://developer.zendesk.com/api-reference/ticketing/users/users/#search-users
+func (c *clientImpl) SearchUsers(query string) (string, error) {
+	endpoint := fmt.Sprintf(
+		"/api/v2/users/search?query=%s",
+		url.QueryEscape(query),
+	)
+	return c.doRequest(http.MethodGet, endpoint, nil)
+}
+
+// refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/user_segments/#list-user-segments-for-user
+func (c *clientImpl) ListUserSegmentsForUser(userID int, page int) (string, error) {
+	endpoint := fmt.Sprintf(
+		"/api/v2/help_center/users/%d/user_segments?per_page=100&page=%d",
+		userID,
+		page,
+	)
+	return c.doRequest(http.MethodGet, endpoint, nil)
+}
+
+// refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/subscriptions/#list-article-subscriptions
+func (c *clientImpl) ListArticleSubscriptions(articleID int, page int) (string, error) {
+	endpoint := fmt.Sprintf(
+		"/api/v2/help_center/articles/%d/subscriptions?per_page=100&page=%d",
+		articleID,
+		page,
+	)
+	return c.doRequest(http.MethodGet, endpoint, nil)
+}
+
+// refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/subscriptions/#create-article-subscription
+func (c *clientImpl) CreateArticleSubscription(articleID int, payload string) (string, error) {
+	endpoint := fmt.Sprintf(
+		"/api/v2/help_center/articles/%d/subscriptions",
+		articleID,
+	)
+	_payload := strings.NewReader(payload)
+	return c.doRequest(http.MethodPost, endpoint, _payload)
+}
+
+// refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/article_comments/#list-comments
+func (c *clientImpl) ListArticleComments(articleID int, page int) (string, error) {
+	endpoint := fmt.Sprintf(
+		"/api/v2/help_center/articles/%d/comments?per_page=100&page=%d",
+		articleID,
+		page,
+	)
+	return c.doRequest(http.MethodGet, endpoint, nil)
+}
+
+// refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/article_comments/#delete-comment
+func (c *clientImpl) DeleteArticleComment(articleID int, commentID int) error {
+	endpoint := fmt.Sprintf(
+		"/api/v2/help_center/articles/%d/comments/%d",
+		articleID,
+		commentID,
+	)
+	_, err := c.doRequest(http.MethodDelete, endpoint, nil)
+	return err
+}
+
+// refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/posts/#list-posts
+// A zero topic lists the posts of all the topics.
+func (c *clientImpl) ListPosts(topicID int, page int) (string, error) {
+	endpoint := fmt.Sprintf("/api/v2/community/posts?per_page=100&page=%d", page)
+	if topicID != 0 {
+		endpoint = fmt.Sprintf(
+			"/api/v2/community/topics/%d/posts?per_page=100&page=%d",
+			topicID,
+			page,
+		)
+	}
+	return c.doRequest(http.MethodGet, endpoint, nil)
+}
+
+// refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/posts/#show-post
+func (c *clientImpl) ShowPost(postID int) (string, error) {
+	endpoint := fmt.Sprintf(
+		"/api/v2/community/posts/%d",
+		postID,
+	)
+	return c.doRequest(http.MethodGet, endpoint, nil)
+}
+
+// refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/posts/#create-post
+func (c *clientImpl) CreatePost(payload string) (string, error) {
+	_payload := strings.NewReader(payload)
+	return c.doRequest(http.MethodPost, "/api/v2/community/posts", _payload)
+}
+
+// refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/posts/#update-post
+func (c *clientImpl) UpdatePost(postID int, payload string) (string, error) {
+	endpoint := fmt.Sprintf(
+		"/api/v2/community/posts/%d",
+		postID,
+	)
+	_payload := strings.NewReader(payload)
+	return c.doRequest(http.MethodPut, endpoint, _payload)
+}
+
+// refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/article_attachments/#create-article-attachment
+func (c *clientImpl) CreateArticleAttachment(articleID int, fileName string, content io.Reader, inline bool) (string, error) {
+	endpoint := fmt.Sprintf(
+		"/api/v2/help_center/articles/%d/attachments",
+		articleID,
+	)
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	if err := w.WriteField("inline", strconv.FormatBool(inline)); err != nil {
+		return "", err
+	}
+	part, err := w.CreateFormFile("file", fileName)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(part, content); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return c.send(http.MethodPost, endpoint, w.FormDataContentType(), &buf)
+}
+
+// localePath returns the path segment of the locale in the endpoints, which is omitted for an empty locale.
+func localePath(locale string) string {
+	if locale == "" {
+		return ""
+	}
+	return "/" + locale
+}
+
 func (c *clientImpl) doRequest(method string, endpoint string, payload io.Reader) (string, error) {
+	return c.send(method, endpoint, "application/json", payload)
+}
+
+// send sends the payload of the content type to the endpoint and returns the body of the response.
+func (c *clientImpl) send(method string, endpoint string, contentType string, payload io.Reader) (string, error) {
 	if endpoint == "" {
 		return "", fmt.Errorf("endpoint is required")
 	}
@@ -110,18 +505,31 @@ func (c *clientImpl) doRequest(method string, endpoint string, payload io.Reader
 		return "", err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", contentType)
 	req.Header.Set("Authorization", "Basic "+c.authorizationToken())
 
 	client := &http.Client{}
 	res, err := client.Do(req)
 	if err != nil {
+		metrics.APIErrors.Inc()
 		return "", err
 	}
 	defer res.Body.Close()
+	c.updateRateLimit(res.Header)
 
-	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
-		return "", fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated && res.StatusCode != http.StatusNoContent {
+		metrics.APIErrors.Inc()
+		se := &StatusError{StatusCode: res.StatusCode, Path: endpoint}
+		if body, err := io.ReadAll(res.Body); err == nil {
+			se.parseErrorBody(body)
+		}
+		if res.StatusCode == http.StatusTooManyRequests {
+			metrics.RateLimits.Inc()
+			if sec, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil {
+				se.RetryAfter = time.Duration(sec) * time.Second
+			}
+		}
+		return "", se
 	}
 
 	resPayload, err := io.ReadAll(res.Body)
@@ -131,7 +539,34 @@ func (c *clientImpl) doRequest(method string, endpoint string, payload io.Reader
 	return string(resPayload), nil
 }
 
+// updateRateLimit records the rate limit of the response if it is reported.
+func (c *clientImpl) updateRateLimit(h http.Header) {
+	limit, err := strconv.Atoi(h.Get("X-Rate-Limit"))
+	if err != nil {
+		return
+	}
+	remaining, err := strconv.Atoi(h.Get("X-Rate-Limit-Remaining"))
+	if err != nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rateLimit = &RateLimit{Limit: limit, Remaining: remaining}
+}
+
+func (c *clientImpl) RateLimit() (RateLimit, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.rateLimit == nil {
+		return RateLimit{}, false
+	}
+	return *c.rateLimit, true
+}
+
 func (c *clientImpl) baseURL() string {
+	if c.base != "" {
+		return c.base
+	}
 	return fmt.Sprintf(BaseURL, c.subdomain)
 }
 
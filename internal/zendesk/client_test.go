@@ -0,0 +1,119 @@
+package zendesk
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"rate limit", &StatusError{StatusCode: 429}, true},
+		{"server error", fmt.Errorf("article 1: %w", &StatusError{StatusCode: 503}), true},
+		{"network error", &url.Error{Op: "Put", URL: "https://example.zendesk.com", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}, true},
+		{"not found", &StatusError{StatusCode: 404}, false},
+		{"other error", errors.New("invalid payload"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTransient(tt.err); got != tt.expected {
+				t.Errorf("IsTransient() failed: got %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	c := &clientImpl{}
+	if _, ok := c.RateLimit(); ok {
+		t.Error("RateLimit() should not be reported before the responses")
+	}
+	c.updateRateLimit(http.Header{"X-Rate-Limit": {"700"}, "X-Rate-Limit-Remaining": {"42"}})
+	c.updateRateLimit(http.Header{})
+	if r, ok := c.RateLimit(); !ok || r != (RateLimit{Limit: 700, Remaining: 42}) {
+		t.Errorf("RateLimit() failed: got %+v, %v", r, ok)
+	}
+}
+
+func TestDeleteArticleComment(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Method + " " + r.URL.Path
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c := NewClientWithBaseURL(srv.URL, "user@example.com", "token")
+	if err := c.DeleteArticleComment(1, 2); err != nil {
+		t.Fatalf("DeleteArticleComment() failed: %v", err)
+	}
+	if got != "DELETE /api/v2/help_center/articles/1/comments/2" {
+		t.Errorf("DeleteArticleComment() failed: got %s", got)
+	}
+}
+
+func TestLocalePath(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.URL.RequestURI())
+		fmt.Fprint(w, `{}`)
+	}))
+	defer srv.Close()
+
+	c := NewClientWithBaseURL(srv.URL, "user@example.com", "token")
+	for _, locale := range []string{"ja", ""} {
+		if _, err := c.ShowCategory(locale, 1); err != nil {
+			t.Fatalf("ShowCategory() failed: %v", err)
+		}
+	}
+	if _, err := c.ListTranslations(1, 2); err != nil {
+		t.Fatalf("ListTranslations() failed: %v", err)
+	}
+	expected := []string{
+		"/api/v2/help_center/ja/categories/1",
+		"/api/v2/help_center/categories/1",
+		"/api/v2/help_center/articles/1/translations?per_page=100&page=2",
+	}
+	if strings.Join(got, " ") != strings.Join(expected, " ") {
+		t.Errorf("requests failed: got %v, want %v", got, expected)
+	}
+}
+
+func TestCreateArticleAttachment(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f, h, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("no file is posted: %v", err)
+			return
+		}
+		b, _ := io.ReadAll(f)
+		got = fmt.Sprintf("%s %s %s:%s inline=%s", r.Method, r.URL.Path, h.Filename, b, r.FormValue("inline"))
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"article_attachment":{"id":3,"article_id":1,"file_name":"shot.png","content_url":"https://example.zendesk.com/hc/article_attachments/3"}}`)
+	}))
+	defer srv.Close()
+
+	c := NewClientWithBaseURL(srv.URL, "user@example.com", "token")
+	res, err := c.CreateArticleAttachment(1, "shot.png", strings.NewReader("png"), true)
+	if err != nil {
+		t.Fatalf("CreateArticleAttachment() failed: %v", err)
+	}
+	if got != "POST /api/v2/help_center/articles/1/attachments shot.png:png inline=true" {
+		t.Errorf("CreateArticleAttachment() failed: got %s", got)
+	}
+	a := &ArticleAttachment{}
+	if err := a.FromJson(res); err != nil || a.ContentURL != "https://example.zendesk.com/hc/article_attachments/3" {
+		t.Errorf("FromJson() failed: got %+v, %v", a, err)
+	}
+}
@@ -0,0 +1,33 @@
+package zendesk
+
+import "encoding/json"
+
+// Comment is the comment of a user on an article, whose body is HTML.
+// refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/article_comments/
+type Comment struct {
+	ID        int    `json:"id"`
+	AuthorID  int    `json:"author_id"`
+	Body      string `json:"body"`
+	HtmlURL   string `json:"html_url"`
+	Locale    string `json:"locale"`
+	SourceID  int    `json:"source_id"`
+	VoteSum   int    `json:"vote_sum"`
+	VoteCount int    `json:"vote_count"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type wrappedComments struct {
+	Comments []Comment `json:"comments"`
+	NextPage *string   `json:"next_page"`
+}
+
+// CommentsFromJson returns the comments of the page of the list and whether the next page exists.
+func CommentsFromJson(jsonStr string) ([]Comment, bool, error) {
+	wrapped := wrappedComments{}
+	err := json.Unmarshal([]byte(jsonStr), &wrapped)
+	if err != nil {
+		return nil, false, err
+	}
+	return wrapped.Comments, wrapped.NextPage != nil && *wrapped.NextPage != "", nil
+}
@@ -0,0 +1,203 @@
+package zendesk
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// LineEnding is the line ending of the files written, which is "\n" or "\r\n". If it is empty, the line ending of the
+// existing file is kept, and the new files are written with "\n".
+var LineEnding = ""
+
+var (
+	utf8BOM    = []byte{0xef, 0xbb, 0xbf}
+	utf16LEBOM = []byte{0xff, 0xfe}
+	utf16BEBOM = []byte{0xfe, 0xff}
+)
+
+// newTextReader returns the reader of the text of the file, which is read as UTF-8 without the BOM and with the line
+// endings of "\n", so that the files edited on Windows are read as the others. The files of UTF-16 with the BOM are
+// converted to UTF-8.
+func newTextReader(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(3)
+	switch {
+	case bytes.HasPrefix(head, utf8BOM):
+		if _, err := br.Discard(len(utf8BOM)); err != nil {
+			return nil, err
+		}
+	case bytes.HasPrefix(head, utf16LEBOM), bytes.HasPrefix(head, utf16BEBOM):
+		b, err := io.ReadAll(br)
+		if err != nil {
+			return nil, err
+		}
+		text, err := decodeUTF16(b)
+		if err != nil {
+			return nil, err
+		}
+		br = bufio.NewReader(bytes.NewReader(text))
+	}
+	return &crlfReader{r: br}, nil
+}
+
+// NormalizeText returns the text of the content of the file as newTextReader reads it, which fails if the text is
+// not UTF-8.
+func NormalizeText(b []byte) ([]byte, error) {
+	r, err := newTextReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	text, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := validUTF8(text); err != nil {
+		return nil, err
+	}
+	return text, nil
+}
+
+// ReadText reads the file as NormalizeText.
+func ReadText(path string) ([]byte, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	text, err := NormalizeText(b)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return text, nil
+}
+
+// validUTF8 returns the error of the first byte which is not UTF-8.
+func validUTF8(b []byte) error {
+	if utf8.Valid(b) {
+		return nil
+	}
+	for i := 0; i < len(b); {
+		r, size := utf8.DecodeRune(b[i:])
+		if r == utf8.RuneError && size == 1 {
+			return invalidUTF8Error(b[i], i)
+		}
+		i += size
+	}
+	return nil
+}
+
+func invalidUTF8Error(b byte, offset int) error {
+	return fmt.Errorf("the file is not encoded in UTF-8: invalid byte 0x%02x at offset %d, convert it to UTF-8", b, offset)
+}
+
+// decodeUTF16 converts the text of UTF-16 with the BOM to UTF-8.
+func decodeUTF16(b []byte) ([]byte, error) {
+	if len(b)%2 != 0 {
+		return nil, fmt.Errorf("the file of UTF-16 has an odd number of bytes")
+	}
+	big := bytes.HasPrefix(b, utf16BEBOM)
+	units := make([]uint16, 0, len(b)/2-1)
+	for i := 2; i+1 < len(b); i += 2 {
+		if big {
+			units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+		} else {
+			units = append(units, uint16(b[i+1])<<8|uint16(b[i]))
+		}
+	}
+	var buf bytes.Buffer
+	for _, r := range utf16.Decode(units) {
+		buf.WriteRune(r)
+	}
+	return buf.Bytes(), nil
+}
+
+// crlfReader reads "\r\n" as "\n", keeping the other "\r".
+type crlfReader struct {
+	r *bufio.Reader
+}
+
+func (c *crlfReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		b, err := c.r.ReadByte()
+		if err != nil {
+			if n > 0 {
+				return n, nil
+			}
+			return 0, err
+		}
+		if b == '\r' {
+			if next, err := c.r.Peek(1); err == nil && next[0] == '\n' {
+				continue
+			}
+		}
+		p[n] = b
+		n++
+	}
+	return n, nil
+}
+
+// lineEndingWriter writes the line endings as eol, whether they are "\n" or "\r\n".
+type lineEndingWriter struct {
+	w   io.Writer
+	eol string
+	// cr is set when the last byte written is "\r", which is written with the next byte unless it is "\n".
+	cr  bool
+	buf []byte
+}
+
+func (l *lineEndingWriter) Write(p []byte) (int, error) {
+	l.buf = l.buf[:0]
+	for _, b := range p {
+		if l.cr {
+			l.cr = false
+			if b != '\n' {
+				l.buf = append(l.buf, '\r')
+			}
+		}
+		switch b {
+		case '\r':
+			l.cr = true
+		case '\n':
+			l.buf = append(l.buf, l.eol...)
+		default:
+			l.buf = append(l.buf, b)
+		}
+	}
+	if _, err := l.w.Write(l.buf); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// close writes the "\r" left at the end.
+func (l *lineEndingWriter) close() error {
+	if !l.cr {
+		return nil
+	}
+	l.cr = false
+	_, err := l.w.Write([]byte{'\r'})
+	return err
+}
+
+// lineEndingOf returns the line ending of the file to write, which is given by LineEnding, or is the one of the
+// existing file if LineEnding is empty.
+func lineEndingOf(path string) string {
+	if LineEnding != "" {
+		return LineEnding
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "\n"
+	}
+	defer f.Close()
+	line, err := bufio.NewReader(f).ReadSlice('\n')
+	if err == nil && bytes.HasSuffix(line, []byte("\r\n")) {
+		return "\r\n"
+	}
+	return "\n"
+}
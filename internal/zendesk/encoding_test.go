@@ -0,0 +1,102 @@
+package zendesk
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNormalizeText(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  []byte
+		expected string
+		err      string
+	}{
+		{"UTF-8", []byte("---\ntitle: 日本語\n---\nbody\r"), "---\ntitle: 日本語\n---\nbody\r", ""},
+		{"BOM and CRLF", []byte("\xef\xbb\xbf---\r\ntitle: t\r\n---\r\nbody\r\n"), "---\ntitle: t\n---\nbody\n", ""},
+		{"UTF-16LE", []byte("\xff\xfe-\x00-\x00-\x00\r\x00\n\x00\xe5\x65\n\x00"), "---\n日\n", ""},
+		{"UTF-16BE", []byte("\xfe\xff\x00-\x00-\x00-\x00\n\x65\xe5"), "---\n日", ""},
+		{"Shift_JIS", []byte("---\ntitle: \x93\xfa\x96\x7b\n---\n"), "", "the file is not encoded in UTF-8: invalid byte 0x93 at offset 11, convert it to UTF-8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := NormalizeText(tt.content)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("NormalizeText() failed: got %v, want %s", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeText() failed: %v", err)
+			}
+			if string(actual) != tt.expected {
+				t.Errorf("NormalizeText() failed: got %q, want %q", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTranslationFromFileCRLF(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "1-ja.md")
+	if err := os.WriteFile(path, []byte("\xef\xbb\xbf---\r\ntitle: t\r\nlocale: ja\r\nsource_id: 1\r\n---\r\n# Heading\r\n\r\nbody\r\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tr := &Translation{}
+	if err := tr.FromFile(path); err != nil {
+		t.Fatalf("FromFile() failed: %v", err)
+	}
+	if tr.Title != "t" || tr.SourceID != 1 || tr.Body != "# Heading\n\nbody\n" {
+		t.Errorf("FromFile() failed: got %+v", tr)
+	}
+
+	if err := os.WriteFile(path, []byte("---\ntitle: t\n---\n\x82\xa0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := tr.FromFile(path); err == nil {
+		t.Error("FromFile() should fail for the file which is not UTF-8")
+	}
+}
+
+func TestWriteFileLineEnding(t *testing.T) {
+	defer func(eol string) { LineEnding = eol }(LineEnding)
+	write := func(path string) {
+		err := writeFile(path, func(w io.Writer) error {
+			_, err := io.WriteString(w, "---\ntitle: t\n---\nline\r\nlast\r")
+			return err
+		})
+		if err != nil {
+			t.Fatalf("writeFile() failed: %v", err)
+		}
+	}
+	dir := t.TempDir()
+	tests := []struct {
+		name     string
+		eol      string
+		existing string
+		expected string
+	}{
+		{"new file", "", "", "---\ntitle: t\n---\nline\nlast\r"},
+		{"kept CRLF", "", "---\r\ntitle: old\r\n", "---\r\ntitle: t\r\n---\r\nline\r\nlast\r"},
+		{"kept LF", "", "---\ntitle: old\r\n", "---\ntitle: t\n---\nline\nlast\r"},
+		{"CRLF", "\r\n", "", "---\r\ntitle: t\r\n---\r\nline\r\nlast\r"},
+		{"LF", "\n", "---\r\ntitle: old\r\n", "---\ntitle: t\n---\nline\nlast\r"},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			LineEnding = tt.eol
+			path := filepath.Join(dir, string(rune('a'+i))+".md")
+			if tt.existing != "" {
+				if err := os.WriteFile(path, []byte(tt.existing), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			write(path)
+			if b, _ := os.ReadFile(path); string(b) != tt.expected {
+				t.Errorf("writeFile() failed: got %q, want %q", b, tt.expected)
+			}
+		})
+	}
+}
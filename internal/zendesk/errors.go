@@ -0,0 +1,88 @@
+package zendesk
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+)
+
+// ErrorDetail is the validation error of a field in the error response.
+type ErrorDetail struct {
+	Error       string `json:"error,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// errorBody is the body of the error response, whose error is either a code such as RecordInvalid
+// or an object with the title and the message.
+// refs: https://developer.zendesk.com/api-reference/introduction/requests/#400-range
+type errorBody struct {
+	Error       json.RawMessage            `json:"error"`
+	Description string                     `json:"description"`
+	Details     map[string]json.RawMessage `json:"details"`
+}
+
+// parseErrorBody sets the code, the description and the details of the error response to the error.
+// The body which is not JSON is ignored.
+func (e *StatusError) parseErrorBody(body []byte) {
+	var b errorBody
+	if err := json.Unmarshal(body, &b); err != nil {
+		return
+	}
+	var code string
+	var object struct {
+		Title   string `json:"title"`
+		Message string `json:"message"`
+	}
+	switch {
+	case json.Unmarshal(b.Error, &code) == nil:
+		e.Code = code
+	case json.Unmarshal(b.Error, &object) == nil:
+		e.Code = object.Title
+		if b.Description == "" {
+			b.Description = object.Message
+		}
+	}
+	e.Description = b.Description
+
+	for field, raw := range b.Details {
+		var details []ErrorDetail
+		if err := json.Unmarshal(raw, &details); err != nil {
+			// some endpoints give the details as the messages.
+			details = nil
+			var messages []string
+			if err := json.Unmarshal(raw, &messages); err != nil {
+				continue
+			}
+			for _, m := range messages {
+				details = append(details, ErrorDetail{Description: m})
+			}
+		}
+		if e.Details == nil {
+			e.Details = map[string][]ErrorDetail{}
+		}
+		e.Details[field] = details
+	}
+}
+
+// detailMessages returns the descriptions of the details in the order of the fields.
+func (e *StatusError) detailMessages() []string {
+	fields := make([]string, 0, len(e.Details))
+	for field := range e.Details {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+	var messages []string
+	for _, field := range fields {
+		for _, d := range e.Details[field] {
+			m := d.Description
+			if m == "" {
+				m = d.Error
+			}
+			if m != "" && !strings.HasPrefix(strings.ToLower(m), strings.ToLower(field)) {
+				m = field + ": " + m
+			}
+			messages = append(messages, m)
+		}
+	}
+	return messages
+}
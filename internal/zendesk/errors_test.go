@@ -0,0 +1,42 @@
+package zendesk
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStatusErrorBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		code     string
+		expected string
+	}{
+		{"validation", `{"error":"RecordInvalid","description":"Record validation errors","details":{"title":[{"description":"Title: cannot be blank","error":"BlankValue"}],"permission_group_id":[{"description":"is invalid","error":"InvalidValue"}]}}`, "RecordInvalid", "unexpected status code: 422: permission_group_id: is invalid; Title: cannot be blank"},
+		{"object", `{"error":{"title":"Forbidden","message":"You do not have access to this page."}}`, "Forbidden", "unexpected status code: 422: You do not have access to this page."},
+		{"messages", `{"error":"InvalidLocale","details":{"locale":["is not enabled"]}}`, "InvalidLocale", "unexpected status code: 422: locale: is not enabled"},
+		{"not JSON", `<html>error</html>`, "", "unexpected status code: 422"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				w.Write([]byte(tt.body))
+			}))
+			defer ts.Close()
+
+			_, err := NewClientWithBaseURL(ts.URL, "user@example.com", "token").ShowArticle("ja", 1)
+			se, ok := err.(*StatusError)
+			if !ok {
+				t.Fatalf("ShowArticle() failed: unexpected error %v", err)
+			}
+			if se.Code != tt.code || se.Path != "/api/v2/help_center/ja/articles/1" {
+				t.Errorf("ShowArticle() failed: got code %q and path %q", se.Code, se.Path)
+			}
+			if se.Error() != tt.expected {
+				t.Errorf("Error() failed: got %q, want %q", se.Error(), tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package zendesk
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/adrg/frontmatter"
+	"gopkg.in/yaml.v3"
+)
+
+// FrontMatterTemplate is a skeleton of the front matter written by Save.
+// The keys of the skeleton determine the order of the fields, and the keys that are not fields
+// of the model are written as custom keys with the values of the skeleton.
+type FrontMatterTemplate struct {
+	keys   []string
+	values map[string]*yaml.Node
+}
+
+func NewFrontMatterTemplate(node *yaml.Node) (*FrontMatterTemplate, error) {
+	if node == nil || node.Kind == 0 {
+		return nil, nil
+	}
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("front matter template must be a mapping")
+	}
+	t := &FrontMatterTemplate{values: map[string]*yaml.Node{}}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		if _, ok := t.values[key]; ok {
+			return nil, fmt.Errorf("front matter template has a duplicated key %s", key)
+		}
+		t.keys = append(t.keys, key)
+		t.values[key] = node.Content[i+1]
+	}
+	return t, nil
+}
+
+// ApplyDefaults sets the values of the skeleton to the fields of v that have zero values.
+func (t *FrontMatterTemplate) ApplyDefaults(v interface{}) error {
+	if t == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(v).Elem()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		key, _, _ := strings.Cut(rt.Field(i).Tag.Get("yaml"), ",")
+		n, ok := t.values[key]
+		if !ok || key == "-" || !rv.Field(i).IsZero() || n.Tag == "!!null" {
+			continue
+		}
+		if err := n.Decode(rv.Field(i).Addr().Interface()); err != nil {
+			return fmt.Errorf("front matter template: %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// render returns the front matter of v ordered by the skeleton.
+// The values of the custom keys of the skeleton already written in the file are kept.
+func (t *FrontMatterTemplate) render(v interface{}, existing map[string]*yaml.Node) (*yaml.Node, error) {
+	encoded := &yaml.Node{}
+	if err := encoded.Encode(v); err != nil {
+		return nil, err
+	}
+	fields := map[string]*yaml.Node{}
+	var order []string
+	for i := 0; i+1 < len(encoded.Content); i += 2 {
+		key := encoded.Content[i].Value
+		fields[key] = encoded.Content[i+1]
+		order = append(order, key)
+	}
+
+	m := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	written := map[string]bool{}
+	for _, key := range t.keys {
+		written[key] = true
+		if value, ok := fields[key]; ok {
+			addKey(m, key, value)
+		} else if value, ok := existing[key]; ok {
+			addKey(m, key, value)
+		} else {
+			addKey(m, key, t.values[key])
+		}
+	}
+	for _, key := range order {
+		if !written[key] {
+			addKey(m, key, fields[key])
+		}
+	}
+	return m, nil
+}
+
+func addKey(m *yaml.Node, key string, value *yaml.Node) {
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
+
+// fieldKeys returns the keys of the front matter that are fields of v.
+func fieldKeys(v interface{}) map[string]bool {
+	keys := map[string]bool{}
+	rt := reflect.TypeOf(v)
+	if rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	if rt.Kind() != reflect.Struct {
+		return keys
+	}
+	for i := 0; i < rt.NumField(); i++ {
+		if key, _, _ := strings.Cut(rt.Field(i).Tag.Get("yaml"), ","); key != "" && key != "-" {
+			keys[key] = true
+		}
+	}
+	return keys
+}
+
+// readCustomKeys reads the keys of the front matter in the file that are not fields of the model,
+// in the order they are written.
+func readCustomKeys(path string, fields map[string]bool) ([]string, map[string]*yaml.Node, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	r, err := newTextReader(f)
+	if err != nil {
+		return nil, nil, err
+	}
+	var fm yaml.Node
+	format := frontmatter.NewFormat("---", "---", yaml.Unmarshal)
+	if _, err := frontmatter.Parse(r, &fm, format); err != nil || len(fm.Content) == 0 || fm.Content[0].Kind != yaml.MappingNode {
+		// the file is overwritten regardless of its current front matter.
+		return nil, nil, nil
+	}
+	var keys []string
+	custom := map[string]*yaml.Node{}
+	m := fm.Content[0]
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		key := m.Content[i].Value
+		if _, ok := custom[key]; !ok && !fields[key] {
+			keys = append(keys, key)
+			custom[key] = m.Content[i+1]
+		}
+	}
+	return keys, custom, nil
+}
+
+// readOnlyComment is the comment of the informational keys, which are not pushed.
+const readOnlyComment = "read-only"
+
+// readOnlyKeys returns the keys of the fields of v tagged with readonly.
+func readOnlyKeys(v interface{}) map[string]bool {
+	keys := map[string]bool{}
+	rt := reflect.TypeOf(v)
+	if rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	if rt.Kind() != reflect.Struct {
+		return keys
+	}
+	for i := 0; i < rt.NumField(); i++ {
+		if _, ok := rt.Field(i).Tag.Lookup("readonly"); ok {
+			key, _, _ := strings.Cut(rt.Field(i).Tag.Get("yaml"), ",")
+			keys[key] = true
+		}
+	}
+	return keys
+}
+
+// writeFrontMatter writes v as the front matter, ordered by the template if any.
+// The informational keys are marked with a comment, and the custom keys already written in the file
+// at path are kept as they are after the others.
+func writeFrontMatter(w io.Writer, v interface{}, path string, tmpl *FrontMatterTemplate) error {
+	customKeys, custom, err := readCustomKeys(path, fieldKeys(v))
+	if err != nil {
+		return err
+	}
+	m := &yaml.Node{}
+	if tmpl != nil {
+		if m, err = tmpl.render(v, custom); err != nil {
+			return err
+		}
+	} else if err := m.Encode(v); err != nil {
+		return err
+	}
+	readOnly := readOnlyKeys(v)
+	written := map[string]bool{}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		written[m.Content[i].Value] = true
+		if readOnly[m.Content[i].Value] {
+			m.Content[i+1].LineComment = readOnlyComment
+		}
+	}
+	for _, key := range customKeys {
+		if !written[key] {
+			addKey(m, key, custom[key])
+		}
+	}
+
+	if _, err := io.WriteString(w, "---\n"); err != nil {
+		return err
+	}
+	ye := yaml.NewEncoder(w)
+	ye.SetIndent(2)
+	if err := ye.Encode(m); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, "---\n"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// writeFile writes the file through a buffer into a temporary file, which replaces the file when it is completed.
+// The mode of the existing file is kept, and the line endings are written as lineEndingOf the file.
+func writeFile(path string, write func(w io.Writer) error) (err error) {
+	mode := os.FileMode(0o644)
+	if fi, err := os.Stat(path); err == nil {
+		mode = fi.Mode().Perm()
+	}
+	eol := lineEndingOf(path)
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+	w := bufio.NewWriter(f)
+	lw := &lineEndingWriter{w: w, eol: eol}
+	if err := write(lw); err != nil {
+		return err
+	}
+	if err := lw.close(); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if err := f.Chmod(mode); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,196 @@
+package zendesk
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func newTestTemplate(t *testing.T, src string) *FrontMatterTemplate {
+	t.Helper()
+	node := &yaml.Node{}
+	if err := yaml.Unmarshal([]byte(src), node); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := NewFrontMatterTemplate(node)
+	if err != nil {
+		t.Fatalf("NewFrontMatterTemplate() failed: %v", err)
+	}
+	return tmpl
+}
+
+func TestNewFrontMatterTemplate(t *testing.T) {
+	tmpl, err := NewFrontMatterTemplate(nil)
+	if err != nil || tmpl != nil {
+		t.Errorf("NewFrontMatterTemplate() failed: got %v, %v", tmpl, err)
+	}
+
+	for _, src := range []string{"- title", "title:\ntitle:\n"} {
+		node := &yaml.Node{}
+		if err := yaml.Unmarshal([]byte(src), node); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := NewFrontMatterTemplate(node); err == nil {
+			t.Errorf("NewFrontMatterTemplate() failed: %q should be an error", src)
+		}
+	}
+}
+
+func TestFrontMatterTemplateApplyDefaults(t *testing.T) {
+	tmpl := newTestTemplate(t, "title:\nuser_segment_id: 99\npermission_group_id: 12\nowner: docs-team\n")
+
+	a := &Article{Title: "Foo", PermissionGroupID: 34}
+	if err := tmpl.ApplyDefaults(a); err != nil {
+		t.Fatalf("ApplyDefaults() failed: %v", err)
+	}
+	if a.Title != "Foo" {
+		t.Errorf("article.Title failed: got %v, want %v", a.Title, "Foo")
+	}
+	if a.UserSegmentID == nil || *a.UserSegmentID != 99 {
+		t.Errorf("article.UserSegmentID failed: got %v, want %v", a.UserSegmentID, 99)
+	}
+	if a.PermissionGroupID != 34 {
+		t.Errorf("article.PermissionGroupID failed: got %v, want %v", a.PermissionGroupID, 34)
+	}
+
+	var nilTmpl *FrontMatterTemplate
+	if err := nilTmpl.ApplyDefaults(a); err != nil {
+		t.Errorf("ApplyDefaults() failed: %v", err)
+	}
+}
+
+func TestTranslationSaveWithTemplate(t *testing.T) {
+	tmpl := newTestTemplate(t, "title:\nsource_id:\nlocale:\nreviewed: false\n")
+	tr := &Translation{Title: "Foo", Locale: "ja", SourceID: 100, Body: "body\n"}
+
+	path := filepath.Join(t.TempDir(), "100-ja.md")
+	if err := tr.SaveWithTemplate(path, false, tmpl); err != nil {
+		t.Fatalf("SaveWithTemplate() failed: %v", err)
+	}
+	expected := "---\ntitle: Foo\nsource_id: 100\nlocale: ja\nreviewed: false\ndraft: false\noutdated: false\nhtml_url: \"\" # read-only\n---\nbody\n"
+	if b, _ := os.ReadFile(path); string(b) != expected {
+		t.Errorf("SaveWithTemplate() failed: got %q, want %q", string(b), expected)
+	}
+
+	// the custom keys already written in the file are kept.
+	if err := os.WriteFile(path, []byte("---\ntitle: Old\nreviewed: true\n---\nold\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := tr.SaveWithTemplate(path, false, tmpl); err != nil {
+		t.Fatalf("SaveWithTemplate() failed: %v", err)
+	}
+	expected = "---\ntitle: Foo\nsource_id: 100\nlocale: ja\nreviewed: true\ndraft: false\noutdated: false\nhtml_url: \"\" # read-only\n---\nbody\n"
+	if b, _ := os.ReadFile(path); string(b) != expected {
+		t.Errorf("SaveWithTemplate() failed: got %q, want %q", string(b), expected)
+	}
+}
+
+func TestTranslationSaveReadOnly(t *testing.T) {
+	tr := &Translation{
+		Title:     "Foo",
+		Locale:    "ja",
+		SourceID:  100,
+		HtmlURL:   "https://example.zendesk.com/hc/ja/articles/100",
+		CreatedAt: "2024-01-01T00:00:00Z",
+		UpdatedAt: "2024-02-01T00:00:00Z",
+		AuthorID:  10,
+		Body:      "body\n",
+	}
+	path := filepath.Join(t.TempDir(), "100-ja.md")
+	if err := tr.Save(path, false); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	expected := "---\ntitle: Foo\nlocale: ja\ndraft: false\noutdated: false\nsource_id: 100\n" +
+		"html_url: https://example.zendesk.com/hc/ja/articles/100 # read-only\n" +
+		"created_at: \"2024-01-01T00:00:00Z\" # read-only\n" +
+		"updated_at: \"2024-02-01T00:00:00Z\" # read-only\n" +
+		"author_id: 10 # read-only\n---\nbody\n"
+	if b, _ := os.ReadFile(path); string(b) != expected {
+		t.Errorf("Save() failed: got %q, want %q", string(b), expected)
+	}
+
+	// the informational keys are read back but not pushed.
+	read := &Translation{}
+	if err := read.FromFile(path); err != nil {
+		t.Fatalf("FromFile() failed: %v", err)
+	}
+	if read.UpdatedAt != tr.UpdatedAt || read.AuthorID != tr.AuthorID {
+		t.Errorf("FromFile() failed: got %+v", read)
+	}
+}
+
+func TestTranslationSaveCustomKeys(t *testing.T) {
+	tr := &Translation{Title: "Foo", Locale: "ja", SourceID: 100, Body: "body\n"}
+	existing := "---\nowner: docs-team\ntitle: Old\nreviewed: true\nreviewers:\n  - alice\n  - bob # lead\n---\nold\n"
+
+	tests := []struct {
+		name     string
+		tmpl     *FrontMatterTemplate
+		expected string
+	}{
+		{
+			"without template",
+			nil,
+			"---\ntitle: Foo\nlocale: ja\ndraft: false\noutdated: false\nsource_id: 100\nhtml_url: \"\" # read-only\n" +
+				"owner: docs-team\nreviewed: true\nreviewers:\n  - alice\n  - bob # lead\n---\nbody\n",
+		},
+		{
+			"with template",
+			newTestTemplate(t, "title:\nreviewed: false\n"),
+			"---\ntitle: Foo\nreviewed: true\nlocale: ja\ndraft: false\noutdated: false\nsource_id: 100\nhtml_url: \"\" # read-only\n" +
+				"owner: docs-team\nreviewers:\n  - alice\n  - bob # lead\n---\nbody\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "100-ja.md")
+			if err := os.WriteFile(path, []byte(existing), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if err := tr.SaveWithTemplate(path, false, tt.tmpl); err != nil {
+				t.Fatalf("SaveWithTemplate() failed: %v", err)
+			}
+			if b, _ := os.ReadFile(path); string(b) != tt.expected {
+				t.Errorf("SaveWithTemplate() failed: got %q, want %q", string(b), tt.expected)
+			}
+		})
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "1-ja.md")
+	if err := os.WriteFile(path, []byte("old"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	err := writeFile(path, func(w io.Writer) error {
+		_, err := io.WriteString(w, "new")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("writeFile() failed: %v", err)
+	}
+	if b, _ := os.ReadFile(path); string(b) != "new" {
+		t.Errorf("writeFile() failed: got %q", b)
+	}
+	if fi, err := os.Stat(path); err != nil || fi.Mode().Perm() != 0o600 {
+		t.Errorf("writeFile() failed: the mode is not kept: %v", fi.Mode())
+	}
+
+	// the file is left as it is if writing fails.
+	err = writeFile(path, func(w io.Writer) error {
+		_, _ = io.WriteString(w, "broken")
+		return io.ErrUnexpectedEOF
+	})
+	if err == nil {
+		t.Fatal("writeFile() should fail")
+	}
+	if b, _ := os.ReadFile(path); string(b) != "new" {
+		t.Errorf("writeFile() failed: got %q", b)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("writeFile() failed: the temporary file is left: %v", entries)
+	}
+}
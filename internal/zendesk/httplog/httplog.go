@@ -2,8 +2,7 @@ package httplog
 
 import (
 	"context"
-	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"time"
 )
@@ -12,6 +11,9 @@ func init() {
 	http.DefaultTransport = DefaultTransport
 }
 
+// LevelTrace is the level of the logs of the requests and the responses, which is below debug.
+const LevelTrace = slog.LevelDebug - 4
+
 type Transport struct {
 	Transport   http.RoundTripper
 	LogRequest  func(req *http.Request)
@@ -29,17 +31,16 @@ var DefaultTransport = &Transport{
 }
 
 func DefaultLogRequest(req *http.Request) {
-	log.Printf("----> [%s] %s", req.Method, req.URL)
+	slog.Log(req.Context(), LevelTrace, "request", "method", req.Method, "url", req.URL.String())
 }
 
 func DefaultLogResponse(res *http.Response) {
 	ctx := res.Request.Context()
 	if requestedAt, ok := ctx.Value(ContextRequestKey).(time.Time); ok {
-		d := fmt.Sprintf("%dms", time.Duration(time.Since(requestedAt)).Milliseconds())
-		pd, _ := time.ParseDuration(d)
-		log.Printf("<---- [%s] %s (%s)", res.Status, res.Request.URL, pd)
+		d := time.Since(requestedAt).Truncate(time.Millisecond)
+		slog.Log(ctx, LevelTrace, "response", "status", res.StatusCode, "url", res.Request.URL.String(), "duration", d)
 	} else {
-		log.Printf("<---- [%s] %s", res.Status, res.Request.URL)
+		slog.Log(ctx, LevelTrace, "response", "status", res.StatusCode, "url", res.Request.URL.String())
 	}
 }
 
@@ -0,0 +1,76 @@
+package zendesk
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+
+	"github.com/adrg/frontmatter"
+)
+
+// HugoPage is a content file of Hugo, whose front matter is written in YAML, TOML or JSON.
+// The page is pushed as the translation of the article given by source_id, which Hugo ignores.
+// refs: https://gohugo.io/content-management/front-matter/
+type HugoPage struct {
+	Title   string   `yaml:"title" toml:"title" json:"title"`
+	Slug    string   `yaml:"slug" toml:"slug" json:"slug"`
+	Draft   bool     `yaml:"draft" toml:"draft" json:"draft"`
+	Weight  int      `yaml:"weight" toml:"weight" json:"weight"`
+	Aliases []string `yaml:"aliases" toml:"aliases" json:"aliases"`
+	Tags    []string `yaml:"tags" toml:"tags" json:"tags"`
+
+	SourceID  int    `yaml:"source_id" toml:"source_id" json:"source_id"`
+	SectionID int    `yaml:"section_id" toml:"section_id" json:"section_id"`
+	Locale    string `yaml:"locale" toml:"locale" json:"locale"`
+	Brand     string `yaml:"brand" toml:"brand" json:"brand"`
+	PublishAt string `yaml:"publish_at" toml:"publish_at" json:"publish_at"`
+	Body      string `yaml:"-" toml:"-" json:"-"`
+}
+
+// FromFile reads the page. The locale falls back to the language of the file name such as about.ja.md,
+// which is the convention of multilingual Hugo sites.
+func (p *HugoPage) FromFile(path string) error {
+	b, err := ReadText(path)
+	if err != nil {
+		return err
+	}
+	body, err := frontmatter.Parse(bytes.NewReader(b), p)
+	if err != nil {
+		return err
+	}
+	p.Body = string(body)
+	if p.Locale == "" {
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		if ext := filepath.Ext(name); ext != "" {
+			p.Locale = ext[1:]
+		}
+	}
+	return nil
+}
+
+// Translation returns the translation of the page.
+func (p *HugoPage) Translation() *Translation {
+	return &Translation{
+		Title:     p.Title,
+		Locale:    p.Locale,
+		Draft:     p.Draft,
+		SectionID: p.SectionID,
+		SourceID:  p.SourceID,
+		PublishAt: p.PublishAt,
+		Brand:     p.Brand,
+		Body:      p.Body,
+	}
+}
+
+// Article returns the article of the page. The weight is mapped to the position and the tags to the labels.
+func (p *HugoPage) Article() *Article {
+	return &Article{
+		ID:         p.SourceID,
+		Title:      p.Title,
+		Locale:     p.Locale,
+		SectionID:  p.SectionID,
+		Brand:      p.Brand,
+		Position:   p.Weight,
+		LabelNames: p.Tags,
+	}
+}
@@ -0,0 +1,39 @@
+package zendesk
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHugoPageFromFile(t *testing.T) {
+	tests := []struct {
+		filepath    string
+		translation Translation
+		article     Article
+	}{
+		{
+			"testdata/hugo/about.ja.md",
+			Translation{Title: "zgsyncについて", Locale: "ja", Draft: true, SectionID: 678, SourceID: 12345, Body: "# zgsyncについて\n"},
+			Article{ID: 12345, Title: "zgsyncについて", Locale: "ja", SectionID: 678, Position: 20, LabelNames: []string{"intro", "cli"}},
+		},
+		{
+			"testdata/hugo/setup.md",
+			Translation{Title: "Setup", Locale: "en-us", SourceID: 23456, Body: "# Setup\n"},
+			Article{ID: 23456, Title: "Setup", Locale: "en-us", Position: 10},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filepath, func(t *testing.T) {
+			p := &HugoPage{}
+			if err := p.FromFile(tt.filepath); err != nil {
+				t.Fatalf("FromFile() failed: %v", err)
+			}
+			if actual := p.Translation(); !reflect.DeepEqual(*actual, tt.translation) {
+				t.Errorf("Translation() failed: got %+v, want %+v", *actual, tt.translation)
+			}
+			if actual := p.Article(); !reflect.DeepEqual(*actual, tt.article) {
+				t.Errorf("Article() failed: got %+v, want %+v", *actual, tt.article)
+			}
+		})
+	}
+}
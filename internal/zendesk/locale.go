@@ -0,0 +1,22 @@
+package zendesk
+
+import "encoding/json"
+
+// refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/help_center_locales/
+type Locales struct {
+	Locales       []string `json:"locales"`
+	DefaultLocale string   `json:"default_locale"`
+}
+
+func (l *Locales) FromJson(jsonStr string) error {
+	return json.Unmarshal([]byte(jsonStr), l)
+}
+
+func (l *Locales) Contains(locale string) bool {
+	for _, v := range l.Locales {
+		if v == locale {
+			return true
+		}
+	}
+	return false
+}
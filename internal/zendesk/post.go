@@ -0,0 +1,134 @@
+package zendesk
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/adrg/frontmatter"
+)
+
+// Post is the community post, whose body is details.
+// refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/posts/
+type Post struct {
+	ID       int    `json:"id,omitempty" yaml:"id"`
+	Title    string `json:"title" yaml:"title"`
+	TopicID  int    `json:"topic_id,omitempty" yaml:"topic_id"`
+	Featured bool   `json:"featured" yaml:"featured"`
+	Pinned   bool   `json:"pinned" yaml:"pinned"`
+	Closed   bool   `json:"closed" yaml:"closed"`
+	// Status is one of none, planned, not_planned, answered and completed.
+	Status string `json:"status,omitempty" yaml:"status,omitempty"`
+	// BodyFormat is the format of the body, which is markdown if empty.
+	BodyFormat string `json:"-" yaml:"body_format,omitempty"`
+	AuthorID   int    `json:"author_id,omitempty" yaml:"author_id,omitempty" readonly:""`
+	HtmlURL    string `json:"html_url,omitempty" yaml:"html_url,omitempty" readonly:""`
+	CreatedAt  string `json:"created_at,omitempty" yaml:"created_at,omitempty" readonly:""`
+	UpdatedAt  string `json:"updated_at,omitempty" yaml:"updated_at,omitempty" readonly:""`
+	Details    string `json:"details,omitempty" yaml:"-"`
+}
+
+type wrappedPost struct {
+	Post Post `json:"post"`
+}
+
+type wrappedPosts struct {
+	Posts    []Post  `json:"posts"`
+	NextPage *string `json:"next_page"`
+}
+
+// IsHTML reports whether the body is raw HTML, which is pushed as is without conversion.
+// An error is returned if body_format is neither markdown nor html.
+func (p *Post) IsHTML() (bool, error) {
+	switch p.BodyFormat {
+	case "", BodyFormatMarkdown:
+		return false, nil
+	case BodyFormatHTML:
+		return true, nil
+	}
+	return false, fmt.Errorf("body_format must be %s or %s: %s", BodyFormatMarkdown, BodyFormatHTML, p.BodyFormat)
+}
+
+func (p *Post) FromFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r, err := newTextReader(f)
+	if err != nil {
+		return err
+	}
+	body, err := frontmatter.Parse(r, &p)
+	if err != nil {
+		return err
+	}
+	if err := validUTF8(body); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	p.Details = string(body)
+	return nil
+}
+
+func (p *Post) FromJson(jsonStr string) error {
+	wrapped := wrappedPost{}
+	err := json.Unmarshal([]byte(jsonStr), &wrapped)
+	if err != nil {
+		return err
+	}
+	*p = wrapped.Post
+	return nil
+}
+
+// PostsFromJson returns the posts of the page of the list and whether the next page exists.
+func PostsFromJson(jsonStr string) ([]Post, bool, error) {
+	wrapped := wrappedPosts{}
+	err := json.Unmarshal([]byte(jsonStr), &wrapped)
+	if err != nil {
+		return nil, false, err
+	}
+	return wrapped.Posts, wrapped.NextPage != nil && *wrapped.NextPage != "", nil
+}
+
+// ToPayload returns the payload to create or update the post without the read-only fields.
+func (p *Post) ToPayload() (string, error) {
+	post := *p
+	post.ID = 0
+	post.AuthorID = 0
+	post.HtmlURL = ""
+	post.CreatedAt = ""
+	post.UpdatedAt = ""
+	b, err := json.Marshal(wrappedPost{Post: post})
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func (p *Post) FileName() string {
+	return strconv.Itoa(p.ID) + ".md"
+}
+
+func (p *Post) Save(path string, appendFileName bool) error {
+	dir := path
+	if !appendFileName {
+		dir = filepath.Dir(path)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	if appendFileName {
+		path = filepath.Join(path, p.FileName())
+	}
+	return writeFile(path, func(w io.Writer) error {
+		if err := writeFrontMatter(w, p, path, nil); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, p.Details)
+		return err
+	})
+}
@@ -0,0 +1,57 @@
+package zendesk
+
+import "encoding/json"
+
+// refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/sections/
+type Section struct {
+	CategoryID      int    `json:"category_id,omitempty"`
+	CreatedAt       string `json:"created_at,omitempty"`
+	Description     string `json:"description,omitempty"`
+	HtmlURL         string `json:"html_url,omitempty"`
+	ID              int    `json:"id,omitempty"`
+	Locale          string `json:"locale,omitempty"`
+	Name            string `json:"name"`
+	Outdated        bool   `json:"outdated,omitempty"`
+	ParentSectionID *int   `json:"parent_section_id,omitempty"`
+	Position        int    `json:"position,omitempty"`
+	SourceLocale    string `json:"source_locale,omitempty"`
+	UpdatedAt       string `json:"updated_at,omitempty"`
+	URL             string `json:"url,omitempty"`
+}
+
+type wrappedSection struct {
+	Section Section `json:"section"`
+}
+
+type wrappedSections struct {
+	Sections []Section `json:"sections"`
+	NextPage *string   `json:"next_page"`
+}
+
+func (s *Section) FromJson(jsonStr string) error {
+	wrapped := wrappedSection{}
+	err := json.Unmarshal([]byte(jsonStr), &wrapped)
+	if err != nil {
+		return err
+	}
+	*s = wrapped.Section
+	return nil
+}
+
+func (s *Section) ToPayload() (string, error) {
+	b, err := json.Marshal(wrappedSection{Section: *s})
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// SectionsFromJson returns the sections of the page of the list and whether the next page exists.
+func SectionsFromJson(jsonStr string) ([]Section, bool, error) {
+	wrapped := wrappedSections{}
+	err := json.Unmarshal([]byte(jsonStr), &wrapped)
+	if err != nil {
+		return nil, false, err
+	}
+	return wrapped.Sections, wrapped.NextPage != nil && *wrapped.NextPage != "", nil
+}
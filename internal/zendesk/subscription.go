@@ -0,0 +1,51 @@
+package zendesk
+
+import "encoding/json"
+
+// Subscription is the subscription of a user to the changes of an article.
+// refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/subscriptions/
+type Subscription struct {
+	ID              int    `json:"id,omitempty"`
+	UserID          int    `json:"user_id,omitempty"`
+	ContentID       int    `json:"content_id,omitempty"`
+	SourceLocale    string `json:"source_locale,omitempty"`
+	IncludeComments bool   `json:"include_comments,omitempty"`
+	CreatedAt       string `json:"created_at,omitempty"`
+}
+
+type wrappedSubscription struct {
+	Subscription Subscription `json:"subscription"`
+}
+
+type wrappedSubscriptions struct {
+	Subscriptions []Subscription `json:"subscriptions"`
+	NextPage      *string        `json:"next_page"`
+}
+
+func (s *Subscription) FromJson(jsonStr string) error {
+	wrapped := wrappedSubscription{}
+	err := json.Unmarshal([]byte(jsonStr), &wrapped)
+	if err != nil {
+		return err
+	}
+	*s = wrapped.Subscription
+	return nil
+}
+
+func (s *Subscription) ToPayload() (string, error) {
+	b, err := json.Marshal(wrappedSubscription{Subscription: *s})
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// SubscriptionsFromJson returns the subscriptions of the page of the list and whether the next page exists.
+func SubscriptionsFromJson(jsonStr string) ([]Subscription, bool, error) {
+	wrapped := wrappedSubscriptions{}
+	err := json.Unmarshal([]byte(jsonStr), &wrapped)
+	if err != nil {
+		return nil, false, err
+	}
+	return wrapped.Subscriptions, wrapped.NextPage != nil && *wrapped.NextPage != "", nil
+}
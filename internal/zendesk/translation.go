@@ -1,28 +1,39 @@
 package zendesk
 
 import (
-	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"strconv"
+	"time"
 
 	"github.com/adrg/frontmatter"
-	"gopkg.in/yaml.v3"
 )
 
 // refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/translations/#update-translation
 type Translation struct {
-	Title       string `json:"title" yaml:"title"`
-	Locale      string `json:"locale" yaml:"locale"`
-	Draft       bool   `json:"draft,omitempty" yaml:"draft"`
-	Outdated    bool   `json:"outdated,omitempty" yaml:"outdated"`
-	SectionID   int    `json:"-" yaml:"section_id,omitempty"`
-	SourceID    int    `json:"source_id,omitempty" yaml:"source_id"`
-	HtmlURL     string `json:"html_url,omitempty" yaml:"html_url"`
-	CreatedAt   string `json:"created_at,omitempty" yaml:"-"`
-	UpdatedAt   string `json:"updated_at,omitempty" yaml:"-"`
+	Title     string `json:"title" yaml:"title"`
+	Locale    string `json:"locale" yaml:"locale"`
+	Draft     bool   `json:"draft,omitempty" yaml:"draft"`
+	Outdated  bool   `json:"outdated,omitempty" yaml:"outdated"`
+	SectionID int    `json:"-" yaml:"section_id,omitempty"`
+	SourceID  int    `json:"source_id,omitempty" yaml:"source_id"`
+	HtmlURL   string `json:"html_url,omitempty" yaml:"html_url" readonly:""`
+	Slug      string `json:"-" yaml:"slug,omitempty" readonly:""`
+	PublishAt string `json:"-" yaml:"publish_at,omitempty"`
+	Brand     string `json:"-" yaml:"brand,omitempty"`
+	// AssetsDir is the directory of the files linked by the body of the locale, relative to the file.
+	AssetsDir string `json:"-" yaml:"assets_dir,omitempty"`
+	// BodyFormat is the format of the body, which is markdown if empty.
+	BodyFormat string `json:"-" yaml:"body_format,omitempty"`
+	// MachineTranslation is set to the translations drafted by machine translation.
+	MachineTranslation *MachineTranslation `json:"-" yaml:"machine_translation,omitempty"`
+	CreatedAt          string              `json:"created_at,omitempty" yaml:"created_at,omitempty" readonly:""`
+	UpdatedAt          string              `json:"updated_at,omitempty" yaml:"updated_at,omitempty" readonly:""`
+	// AuthorID is the author of the article, which is written to the file for reference.
+	AuthorID    int    `json:"-" yaml:"author_id,omitempty" readonly:""`
 	ID          int    `json:"id" yaml:"-"`
 	URL         string `json:"url,omitempty" yaml:"-"`
 	SourceType  string `json:"source_type,omitempty" yaml:"-"`
@@ -31,10 +42,47 @@ type Translation struct {
 	Body        string `json:"body,omitempty" yaml:"-"`
 }
 
+// MachineTranslation records the provenance of a translation drafted by machine translation.
+type MachineTranslation struct {
+	Provider     string `yaml:"provider"`
+	SourceLocale string `yaml:"source_locale"`
+	TranslatedAt string `yaml:"translated_at"`
+}
+
+var publishAtLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+const (
+	BodyFormatMarkdown = "markdown"
+	BodyFormatHTML     = "html"
+)
+
+// IsHTML reports whether the body is raw HTML, which is pushed as is without conversion.
+// An error is returned if body_format is neither markdown nor html.
+func (t *Translation) IsHTML() (bool, error) {
+	switch t.BodyFormat {
+	case "", BodyFormatMarkdown:
+		return false, nil
+	case BodyFormatHTML:
+		return true, nil
+	}
+	return false, fmt.Errorf("body_format must be %s or %s: %s", BodyFormatMarkdown, BodyFormatHTML, t.BodyFormat)
+}
+
 type wrappedTranslation struct {
 	Translation Translation `json:"translation"`
 }
 
+type wrappedTranslations struct {
+	Translations []Translation `json:"translations"`
+	NextPage     *string       `json:"next_page"`
+}
+
 func (t *Translation) FromFile(path string) error {
 	f, err := os.Open(path)
 	if err != nil {
@@ -42,18 +90,18 @@ func (t *Translation) FromFile(path string) error {
 	}
 	defer f.Close()
 
-	b, err := io.ReadAll(f)
+	r, err := newTextReader(f)
 	if err != nil {
 		return err
 	}
-
-	r := bytes.NewReader(b)
-	b, err = frontmatter.Parse(r, &t)
+	body, err := frontmatter.Parse(r, &t)
 	if err != nil {
 		return err
 	}
-	t.Body = string(b)
-
+	if err := validUTF8(body); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	t.Body = string(body)
 	return nil
 }
 
@@ -67,9 +115,24 @@ func (t *Translation) FromJson(jsonStr string) error {
 	return nil
 }
 
+// TranslationsFromJson returns the translations of the page of the list and whether the next page exists.
+func TranslationsFromJson(jsonStr string) ([]Translation, bool, error) {
+	wrapped := wrappedTranslations{}
+	err := json.Unmarshal([]byte(jsonStr), &wrapped)
+	if err != nil {
+		return nil, false, err
+	}
+	return wrapped.Translations, wrapped.NextPage != nil && *wrapped.NextPage != "", nil
+}
+
+// ToPayload returns the payload to update the translation without the read-only fields.
 func (t *Translation) ToPayload() (string, error) {
+	translation := *t
+	translation.HtmlURL = ""
+	translation.CreatedAt = ""
+	translation.UpdatedAt = ""
 	wrapped := wrappedTranslation{
-		Translation: *t,
+		Translation: translation,
 	}
 	b, err := json.Marshal(wrapped)
 	if err != nil {
@@ -78,35 +141,57 @@ func (t *Translation) ToPayload() (string, error) {
 	return string(b), nil
 }
 
-func (t *Translation) Save(path string, appendFileName bool) error {
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		if err := os.MkdirAll(path, 0o755); err != nil {
-			return err
+// PublishTime parses publish_at. Times without a time zone are interpreted in the local time zone.
+// The zero time is returned if publish_at is not set.
+func (t *Translation) PublishTime() (time.Time, error) {
+	if t.PublishAt == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range publishAtLayouts {
+		if pt, err := time.ParseInLocation(layout, t.PublishAt, time.Local); err == nil {
+			return pt, nil
 		}
 	}
+	return time.Time{}, fmt.Errorf("invalid publish_at: %s", t.PublishAt)
+}
 
-	if appendFileName {
-		path = filepath.Join(path, strconv.Itoa(t.SourceID)+"-"+t.Locale+".md")
-	}
-	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
-	if err != nil {
-		return err
+// IsScheduled reports whether publish_at is set to a time after now.
+func (t *Translation) IsScheduled(now time.Time) (bool, error) {
+	pt, err := t.PublishTime()
+	if err != nil || pt.IsZero() {
+		return false, err
 	}
-	defer f.Close()
+	return pt.After(now), nil
+}
 
-	if _, err := f.WriteString("---\n"); err != nil {
-		return err
+func (t *Translation) FileName() string {
+	return strconv.Itoa(t.SourceID) + "-" + t.Locale + ".md"
+}
+
+func (t *Translation) Save(path string, appendFileName bool) error {
+	return t.SaveWithTemplate(path, appendFileName, nil)
+}
+
+// SaveWithTemplate saves the translation with the front matter ordered by the template.
+func (t *Translation) SaveWithTemplate(path string, appendFileName bool, tmpl *FrontMatterTemplate) error {
+	dir := path
+	if !appendFileName {
+		dir = filepath.Dir(path)
 	}
-	ye := yaml.NewEncoder(f)
-	ye.SetIndent(2)
-	if err := ye.Encode(t); err != nil {
-		return err
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
 	}
-	if _, err := f.WriteString("---\n"); err != nil {
-		return err
+
+	if appendFileName {
+		path = filepath.Join(path, t.FileName())
 	}
-	if _, err := f.WriteString(t.Body); err != nil {
+	return writeFile(path, func(w io.Writer) error {
+		if err := writeFrontMatter(w, t, path, tmpl); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, t.Body)
 		return err
-	}
-	return nil
+	})
 }
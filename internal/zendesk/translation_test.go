@@ -3,6 +3,7 @@ package zendesk
 import (
 	"os"
 	"testing"
+	"time"
 )
 
 func TestTranslationFromFile(t *testing.T) {
@@ -77,3 +78,54 @@ func TestTranslationFromJson(t *testing.T) {
 		})
 	}
 }
+
+func TestTranslationIsScheduled(t *testing.T) {
+	translation := &Translation{}
+	if err := translation.FromFile("testdata/translation-scheduled.md"); err != nil {
+		t.Fatalf("TranslationFromFile() failed: %v", err)
+	}
+
+	publishAt := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		now      time.Time
+		expected bool
+	}{
+		{"before publish_at", publishAt.Add(-time.Minute), true},
+		{"at publish_at", publishAt, false},
+		{"after publish_at", publishAt.Add(time.Minute), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := translation.IsScheduled(tt.now)
+			if err != nil {
+				t.Errorf("IsScheduled() failed: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("IsScheduled() failed: got %v, want %v", actual, tt.expected)
+			}
+		})
+	}
+
+	translation.PublishAt = "tomorrow"
+	if _, err := translation.IsScheduled(publishAt); err == nil {
+		t.Errorf("IsScheduled() failed: invalid publish_at should be an error")
+	}
+}
+
+func TestTranslationToPayload(t *testing.T) {
+	translation := &Translation{}
+	if err := translation.FromFile("testdata/translation-ja.md"); err != nil {
+		t.Fatalf("TranslationFromFile() failed: %v", err)
+	}
+	translation.Body = "body"
+	payload, err := translation.ToPayload()
+	if err != nil {
+		t.Fatalf("Translation.ToPayload() failed: %v", err)
+	}
+	expected := `{"translation":{"title":"zgsyncの使い方","locale":"ja","source_id":12345,"id":0,"body":"body"}}`
+	if payload != expected {
+		t.Errorf("Translation.ToPayload() failed: got %s, want %s", payload, expected)
+	}
+}
@@ -0,0 +1,86 @@
+package zendesk
+
+import "encoding/json"
+
+// User is the user of the token.
+// refs: https://developer.zendesk.com/api-reference/ticketing/users/users/
+type User struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	// Role is one of end-user, agent and admin.
+	Role string `json:"role"`
+}
+
+type wrappedUser struct {
+	User User `json:"user"`
+}
+
+type wrappedUsers struct {
+	Users []User `json:"users"`
+}
+
+func (u *User) FromJson(jsonStr string) error {
+	wrapped := wrappedUser{}
+	err := json.Unmarshal([]byte(jsonStr), &wrapped)
+	if err != nil {
+		return err
+	}
+	*u = wrapped.User
+	return nil
+}
+
+// UsersFromJson returns the users of the search results.
+func UsersFromJson(jsonStr string) ([]User, error) {
+	wrapped := wrappedUsers{}
+	err := json.Unmarshal([]byte(jsonStr), &wrapped)
+	if err != nil {
+		return nil, err
+	}
+	return wrapped.Users, nil
+}
+
+// PermissionGroup is the group which gives the user segments the permissions to edit and publish the articles.
+// refs: https://developer.zendesk.com/api-reference/help_center/help-center-api/permission_groups/
+type PermissionGroup struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	BuiltIn bool   `json:"built_in"`
+	Edit    []int  `json:"edit"`
+	Publish []int  `json:"publish"`
+}
+
+type wrappedPermissionGroup struct {
+	PermissionGroup PermissionGroup `json:"permission_group"`
+}
+
+func (p *PermissionGroup) FromJson(jsonStr string) error {
+	wrapped := wrappedPermissionGroup{}
+	err := json.Unmarshal([]byte(jsonStr), &wrapped)
+	if err != nil {
+		return err
+	}
+	*p = wrapped.PermissionGroup
+	return nil
+}
+
+type wrappedUserSegments struct {
+	UserSegments []struct {
+		ID int `json:"id"`
+	} `json:"user_segments"`
+	NextPage *string `json:"next_page"`
+}
+
+// UserSegmentIDsFromJson returns the IDs of the user segments of the page of the list and whether the next page exists.
+func UserSegmentIDsFromJson(jsonStr string) ([]int, bool, error) {
+	wrapped := wrappedUserSegments{}
+	err := json.Unmarshal([]byte(jsonStr), &wrapped)
+	if err != nil {
+		return nil, false, err
+	}
+	ids := make([]int, 0, len(wrapped.UserSegments))
+	for _, s := range wrapped.UserSegments {
+		ids = append(ids, s.ID)
+	}
+	return ids, wrapped.NextPage != nil && *wrapped.NextPage != "", nil
+}